@@ -0,0 +1,310 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const answerContextLines = 3
+
+var (
+	answerOptionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("157"))
+	answerProStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("78"))
+	answerConStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+)
+
+// runAnswerCommand opens questions for answering in chat.
+//
+//	/answer            questions in the current question view, or open ones for you
+//	/answer <qstn-id>  a single question
+//	/answer <msg-id>   the questions asked in a message
+func (m *Model) runAnswerCommand(args []string) error {
+	var questions []types.Question
+	if len(args) > 0 {
+		resolved, err := m.resolveQuestionsRef(args[0])
+		if err != nil {
+			return err
+		}
+		questions = resolved
+	} else if m.currentPseudo != "" {
+		questions = append(questions, m.pseudoQuestions...)
+	} else {
+		targeted, err := db.GetQuestions(m.db, &types.QuestionQueryOptions{
+			Statuses: []types.QuestionStatus{types.QuestionStatusOpen},
+			ToAgent:  &m.username,
+		})
+		if err != nil {
+			return err
+		}
+		untargeted, err := db.GetQuestions(m.db, &types.QuestionQueryOptions{
+			Statuses:     []types.QuestionStatus{types.QuestionStatusOpen},
+			NoTargetOnly: true,
+		})
+		if err != nil {
+			return err
+		}
+		questions = append(targeted, untargeted...)
+	}
+
+	pending := make([]types.Question, 0, len(questions))
+	for _, question := range questions {
+		if question.Status == types.QuestionStatusOpen || question.Status == types.QuestionStatusUnasked {
+			pending = append(pending, question)
+		}
+	}
+	if len(pending) == 0 {
+		return fmt.Errorf("no open questions")
+	}
+
+	m.answerSavedInput = m.input.Value()
+	m.answerQueue = pending
+	m.answerIndex = 0
+	m.answerPairs = nil
+	m.clearSuggestions()
+	m.input.Reset()
+	m.resize()
+	return nil
+}
+
+// resolveQuestionsRef resolves a question GUID/prefix, or a message whose
+// questions should all be answered.
+func (m *Model) resolveQuestionsRef(ref string) ([]types.Question, error) {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(ref, "#"))
+	if trimmed == "" {
+		return nil, fmt.Errorf("question reference is required")
+	}
+
+	candidates := []string{trimmed}
+	if !strings.HasPrefix(strings.ToLower(trimmed), "qstn-") {
+		candidates = append(candidates, "qstn-"+trimmed)
+	}
+	for _, candidate := range candidates {
+		question, err := db.GetQuestion(m.db, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if question != nil {
+			return []types.Question{*question}, nil
+		}
+	}
+	if question, err := db.GetQuestionByPrefix(m.db, trimmed); err != nil {
+		return nil, err
+	} else if question != nil {
+		return []types.Question{*question}, nil
+	}
+
+	msg, err := db.GetMessage(m.db, trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		msg, err = db.GetMessageByPrefix(m.db, trimmed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("question not found: %s", ref)
+	}
+	questions, err := db.GetQuestions(m.db, &types.QuestionQueryOptions{AskedIn: &msg.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("no questions asked in %s", msg.ID)
+	}
+	return questions, nil
+}
+
+func (m *Model) answerActive() bool {
+	return len(m.answerQueue) > 0
+}
+
+func (m *Model) currentAnswerQuestion() *types.Question {
+	if m.answerIndex < 0 || m.answerIndex >= len(m.answerQueue) {
+		return nil
+	}
+	return &m.answerQueue[m.answerIndex]
+}
+
+func (m *Model) handleAnswerKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if !m.answerActive() {
+		return false, nil
+	}
+	question := m.currentAnswerQuestion()
+
+	if m.keyIs(msg, keyNewline) {
+		m.insertInputText("\n")
+		return true, nil
+	}
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.finishAnswering()
+		return true, nil
+	case tea.KeyEsc:
+		m.advanceAnswer()
+		return true, nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.input.Value())
+		lower := strings.ToLower(value)
+		switch {
+		case lower == "/ask" || strings.HasPrefix(lower, "/ask "):
+			if err := m.promoteQuestion(*question, strings.TrimSpace(value[len("/ask"):])); err != nil {
+				m.status = err.Error()
+				return true, nil
+			}
+		case value == "" || lower == "s" || lower == "skip":
+			// skip
+		case len(lower) == 1 && len(question.Options) > 0 && lower[0] >= 'a' && int(lower[0]-'a') < len(question.Options):
+			m.answerPairs = append(m.answerPairs, db.QuestionAnswer{Question: *question, Answer: question.Options[lower[0]-'a'].Label})
+		default:
+			m.answerPairs = append(m.answerPairs, db.QuestionAnswer{Question: *question, Answer: value})
+		}
+		m.advanceAnswer()
+		return true, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.resize()
+	return true, cmd
+}
+
+func (m *Model) advanceAnswer() {
+	m.input.Reset()
+	m.answerIndex++
+	if m.answerIndex >= len(m.answerQueue) {
+		m.finishAnswering()
+		return
+	}
+	m.resize()
+}
+
+// finishAnswering records collected answers exactly like `fray answer`: one
+// summary message plus a question_update per question.
+func (m *Model) finishAnswering() {
+	pairs := m.answerPairs
+	m.answerQueue = nil
+	m.answerPairs = nil
+	m.answerIndex = 0
+	m.input.SetValue(m.answerSavedInput)
+	m.input.CursorEnd()
+	m.answerSavedInput = ""
+	m.lastInputValue = m.input.Value()
+	m.lastInputPos = m.inputCursorPos()
+	m.updateInputStyle()
+
+	if len(pairs) > 0 {
+		if _, err := db.PostAnswerSummary(m.db, m.projectDBPath, m.username, pairs); err != nil {
+			m.status = err.Error()
+		} else {
+			m.status = fmt.Sprintf("Answered %d question(s)", len(pairs))
+		}
+	}
+	m.refreshQuestionCounts()
+	m.refreshPseudoQuestions()
+	if m.currentThread != nil {
+		m.refreshThreadMessages()
+	}
+	m.resize()
+	m.refreshViewport(false)
+}
+
+// promoteQuestion turns a wondering (unasked) question into an ask, posted by
+// the user and optionally addressed to an agent.
+func (m *Model) promoteQuestion(question types.Question, target string) error {
+	if question.Status != types.QuestionStatusUnasked {
+		return fmt.Errorf("%s is already asked", question.GUID)
+	}
+	var toAgent *string
+	if target != "" {
+		resolved := core.NormalizeAgentRef(strings.TrimPrefix(target, "@"))
+		toAgent = &resolved
+	}
+	updated, _, err := db.AskQuestion(m.db, m.projectDBPath, question, m.username, toAgent, nil)
+	if err != nil {
+		return err
+	}
+	m.status = fmt.Sprintf("Asked %s", updated.GUID)
+	return nil
+}
+
+func (m *Model) answerPanelHeight() int {
+	if !m.answerActive() {
+		return 0
+	}
+	return lipgloss.Height(m.renderAnswerPanel())
+}
+
+// renderAnswerPanel shows the current question above the input: who asked,
+// source message context, the question, and options with pros/cons.
+func (m *Model) renderAnswerPanel() string {
+	question := m.currentAnswerQuestion()
+	if question == nil {
+		return ""
+	}
+	width := m.mainWidth()
+	metaStyle := lipgloss.NewStyle().Foreground(metaColor)
+	wrapStyle := func(style lipgloss.Style) lipgloss.Style {
+		if width > 0 {
+			return style.Width(width)
+		}
+		return style
+	}
+
+	header := fmt.Sprintf("Question %d/%d · from @%s", m.answerIndex+1, len(m.answerQueue), question.FromAgent)
+	if question.ToAgent != nil {
+		header += " → @" + *question.ToAgent
+	}
+	if question.ThreadGUID != nil {
+		if thread, _ := db.GetThread(m.db, *question.ThreadGUID); thread != nil {
+			header += " · " + thread.Name
+		}
+	}
+	if question.Status == types.QuestionStatusUnasked {
+		header += " · wondering"
+	}
+	lines := []string{metaStyle.Render(truncateLine(header, width))}
+
+	if question.AskedIn != nil {
+		if msg, err := db.GetMessage(m.db, *question.AskedIn); err == nil && msg != nil {
+			context := strings.TrimSpace(core.StripQuestionSections(msg.Body))
+			if context != "" {
+				contextLines := strings.Split(wrapStyle(metaStyle).Render(context), "\n")
+				if len(contextLines) > answerContextLines {
+					contextLines = append(contextLines[:answerContextLines], metaStyle.Render("…"))
+				}
+				lines = append(lines, contextLines...)
+			}
+		}
+	}
+
+	questionStyle := lipgloss.NewStyle().Foreground(textColor).Bold(true)
+	lines = append(lines, wrapStyle(questionStyle).Render(question.Re))
+	for i, option := range question.Options {
+		letter := string(rune('a' + i))
+		lines = append(lines, fmt.Sprintf("  %s. %s", answerOptionStyle.Render(letter), option.Label))
+		for _, pro := range option.Pros {
+			lines = append(lines, fmt.Sprintf("     %s %s", answerProStyle.Render("+ Pro:"), pro))
+		}
+		for _, con := range option.Cons {
+			lines = append(lines, fmt.Sprintf("     %s %s", answerConStyle.Render("- Con:"), con))
+		}
+	}
+
+	help := "enter answer · empty/esc skip · ctrl-c done"
+	if len(question.Options) > 0 {
+		help = fmt.Sprintf("[a-%c] select · %s", 'a'+len(question.Options)-1, help)
+	}
+	if question.Status == types.QuestionStatusUnasked {
+		help = "/ask [@agent] promote · " + help
+	}
+	lines = append(lines, metaStyle.Render(truncateLine(help, width)))
+	return strings.Join(lines, "\n")
+}
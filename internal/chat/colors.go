@@ -80,11 +80,14 @@ func colorForAgent(agentID string, colorMap map[string]lipgloss.Color) lipgloss.
 }
 
 func contrastTextColor(color lipgloss.Color) lipgloss.Color {
-	code, ok := parseColorCode(color)
+	r, g, b, ok := parseHexColor(color)
 	if !ok {
-		return lipgloss.Color("231")
+		code, ok := parseColorCode(color)
+		if !ok {
+			return lipgloss.Color("231")
+		}
+		r, g, b = colorCodeToRGB(code)
 	}
-	r, g, b := colorCodeToRGB(code)
 	luminance := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
 	if luminance > 128 {
 		return lipgloss.Color("16")
@@ -92,6 +95,19 @@ func contrastTextColor(color lipgloss.Color) lipgloss.Color {
 	return lipgloss.Color("231")
 }
 
+// parseHexColor parses a truecolor "#rrggbb" value.
+func parseHexColor(color lipgloss.Color) (int, int, int, bool) {
+	trimmed := strings.TrimSpace(string(color))
+	if len(trimmed) != 7 || trimmed[0] != '#' {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(trimmed[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), true
+}
+
 func parseColorCode(color lipgloss.Color) (int, bool) {
 	trimmed := strings.TrimSpace(string(color))
 	if trimmed == "" {
@@ -93,6 +93,32 @@ func (m *Model) runSlashCommand(input string) (tea.Cmd, error) {
 	case "/help":
 		m.showHelp()
 		return nil, nil
+	case "/search":
+		// Search messages across room, threads, and archive
+		m.enterSearch(strings.TrimSpace(strings.TrimPrefix(input, fields[0])))
+		return nil, nil
+	case "/answer":
+		// Answer questions inline (current question view, a question, or a message's questions)
+		return nil, m.runAnswerCommand(fields[1:])
+	case "/agents":
+		// Toggle the agents dashboard
+		m.toggleDashboard()
+		return nil, nil
+	case "/split":
+		// Open a stacked pane (current view, main, a question view, or a thread)
+		return nil, m.runSplitCommand(splitHorizontal, fields[1:])
+	case "/vsplit":
+		// Open a side-by-side pane
+		return nil, m.runSplitCommand(splitVertical, fields[1:])
+	case "/close":
+		// Close the focused pane
+		return nil, m.closePane()
+	case "/compose":
+		// Write the message in $EDITOR
+		return m.openComposer(), nil
+	case "/later":
+		// Schedule a message, list pending sends, or cancel one
+		return nil, m.runLaterCommand(strings.TrimPrefix(input, fields[0]))
 	case "/n":
 		// Set nickname for selected thread
 		return m.setThreadNickname(fields[1:])
@@ -218,6 +244,13 @@ func buildHelpText() string {
 			25,
 			22,
 		),
+		formatHelpRow(
+			helpItemStyle+"Ctrl-F"+helpResetStyle+" - search",
+			helpItemStyle+"/search <text>"+helpResetStyle,
+			helpItemStyle+"Ctrl-G"+helpResetStyle+" - agents (w wake, n nudge, e end)",
+			25,
+			22,
+		),
 		"",
 		helpLabelStyle + "Create Threads" + helpResetStyle,
 		formatHelpRow(
@@ -255,11 +288,29 @@ func buildHelpText() string {
 		formatHelpRow(
 			helpItemStyle+"/edit <id> <text> -m <reason>"+helpResetStyle,
 			helpItemStyle+"/delete <id>"+helpResetStyle,
-			"",
+			helpItemStyle+"/answer [id]"+helpResetStyle,
 			35,
 			22,
 		),
 		"",
+		helpLabelStyle + "Panes" + helpResetStyle,
+		formatHelpRow(
+			helpItemStyle+"/split [thread]"+helpResetStyle,
+			helpItemStyle+"/vsplit [thread]"+helpResetStyle,
+			helpItemStyle+"/close"+helpResetStyle+" · "+helpItemStyle+"Ctrl-O"+helpResetStyle+" - next pane",
+			18,
+			22,
+		),
+		"",
+		helpLabelStyle + "Compose" + helpResetStyle + " (drafts are kept per thread)",
+		formatHelpRow(
+			helpItemStyle+"Ctrl-X"+helpResetStyle+" - $EDITOR",
+			helpItemStyle+"/later <time> <msg>"+helpResetStyle,
+			helpItemStyle+"/later"+helpResetStyle+" - list · "+helpItemStyle+"/later cancel <id>"+helpResetStyle,
+			18,
+			22,
+		),
+		"",
 		helpItemStyle + "Click" + helpResetStyle + " a message to reply. " +
 			helpItemStyle + "Double-click" + helpResetStyle + " to copy.",
 	}
@@ -493,7 +544,7 @@ func (m *Model) resolveMessageInput(raw string) (*types.Message, error) {
 
 func (m *Model) appendMessageUpdate(msg types.Message) error {
 	body := msg.Body
-	update := db.MessageUpdateJSONLRecord{ID: msg.ID, Body: &body}
+	update := db.MessageUpdateJSONLRecord{ID: msg.ID, Body: &body, Signature: msg.Signature}
 	if msg.EditedAt != nil {
 		update.EditedAt = msg.EditedAt
 	}
@@ -505,7 +556,7 @@ func (m *Model) appendMessageUpdate(msg types.Message) error {
 
 func (m *Model) appendMessageEditUpdate(msg types.Message, reason string) error {
 	body := msg.Body
-	update := db.MessageUpdateJSONLRecord{ID: msg.ID, Body: &body, Reason: &reason}
+	update := db.MessageUpdateJSONLRecord{ID: msg.ID, Body: &body, Reason: &reason, Signature: msg.Signature}
 	if msg.EditedAt != nil {
 		update.EditedAt = msg.EditedAt
 	}
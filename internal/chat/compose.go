@@ -0,0 +1,278 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// composeDoneMsg is sent when the external editor exits.
+type composeDoneMsg struct {
+	path string
+	err  error
+}
+
+// draftKey is the home the input belongs to: the current thread, or the room.
+func (m *Model) draftKey() string {
+	if m.currentThread != nil {
+		return m.currentThread.GUID
+	}
+	return "room"
+}
+
+// composing reports whether the input holds a message being written, rather
+// than a search term or an answer.
+func (m *Model) composing() bool {
+	return !m.searchActive && !m.answerActive()
+}
+
+// stashDraft records the input as the draft for draftHome, reporting whether
+// it changed.
+func (m *Model) stashDraft() bool {
+	if m.drafts == nil {
+		m.drafts = map[string]string{}
+	}
+	value := m.input.Value()
+	if strings.TrimSpace(value) == "" {
+		value = ""
+	}
+	if m.drafts[m.draftHome] == value {
+		return false
+	}
+	if value == "" {
+		delete(m.drafts, m.draftHome)
+	} else {
+		m.drafts[m.draftHome] = value
+	}
+	return true
+}
+
+// syncDraft swaps the input when the view moves to another home: the old
+// home's text is kept as its draft and the new home's draft is restored.
+func (m *Model) syncDraft() {
+	if !m.composing() {
+		return
+	}
+	home := m.draftKey()
+	if home == m.draftHome {
+		return
+	}
+	m.stashDraft()
+	m.draftHome = home
+	m.setInputValue(m.drafts[home])
+	m.saveDrafts()
+}
+
+// persistDraft saves the current draft if it changed since the last save.
+func (m *Model) persistDraft() {
+	if m.composing() && m.stashDraft() {
+		m.saveDrafts()
+	}
+}
+
+func (m *Model) saveDrafts() {
+	if m.username == "" || m.projectRoot == "" {
+		return
+	}
+	if err := core.WriteChatDrafts(m.projectRoot, m.username, m.drafts); err != nil {
+		m.status = err.Error()
+	}
+}
+
+// restoreDrafts loads saved drafts and fills the input for the current home.
+func (m *Model) restoreDrafts() {
+	m.draftHome = m.draftKey()
+	if m.username == "" || m.projectRoot == "" {
+		return
+	}
+	drafts, err := core.ReadChatDrafts(m.projectRoot, m.username)
+	if err != nil {
+		return
+	}
+	m.drafts = drafts
+	m.setInputValue(drafts[m.draftHome])
+}
+
+func (m *Model) setInputValue(value string) {
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.clearSuggestions()
+	m.lastInputValue = m.input.Value()
+	m.lastInputPos = m.inputCursorPos()
+	m.updateInputStyle()
+	m.resize()
+}
+
+// composeEditor returns the editor command: $VISUAL, then $EDITOR, then vi.
+func composeEditor() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openComposer suspends the UI and edits the input in an external editor.
+// The result lands back in the input to review before sending.
+func (m *Model) openComposer() tea.Cmd {
+	file, err := os.CreateTemp("", "fray-compose-*.md")
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	_, err = file.WriteString(m.input.Value())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		m.status = err.Error()
+		return nil
+	}
+
+	editor := composeEditor()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	path := file.Name()
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return composeDoneMsg{path: path, err: err}
+	})
+}
+
+func (m *Model) handleComposeDone(msg composeDoneMsg) {
+	defer os.Remove(msg.path)
+	if msg.err != nil {
+		m.status = fmt.Sprintf("editor: %v", msg.err)
+		return
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.setInputValue(strings.TrimRight(normalizeNewlines(string(data)), "\n"))
+	m.status = "Composed · Enter to send"
+	m.persistDraft()
+}
+
+// runLaterCommand handles /later: list pending sends, cancel one, or schedule
+// a message to the current room or thread.
+func (m *Model) runLaterCommand(input string) error {
+	args := strings.Fields(input)
+	if len(args) == 0 {
+		return m.showScheduled()
+	}
+	if args[0] == "cancel" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: /later cancel <id>")
+		}
+		pending, err := db.ReadPendingScheduled(m.projectDBPath)
+		if err != nil {
+			return err
+		}
+		scheduled, err := db.MatchScheduled(pending, args[1])
+		if err != nil {
+			return err
+		}
+		if err := db.CancelScheduledMessage(m.projectDBPath, scheduled.GUID, m.username); err != nil {
+			return err
+		}
+		m.status = "Cancelled " + scheduled.GUID
+		return nil
+	}
+
+	if m.currentPseudo != "" {
+		return fmt.Errorf("select a thread or #main to schedule")
+	}
+	sendAt, body, err := parseLaterArgs(input, time.Now())
+	if err != nil {
+		return err
+	}
+	scheduled, err := db.AppendScheduledMessage(m.projectDBPath, types.ScheduledMessage{
+		FromAgent: m.username,
+		Home:      m.draftKey(),
+		Body:      body,
+		Type:      types.MessageTypeUser,
+		SendAt:    sendAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	m.status = fmt.Sprintf("Scheduled %s for %s", scheduled.GUID, sendAt.Format("Mon Jan 2 15:04"))
+	return nil
+}
+
+// parseLaterArgs splits "<time> <message>", trying two-word times
+// ("tomorrow 9am", "in 2h") before one-word ones. The message keeps its
+// line breaks.
+func parseLaterArgs(input string, now time.Time) (time.Time, string, error) {
+	args := strings.Fields(input)
+	for n := min(2, len(args)-1); n >= 1; n-- {
+		if sendAt, err := core.ParseSendTime(strings.Join(args[:n], " "), now); err == nil {
+			return sendAt, dropFields(input, n), nil
+		}
+	}
+	if len(args) == 0 {
+		return time.Time{}, "", fmt.Errorf("usage: /later <time> <message>")
+	}
+	if _, err := core.ParseSendTime(args[0], now); err != nil {
+		return time.Time{}, "", err
+	}
+	return time.Time{}, "", fmt.Errorf("usage: /later <time> <message>")
+}
+
+// dropFields removes the first n whitespace-separated fields from s.
+func dropFields(s string, n int) string {
+	for i := 0; i < n; i++ {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		s = s[end:]
+	}
+	return strings.TrimSpace(s)
+}
+
+// showScheduled lists this user's pending scheduled messages as an event.
+func (m *Model) showScheduled() error {
+	pending, err := db.ReadPendingScheduled(m.projectDBPath)
+	if err != nil {
+		return err
+	}
+	lines := []string{helpLabelStyle + "Scheduled" + helpResetStyle}
+	for _, scheduled := range pending {
+		if scheduled.FromAgent != m.username {
+			continue
+		}
+		where := "#main"
+		if scheduled.Home != "room" {
+			where = scheduled.Home
+			if thread, err := db.GetThread(m.db, scheduled.Home); err == nil && thread != nil {
+				if path, err := threadPath(m.db, thread); err == nil && path != "" {
+					where = path
+				}
+			}
+		}
+		preview := truncateLine(strings.ReplaceAll(scheduled.Body, "\n", " "), 60)
+		sendAt := time.Unix(scheduled.SendAt, 0).Format("Mon Jan 2 15:04")
+		lines = append(lines, fmt.Sprintf("%s%s%s %s → %s: %s", helpItemStyle, scheduled.GUID, helpResetStyle, sendAt, where, preview))
+	}
+	if len(lines) == 1 {
+		m.status = "No scheduled messages"
+		return nil
+	}
+	lines = append(lines, "", helpItemStyle+"/later cancel <id>"+helpResetStyle+" to cancel")
+	m.messages = append(m.messages, newEventMessage(strings.Join(lines, "\n")))
+	m.status = ""
+	m.refreshViewport(true)
+	return nil
+}
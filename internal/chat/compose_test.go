@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+func TestSyncDraftSwapsPerHome(t *testing.T) {
+	m := &Model{input: textarea.New(), draftHome: "room"}
+	m.input.SetValue("room draft")
+
+	m.currentThread = &types.Thread{GUID: "thrd-1"}
+	m.syncDraft()
+	if m.input.Value() != "" || m.drafts["room"] != "room draft" {
+		t.Fatalf("expected room draft stashed and empty thread input, got %q %v", m.input.Value(), m.drafts)
+	}
+
+	m.input.SetValue("thread draft")
+	m.currentThread = nil
+	m.syncDraft()
+	if m.input.Value() != "room draft" || m.drafts["thrd-1"] != "thread draft" {
+		t.Fatalf("expected room draft restored, got %q %v", m.input.Value(), m.drafts)
+	}
+
+	m.searchActive = true
+	m.currentThread = &types.Thread{GUID: "thrd-1"}
+	m.syncDraft()
+	if m.draftHome != "room" {
+		t.Fatalf("expected no swap while searching")
+	}
+}
+
+func TestParseLaterArgs(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	at, body, err := parseLaterArgs(" tomorrow 9am  ship it\n- notes", now)
+	if err != nil || body != "ship it\n- notes" || !at.Equal(time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("two-word time: %s %q %v", at, body, err)
+	}
+	at, body, err = parseLaterArgs("30m tomorrow's plan", now)
+	if err != nil || body != "tomorrow's plan" || !at.Equal(now.Add(30*time.Minute)) {
+		t.Fatalf("one-word time: %s %q %v", at, body, err)
+	}
+	if _, _, err := parseLaterArgs("2h", now); err == nil {
+		t.Fatalf("expected usage error without a message")
+	}
+	if _, _, err := parseLaterArgs("whenever hi", now); err == nil {
+		t.Fatalf("expected invalid time error")
+	}
+}
@@ -0,0 +1,369 @@
+package chat
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/daemon"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const dashboardPanelWidth = 34
+
+// dashboardAgent is one row of the agents dashboard.
+type dashboardAgent struct {
+	Agent      types.Agent
+	Claims     []types.Claim
+	Playing    []string
+	RecycleIn  time.Duration
+	HasRecycle bool // true when the daemon will recycle an idle session
+}
+
+var presenceOrder = map[types.PresenceState]int{
+	types.PresenceActive:   0,
+	types.PresenceSpawning: 1,
+	types.PresenceIdle:     2,
+	types.PresenceError:    3,
+	types.PresenceOffline:  4,
+	"":                     5,
+}
+
+func (m *Model) dashboardWidth() int {
+	if !m.dashboardOpen {
+		return 0
+	}
+	return dashboardPanelWidth
+}
+
+func (m *Model) toggleDashboard() {
+	m.dashboardOpen = !m.dashboardOpen
+	m.dashboardFocus = m.dashboardOpen
+	if m.dashboardOpen {
+		m.refreshDashboard()
+	}
+	m.clearSuggestions()
+	m.resize()
+}
+
+// refreshDashboard reloads presence, claims, roles, and daemon state.
+func (m *Model) refreshDashboard() {
+	if !m.dashboardOpen {
+		return
+	}
+	agents, err := db.GetAllAgents(m.db)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	claims, err := db.GetAllClaims(m.db)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	roles, err := db.GetAllAgentRoles(m.db)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	claimsByAgent := map[string][]types.Claim{}
+	for _, claim := range claims {
+		claimsByAgent[claim.AgentID] = append(claimsByAgent[claim.AgentID], claim)
+	}
+
+	staleHours := 4
+	if value, err := db.GetConfig(m.db, "stale_hours"); err == nil && value != "" {
+		fmt.Sscanf(value, "%d", &staleHours)
+	}
+	staleCutoff := time.Now().Add(-time.Duration(staleHours) * time.Hour).Unix()
+	now := time.Now()
+
+	entries := make([]dashboardAgent, 0, len(agents))
+	for _, agent := range agents {
+		if !agent.Managed && (agent.LeftAt != nil || agent.LastSeen < staleCutoff) {
+			continue
+		}
+		entry := dashboardAgent{Agent: agent, Claims: claimsByAgent[agent.AgentID]}
+		if agentRoles := roles[agent.AgentID]; agentRoles != nil {
+			entry.Playing = agentRoles.Playing
+		}
+		lastPost, _ := db.GetAgentLastPostTime(m.db, agent.AgentID)
+		entry.RecycleIn, entry.HasRecycle = recycleIn(agent, lastPost, now)
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		left, right := presenceOrder[entries[i].Agent.Presence], presenceOrder[entries[j].Agent.Presence]
+		if left != right {
+			return left < right
+		}
+		return entries[i].Agent.AgentID < entries[j].Agent.AgentID
+	})
+
+	m.dashboardAgents = entries
+	if m.dashboardIndex >= len(entries) {
+		m.dashboardIndex = len(entries) - 1
+	}
+	if m.dashboardIndex < 0 {
+		m.dashboardIndex = 0
+	}
+	m.daemonRunning = daemon.IsLocked(filepath.Dir(m.projectDBPath))
+}
+
+// recycleIn estimates how long until the daemon recycles an idle managed
+// session: min_checkin after the latest post or heartbeat.
+func recycleIn(agent types.Agent, lastPostSec int64, now time.Time) (time.Duration, bool) {
+	if !agent.Managed || agent.Presence != types.PresenceIdle {
+		return 0, false
+	}
+	_, _, minCheckin, _ := daemon.GetTimeouts(agent.Invoke)
+	lastActivity := lastPostSec * 1000
+	if agent.LastHeartbeat != nil && *agent.LastHeartbeat > lastActivity {
+		lastActivity = *agent.LastHeartbeat
+	}
+	if lastActivity == 0 {
+		return 0, false
+	}
+	remaining := minCheckin - (now.UnixMilli() - lastActivity)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return time.Duration(remaining) * time.Millisecond, true
+}
+
+func (m *Model) selectedDashboardAgent() *dashboardAgent {
+	if m.dashboardIndex < 0 || m.dashboardIndex >= len(m.dashboardAgents) {
+		return nil
+	}
+	return &m.dashboardAgents[m.dashboardIndex]
+}
+
+func (m *Model) handleDashboardKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if m.keyIs(msg, keyAgents) {
+		m.toggleDashboard()
+		return true, nil
+	}
+	if !m.dashboardOpen || !m.dashboardFocus {
+		return false, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Return focus to input; the panel stays open
+		m.dashboardFocus = false
+		return true, nil
+	case tea.KeyUp:
+		m.moveDashboardSelection(-1)
+		return true, nil
+	case tea.KeyDown:
+		m.moveDashboardSelection(1)
+		return true, nil
+	case tea.KeyRunes:
+		if msg.Paste {
+			return true, nil
+		}
+	default:
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "j":
+		m.moveDashboardSelection(1)
+	case "k":
+		m.moveDashboardSelection(-1)
+	case "w":
+		return true, m.wakeSelectedAgent()
+	case "n":
+		m.nudgeSelectedAgent()
+	case "e":
+		m.endSelectedAgent()
+	}
+	return true, nil
+}
+
+func (m *Model) moveDashboardSelection(delta int) {
+	if len(m.dashboardAgents) == 0 {
+		return
+	}
+	m.dashboardIndex += delta
+	if m.dashboardIndex < 0 {
+		m.dashboardIndex = 0
+	}
+	if m.dashboardIndex >= len(m.dashboardAgents) {
+		m.dashboardIndex = len(m.dashboardAgents) - 1
+	}
+}
+
+// wakeSelectedAgent posts a direct address, which the daemon turns into a spawn.
+func (m *Model) wakeSelectedAgent() tea.Cmd {
+	entry := m.selectedDashboardAgent()
+	if entry == nil {
+		return nil
+	}
+	if !entry.Agent.Managed {
+		m.status = fmt.Sprintf("@%s is not managed by the daemon", entry.Agent.AgentID)
+		return nil
+	}
+	cmd := m.handleSubmit(fmt.Sprintf("@%s wake up", entry.Agent.AgentID))
+	if m.status == "" {
+		m.status = fmt.Sprintf("Woke @%s", entry.Agent.AgentID)
+		if !m.daemonRunning {
+			m.status += " (daemon is not running)"
+		}
+	}
+	return cmd
+}
+
+// nudgeSelectedAgent prefills a mention so the user can write the nudge.
+func (m *Model) nudgeSelectedAgent() {
+	entry := m.selectedDashboardAgent()
+	if entry == nil {
+		return
+	}
+	m.dashboardFocus = false
+	m.input.SetValue("@" + entry.Agent.AgentID + " ")
+	m.input.CursorEnd()
+	m.lastInputValue = m.input.Value()
+	m.lastInputPos = m.inputCursorPos()
+	m.updateInputStyle()
+	m.resize()
+}
+
+// endSelectedAgent ends a managed session, like fray agent end.
+func (m *Model) endSelectedAgent() {
+	entry := m.selectedDashboardAgent()
+	if entry == nil {
+		return
+	}
+	if !entry.Agent.Managed {
+		m.status = fmt.Sprintf("@%s is not managed by the daemon", entry.Agent.AgentID)
+		return
+	}
+	// The daemon owns running sessions; without one there is no process to stop.
+	frayDir := filepath.Dir(m.projectDBPath)
+	if m.daemonRunning {
+		if _, err := daemon.Call(frayDir, daemon.Request{Op: daemon.OpEnd, AgentID: entry.Agent.AgentID}); err != nil {
+			m.status = err.Error()
+			return
+		}
+	} else if err := db.UpdateAgentPresence(m.db, entry.Agent.AgentID, types.PresenceOffline); err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.status = fmt.Sprintf("Ended session for @%s", entry.Agent.AgentID)
+	m.refreshDashboard()
+}
+
+func (m *Model) renderDashboard() string {
+	width := m.dashboardWidth()
+	if width <= 0 {
+		return ""
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(brightColor).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(panelColor)
+	nameStyle := lipgloss.NewStyle().Foreground(brightColor)
+	selectedStyle := lipgloss.NewStyle().Foreground(brightColor).Background(selectedBg).Bold(true)
+
+	daemonState := "daemon stopped"
+	if m.daemonRunning {
+		daemonState = "daemon running"
+	}
+	lines := []string{
+		headerStyle.Render(" Agents "),
+		itemStyle.Render(" " + daemonState),
+		"",
+	}
+
+	if len(m.dashboardAgents) == 0 {
+		lines = append(lines, itemStyle.Render(" (none)"))
+	}
+	for i, entry := range m.dashboardAgents {
+		agent := entry.Agent
+		name := "@" + agent.AgentID
+		if len(entry.Playing) > 0 {
+			name += " [" + strings.Join(entry.Playing, ", ") + "]"
+		}
+		name = truncateLine(name, width-4)
+		style := nameStyle
+		if i == m.dashboardIndex && m.dashboardFocus {
+			style = selectedStyle
+		}
+		lines = append(lines, " "+presenceGlyph(agent.Presence)+" "+style.Render(name))
+
+		detail := presenceLabel(agent)
+		if entry.HasRecycle {
+			detail += " · recycle " + formatCountdown(entry.RecycleIn)
+		}
+		if agent.Status != nil && *agent.Status != "" {
+			detail += " · " + *agent.Status
+		}
+		lines = append(lines, itemStyle.Render(truncateLine("   "+detail, width-1)))
+
+		if len(entry.Claims) > 0 {
+			items := make([]string, len(entry.Claims))
+			for j, claim := range entry.Claims {
+				items[j] = formatDashboardClaim(claim)
+			}
+			lines = append(lines, itemStyle.Render(truncateLine("   claims: "+strings.Join(items, ", "), width-1)))
+		}
+	}
+
+	footer := " w wake · n nudge · e end"
+	if m.height > 0 {
+		for len(lines) < m.height-1 {
+			lines = append(lines, "")
+		}
+		if len(lines) > m.height-1 {
+			lines = lines[:m.height-1]
+		}
+	}
+	lines = append(lines, itemStyle.Render(footer))
+
+	content := strings.Join(lines, "\n")
+	return lipgloss.NewStyle().Width(width).Render(content)
+}
+
+func presenceGlyph(presence types.PresenceState) string {
+	switch presence {
+	case types.PresenceActive:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("●")
+	case types.PresenceSpawning:
+		return lipgloss.NewStyle().Foreground(reactionColor).Render("◐")
+	case types.PresenceIdle:
+		return lipgloss.NewStyle().Foreground(metaColor).Render("○")
+	case types.PresenceError:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✕")
+	}
+	return lipgloss.NewStyle().Foreground(statusColor).Render("·")
+}
+
+func presenceLabel(agent types.Agent) string {
+	if agent.Presence != "" {
+		return string(agent.Presence)
+	}
+	return "seen " + formatCountdown(time.Since(time.Unix(agent.LastSeen, 0))) + " ago"
+}
+
+func formatDashboardClaim(claim types.Claim) string {
+	if claim.ClaimType == types.ClaimTypeFile {
+		return claim.Pattern
+	}
+	return fmt.Sprintf("%s:%s", claim.ClaimType, claim.Pattern)
+}
+
+// formatCountdown renders a duration as a compact 45s / 7m / 2h.
+func formatCountdown(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
@@ -0,0 +1,58 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+func TestRecycleIn(t *testing.T) {
+	now := time.Unix(10_000, 0)
+	heartbeat := now.Add(-4 * time.Minute).UnixMilli()
+	idle := types.Agent{
+		AgentID:       "alice",
+		Managed:       true,
+		Presence:      types.PresenceIdle,
+		Invoke:        &types.InvokeConfig{MinCheckinMs: int64(10 * time.Minute / time.Millisecond)},
+		LastHeartbeat: &heartbeat,
+	}
+
+	remaining, ok := recycleIn(idle, now.Add(-8*time.Minute).Unix(), now)
+	if !ok {
+		t.Fatalf("expected idle managed agent to have a recycle countdown")
+	}
+	if remaining != 6*time.Minute {
+		t.Fatalf("expected 6m from latest heartbeat, got %s", remaining)
+	}
+
+	remaining, _ = recycleIn(idle, now.Add(-time.Minute).Unix(), now)
+	if remaining != 9*time.Minute {
+		t.Fatalf("expected 9m from latest post, got %s", remaining)
+	}
+
+	active := idle
+	active.Presence = types.PresenceActive
+	if _, ok := recycleIn(active, 0, now); ok {
+		t.Fatalf("active agents are not recycled")
+	}
+
+	unmanaged := idle
+	unmanaged.Managed = false
+	if _, ok := recycleIn(unmanaged, 0, now); ok {
+		t.Fatalf("unmanaged agents are not recycled")
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second: "45s",
+		7 * time.Minute:  "7m",
+		3 * time.Hour:    "3h",
+	}
+	for d, want := range tests {
+		if got := formatCountdown(d); got != want {
+			t.Fatalf("formatCountdown(%s): got %q want %q", d, got, want)
+		}
+	}
+}
@@ -11,7 +11,8 @@ import (
 	"github.com/alecthomas/chroma/styles"
 )
 
-const chromaStyleName = "dracula"
+// chromaStyleName is the code block style; themes may override it.
+var chromaStyleName = "dracula"
 
 func highlightCodeBlocks(body string) string {
 	if body == "" || os.Getenv("NO_COLOR") != "" {
@@ -31,6 +31,7 @@ func (m *Model) mainWidth() int {
 	if m.sidebarOpen {
 		width -= m.sidebarWidth()
 	}
+	width -= m.dashboardWidth()
 	if width < 1 {
 		width = 1
 	}
@@ -139,13 +140,14 @@ func (m *Model) resize() {
 	inputHeight := m.input.Height() + 2
 
 	statusHeight := 1
-	suggestionHeight := m.suggestionHeight()
+	suggestionHeight := m.suggestionHeight() + m.searchResultsHeight() + m.answerPanelHeight()
 	marginHeight := 1
 	m.viewport.Width = width
 	m.viewport.Height = m.height - inputHeight - statusHeight - suggestionHeight - marginHeight
 	if m.viewport.Height < 1 {
 		m.viewport.Height = 1
 	}
+	m.layoutPanes()
 	if m.initialScroll {
 		m.refreshViewport(true)
 		m.initialScroll = false
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"sort"
 	"strings"
@@ -47,7 +48,7 @@ func (m *Model) formatMessage(msg types.Message, prefixLength int, readToMap map
 	if msg.Type == types.MessageTypeEvent {
 		body := msg.Body
 		hasANSI := strings.Contains(body, "\x1b[")
-		width := m.mainWidth()
+		width := m.messageWidth()
 		if width > 0 {
 			body = ansi.Wrap(body, width, "")
 		}
@@ -73,8 +74,12 @@ func (m *Model) formatMessage(msg types.Message, prefixLength int, readToMap map
 	bylineText := renderByline(msg.FromAgent, avatar, color)
 	sender := m.zoneManager.Mark("byline-"+msg.ID, bylineText)
 
-	body := highlightCodeBlocks(msg.Body)
-	width := m.mainWidth()
+	width := m.messageWidth()
+	body := msg.Body
+	if !m.rawMarkdown {
+		body = core.RenderMarkdown(body, core.MarkdownOptions{Width: width, Color: os.Getenv("NO_COLOR") == ""})
+	}
+	body = highlightCodeBlocks(body)
 	if width > 0 {
 		body = ansi.Wrap(body, width, "")
 	}
@@ -85,6 +90,9 @@ func (m *Model) formatMessage(msg types.Message, prefixLength int, readToMap map
 	if msg.Edited || msg.EditCount > 0 || msg.EditedAt != nil {
 		editedSuffix = " (edited)"
 	}
+	if label := core.SignatureLabel(msg.SignatureStatus); label != "" {
+		editedSuffix += " " + label
+	}
 
 	// Build the meta line with guid and read_to markers
 	guidPrefix := core.GetGUIDPrefix(msg.ID, prefixLength)
@@ -127,6 +135,18 @@ func (m *Model) formatMessage(msg types.Message, prefixLength int, readToMap map
 		lines = append(lines, m.replyContext(*msg.ReplyTo, prefixLength))
 	}
 	lines = append(lines, fmt.Sprintf("%s\n%s", sender, bodyLine))
+	if len(msg.Attachments) > 0 {
+		chipStyle := lipgloss.NewStyle().Foreground(color).Faint(true)
+		chips := make([]string, len(msg.Attachments))
+		for i, attachment := range msg.Attachments {
+			chips[i] = chipStyle.Render("[📎 " + core.AttachmentLabel(attachment) + "]")
+		}
+		chipLine := strings.Join(chips, " ")
+		if width > 0 {
+			chipLine = ansi.Wrap(chipLine, width, "")
+		}
+		lines = append(lines, chipLine)
+	}
 	if reactionLine := formatReactionSummary(msg.Reactions); reactionLine != "" {
 		line := lipgloss.NewStyle().Foreground(metaColor).Faint(true).Render(reactionLine)
 		if width > 0 {
@@ -217,7 +237,7 @@ func (m *Model) markBodyZones(msgID string, body string, color lipgloss.Color) s
 func (m *Model) styleLineWithInlineIDs(msgID string, lineNum int, line string, textStyle, idStyle lipgloss.Style) string {
 	matches := inlineIDPattern.FindAllStringIndex(line, -1)
 	if len(matches) == 0 {
-		return textStyle.Render(line)
+		return m.highlightSearchMatches(line, textStyle)
 	}
 
 	var result strings.Builder
@@ -226,7 +246,7 @@ func (m *Model) styleLineWithInlineIDs(msgID string, lineNum int, line string, t
 	for idx, match := range matches {
 		// Add text before this match
 		if match[0] > cursor {
-			result.WriteString(textStyle.Render(line[cursor:match[0]]))
+			result.WriteString(m.highlightSearchMatches(line[cursor:match[0]], textStyle))
 		}
 
 		// Style and zone the ID
@@ -234,18 +254,30 @@ func (m *Model) styleLineWithInlineIDs(msgID string, lineNum int, line string, t
 		styledID := idStyle.Render(idText)
 		zoneID := fmt.Sprintf("inlineid-%s-%d-%d", msgID, lineNum, idx)
 		result.WriteString(m.zoneManager.Mark(zoneID, styledID))
+		if issue := core.LookupIssue(m.issueProviders, idText); issue != nil {
+			result.WriteString(renderIssueStatus(*issue))
+		}
 
 		cursor = match[1]
 	}
 
 	// Add remaining text after last match
 	if cursor < len(line) {
-		result.WriteString(textStyle.Render(line[cursor:]))
+		result.WriteString(m.highlightSearchMatches(line[cursor:], textStyle))
 	}
 
 	return result.String()
 }
 
+// renderIssueStatus renders a dim status tag shown after a resolved issue ref.
+func renderIssueStatus(issue core.Issue) string {
+	style := lipgloss.NewStyle().Foreground(metaColor).Faint(true)
+	if issue.IsClosed() {
+		style = style.Strikethrough(true)
+	}
+	return style.Render(fmt.Sprintf(" [%s]", issue.Status))
+}
+
 func renderByline(agent string, avatar string, color lipgloss.Color) string {
 	var content string
 	if avatar != "" {
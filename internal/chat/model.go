@@ -4,12 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/notify"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -30,6 +32,9 @@ var (
 	reactionColor = lipgloss.Color("220")
 	textColor     = lipgloss.Color("255")
 	blurText      = lipgloss.Color("248")
+	brightColor   = lipgloss.Color("231")
+	panelColor    = lipgloss.Color("245")
+	selectedBg    = lipgloss.Color("236")
 )
 
 // Options configure chat.
@@ -42,6 +47,8 @@ type Options struct {
 	Last            int
 	ShowUpdates     bool
 	IncludeArchived bool
+	Theme           string // overrides the theme in ~/.config/fray/chat.json
+	RawMarkdown     bool   // show message bodies without markdown rendering
 }
 
 // Run starts the chat UI.
@@ -67,6 +74,10 @@ func Run(opts Options) error {
 
 	// Clean up TTY file on exit
 	_ = os.Remove("/tmp/fray-tty")
+	if model.splitActive() {
+		model.saveLayout() // remember what the focused pane was showing
+	}
+	model.persistDraft()
 	model.Close()
 	return err
 }
@@ -77,6 +88,7 @@ type Model struct {
 	projectName         string
 	projectRoot         string
 	projectDBPath       string
+	issueProviders      []core.IssueProvider // issue trackers for annotating #prefix-id refs
 	username            string
 	showUpdates         bool
 	includeArchived     bool
@@ -139,6 +151,33 @@ type Model struct {
 	sidebarPersistent    bool              // if true, Tab just changes focus (doesn't close)
 	pendingNicknameGUID  string            // thread GUID for pending /n command (set by Ctrl-N)
 	helpMessageID       string
+	searchActive        bool             // true while the input is a message search
+	searchResults       []searchResult   // matches for the current search input
+	searchIndex         int              // selected search result
+	searchSavedInput    string           // draft restored when search exits
+	searchHighlight     *regexp.Regexp   // term highlighted in message bodies
+	dashboardOpen       bool             // agents dashboard visible on the right
+	dashboardFocus      bool             // dashboard receives navigation/action keys
+	dashboardAgents     []dashboardAgent // rows shown in the dashboard
+	dashboardIndex      int              // selected dashboard row
+	daemonRunning       bool             // daemon lock held, as of last refresh
+	answerQueue         []types.Question    // questions being answered inline
+	answerIndex         int                 // current question in answerQueue
+	answerPairs         []db.QuestionAnswer // answers collected this session
+	answerSavedInput    string              // draft restored when answering ends
+	keys                map[keyAction]string // rebindable shortcuts
+	themeName           string               // --theme override
+	chatConfigModTime   time.Time            // chat.json mtime, for live reload
+	rawMarkdown         bool                 // --raw: show bodies unrendered
+	panes               []pane               // split panes (nil when not split)
+	paneIndex           int                  // focused pane; its state is in current*/viewport
+	paneSplit           paneSplit            // how panes are tiled
+	drafts              map[string]string    // unsent input per home ("room" or thread GUID)
+	draftHome           string               // home the input currently belongs to
+	notifier            *notify.Notifier     // notification rules from notify.json
+	notifyConfigModTime time.Time
+	questionNotifyTS    int64 // newest question already notified about
+	pendingNotify       []notify.Event // events awaiting delivery off the UI loop
 	initialScroll       bool
 	lastClickID         string
 	lastClickAt         time.Time
@@ -157,6 +196,12 @@ func NewModel(opts Options) (*Model, error) {
 	channels, channelIndex := loadChannels(opts.ProjectRoot)
 	threads, threadIndex := loadThreads(opts.DB, opts.Username)
 
+	configModTime := chatConfigModTime()
+	keys, err := loadChatSettings(opts.Theme)
+	if err != nil {
+		return nil, err
+	}
+
 	colorMap, err := buildColorMap(opts.DB, 50, opts.IncludeArchived)
 	if err != nil {
 		return nil, err
@@ -220,6 +265,7 @@ func NewModel(opts Options) (*Model, error) {
 		projectName:     opts.ProjectName,
 		projectRoot:     opts.ProjectRoot,
 		projectDBPath:   opts.ProjectDBPath,
+		issueProviders:  core.IssueProviders(opts.ProjectRoot),
 		username:        opts.Username,
 		showUpdates:     opts.ShowUpdates,
 		includeArchived: opts.IncludeArchived,
@@ -250,6 +296,10 @@ func NewModel(opts Options) (*Model, error) {
 		channels:        channels,
 		channelIndex:    channelIndex,
 		initialScroll:   true,
+		keys:              keys,
+		themeName:         opts.Theme,
+		chatConfigModTime: configModTime,
+		rawMarkdown:       opts.RawMarkdown,
 	}
 	model.refreshQuestionCounts()
 	model.refreshUnreadCounts()
@@ -259,6 +309,10 @@ func NewModel(opts Options) (*Model, error) {
 	model.refreshThreadNicknames()
 	model.refreshAvatars()
 	model.calculateThreadPanelWidth() // Calculate initial width since panel starts open
+	model.restoreLayout()
+	model.restoreDrafts()
+	model.questionNotifyTS = time.Now().Unix()
+	model.loadNotifier()
 	return model, nil
 }
 
@@ -273,6 +327,12 @@ func (m *Model) Close() {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	m.syncDraft()
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -280,6 +340,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.resize()
 		return m, nil
 	case tea.KeyMsg:
+		if handled, cmd := m.handleAnswerKeys(msg); handled {
+			return m, cmd
+		}
+		if handled, cmd := m.handleSearchKeys(msg); handled {
+			return m, cmd
+		}
+		if handled, cmd := m.handleDashboardKeys(msg); handled {
+			return m, cmd
+		}
 		if handled, cmd := m.handleSuggestionKeys(msg); handled {
 			return m, cmd
 		}
@@ -289,7 +358,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if handled, cmd := m.handleSidebarKeys(msg); handled {
 			return m, cmd
 		}
-		if msg.Type == tea.KeyRunes && !msg.Paste && msg.String() == "?" && m.input.Value() == "" {
+		if !msg.Paste && m.keyIs(msg, keyHelp) && m.input.Value() == "" {
 			m.showHelp()
 			return m, nil
 		}
@@ -298,10 +367,32 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
-		if msg.Type == tea.KeyCtrlJ {
+		if m.keyIs(msg, keyNewline) {
 			m.insertInputText("\n")
 			return m, nil
 		}
+		if m.keyIs(msg, keyChannels) {
+			m.openChannelPanel()
+			return m, nil
+		}
+		if m.keyIs(msg, keyThreads) {
+			if len(m.suggestions) > 0 {
+				return m, nil
+			}
+			m.openThreadPanel()
+			return m, nil
+		}
+		if m.keyIs(msg, keyNextPane) {
+			m.focusNextPane()
+			return m, nil
+		}
+		if m.keyIs(msg, keyCompose) {
+			return m, m.openComposer()
+		}
+		if m.keyIs(msg, keyPinSidebar) {
+			m.toggleSidebarPersistence()
+			return m, nil
+		}
 		if msg.Type == tea.KeyRunes && !msg.Paste && strings.ContainsRune(string(msg.Runes), '\n') {
 			m.insertInputText(normalizeNewlines(string(msg.Runes)))
 			return m, nil
@@ -337,21 +428,6 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 			return m, m.handleSubmit(value)
-		case tea.KeyShiftTab:
-			// Shift-Tab: open channels panel
-			m.openChannelPanel()
-			return m, nil
-		case tea.KeyTab:
-			// Tab: open threads panel
-			if len(m.suggestions) > 0 {
-				return m, nil
-			}
-			m.openThreadPanel()
-			return m, nil
-		case tea.KeyCtrlB:
-			// Ctrl-B: toggle sidebar persistence
-			m.toggleSidebarPersistence()
-			return m, nil
 		case tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd:
 			var cmd tea.Cmd
 			m.viewport, cmd = m.viewport.Update(msg)
@@ -420,8 +496,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			roomMsgIDs[rm.ID] = struct{}{}
 		}
 
+		newRoomMessages := 0
 		if len(msg.roomMessages) > 0 {
 			incoming := m.filterNewMessages(msg.roomMessages)
+			newRoomMessages = len(incoming)
 			last := msg.roomMessages[len(msg.roomMessages)-1]
 			m.lastCursor = &types.MessageCursor{GUID: last.ID, TS: last.TS}
 
@@ -498,8 +576,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.threads = msg.threads
 		}
 
+		m.refreshParkedPanes(newRoomMessages)
 		m.refreshQuestionCounts()
 		m.refreshUnreadCounts()
+		m.refreshDashboard()
+		m.reloadChatSettingsIfChanged()
+		m.reloadNotifierIfChanged()
+		if m.notifier != nil {
+			m.notifyQuestions(msg.assignedQuestions)
+		}
+		m.persistDraft()
 
 		if err := m.refreshReactions(); err != nil {
 			m.status = err.Error()
@@ -508,7 +594,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Check for navigation request from notification click
 		m.checkGotoFile()
 
-		return m, m.pollCmd()
+		return m, tea.Batch(m.pollCmd(), m.deliverNotificationsCmd())
+	case notifyResultMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		}
+		return m, nil
+	case composeDoneMsg:
+		m.handleComposeDone(msg)
+		return m, nil
 	case errMsg:
 		m.status = msg.err.Error()
 		return m, m.pollCmd()
@@ -523,10 +617,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) View() string {
 	statusLine := lipgloss.NewStyle().Foreground(statusColor).Render(m.statusLine())
 
-	lines := []string{m.viewport.View()}
+	lines := []string{m.renderPanes()}
 	if suggestions := m.renderSuggestions(); suggestions != "" {
 		lines = append(lines, suggestions)
 	}
+	if results := m.renderSearchResults(); results != "" {
+		lines = append(lines, results)
+	}
+	if question := m.renderAnswerPanel(); question != "" {
+		lines = append(lines, question)
+	}
 	lines = append(lines, "", m.renderInput(), statusLine)
 
 	main := lipgloss.JoinVertical(lipgloss.Left, lines...)
@@ -548,6 +648,11 @@ func (m *Model) View() string {
 		left := lipgloss.JoinHorizontal(lipgloss.Top, panels...)
 		output = lipgloss.JoinHorizontal(lipgloss.Top, left, main)
 	}
+	if m.dashboardOpen {
+		if dashboard := m.renderDashboard(); dashboard != "" {
+			output = lipgloss.JoinHorizontal(lipgloss.Top, output, dashboard)
+		}
+	}
 	return m.zoneManager.Scan(output)
 }
 
@@ -652,6 +757,7 @@ func (m *Model) handleSubmit(text string) tea.Cmd {
 		m.status = err.Error()
 		return nil
 	}
+	forwarded, forwardErr := db.ForwardChannelMentions(m.projectDBPath, created)
 
 	if m.currentThread != nil {
 		m.threadMessages = append(m.threadMessages, created)
@@ -665,6 +771,15 @@ func (m *Model) handleSubmit(text string) tea.Cmd {
 		m.messageCount++
 	}
 	m.status = ""
+	if forwardErr != nil {
+		m.status = forwardErr.Error()
+	} else if len(forwarded) > 0 {
+		channels := make([]string, 0, len(forwarded))
+		for _, fwd := range forwarded {
+			channels = append(channels, "#"+fwd.Channel)
+		}
+		m.status = "Forwarded to " + strings.Join(channels, ", ")
+	}
 	if m.currentThread != nil && replyMsg != nil && replyMsg.Home != m.currentThread.GUID {
 		if err := db.AddMessageToThread(m.db, m.currentThread.GUID, replyMsg.ID, m.username, time.Now().Unix()); err == nil {
 			_ = db.AppendThreadMessage(m.projectDBPath, db.ThreadMessageJSONLRecord{
@@ -702,6 +817,12 @@ func (m *Model) handleReaction(reaction, messageID string, match *ReplyMatch) te
 	eventLine := core.FormatReactionEvent([]string{m.username}, reaction, updated.ID, body)
 	m.messages = append(m.messages, newEventMessage(eventLine))
 	m.status = ""
+	effect, err := db.ApplyReactionMeaning(m.db, m.projectDBPath, *updated, m.username, reaction, reactedAt)
+	if err != nil {
+		m.status = err.Error()
+	} else if summary := effect.Summary(); summary != "" {
+		m.status = fmt.Sprintf("%s %s: %s", reaction, effect.Meaning, summary)
+	}
 	m.refreshViewport(true)
 
 	return nil
@@ -769,6 +890,22 @@ func (m *Model) handleMouseClick(msg tea.MouseMsg) (bool, tea.Cmd) {
 	m.threadPanelFocus = false
 	m.sidebarFocus = false
 
+	if m.splitActive() {
+		// Clicking another pane focuses it; clicks in the focused pane map to its viewport
+		index, y, ok := m.paneAt(msg.X-threadWidth-m.sidebarWidth(), msg.Y)
+		if !ok {
+			return false, nil
+		}
+		if index != m.paneIndex {
+			m.focusPane(index)
+			return true, nil
+		}
+		if y < 0 {
+			return true, nil
+		}
+		msg.Y = y
+	}
+
 	if msg.Y >= m.viewport.Height {
 		return false, nil
 	}
@@ -1134,10 +1271,14 @@ func isAlphaNum(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
-// maybeNotify sends an OS notification if the message warrants it.
-// Triggers on: direct @mention, reply to agent's message.
+// maybeNotify runs a message through the notification rules (by default:
+// direct @mention or reply to the user's message).
 // Suppressed if: from self, in muted thread, event/surface message, user is not human.
 func (m *Model) maybeNotify(msg types.Message) {
+	if m.notifier == nil {
+		return
+	}
+
 	// Only notify human users (not agents testing in chat)
 	users, _ := db.GetActiveUsers(m.db)
 	isHumanUser := false
@@ -1166,13 +1307,30 @@ func (m *Model) maybeNotify(msg types.Message) {
 		return
 	}
 
-	// Check if should notify: direct mention or reply to own message
-	shouldNotify := IsDirectMention(msg.Body, m.username) || IsReplyToAgent(m.db, msg, m.username)
-	if !shouldNotify {
-		return
+	kinds := []string{core.NotifyMessage}
+	if IsDirectMention(msg.Body, m.username) {
+		kinds = append(kinds, core.NotifyMention)
+	} else if HasMention(msg, m.username) {
+		kinds = append(kinds, core.NotifyFYI)
+	}
+	if IsReplyToAgent(m.db, msg, m.username) {
+		kinds = append(kinds, core.NotifyReply)
 	}
 
-	_ = SendNotification(msg, m.projectName)
+	event := notify.Event{
+		Kinds:     kinds,
+		Project:   m.projectName,
+		From:      msg.FromAgent,
+		MessageID: msg.ID,
+		Title:     m.notifyTitle("@" + msg.FromAgent),
+		Body:      truncateNotification(msg.Body, 200),
+		At:        time.Unix(msg.TS, 0),
+	}
+	if msg.Home != "" && msg.Home != "room" {
+		event.ThreadGUID = msg.Home
+		event.ThreadPath = m.notifyThreadPath(msg.Home)
+	}
+	m.pendingNotify = append(m.pendingNotify, event)
 }
 
 // checkGotoFile checks for a navigation request from notification click.
@@ -1213,6 +1371,16 @@ func (m *Model) checkGotoFile() {
 		}
 	}
 
-	// TODO: scroll to specific message if messageID is provided
-	_ = messageID
+	if messageID == "" {
+		return
+	}
+	msg, err := db.GetMessage(m.db, messageID)
+	if err != nil || msg == nil {
+		return
+	}
+	if threadGUID != "" && m.currentThread != nil {
+		m.revealMessage(*msg)
+		return
+	}
+	m.jumpToMessage(*msg)
 }
@@ -3,6 +3,7 @@ package chat
 import (
 	"database/sql"
 	_ "embed"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,10 +11,13 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/notify"
 	"github.com/adamavenir/fray/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gen2brain/beeep"
 )
 
@@ -111,8 +115,20 @@ func SendNotification(msg types.Message, projectName string) error {
 	if projectName != "" {
 		title = projectName + " · " + title
 	}
-	body := truncateNotification(msg.Body, 100)
+	return showNotification(title, truncateNotification(msg.Body, 100), notificationTarget(msg.Home, msg.ID))
+}
+
+// notificationTarget is what the focus script navigates to on click.
+func notificationTarget(home, messageID string) string {
+	if home != "" && home != "room" {
+		return home + "#" + messageID
+	}
+	return messageID
+}
 
+// showNotification shows a desktop notification; target, when set, is where a
+// click navigates.
+func showNotification(title, body, target string) error {
 	// On macOS with Fray-Notifier.app, use it directly for proper icon
 	if notifier := getNotifierPath(); notifier != "" {
 		args := []string{
@@ -121,12 +137,7 @@ func SendNotification(msg types.Message, projectName string) error {
 			"-group", "fray",
 		}
 		// Add click-to-focus if the focus script is available
-		if focusScript := getFocusScriptPath(); focusScript != "" {
-			// Pass thread/message info as argument to focus script
-			target := msg.ID
-			if msg.Home != "" {
-				target = msg.Home + "#" + msg.ID
-			}
+		if focusScript := getFocusScriptPath(); focusScript != "" && target != "" {
 			args = append(args, "-execute", focusScript+" "+target)
 		}
 		cmd := exec.Command(notifier, args...)
@@ -137,6 +148,139 @@ func SendNotification(msg types.Message, projectName string) error {
 	return beeep.Notify(title, body, "")
 }
 
+// osNotifySink delivers rule-matched notifications through the desktop
+// notifier, with click-to-focus for single-message notifications.
+var osNotifySink = notify.SinkFunc(func(n notify.Notification) error {
+	target := ""
+	if len(n.Events) == 1 && n.Events[0].MessageID != "" {
+		target = notificationTarget(n.Events[0].ThreadGUID, n.Events[0].MessageID)
+	}
+	return showNotification(n.Title, truncateNotification(n.Body, 200), target)
+})
+
+// loadNotifier (re)reads notify.json, keeping the current rules on error.
+func (m *Model) loadNotifier() {
+	path, err := core.NotifyConfigPath()
+	if err != nil {
+		return
+	}
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	m.notifyConfigModTime = modTime
+	notifier, err := notify.Load(notify.Options{OS: osNotifySink})
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.notifier = notifier
+}
+
+// reloadNotifierIfChanged picks up edits to notify.json.
+func (m *Model) reloadNotifierIfChanged() {
+	path, err := core.NotifyConfigPath()
+	if err != nil {
+		return
+	}
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	if !modTime.Equal(m.notifyConfigModTime) {
+		m.loadNotifier()
+	}
+}
+
+// notifyQuestions notifies about questions newly asked of the user.
+func (m *Model) notifyQuestions(questions []types.Question) {
+	latest := m.questionNotifyTS
+	for _, question := range questions {
+		if question.CreatedAt <= m.questionNotifyTS || question.FromAgent == m.username {
+			continue
+		}
+		latest = max(latest, question.CreatedAt)
+		event := notify.Event{
+			Kinds:   []string{core.NotifyQuestion},
+			Project: m.projectName,
+			From:    question.FromAgent,
+			Title:   m.notifyTitle("@" + question.FromAgent + " asks"),
+			Body:    truncateNotification(question.Re, 200),
+			At:      time.Unix(question.CreatedAt, 0),
+		}
+		if question.ThreadGUID != nil {
+			event.ThreadGUID = *question.ThreadGUID
+			event.ThreadPath = m.notifyThreadPath(*question.ThreadGUID)
+		}
+		if question.AskedIn != nil {
+			event.MessageID = *question.AskedIn
+		}
+		m.pendingNotify = append(m.pendingNotify, event)
+	}
+	m.questionNotifyTS = latest
+}
+
+// notifyResultMsg reports the outcome of a background notification delivery.
+type notifyResultMsg struct {
+	err error
+}
+
+// notifyDelivery serializes deliveries so digest flushes never overlap.
+var notifyDelivery sync.Mutex
+
+// deliverNotificationsCmd hands pending events and the digest flush to a
+// tea.Cmd, since sinks (commands, webhooks) can block for seconds and must not
+// stall the UI loop. A flush-only delivery is skipped while another is running.
+func (m *Model) deliverNotificationsCmd() tea.Cmd {
+	if m.notifier == nil {
+		m.pendingNotify = nil
+		return nil
+	}
+	notifier := m.notifier
+	events := m.pendingNotify
+	m.pendingNotify = nil
+	return func() tea.Msg {
+		if len(events) == 0 {
+			if !notifyDelivery.TryLock() {
+				return nil
+			}
+		} else {
+			notifyDelivery.Lock()
+		}
+		defer notifyDelivery.Unlock()
+
+		var errs []error
+		for _, event := range events {
+			if err := notifier.Notify(event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := notifier.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+		return notifyResultMsg{err: errors.Join(errs...)}
+	}
+}
+
+func (m *Model) notifyTitle(title string) string {
+	if m.projectName != "" {
+		return m.projectName + " · " + title
+	}
+	return title
+}
+
+func (m *Model) notifyThreadPath(guid string) string {
+	thread, err := db.GetThread(m.db, guid)
+	if err != nil || thread == nil {
+		return ""
+	}
+	path, err := threadPath(m.db, thread)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
 func truncateNotification(s string, maxLen int) string {
 	// Collapse whitespace for notification
 	s = strings.Join(strings.Fields(s), " ")
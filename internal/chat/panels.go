@@ -68,10 +68,10 @@ func (m *Model) renderSidebar() string {
 	}
 
 	// White color scheme for channels
-	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Bold(true)
-	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")) // dim white
-	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Bold(true)
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Background(lipgloss.Color("236")).Bold(true)
+	headerStyle := lipgloss.NewStyle().Foreground(brightColor).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(panelColor) // dim white
+	activeStyle := lipgloss.NewStyle().Foreground(brightColor).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(brightColor).Background(selectedBg).Bold(true)
 
 	header := " Channels "
 	if m.sidebarFilterActive {
@@ -847,9 +847,9 @@ func (m *Model) renderThreadPanel() string {
 	depthColor := m.depthColor()
 	headerStyle := lipgloss.NewStyle().Foreground(depthColor).Bold(true)
 	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("67"))                  // dim blue
-	mainStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Bold(true)      // bright white bold for main
+	mainStyle := lipgloss.NewStyle().Foreground(brightColor).Bold(true)      // bright white bold for main
 	activeStyle := lipgloss.NewStyle().Foreground(depthColor).Bold(true)
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Background(lipgloss.Color("24")).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(brightColor).Background(lipgloss.Color("24")).Bold(true)
 	collapsedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))            // dim grey for non-subscribed
 
 	// Only show header when filtering or drilled in
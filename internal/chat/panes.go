@@ -0,0 +1,408 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type paneSplit string
+
+const (
+	splitHorizontal paneSplit = "horizontal" // panes stacked top to bottom
+	splitVertical   paneSplit = "vertical"   // panes side by side
+)
+
+const maxPanes = 4
+
+// pane is a message view bound to the room, a thread, or a pseudo-thread.
+// The focused pane lives in the Model's current* fields and viewport; the
+// others are parked here with their own scrollback and unread count.
+type pane struct {
+	thread          *types.Thread
+	pseudo          pseudoThreadKind
+	threadMessages  []types.Message
+	pseudoQuestions []types.Question
+	viewport        viewport.Model
+	unread          int
+}
+
+func (m *Model) splitActive() bool {
+	return len(m.panes) > 1
+}
+
+func (m *Model) parkPane() pane {
+	return pane{
+		thread:          m.currentThread,
+		pseudo:          m.currentPseudo,
+		threadMessages:  m.threadMessages,
+		pseudoQuestions: m.pseudoQuestions,
+		viewport:        m.viewport,
+	}
+}
+
+func (m *Model) loadPane(p pane) {
+	m.currentThread = p.thread
+	m.currentPseudo = p.pseudo
+	m.threadMessages = p.threadMessages
+	m.pseudoQuestions = p.pseudoQuestions
+	m.viewport = p.viewport
+}
+
+// withPane runs fn with pane i loaded as the current view, saving any changes
+// back to the pane.
+func (m *Model) withPane(i int, fn func()) {
+	if i == m.paneIndex {
+		fn()
+		return
+	}
+	focused := m.parkPane()
+	m.loadPane(m.panes[i])
+	fn()
+	unread := m.panes[i].unread
+	m.panes[i] = m.parkPane()
+	m.panes[i].unread = unread
+	m.loadPane(focused)
+}
+
+// loadPaneContent fetches messages for the current view's binding.
+func (m *Model) loadPaneContent() {
+	m.refreshThreadMessages()
+	if m.currentPseudo != "" {
+		m.refreshPseudoQuestions()
+	}
+}
+
+// splitPane opens a new pane bound to thread/pseudo (nil/"" = room) and focuses it.
+func (m *Model) splitPane(split paneSplit, thread *types.Thread, pseudo pseudoThreadKind) error {
+	if len(m.panes) >= maxPanes {
+		return fmt.Errorf("at most %d panes", maxPanes)
+	}
+	if len(m.panes) == 0 {
+		m.panes = []pane{{}}
+		m.paneIndex = 0
+	}
+	m.panes[m.paneIndex] = m.parkPane()
+	m.panes = append(m.panes, pane{})
+	m.paneIndex = len(m.panes) - 1
+	m.paneSplit = split
+	m.loadPane(pane{thread: thread, pseudo: pseudo, viewport: viewport.New(0, 0)})
+	m.loadPaneContent()
+	m.resize()
+	m.refreshViewport(true)
+	m.saveLayout()
+	return nil
+}
+
+// closePane closes the focused pane, focusing its neighbour.
+func (m *Model) closePane() error {
+	if !m.splitActive() {
+		return fmt.Errorf("no split to close")
+	}
+	m.panes = append(m.panes[:m.paneIndex], m.panes[m.paneIndex+1:]...)
+	if m.paneIndex >= len(m.panes) {
+		m.paneIndex = len(m.panes) - 1
+	}
+	m.loadPane(m.panes[m.paneIndex])
+	if len(m.panes) == 1 {
+		m.panes = nil
+		m.paneIndex = 0
+	}
+	m.resize()
+	m.saveLayout()
+	return nil
+}
+
+func (m *Model) focusPane(i int) {
+	if !m.splitActive() || i == m.paneIndex || i < 0 || i >= len(m.panes) {
+		return
+	}
+	m.panes[m.paneIndex] = m.parkPane()
+	m.paneIndex = i
+	m.loadPane(m.panes[i])
+	m.panes[i].unread = 0
+	m.clearSuggestions()
+	m.resize()
+	m.saveLayout()
+}
+
+func (m *Model) focusNextPane() {
+	if m.splitActive() {
+		m.focusPane((m.paneIndex + 1) % len(m.panes))
+	}
+}
+
+// refreshParkedPanes reloads unfocused panes after a poll, counting new room
+// or thread messages as unread.
+func (m *Model) refreshParkedPanes(newRoomMessages int) {
+	for i := range m.panes {
+		if i == m.paneIndex {
+			continue
+		}
+		added := 0
+		m.withPane(i, func() {
+			switch {
+			case m.currentPseudo != "":
+				m.refreshPseudoQuestions()
+			case m.currentThread != nil:
+				before := len(m.threadMessages)
+				m.refreshThreadMessages()
+				added = max(len(m.threadMessages)-before, 0)
+			default:
+				added = newRoomMessages
+			}
+			m.refreshViewport(m.viewport.AtBottom())
+		})
+		m.panes[i].unread += added
+	}
+}
+
+// messageWidth is the width messages wrap to: a column of a vertical split,
+// otherwise the main area.
+func (m *Model) messageWidth() int {
+	width := m.mainWidth()
+	if !m.splitActive() || m.paneSplit != splitVertical || width == 0 {
+		return width
+	}
+	count := len(m.panes)
+	return max((width-(count-1))/count, 1)
+}
+
+// paneHeights splits the message area height between panes, less one header
+// line each.
+func (m *Model) paneHeights(total int) []int {
+	count := len(m.panes)
+	heights := make([]int, count)
+	if m.paneSplit == splitVertical {
+		for i := range heights {
+			heights[i] = max(total-1, 1)
+		}
+		return heights
+	}
+	available := total - count
+	for i := range heights {
+		heights[i] = max(available/count, 1)
+	}
+	heights[count-1] = max(available-(count-1)*(available/count), 1)
+	return heights
+}
+
+// layoutPanes sizes every pane from the message area the focused viewport was
+// given by resize, and re-renders the parked ones.
+func (m *Model) layoutPanes() {
+	if !m.splitActive() {
+		return
+	}
+	heights := m.paneHeights(m.viewport.Height)
+	width := m.messageWidth()
+	for i := range m.panes {
+		if i == m.paneIndex {
+			continue
+		}
+		m.panes[i].viewport.Width = width
+		m.panes[i].viewport.Height = heights[i]
+		m.withPane(i, func() {
+			m.refreshViewport(m.viewport.AtBottom())
+		})
+	}
+	m.viewport.Width = width
+	m.viewport.Height = heights[m.paneIndex]
+}
+
+// paneAt maps a point in the message area to a pane and a line within its
+// viewport (-1 on the header).
+func (m *Model) paneAt(x, y int) (int, int, bool) {
+	heights := m.paneHeights(m.viewport.Height)
+	if m.paneSplit == splitVertical {
+		if y > heights[0] {
+			return 0, 0, false
+		}
+		index := min(x/(m.messageWidth()+1), len(m.panes)-1)
+		return index, y - 1, true
+	}
+	top := 0
+	for i, height := range heights {
+		if y < top+1+height {
+			return i, y - top - 1, true
+		}
+		top += 1 + height
+	}
+	return 0, 0, false
+}
+
+func (m *Model) paneLabel(p pane) string {
+	label := "#main"
+	if p.thread != nil {
+		label = p.thread.GUID
+		if path, err := threadPath(m.db, p.thread); err == nil && path != "" {
+			label = path
+		}
+	}
+	if p.pseudo != "" {
+		if p.thread != nil {
+			label += " ❯ " + string(p.pseudo)
+		} else {
+			label = string(p.pseudo)
+		}
+	}
+	return label
+}
+
+// renderPanes renders the message area: the viewport alone, or each pane under
+// a header showing its binding and unread count.
+func (m *Model) renderPanes() string {
+	if !m.splitActive() {
+		return m.viewport.View()
+	}
+	width := m.messageWidth()
+	blocks := make([]string, len(m.panes))
+	for i := range m.panes {
+		p := m.panes[i]
+		view := p.viewport
+		if i == m.paneIndex {
+			p = m.parkPane()
+			view = m.viewport
+		}
+		label := " " + m.paneLabel(p)
+		if p.unread > 0 && i != m.paneIndex {
+			label += fmt.Sprintf(" · %d new", p.unread)
+		}
+		style := lipgloss.NewStyle().Foreground(metaColor)
+		if i == m.paneIndex {
+			style = lipgloss.NewStyle().Foreground(brightColor).Background(selectedBg).Bold(true)
+		}
+		header := style.Width(width).Render(truncateLine(label, width))
+		blocks[i] = lipgloss.JoinVertical(lipgloss.Left, header, view.View())
+	}
+	if m.paneSplit == splitHorizontal {
+		return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+	}
+	height := lipgloss.Height(blocks[0])
+	divider := lipgloss.NewStyle().Foreground(metaColor).Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+	joined := make([]string, 0, len(blocks)*2-1)
+	for i, block := range blocks {
+		if i > 0 {
+			joined = append(joined, divider)
+		}
+		joined = append(joined, block)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, joined...)
+}
+
+// resolvePaneTarget resolves a /split target: empty for the current view,
+// main/room, a question view (open-qs, ...), or a thread reference.
+func (m *Model) resolvePaneTarget(args []string) (*types.Thread, pseudoThreadKind, error) {
+	if len(args) == 0 {
+		return m.currentThread, m.currentPseudo, nil
+	}
+	ref := strings.TrimSpace(strings.Join(args, " "))
+	switch strings.ToLower(ref) {
+	case "main", "#main", "room":
+		return nil, "", nil
+	}
+	if kind, ok := parsePseudoKind(ref); ok {
+		return nil, kind, nil
+	}
+	thread, err := m.resolveThreadRef(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	return thread, "", nil
+}
+
+func parsePseudoKind(name string) (pseudoThreadKind, bool) {
+	switch kind := pseudoThreadKind(strings.ToLower(name)); kind {
+	case pseudoThreadOpen, pseudoThreadClosed, pseudoThreadWonder, pseudoThreadStale:
+		return kind, true
+	}
+	return "", false
+}
+
+func (m *Model) runSplitCommand(split paneSplit, args []string) error {
+	thread, pseudo, err := m.resolvePaneTarget(args)
+	if err != nil {
+		return err
+	}
+	return m.splitPane(split, thread, pseudo)
+}
+
+// saveLayout persists the split layout for this user and project (clearing it
+// when there is no split).
+func (m *Model) saveLayout() {
+	if m.username == "" || m.projectRoot == "" {
+		return
+	}
+	var layout *core.ChatLayout
+	if m.splitActive() {
+		layout = &core.ChatLayout{Split: string(m.paneSplit), Focus: m.paneIndex}
+		for i, p := range m.panes {
+			if i == m.paneIndex {
+				p = m.parkPane()
+			}
+			entry := core.ChatLayoutPane{Pseudo: string(p.pseudo)}
+			if p.thread != nil {
+				entry.Thread = p.thread.GUID
+			}
+			layout.Panes = append(layout.Panes, entry)
+		}
+	}
+	if err := core.WriteChatLayout(m.projectRoot, m.username, layout); err != nil {
+		m.status = err.Error()
+	}
+}
+
+// restoreLayout reopens the panes saved by saveLayout, skipping threads that
+// no longer exist.
+func (m *Model) restoreLayout() {
+	if m.username == "" || m.projectRoot == "" {
+		return
+	}
+	layout, err := core.ReadChatLayout(m.projectRoot, m.username)
+	if err != nil || layout == nil {
+		return
+	}
+	panes := make([]pane, 0, len(layout.Panes))
+	focus := 0
+	for i, entry := range layout.Panes {
+		if len(panes) == maxPanes {
+			break
+		}
+		p := pane{viewport: viewport.New(0, 0)}
+		if kind, ok := parsePseudoKind(entry.Pseudo); ok {
+			p.pseudo = kind
+		}
+		if entry.Thread != "" {
+			thread, err := db.GetThread(m.db, entry.Thread)
+			if err != nil || thread == nil {
+				continue
+			}
+			p.thread = thread
+		}
+		if i == layout.Focus {
+			focus = len(panes)
+		}
+		panes = append(panes, p)
+	}
+	if len(panes) < 2 {
+		return
+	}
+
+	m.panes = panes
+	m.paneIndex = focus
+	m.paneSplit = splitHorizontal
+	if paneSplit(layout.Split) == splitVertical {
+		m.paneSplit = splitVertical
+	}
+	for i := range m.panes {
+		if i != focus {
+			m.withPane(i, m.loadPaneContent)
+		}
+	}
+	m.loadPane(m.panes[focus])
+	m.loadPaneContent()
+}
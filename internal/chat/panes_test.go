@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaneHeights(t *testing.T) {
+	m := &Model{panes: make([]pane, 3), paneSplit: splitHorizontal}
+	if got := m.paneHeights(20); !reflect.DeepEqual(got, []int{5, 5, 7}) {
+		t.Fatalf("stacked heights: got %v", got)
+	}
+	m.paneSplit = splitVertical
+	if got := m.paneHeights(20); !reflect.DeepEqual(got, []int{19, 19, 19}) {
+		t.Fatalf("side-by-side heights: got %v", got)
+	}
+}
+
+func TestPaneAt(t *testing.T) {
+	m := &Model{panes: make([]pane, 2), paneSplit: splitHorizontal}
+	m.viewport.Height = 10 // panes of 4 lines under a header each
+
+	for _, tt := range []struct{ y, index, line int }{
+		{0, 0, -1},
+		{1, 0, 0},
+		{4, 0, 3},
+		{5, 1, -1},
+		{9, 1, 3},
+	} {
+		index, line, ok := m.paneAt(0, tt.y)
+		if !ok || index != tt.index || line != tt.line {
+			t.Fatalf("y=%d: got pane %d line %d ok=%v", tt.y, index, line, ok)
+		}
+	}
+	if _, _, ok := m.paneAt(0, 10); ok {
+		t.Fatalf("expected y past the panes to miss")
+	}
+
+	m.paneSplit = splitVertical
+	m.width = 41 // two 20-column panes and a divider
+	if index, line, _ := m.paneAt(25, 3); index != 1 || line != 2 {
+		t.Fatalf("side-by-side: got pane %d line %d", index, line)
+	}
+}
+
+func TestParsePseudoKind(t *testing.T) {
+	if kind, ok := parsePseudoKind("Open-QS"); !ok || kind != pseudoThreadOpen {
+		t.Fatalf("expected open-qs, got %q %v", kind, ok)
+	}
+	if _, ok := parsePseudoKind("design"); ok {
+		t.Fatalf("expected thread names not to parse as question views")
+	}
+}
@@ -0,0 +1,406 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	searchResultLimit     = 50
+	searchContextMessages = 3 // messages loaded above a jump target for context
+	searchSnippetLead     = 24
+)
+
+// searchResult is a message matching the current search, with its location label.
+type searchResult struct {
+	Message  types.Message
+	Location string
+}
+
+// enterSearch switches the input into search mode, saving any draft.
+func (m *Model) enterSearch(term string) {
+	if !m.searchActive {
+		m.searchSavedInput = m.input.Value()
+	}
+	m.searchActive = true
+	m.clearSuggestions()
+	m.input.SetValue(term)
+	m.input.CursorEnd()
+	m.runSearch(term)
+	m.resize()
+}
+
+// exitSearch leaves search mode and restores the saved draft.
+func (m *Model) exitSearch() {
+	m.searchActive = false
+	m.searchResults = nil
+	m.searchIndex = 0
+	m.input.SetValue(m.searchSavedInput)
+	m.input.CursorEnd()
+	m.searchSavedInput = ""
+	m.lastInputValue = m.input.Value()
+	m.lastInputPos = m.inputCursorPos()
+	m.updateInputStyle()
+	m.resize()
+}
+
+// setSearchHighlight sets the term highlighted in rendered message bodies.
+func (m *Model) setSearchHighlight(term string) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		m.searchHighlight = nil
+		return
+	}
+	m.searchHighlight = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+}
+
+func (m *Model) handleSearchKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if !m.searchActive {
+		if m.keyIs(msg, keySearch) && !m.threadPanelOpen {
+			m.enterSearch("")
+			return true, nil
+		}
+		switch msg.Type {
+		case tea.KeyEsc:
+			if m.searchHighlight != nil && len(m.suggestions) == 0 {
+				m.searchHighlight = nil
+				m.refreshViewport(false)
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if m.keyIs(msg, keySearch) {
+		m.exitSearch()
+		m.searchHighlight = nil
+		m.refreshViewport(false)
+		return true, nil
+	}
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.exitSearch()
+		m.searchHighlight = nil
+		m.refreshViewport(false)
+		return true, nil
+	case tea.KeyUp:
+		if len(m.searchResults) > 0 {
+			m.searchIndex--
+			if m.searchIndex < 0 {
+				m.searchIndex = len(m.searchResults) - 1
+			}
+		}
+		return true, nil
+	case tea.KeyDown:
+		if len(m.searchResults) > 0 {
+			m.searchIndex++
+			if m.searchIndex >= len(m.searchResults) {
+				m.searchIndex = 0
+			}
+		}
+		return true, nil
+	case tea.KeyEnter:
+		if len(m.searchResults) == 0 {
+			m.status = "No matches"
+			return true, nil
+		}
+		target := m.searchResults[m.searchIndex].Message
+		term := strings.TrimSpace(m.input.Value())
+		m.exitSearch()
+		m.setSearchHighlight(term)
+		m.jumpToMessage(target)
+		return true, nil
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyCtrlJ:
+		return true, nil
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if value := m.input.Value(); value != before {
+		m.runSearch(value)
+		m.resize()
+	}
+	return true, cmd
+}
+
+// runSearch queries message bodies across the room, threads, and archive.
+func (m *Model) runSearch(term string) {
+	m.searchIndex = 0
+	m.searchResults = nil
+	m.setSearchHighlight(term)
+	if m.searchHighlight == nil {
+		return
+	}
+	messages, err := db.SearchMessages(m.db, term, searchResultLimit)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	locations := map[string]string{}
+	for _, msg := range messages {
+		home := msg.Home
+		if home == "" {
+			home = "room"
+		}
+		location, ok := locations[home]
+		if !ok {
+			location = m.searchLocation(home)
+			locations[home] = location
+		}
+		m.searchResults = append(m.searchResults, searchResult{Message: msg, Location: location})
+	}
+}
+
+func (m *Model) searchLocation(home string) string {
+	if home == "room" {
+		return "room"
+	}
+	thread, err := db.GetThread(m.db, home)
+	if err != nil || thread == nil {
+		return home
+	}
+	path, err := threadPath(m.db, thread)
+	if err != nil || path == "" {
+		return thread.Name
+	}
+	return path
+}
+
+func (m *Model) searchResultsHeight() int {
+	if !m.searchActive {
+		return 0
+	}
+	return lipgloss.Height(m.renderSearchResults())
+}
+
+// renderSearchResults shows a window of results around the selection, each
+// with a snippet of the body around the first match.
+func (m *Model) renderSearchResults() string {
+	if !m.searchActive {
+		return ""
+	}
+	normalStyle := lipgloss.NewStyle().Foreground(metaColor)
+	selectedStyle := lipgloss.NewStyle().Foreground(userColor).Bold(true)
+	width := m.mainWidth()
+
+	term := strings.TrimSpace(m.input.Value())
+	var header string
+	switch {
+	case term == "":
+		header = "Search messages (↑/↓ select · enter jump · esc cancel)"
+	case len(m.searchResults) == 0:
+		header = fmt.Sprintf("No matches for %q", term)
+	case len(m.searchResults) >= searchResultLimit:
+		header = fmt.Sprintf("%d+ matches for %q", searchResultLimit, term)
+	default:
+		header = fmt.Sprintf("%d matches for %q", len(m.searchResults), term)
+	}
+	lines := []string{normalStyle.Render(truncateLine(header, width))}
+
+	start := 0
+	if m.searchIndex >= suggestionLimit {
+		start = m.searchIndex - suggestionLimit + 1
+	}
+	end := start + suggestionLimit
+	if end > len(m.searchResults) {
+		end = len(m.searchResults)
+	}
+	prefixLength := core.GetDisplayPrefixLength(m.messageCount)
+	for i := start; i < end; i++ {
+		result := m.searchResults[i]
+		marker := "  "
+		style := normalStyle
+		if i == m.searchIndex {
+			marker = "> "
+			style = selectedStyle
+		}
+		label := fmt.Sprintf("%s#%s @%s (%s) ", marker, core.GetGUIDPrefix(result.Message.ID, prefixLength), result.Message.FromAgent, result.Location)
+		if result.Message.ArchivedAt != nil {
+			label += "[archived] "
+		}
+		available := 0
+		if width > 0 {
+			label = truncateLine(label, width)
+			available = width - lipgloss.Width(label)
+			if available < 1 {
+				lines = append(lines, style.Render(label))
+				continue
+			}
+		}
+		snippet := searchSnippet(result.Message.Body, m.searchHighlight, available)
+		lines = append(lines, style.Render(label)+m.highlightSearchMatches(snippet, style))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// searchSnippet returns the body on one line, starting a little before the
+// first match and truncated to maxLen runes (0 = no limit).
+func searchSnippet(body string, pattern *regexp.Regexp, maxLen int) string {
+	compact := strings.Join(strings.Fields(body), " ")
+	if pattern != nil {
+		if loc := pattern.FindStringIndex(compact); loc != nil {
+			runeStart := utf8.RuneCountInString(compact[:loc[0]])
+			if runeStart > searchSnippetLead {
+				runes := []rune(compact)
+				compact = "…" + string(runes[runeStart-searchSnippetLead:])
+			}
+		}
+	}
+	return truncateLine(compact, maxLen)
+}
+
+// highlightSearchMatches renders text with base, marking matches of the active
+// search term. Lines that already carry ANSI styling are left untouched.
+func (m *Model) highlightSearchMatches(text string, base lipgloss.Style) string {
+	if m.searchHighlight == nil || strings.Contains(text, "\x1b[") {
+		return base.Render(text)
+	}
+	matches := m.searchHighlight.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return base.Render(text)
+	}
+	matchStyle := lipgloss.NewStyle().Background(reactionColor).Foreground(contrastTextColor(reactionColor))
+	var result strings.Builder
+	cursor := 0
+	for _, match := range matches {
+		if match[0] == match[1] {
+			continue
+		}
+		if match[0] > cursor {
+			result.WriteString(base.Render(text[cursor:match[0]]))
+		}
+		result.WriteString(matchStyle.Render(text[match[0]:match[1]]))
+		cursor = match[1]
+	}
+	if cursor < len(text) {
+		result.WriteString(base.Render(text[cursor:]))
+	}
+	return result.String()
+}
+
+// jumpToMessage switches to the view containing target and scrolls to it.
+func (m *Model) jumpToMessage(target types.Message) {
+	home := target.Home
+	if home == "" || home == "room" {
+		m.currentThread = nil
+		m.currentPseudo = ""
+		m.threadMessages = nil
+		m.markRoomAsRead()
+	} else {
+		thread, err := db.GetThread(m.db, home)
+		if err != nil || thread == nil {
+			m.status = fmt.Sprintf("thread not found: %s", home)
+			return
+		}
+		m.currentThread = thread
+		m.currentPseudo = ""
+		m.visitedThreads[thread.GUID] = *thread
+		m.addRecentThread(*thread)
+		m.markThreadAsRead(thread.GUID)
+		m.refreshThreadMessages()
+	}
+	m.revealMessage(target)
+}
+
+// revealMessage makes sure target is loaded in the current view, paging in
+// older room history when needed, then scrolls it to the top of the viewport.
+func (m *Model) revealMessage(target types.Message) {
+	if m.currentThread != nil {
+		if indexOfMessage(m.threadMessages, target.ID) < 0 {
+			m.threadMessages = insertMessageByTime(m.threadMessages, target)
+		}
+	} else if indexOfMessage(m.messages, target.ID) < 0 {
+		if err := m.loadRoomThrough(target); err != nil {
+			m.status = err.Error()
+			return
+		}
+	}
+
+	m.refreshViewport(false)
+	offset, ok := m.messageLineOffset(target.ID)
+	if !ok {
+		m.status = fmt.Sprintf("#%s is hidden in this view", target.ID)
+		return
+	}
+	m.viewport.SetYOffset(offset)
+	m.status = fmt.Sprintf("Jumped to #%s", core.GetGUIDPrefix(target.ID, core.GetDisplayPrefixLength(m.messageCount)))
+}
+
+// loadRoomThrough pages room history back to target (plus a little context)
+// and inserts target directly when it is filtered out of the loaded page,
+// e.g. because it is archived.
+func (m *Model) loadRoomThrough(target types.Message) error {
+	olderThanLoaded := m.oldestCursor != nil &&
+		(target.TS < m.oldestCursor.TS || (target.TS == m.oldestCursor.TS && target.ID < m.oldestCursor.GUID))
+	if !olderThanLoaded {
+		m.messages = insertMessageByTime(m.messages, target)
+		return nil
+	}
+
+	older, err := db.GetMessages(m.db, &types.MessageQueryOptions{
+		Before:          m.oldestCursor,
+		IncludeArchived: m.includeArchived,
+	})
+	if err != nil {
+		return err
+	}
+	idx := indexOfMessage(older, target.ID)
+	if idx < 0 {
+		older = insertMessageByTime(older, target)
+		idx = indexOfMessage(older, target.ID)
+	}
+	start := idx - searchContextMessages
+	if start < 0 {
+		start = 0
+	}
+	older = older[start:]
+	first := older[0]
+	m.oldestCursor = &types.MessageCursor{GUID: first.ID, TS: first.TS}
+	m.hasMore = start > 0
+	m.messages = append(filterUpdates(older, m.showUpdates), m.messages...)
+	return nil
+}
+
+// messageLineOffset returns the first rendered line of a message in the
+// viewport content, mirroring the layout used by messageAtLine.
+func (m *Model) messageLineOffset(messageID string) (int, bool) {
+	prefixLength := core.GetDisplayPrefixLength(m.messageCount)
+	cursor := 0
+	emptyReadTo := map[string][]string{}
+	for _, msg := range m.currentMessages() {
+		if msg.ID == messageID {
+			return cursor, true
+		}
+		cursor += lipgloss.Height(m.formatMessage(msg, prefixLength, emptyReadTo)) + 1
+	}
+	return 0, false
+}
+
+func indexOfMessage(messages []types.Message, id string) int {
+	for i, msg := range messages {
+		if msg.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func insertMessageByTime(messages []types.Message, msg types.Message) []types.Message {
+	idx := sort.Search(len(messages), func(i int) bool {
+		return messages[i].TS > msg.TS || (messages[i].TS == msg.TS && messages[i].ID > msg.ID)
+	})
+	messages = append(messages, types.Message{})
+	copy(messages[idx+1:], messages[idx:])
+	messages[idx] = msg
+	return messages
+}
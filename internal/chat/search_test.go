@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+func TestSearchSnippet(t *testing.T) {
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta("needle"))
+	tests := []struct {
+		name   string
+		body   string
+		maxLen int
+		want   string
+	}{
+		{
+			name: "match near start",
+			body: "a needle here",
+			want: "a needle here",
+		},
+		{
+			name: "collapses whitespace",
+			body: "line one\n\nline   two NEEDLE",
+			want: "line one line two NEEDLE",
+		},
+		{
+			name: "leads into a late match",
+			body: "0123456789012345678901234567890123456789 needle end",
+			want: "…78901234567890123456789 needle end",
+		},
+		{
+			name:   "truncates",
+			body:   "needle in a long haystack",
+			maxLen: 10,
+			want:   "needle in…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchSnippet(tt.body, pattern, tt.maxLen); got != tt.want {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertMessageByTime(t *testing.T) {
+	messages := []types.Message{
+		{ID: "msg-a", TS: 10},
+		{ID: "msg-c", TS: 30},
+	}
+	messages = insertMessageByTime(messages, types.Message{ID: "msg-b", TS: 20})
+	messages = insertMessageByTime(messages, types.Message{ID: "msg-0", TS: 1})
+
+	want := []string{"msg-0", "msg-a", "msg-b", "msg-c"}
+	for i, id := range want {
+		if messages[i].ID != id {
+			t.Fatalf("position %d: got %s want %s", i, messages[i].ID, id)
+		}
+	}
+	if indexOfMessage(messages, "msg-b") != 2 {
+		t.Fatalf("expected msg-b at index 2")
+	}
+}
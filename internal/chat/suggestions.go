@@ -34,6 +34,14 @@ var allCommands = []commandDef{
 	{Name: "/quit", Desc: "Exit chat"},
 	{Name: "/exit", Desc: "Exit chat"},
 	{Name: "/help", Desc: "Show help"},
+	{Name: "/search", Desc: "Search messages"},
+	{Name: "/agents", Desc: "Toggle agents dashboard"},
+	{Name: "/answer", Desc: "Answer questions"},
+	{Name: "/split", Desc: "Open stacked pane"},
+	{Name: "/vsplit", Desc: "Open side-by-side pane"},
+	{Name: "/close", Desc: "Close focused pane"},
+	{Name: "/compose", Desc: "Write message in $EDITOR"},
+	{Name: "/later", Desc: "Schedule a message"},
 	{Name: "/fave", Desc: "Fave current thread"},
 	{Name: "/unfave", Desc: "Unfave current thread"},
 	{Name: "/follow", Desc: "Follow current thread"},
@@ -0,0 +1,259 @@
+package chat
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme holds the colors chat renders with.
+type theme struct {
+	User         lipgloss.Color
+	Status       lipgloss.Color
+	Meta         lipgloss.Color
+	InputBg      lipgloss.Color
+	Caret        lipgloss.Color
+	Reaction     lipgloss.Color
+	Text         lipgloss.Color
+	Blur         lipgloss.Color
+	Bright       lipgloss.Color // panel headers and active entries
+	Panel        lipgloss.Color // panel items
+	SelectedBg   lipgloss.Color // selected panel entry background
+	AgentPalette []lipgloss.Color
+	ChromaStyle  string
+}
+
+var builtinThemes = map[string]theme{
+	"dark": {
+		User: "249", Status: "241", Meta: "242", InputBg: "236", Caret: "243",
+		Reaction: "220", Text: "255", Blur: "248",
+		Bright: "231", Panel: "245", SelectedBg: "236",
+		AgentPalette: []lipgloss.Color{"111", "157", "216", "36", "183", "230"},
+		ChromaStyle:  "dracula",
+	},
+	"light": {
+		User: "238", Status: "244", Meta: "243", InputBg: "254", Caret: "240",
+		Reaction: "130", Text: "232", Blur: "240",
+		Bright: "16", Panel: "240", SelectedBg: "252",
+		AgentPalette: []lipgloss.Color{"25", "28", "166", "30", "91", "94"},
+		ChromaStyle:  "github",
+	},
+	"high-contrast": {
+		User: "231", Status: "250", Meta: "250", InputBg: "16", Caret: "231",
+		Reaction: "226", Text: "231", Blur: "231",
+		Bright: "231", Panel: "252", SelectedBg: "21",
+		AgentPalette: []lipgloss.Color{"51", "46", "226", "201", "208", "123"},
+		ChromaStyle:  "native",
+	},
+}
+
+const defaultThemeName = "dark"
+
+// applyTheme installs t as the package-wide render colors.
+func applyTheme(t theme) {
+	userColor = t.User
+	statusColor = t.Status
+	metaColor = t.Meta
+	inputBg = t.InputBg
+	caretColor = t.Caret
+	reactionColor = t.Reaction
+	textColor = t.Text
+	blurText = t.Blur
+	brightColor = t.Bright
+	panelColor = t.Panel
+	selectedBg = t.SelectedBg
+	agentPalette = append([]lipgloss.Color(nil), t.AgentPalette...)
+	chromaStyleName = t.ChromaStyle
+}
+
+// resolveTheme returns the named theme: a custom theme from config layered on
+// its base, or a built-in. An empty name uses config.theme, then "dark".
+func resolveTheme(name string, config *core.ChatConfig) (theme, error) {
+	if name == "" && config != nil {
+		name = config.Theme
+	}
+	if name == "" {
+		name = defaultThemeName
+	}
+
+	var custom *core.ChatTheme
+	if config != nil {
+		if entry, ok := config.Themes[name]; ok {
+			custom = &entry
+		}
+	}
+	if custom == nil {
+		builtin, ok := builtinThemes[name]
+		if !ok {
+			return theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames(config), ", "))
+		}
+		return builtin, nil
+	}
+
+	baseName := custom.Base
+	if baseName == "" {
+		baseName = defaultThemeName
+	}
+	result, ok := builtinThemes[baseName]
+	if !ok {
+		return theme{}, fmt.Errorf("theme %q: unknown base %q", name, baseName)
+	}
+	slots := map[string]*lipgloss.Color{
+		"user": &result.User, "status": &result.Status, "meta": &result.Meta,
+		"input_bg": &result.InputBg, "caret": &result.Caret, "reaction": &result.Reaction,
+		"text": &result.Text, "blur": &result.Blur, "bright": &result.Bright,
+		"panel": &result.Panel, "selected_bg": &result.SelectedBg,
+	}
+	for key, value := range custom.Colors {
+		slot, ok := slots[key]
+		if !ok {
+			return theme{}, fmt.Errorf("theme %q: unknown color %q", name, key)
+		}
+		*slot = lipgloss.Color(value)
+	}
+	if len(custom.AgentColors) > 0 {
+		result.AgentPalette = make([]lipgloss.Color, len(custom.AgentColors))
+		for i, value := range custom.AgentColors {
+			result.AgentPalette[i] = lipgloss.Color(value)
+		}
+	}
+	if custom.ChromaStyle != "" {
+		result.ChromaStyle = custom.ChromaStyle
+	}
+	return result, nil
+}
+
+func themeNames(config *core.ChatConfig) []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	if config != nil {
+		for name := range config.Themes {
+			if _, ok := builtinThemes[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyAction is a rebindable chat shortcut.
+type keyAction string
+
+const (
+	keySearch     keyAction = "search"
+	keyAgents     keyAction = "agents"
+	keyThreads    keyAction = "threads"
+	keyChannels   keyAction = "channels"
+	keyPinSidebar keyAction = "pin_sidebar"
+	keyNewline    keyAction = "newline"
+	keyHelp       keyAction = "help"
+	keyNextPane   keyAction = "next_pane"
+	keyCompose    keyAction = "compose"
+)
+
+// defaultKeys uses bubbletea key names (tea.KeyMsg.String()).
+var defaultKeys = map[keyAction]string{
+	keySearch:     "ctrl+f",
+	keyAgents:     "ctrl+g",
+	keyThreads:    "tab",
+	keyChannels:   "shift+tab",
+	keyPinSidebar: "ctrl+b",
+	keyNewline:    "ctrl+j",
+	keyHelp:       "?",
+	keyNextPane:   "ctrl+o",
+	keyCompose:    "ctrl+x",
+}
+
+// resolveKeys layers config key overrides on the defaults.
+func resolveKeys(config *core.ChatConfig) (map[keyAction]string, error) {
+	keys := make(map[keyAction]string, len(defaultKeys))
+	for action, key := range defaultKeys {
+		keys[action] = key
+	}
+	if config == nil {
+		return keys, nil
+	}
+	for name, key := range config.Keys {
+		action := keyAction(name)
+		if _, ok := defaultKeys[action]; !ok {
+			return nil, fmt.Errorf("unknown key action %q", name)
+		}
+		keys[action] = strings.ToLower(strings.TrimSpace(key))
+	}
+	return keys, nil
+}
+
+// keyIs reports whether msg is bound to action.
+func (m *Model) keyIs(msg tea.KeyMsg, action keyAction) bool {
+	key, ok := m.keys[action]
+	if !ok {
+		key = defaultKeys[action]
+	}
+	return key != "" && msg.String() == key
+}
+
+// loadChatSettings reads chat.json, applies its theme, and returns the key
+// bindings. themeName (from --theme) takes precedence over the config's theme.
+func loadChatSettings(themeName string) (map[keyAction]string, error) {
+	config, err := core.ReadChatConfig()
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveTheme(themeName, config)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := resolveKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	applyTheme(resolved)
+	return keys, nil
+}
+
+// chatConfigModTime returns chat.json's modification time (zero if missing).
+func chatConfigModTime() time.Time {
+	path, err := core.ChatConfigPath()
+	if err != nil {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reloadChatSettingsIfChanged re-applies chat.json when it changes on disk.
+func (m *Model) reloadChatSettingsIfChanged() {
+	modTime := chatConfigModTime()
+	if modTime.Equal(m.chatConfigModTime) {
+		return
+	}
+	m.chatConfigModTime = modTime
+	keys, err := loadChatSettings(m.themeName)
+	if err != nil {
+		m.status = fmt.Sprintf("chat config: %v", err)
+		return
+	}
+	m.keys = keys
+	if colorMap, err := buildColorMap(m.db, 50, m.includeArchived); err == nil {
+		m.colorMap = colorMap
+	}
+	if m.reactionMode {
+		applyInputStyles(&m.input, reactionColor, reactionColor)
+	} else {
+		applyInputStyles(&m.input, textColor, blurText)
+	}
+	m.status = "Reloaded chat config"
+	m.refreshViewport(false)
+}
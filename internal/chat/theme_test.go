@@ -0,0 +1,81 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResolveThemeCustomOverlay(t *testing.T) {
+	config := &core.ChatConfig{
+		Theme: "mine",
+		Themes: map[string]core.ChatTheme{
+			"mine": {
+				Base:        "light",
+				Colors:      map[string]string{"text": "#101010", "selected_bg": "250"},
+				AgentColors: []string{"#ff0000", "#00ff00"},
+			},
+		},
+	}
+
+	resolved, err := resolveTheme("", config)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	light := builtinThemes["light"]
+	if resolved.Text != "#101010" || resolved.SelectedBg != "250" {
+		t.Fatalf("expected overridden colors, got text=%s selected=%s", resolved.Text, resolved.SelectedBg)
+	}
+	if resolved.Meta != light.Meta || resolved.ChromaStyle != light.ChromaStyle {
+		t.Fatalf("expected unset values from base theme")
+	}
+	if len(resolved.AgentPalette) != 2 || resolved.AgentPalette[0] != lipgloss.Color("#ff0000") {
+		t.Fatalf("unexpected agent palette: %v", resolved.AgentPalette)
+	}
+
+	override, err := resolveTheme("high-contrast", config)
+	if err != nil {
+		t.Fatalf("resolve override: %v", err)
+	}
+	if override.SelectedBg != builtinThemes["high-contrast"].SelectedBg {
+		t.Fatalf("expected --theme to take precedence over config theme")
+	}
+}
+
+func TestResolveThemeErrors(t *testing.T) {
+	if _, err := resolveTheme("nope", nil); err == nil {
+		t.Fatalf("expected unknown theme error")
+	}
+	config := &core.ChatConfig{Themes: map[string]core.ChatTheme{
+		"bad": {Colors: map[string]string{"sparkle": "1"}},
+	}}
+	if _, err := resolveTheme("bad", config); err == nil {
+		t.Fatalf("expected unknown color error")
+	}
+}
+
+func TestResolveKeys(t *testing.T) {
+	keys, err := resolveKeys(&core.ChatConfig{Keys: map[string]string{"search": " Ctrl+S "}})
+	if err != nil {
+		t.Fatalf("resolve keys: %v", err)
+	}
+	if keys[keySearch] != "ctrl+s" {
+		t.Fatalf("expected search rebound, got %q", keys[keySearch])
+	}
+	if keys[keyAgents] != defaultKeys[keyAgents] {
+		t.Fatalf("expected default for unbound action")
+	}
+	if _, err := resolveKeys(&core.ChatConfig{Keys: map[string]string{"launch": "x"}}); err == nil {
+		t.Fatalf("expected unknown action error")
+	}
+}
+
+func TestContrastTextColorHex(t *testing.T) {
+	if got := contrastTextColor("#ffffff"); got != "16" {
+		t.Fatalf("expected dark text on white, got %s", got)
+	}
+	if got := contrastTextColor("#000080"); got != "231" {
+		t.Fatalf("expected light text on navy, got %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package chat
 import (
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,12 +13,13 @@ import (
 const pollInterval = time.Second
 
 type pollMsg struct {
-	roomMessages    []types.Message
-	threadMessages  []types.Message
-	threadID        string
-	questions       []types.Question
-	threads         []types.Thread
-	mentionMessages []types.Message
+	roomMessages      []types.Message
+	threadMessages    []types.Message
+	threadID          string
+	questions         []types.Question
+	threads           []types.Thread
+	mentionMessages   []types.Message
+	assignedQuestions []types.Question // open questions asked of the user
 }
 
 func (m *Model) pollCmd() tea.Cmd {
@@ -28,8 +30,12 @@ func (m *Model) pollCmd() tea.Cmd {
 	showUpdates := m.showUpdates
 	currentThread := m.currentThread
 	currentPseudo := m.currentPseudo
+	notifier := m.notifier
+
+	return tea.Tick(pollInterval, func(now time.Time) tea.Msg {
+		// Deliver due scheduled messages so they show up in this poll.
+		_, _ = db.DeliverDueScheduled(m.db, m.projectDBPath, now)
 
-	return tea.Tick(pollInterval, func(time.Time) tea.Msg {
 		options := types.MessageQueryOptions{Since: cursor, IncludeArchived: includeArchived}
 		roomMessages, err := db.GetMessages(m.db, &options)
 		if err != nil {
@@ -100,26 +106,39 @@ func (m *Model) pollCmd() tea.Cmd {
 			threads = nil // Don't fail the poll, just return empty
 		}
 
-		// Fetch mentions from all threads for notifications
+		// Fetch mentions from all threads for notifications, or every new
+		// message when a notification rule matches on any message.
 		var mentionMessages []types.Message
+		var assignedQuestions []types.Question
 		if username != "" {
 			allHomes := "" // empty string = all homes (room + threads)
 			mentionOpts := &types.MessageQueryOptions{
-				Since:                  mentionCursor,
-				IncludeArchived:        false,
-				Home:                   &allHomes,
-				IncludeRepliesToAgent:  username,
+				Since:                 mentionCursor,
+				IncludeArchived:       false,
+				Home:                  &allHomes,
+				IncludeRepliesToAgent: username,
+			}
+			if notifier != nil && notifier.Wants(core.NotifyMessage) {
+				mentionMessages, _ = db.GetMessages(m.db, mentionOpts)
+			} else {
+				mentionMessages, _ = db.GetMessagesWithMention(m.db, username, mentionOpts)
+			}
+			if notifier != nil && notifier.Wants(core.NotifyQuestion) {
+				assignedQuestions, _ = db.GetQuestions(m.db, &types.QuestionQueryOptions{
+					Statuses: []types.QuestionStatus{types.QuestionStatusOpen},
+					ToAgent:  &username,
+				})
 			}
-			mentionMessages, _ = db.GetMessagesWithMention(m.db, username, mentionOpts)
 		}
 
 		return pollMsg{
-			roomMessages:    roomMessages,
-			threadMessages:  threadMessages,
-			threadID:        threadID,
-			questions:       questions,
-			threads:         threads,
-			mentionMessages: mentionMessages,
+			roomMessages:      roomMessages,
+			threadMessages:    threadMessages,
+			threadID:          threadID,
+			questions:         questions,
+			threads:           threads,
+			mentionMessages:   mentionMessages,
+			assignedQuestions: assignedQuestions,
 		}
 	})
 }
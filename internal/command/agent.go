@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -35,6 +36,7 @@ func NewAgentCmd() *cobra.Command {
 		NewAgentListCmd(),
 		NewAgentCheckCmd(),
 		NewAgentAvatarCmd(),
+		NewAgentKeygenCmd(),
 	)
 
 	return cmd
@@ -160,7 +162,19 @@ func NewAgentStartCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "start <name>",
 		Short: "Start a fresh session for a managed agent",
-		Args:  cobra.ExactArgs(1),
+		Long: `Start a fresh session for a managed agent.
+
+The running daemon spawns and supervises the session, so presence, session
+records, and done-detection work the same as for @mention wakes. Without a
+daemon, fray offers to start one in the background or to supervise the
+session from this terminal until it exits.
+
+Examples:
+  fray agent start opus
+  fray agent start opus --prompt "Review the open PRs"
+  fray agent start opus --start-daemon   # Start a background daemon if needed
+  fray agent start opus --supervise      # Supervise here until the session exits`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmdCtx, err := GetContext(cmd)
 			if err != nil {
@@ -177,56 +191,21 @@ func NewAgentStartCmd() *cobra.Command {
 				return writeCommandError(cmd, fmt.Errorf("agent @%s is not managed (use 'fray agent create' first)", agent.AgentID))
 			}
 
-			if agent.Invoke == nil || agent.Invoke.Driver == "" {
-				return writeCommandError(cmd, fmt.Errorf("agent @%s has no driver configured", agent.AgentID))
-			}
-
-			driver := daemon.GetDriver(agent.Invoke.Driver)
-			if driver == nil {
-				return writeCommandError(cmd, fmt.Errorf("unknown driver: %s", agent.Invoke.Driver))
+			if err := checkAgentDriver(agent); err != nil {
+				return writeCommandError(cmd, err)
 			}
 
-			customPrompt, _ := cmd.Flags().GetString("prompt")
-			prompt := customPrompt
+			prompt, _ := cmd.Flags().GetString("prompt")
 			if prompt == "" {
 				prompt = buildFlyPrompt(agent.AgentID)
 			}
 
-			ctx := context.Background()
-			proc, err := driver.Spawn(ctx, *agent, prompt)
-			if err != nil {
-				return writeCommandError(cmd, fmt.Errorf("spawn failed: %w", err))
-			}
-
-			// Drain pipes in background to prevent blocking
-			drainProcessPipes(proc)
-
-			if err := db.UpdateAgentPresence(cmdCtx.DB, agent.AgentID, types.PresenceSpawning); err != nil {
-				driver.Cleanup(proc)
-				return writeCommandError(cmd, err)
-			}
-
-			sessionStart := types.SessionStart{
-				AgentID:   agent.AgentID,
-				SessionID: proc.SessionID,
-				StartedAt: time.Now().Unix(),
-			}
-			db.AppendSessionStart(cmdCtx.Project.DBPath, sessionStart)
-
-			if cmdCtx.JSONMode {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
-					"agent_id":   agent.AgentID,
-					"session_id": proc.SessionID,
-					"driver":     agent.Invoke.Driver,
-				})
-			}
-
-			fmt.Fprintf(cmd.OutOrStdout(), "Started @%s (session: %s)\n", agent.AgentID, proc.SessionID)
-			return nil
+			return runAgentSession(cmd, cmdCtx, agent, daemon.Request{Op: daemon.OpStart, AgentID: agent.AgentID, Prompt: prompt})
 		},
 	}
 
 	cmd.Flags().String("prompt", "", "custom prompt (default: /fly equivalent)")
+	addAgentSessionFlags(cmd)
 
 	return cmd
 }
@@ -236,7 +215,12 @@ func NewAgentRefreshCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "refresh <name>",
 		Short: "End current session and start a new one",
-		Args:  cobra.ExactArgs(1),
+		Long: `End the agent's current session and start a fresh one.
+
+The daemon stops the running process (SIGTERM, then kill after a grace
+period), records the session end, and spawns the new session. Without a
+daemon, the same choices as 'fray agent start' apply.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmdCtx, err := GetContext(cmd)
 			if err != nil {
@@ -253,51 +237,17 @@ func NewAgentRefreshCmd() *cobra.Command {
 				return writeCommandError(cmd, fmt.Errorf("agent @%s is not managed", agent.AgentID))
 			}
 
-			if agent.Invoke == nil || agent.Invoke.Driver == "" {
-				return writeCommandError(cmd, fmt.Errorf("agent @%s has no driver configured", agent.AgentID))
-			}
-
-			// Skip session_end recording - we don't track session_id for manual refreshes
-			// The daemon handles session lifecycle properly via monitorProcess
-			db.UpdateAgentPresence(cmdCtx.DB, agent.AgentID, types.PresenceOffline)
-
-			driver := daemon.GetDriver(agent.Invoke.Driver)
-			if driver == nil {
-				return writeCommandError(cmd, fmt.Errorf("unknown driver: %s", agent.Invoke.Driver))
+			if err := checkAgentDriver(agent); err != nil {
+				return writeCommandError(cmd, err)
 			}
 
 			prompt := buildFlyPrompt(agent.AgentID)
-			ctx := context.Background()
-			proc, err := driver.Spawn(ctx, *agent, prompt)
-			if err != nil {
-				return writeCommandError(cmd, fmt.Errorf("spawn failed: %w", err))
-			}
-
-			// Drain pipes in background to prevent blocking
-			drainProcessPipes(proc)
-
-			db.UpdateAgentPresence(cmdCtx.DB, agent.AgentID, types.PresenceSpawning)
-
-			sessionStart := types.SessionStart{
-				AgentID:   agent.AgentID,
-				SessionID: proc.SessionID,
-				StartedAt: time.Now().Unix(),
-			}
-			db.AppendSessionStart(cmdCtx.Project.DBPath, sessionStart)
-
-			if cmdCtx.JSONMode {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
-					"agent_id":   agent.AgentID,
-					"session_id": proc.SessionID,
-					"refreshed":  true,
-				})
-			}
-
-			fmt.Fprintf(cmd.OutOrStdout(), "Refreshed @%s (session: %s)\n", agent.AgentID, proc.SessionID)
-			return nil
+			return runAgentSession(cmd, cmdCtx, agent, daemon.Request{Op: daemon.OpRefresh, AgentID: agent.AgentID, Prompt: prompt})
 		},
 	}
 
+	addAgentSessionFlags(cmd)
+
 	return cmd
 }
 
@@ -306,7 +256,11 @@ func NewAgentEndCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "end <name>",
 		Short: "Gracefully end an agent session",
-		Args:  cobra.ExactArgs(1),
+		Long: `Gracefully end an agent session.
+
+With a daemon running, the daemon stops the agent's process and records the
+session end. Otherwise the agent is marked offline.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmdCtx, err := GetContext(cmd)
 			if err != nil {
@@ -323,25 +277,25 @@ func NewAgentEndCmd() *cobra.Command {
 				return writeCommandError(cmd, fmt.Errorf("agent @%s is not managed", agent.AgentID))
 			}
 
-			// Skip session_end recording - we don't track session_id for manual ends
-			// The daemon handles session lifecycle properly via monitorProcess
-			db.UpdateAgentPresence(cmdCtx.DB, agent.AgentID, types.PresenceOffline)
-
-			if cmdCtx.JSONMode {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
-					"agent_id": agent.AgentID,
-					"ended":    true,
-				})
-			}
-
-			fmt.Fprintf(cmd.OutOrStdout(), "Ended session for @%s\n", agent.AgentID)
-			return nil
+			return runAgentSession(cmd, cmdCtx, agent, daemon.Request{Op: daemon.OpEnd, AgentID: agent.AgentID})
 		},
 	}
 
 	return cmd
 }
 
+// checkAgentDriver verifies the agent has a known driver before handing it
+// to a supervisor.
+func checkAgentDriver(agent *types.Agent) error {
+	if agent.Invoke == nil || agent.Invoke.Driver == "" {
+		return fmt.Errorf("agent @%s has no driver configured", agent.AgentID)
+	}
+	if daemon.GetDriver(agent.Invoke.Driver) == nil {
+		return fmt.Errorf("unknown driver: %s", agent.Invoke.Driver)
+	}
+	return nil
+}
+
 // NewAgentListCmd lists all agents with their managed status.
 func NewAgentListCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -698,6 +652,97 @@ Examples:
 	return cmd
 }
 
+// NewAgentKeygenCmd creates a signing key for an agent and registers its
+// public key with the project.
+func NewAgentKeygenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen <name>",
+		Short: "Create a signing key for an agent",
+		Long: `Create an ed25519 signing key for an agent and register its public key in
+.fray/fray-config.json. The private key stays in ~/.config/fray/keys/, outside
+the repo; messages posted as the agent from this machine are signed with it.
+
+Once an agent has a registered key, its messages show as verified, unverified
+(unsigned, e.g. someone else posting with --as), or invalid (altered or
+re-attributed). Run keygen again in another project to register the same key
+there. --rotate replaces the key; the old public key stays registered as
+retired so messages it signed still verify.
+
+Examples:
+  fray agent keygen alice
+  fray agent keygen alice --rotate`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer cmdCtx.DB.Close()
+
+			rotate, _ := cmd.Flags().GetBool("rotate")
+			agentID := core.NormalizeAgentRef(args[0])
+			agent, err := db.GetAgent(cmdCtx.DB, agentID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if agent == nil {
+				return writeCommandError(cmd, fmt.Errorf("agent not found: @%s", agentID))
+			}
+
+			key, err := core.LoadAgentKey(agentID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			generated := false
+			if key == nil || rotate {
+				key, err = core.GenerateAgentKey(agentID)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				generated = true
+			}
+
+			previous, err := db.GetAgentPublicKeys(cmdCtx.DB, agentID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			publicKey := key.Public().(ed25519.PublicKey)
+			retired := len(previous) > 0 && !previous[0].Equal(publicKey)
+			if err := db.RegisterAgentPublicKey(cmdCtx.DB, cmdCtx.Project.DBPath, agent.GUID, agentID, publicKey); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			keyPath, _ := core.AgentKeyPath(agentID)
+
+			if cmdCtx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"agent_id":    agentID,
+					"public_key":  core.EncodePublicKey(publicKey),
+					"key_id":      core.PublicKeyID(publicKey),
+					"key_path":    keyPath,
+					"generated":   generated,
+					"retired_key": retired,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			if generated {
+				fmt.Fprintf(out, "Generated signing key for @%s (%s)\n", agentID, keyPath)
+			} else {
+				fmt.Fprintf(out, "Registered existing key for @%s (%s)\n", agentID, keyPath)
+			}
+			fmt.Fprintf(out, "  public key: %s (id %s)\n", core.EncodePublicKey(publicKey), core.PublicKeyID(publicKey))
+			if retired {
+				fmt.Fprintln(out, "  previous key retired; messages signed with it still verify")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("rotate", false, "replace the existing private key")
+
+	return cmd
+}
+
 func init() {
 	_ = os.Getenv("FRAY_AGENT_ID")
 }
@@ -0,0 +1,223 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adamavenir/fray/internal/daemon"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+const daemonStartupTimeout = 5 * time.Second
+
+// addAgentSessionFlags registers the flags that pick a supervisor when no
+// daemon is running.
+func addAgentSessionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("start-daemon", false, "start a background daemon if none is running")
+	cmd.Flags().Bool("supervise", false, "if no daemon is running, supervise the session here until it exits")
+}
+
+// runAgentSession hands a start/refresh/end request to the project daemon so
+// it owns the agent process. Without a daemon, start and refresh either launch
+// a background daemon or run an embedded supervisor until the session exits;
+// end just marks the agent offline.
+func runAgentSession(cmd *cobra.Command, cmdCtx *CommandContext, agent *types.Agent, req daemon.Request) error {
+	frayDir := filepath.Dir(cmdCtx.Project.DBPath)
+
+	if !daemon.IsLocked(frayDir) {
+		if req.Op == daemon.OpEnd {
+			// Nothing supervises the agent, so there is no process to stop
+			if err := db.UpdateAgentPresence(cmdCtx.DB, agent.AgentID, types.PresenceOffline); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			return printAgentSession(cmd, cmdCtx, agent, daemon.Response{OK: true, AgentID: agent.AgentID})
+		}
+
+		mode, err := chooseSupervisor(cmd, cmdCtx, agent.AgentID)
+		if err != nil {
+			return writeCommandError(cmd, err)
+		}
+		if mode == "supervise" {
+			return superviseAgentSession(cmd, cmdCtx, agent, req)
+		}
+		if err := startBackgroundDaemon(cmdCtx, frayDir); err != nil {
+			return writeCommandError(cmd, err)
+		}
+		if !cmdCtx.JSONMode {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Started daemon (log: %s)\n", filepath.Join(frayDir, "daemon.log"))
+		}
+	}
+
+	resp, err := daemon.Call(frayDir, req)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	return printAgentSession(cmd, cmdCtx, agent, resp)
+}
+
+// chooseSupervisor returns "daemon" or "supervise" from flags, or by asking
+// on a terminal.
+func chooseSupervisor(cmd *cobra.Command, cmdCtx *CommandContext, agentID string) (string, error) {
+	startDaemon, _ := cmd.Flags().GetBool("start-daemon")
+	supervise, _ := cmd.Flags().GetBool("supervise")
+	switch {
+	case startDaemon && supervise:
+		return "", fmt.Errorf("use only one of --start-daemon and --supervise")
+	case startDaemon:
+		return "daemon", nil
+	case supervise:
+		return "supervise", nil
+	}
+
+	if cmdCtx.JSONMode || !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("no daemon is running; start one with 'fray daemon', or pass --start-daemon or --supervise")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprintf(cmd.OutOrStdout(), "No daemon is running. Start one in the background [d], supervise @%s here until it exits [s], or cancel [c]? [D/s/c] ", agentID)
+	text, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "d", "daemon":
+		return "daemon", nil
+	case "s", "supervise":
+		return "supervise", nil
+	default:
+		return "", fmt.Errorf("cancelled")
+	}
+}
+
+// startBackgroundDaemon launches a detached `fray daemon` for the project and
+// waits for it to answer on its socket.
+func startBackgroundDaemon(cmdCtx *CommandContext, frayDir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate fray binary: %w", err)
+	}
+	logFile, err := os.OpenFile(filepath.Join(frayDir, "daemon.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	proc := exec.Command(exe, "daemon")
+	proc.Dir = cmdCtx.Project.Root
+	proc.Stdout = logFile
+	proc.Stderr = logFile
+	proc.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := proc.Start(); err != nil {
+		return fmt.Errorf("start daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- proc.Wait() }()
+
+	deadline := time.Now().Add(daemonStartupTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("daemon exited during startup (%v); see %s", err, logFile.Name())
+		case <-time.After(100 * time.Millisecond):
+		}
+		if _, err := daemon.Call(frayDir, daemon.Request{Op: daemon.OpPing}); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("daemon did not start within %s; see %s", daemonStartupTimeout, logFile.Name())
+}
+
+// superviseAgentSession runs an embedded daemon in this process, starts the
+// session through it, and keeps supervising until the agent exits or the
+// user interrupts. The embedded daemon only supervises this session; it does
+// not wake other agents, deliver scheduled messages or run retention.
+func superviseAgentSession(cmd *cobra.Command, cmdCtx *CommandContext, agent *types.Agent, req daemon.Request) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	cfg := daemon.DefaultConfig()
+	cfg.Session = agent.AgentID
+	d := daemon.New(cmdCtx.Project, cmdCtx.DB, cfg)
+	if err := d.Start(ctx); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	resp := d.Do(req)
+	if resp.Error != "" {
+		d.Stop()
+		return writeCommandError(cmd, errors.New(resp.Error))
+	}
+	if err := printAgentSession(cmd, cmdCtx, agent, resp); err != nil {
+		d.Stop()
+		return err
+	}
+	if !cmdCtx.JSONMode {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Supervising @%s until it exits (Ctrl+C to end the session)\n", agent.AgentID)
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	interrupted := false
+	for !interrupted && d.Running(agent.AgentID) {
+		select {
+		case <-sigCh:
+			d.Do(daemon.Request{Op: daemon.OpEnd, AgentID: agent.AgentID})
+			interrupted = true
+		case <-ticker.C:
+		}
+	}
+
+	if err := d.Stop(); err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if !cmdCtx.JSONMode {
+		fmt.Fprintf(cmd.ErrOrStderr(), "@%s exited; supervisor stopped\n", agent.AgentID)
+	}
+	return nil
+}
+
+func printAgentSession(cmd *cobra.Command, cmdCtx *CommandContext, agent *types.Agent, resp daemon.Response) error {
+	if cmdCtx.JSONMode {
+		payload := map[string]any{"agent_id": agent.AgentID}
+		if resp.SessionID != "" {
+			payload["session_id"] = resp.SessionID
+			payload["pid"] = resp.PID
+			if agent.Invoke != nil {
+				payload["driver"] = agent.Invoke.Driver
+			}
+		}
+		switch cmd.Name() {
+		case "refresh":
+			payload["refreshed"] = true
+		case "end":
+			payload["ended"] = true
+			payload["stopped"] = resp.Ended
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
+	}
+
+	out := cmd.OutOrStdout()
+	switch cmd.Name() {
+	case "refresh":
+		fmt.Fprintf(out, "Refreshed @%s (session: %s)\n", agent.AgentID, resp.SessionID)
+	case "end":
+		fmt.Fprintf(out, "Ended session for @%s\n", agent.AgentID)
+	default:
+		fmt.Fprintf(out, "Started @%s (session: %s)\n", agent.AgentID, resp.SessionID)
+	}
+	return nil
+}
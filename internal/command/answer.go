@@ -6,10 +6,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strings"
-	"time"
 
-	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/charmbracelet/lipgloss"
@@ -234,92 +231,12 @@ func groupQuestionSets(questions []types.Question) []questionSet {
 
 // postAnswerSummary posts a single message with all Q&A pairs formatted nicely.
 func postAnswerSummary(database *sql.DB, dbPath string, identity string, pairs []qaPair) error {
-	now := time.Now().Unix()
-
-	// Collect unique askers
-	askers := make(map[string]struct{})
-	for _, pair := range pairs {
-		askers[pair.question.FromAgent] = struct{}{}
-	}
-	askerList := make([]string, 0, len(askers))
-	for asker := range askers {
-		askerList = append(askerList, "@"+asker)
-	}
-
-	// Build the summary message body in parseable format
-	var body strings.Builder
-	body.WriteString(fmt.Sprintf("answered %s\n\n", strings.Join(askerList, " ")))
-
-	for _, pair := range pairs {
-		// Question
-		body.WriteString(fmt.Sprintf("Q: %s\n", pair.question.Re))
-
-		// Answer with indented multi-line support
-		answerLines := strings.Split(pair.answer, "\n")
-		body.WriteString(fmt.Sprintf("A: %s\n", answerLines[0]))
-		for _, line := range answerLines[1:] {
-			body.WriteString(fmt.Sprintf("   %s\n", line))
-		}
-		body.WriteString("\n")
-	}
-
-	bodyStr := strings.TrimSpace(body.String())
-
-	// Extract mentions from all answers
-	bases, _ := db.GetAgentBases(database)
-	mentions := core.ExtractMentions(bodyStr, bases)
-	mentions = core.ExpandAllMention(mentions, bases)
-
-	// Determine home (use first question's thread if any)
-	home := ""
-	if len(pairs) > 0 && pairs[0].question.ThreadGUID != nil {
-		home = *pairs[0].question.ThreadGUID
-	}
-
-	// Create the summary message
-	created, err := db.CreateMessage(database, types.Message{
-		TS:        now,
-		FromAgent: identity,
-		Body:      bodyStr,
-		Mentions:  mentions,
-		Home:      home,
-	})
-	if err != nil {
-		return err
+	answers := make([]db.QuestionAnswer, len(pairs))
+	for i, pair := range pairs {
+		answers[i] = db.QuestionAnswer{Question: pair.question, Answer: pair.answer}
 	}
-
-	if err := db.AppendMessage(dbPath, created); err != nil {
-		return err
-	}
-
-	// Update agent last seen (if this is an agent, not a user)
-	agent, _ := db.GetAgent(database, identity)
-	if agent != nil {
-		updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
-		_ = db.UpdateAgent(database, identity, updates)
-	}
-
-	// Update all questions to point to this summary message
-	statusValue := string(types.QuestionStatusAnswered)
-	for _, pair := range pairs {
-		updated, err := db.UpdateQuestion(database, pair.question.GUID, db.QuestionUpdates{
-			Status:     types.OptionalString{Set: true, Value: &statusValue},
-			AnsweredIn: types.OptionalString{Set: true, Value: &created.ID},
-		})
-		if err != nil {
-			return err
-		}
-
-		if err := db.AppendQuestionUpdate(dbPath, db.QuestionUpdateJSONLRecord{
-			GUID:       updated.GUID,
-			Status:     &statusValue,
-			AnsweredIn: &created.ID,
-		}); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	_, err := db.PostAnswerSummary(database, dbPath, identity, answers)
+	return err
 }
 
 func printSummary(answered, stillSkipped int) {
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
@@ -74,19 +73,19 @@ func NewAskCmd() *cobra.Command {
 				}
 			}
 
-			now := time.Now().Unix()
+			var threadGUID *string
+			if thread != nil {
+				threadGUID = &thread.GUID
+			}
+
 			if question == nil {
-				threadGUID := (*string)(nil)
-				if thread != nil {
-					threadGUID = &thread.GUID
-				}
 				createdQuestion, err := db.CreateQuestion(ctx.DB, types.Question{
 					Re:         questionInput,
 					FromAgent:  agentID,
 					ToAgent:    toAgent,
 					Status:     types.QuestionStatusOpen,
 					ThreadGUID: threadGUID,
-					CreatedAt:  now,
+					CreatedAt:  time.Now().Unix(),
 				})
 				if err != nil {
 					return writeCommandError(cmd, err)
@@ -97,75 +96,10 @@ func NewAskCmd() *cobra.Command {
 				question = &createdQuestion
 			}
 
-			body := question.Re
-			if toAgent != nil {
-				body = fmt.Sprintf("@%s %s", *toAgent, question.Re)
-			}
-
-			bases, err := db.GetAgentBases(ctx.DB)
-			if err != nil {
-				return writeCommandError(cmd, err)
-			}
-			mentions := core.ExtractMentions(body, bases)
-			mentions = core.ExpandAllMention(mentions, bases)
-
-			home := ""
-			if thread != nil {
-				home = thread.GUID
-			} else if question.ThreadGUID != nil {
-				home = *question.ThreadGUID
-			}
-
-			created, err := db.CreateMessage(ctx.DB, types.Message{
-				TS:        now,
-				FromAgent: agentID,
-				Body:      body,
-				Mentions:  mentions,
-				Home:      home,
-			})
-			if err != nil {
-				return writeCommandError(cmd, err)
-			}
-
-			if err := db.AppendMessage(ctx.Project.DBPath, created); err != nil {
-				return writeCommandError(cmd, err)
-			}
-
-			updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
-			if err := db.UpdateAgent(ctx.DB, agentID, updates); err != nil {
-				return writeCommandError(cmd, err)
-			}
-
-			statusValue := string(types.QuestionStatusOpen)
-			questionUpdates := db.QuestionUpdates{
-				Status:  types.OptionalString{Set: true, Value: &statusValue},
-				AskedIn: types.OptionalString{Set: true, Value: &created.ID},
-			}
-			if toAgent != nil {
-				questionUpdates.ToAgent = types.OptionalString{Set: true, Value: toAgent}
-			}
-			if thread != nil && question.ThreadGUID == nil {
-				questionUpdates.ThreadGUID = types.OptionalString{Set: true, Value: &thread.GUID}
-			}
-
-			updated, err := db.UpdateQuestion(ctx.DB, question.GUID, questionUpdates)
+			updated, created, err := db.AskQuestion(ctx.DB, ctx.Project.DBPath, *question, agentID, toAgent, threadGUID)
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
-			updateRecord := db.QuestionUpdateJSONLRecord{
-				GUID:    updated.GUID,
-				Status:  &statusValue,
-				AskedIn: &created.ID,
-			}
-			if toAgent != nil {
-				updateRecord.ToAgent = toAgent
-			}
-			if thread != nil && question.ThreadGUID == nil {
-				updateRecord.ThreadGUID = &thread.GUID
-			}
-			if err := db.AppendQuestionUpdate(ctx.Project.DBPath, updateRecord); err != nil {
-				return writeCommandError(cmd, err)
-			}
 
 			if ctx.JSONMode {
 				payload := map[string]any{
@@ -0,0 +1,228 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// recordAudit appends an administrative action to the project's audit log.
+// A failure to write the log is reported but does not undo the action.
+func recordAudit(cmd *cobra.Command, ctx *CommandContext, action, target string, before, after map[string]any) {
+	if err := writeAudit(cmd, ctx, action, target, before, after); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not write audit log: %v\n", err)
+	}
+}
+
+// writeAudit appends an administrative action to the project's audit log.
+// Renames, merges and links call it before mutating, since rebuild replays
+// them from the log and an action missing there would be undone.
+func writeAudit(cmd *cobra.Command, ctx *CommandContext, action, target string, before, after map[string]any) error {
+	_, err := db.AppendAudit(ctx.Project.DBPath, db.AuditJSONLRecord{
+		Actor:  auditActor(cmd, ctx),
+		Action: action,
+		Target: target,
+		Before: before,
+		After:  after,
+	})
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// auditActor names who ran a command: --as, then FRAY_AGENT_ID, then the
+// stored username, then the OS user.
+func auditActor(cmd *cobra.Command, ctx *CommandContext) string {
+	actor := ""
+	if cmd.Flags().Lookup("as") != nil {
+		actor, _ = cmd.Flags().GetString("as")
+	}
+	if actor == "" {
+		actor = os.Getenv("FRAY_AGENT_ID")
+	}
+	if actor == "" && ctx != nil && ctx.DB != nil {
+		actor, _ = db.GetConfig(ctx.DB, "username")
+	}
+	if actor == "" {
+		actor = os.Getenv("USER")
+	}
+	if actor == "" {
+		return "unknown"
+	}
+	return core.NormalizeAgentRef(actor)
+}
+
+// recordThreadStatusAudit records an archive or restore.
+func recordThreadStatusAudit(cmd *cobra.Command, ctx *CommandContext, thread *types.Thread, status string) {
+	action := "thread.restore"
+	if status == string(types.ThreadStatusArchived) {
+		action = "thread.archive"
+	}
+	recordAudit(cmd, ctx, action, thread.GUID,
+		map[string]any{"status": string(thread.Status)}, map[string]any{"status": status})
+}
+
+// auditNullable records an empty value as null.
+func auditNullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// NewAuditCmd creates the audit command.
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the log of administrative actions",
+		Long: `Show who renamed, merged, moved, archived, restored, or deleted what.
+
+Every administrative command appends an entry to .fray/audit.jsonl with the
+actor, the action, its target, and the state before and after. The log is
+append-only; rebuild replays agent renames and merges from it so they survive
+a cache rebuild. Channel destroys are recorded in ~/.config/fray/audit.jsonl,
+shown with --global.
+
+Examples:
+  fray audit                      # Everything, oldest first
+  fray audit --actor alice        # Only alice's actions
+  fray audit --since 1d           # The last day
+  fray audit --action agent.merge # Only merges
+  fray audit --global             # Destroyed channels`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actor, _ := cmd.Flags().GetString("actor")
+			since, _ := cmd.Flags().GetString("since")
+			action, _ := cmd.Flags().GetString("action")
+			last, _ := cmd.Flags().GetInt("last")
+			global, _ := cmd.Flags().GetBool("global")
+
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			var entries []db.AuditJSONLRecord
+			if global {
+				entries, err = db.ReadGlobalAudit()
+			} else {
+				entries, err = db.ReadAudit(ctx.Project.DBPath)
+			}
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			var sinceTS int64
+			if since != "" {
+				cursor, err := core.ParseTimeExpression(ctx.DB, since, "since")
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				sinceTS = cursor.TS
+			}
+			actor = core.NormalizeAgentRef(actor)
+
+			filtered := make([]db.AuditJSONLRecord, 0, len(entries))
+			for _, entry := range entries {
+				if actor != "" && entry.Actor != actor {
+					continue
+				}
+				if action != "" && entry.Action != action && !strings.HasPrefix(entry.Action, action+".") {
+					continue
+				}
+				if sinceTS > 0 && entry.TS < sinceTS {
+					continue
+				}
+				filtered = append(filtered, entry)
+			}
+			if last > 0 && len(filtered) > last {
+				filtered = filtered[len(filtered)-last:]
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(filtered)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(filtered) == 0 {
+				fmt.Fprintln(out, "No audit entries")
+				return nil
+			}
+			for _, entry := range filtered {
+				when := time.Unix(entry.TS, 0).Format("2006-01-02 15:04")
+				fmt.Fprintf(out, "%s  @%s  %s  %s\n", when, entry.Actor, entry.Action, entry.Target)
+				if change := formatAuditChange(entry.Before, entry.After); change != "" {
+					fmt.Fprintf(out, "    %s\n", change)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("actor", "", "only actions by this agent or user")
+	cmd.Flags().String("since", "", "only actions after time or GUID")
+	cmd.Flags().String("action", "", "only this action or action family (e.g. thread, agent.rename)")
+	cmd.Flags().Int("last", 0, "show only the last N entries")
+	cmd.Flags().Bool("global", false, "show the global log (channel destroys)")
+
+	return cmd
+}
+
+// formatAuditChange renders before/after as "key: old → new" pairs.
+func formatAuditChange(before, after map[string]any) string {
+	keys := map[string]struct{}{}
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	parts := make([]string, 0, len(sorted))
+	for _, key := range sorted {
+		oldValue, hadOld := before[key]
+		newValue, hasNew := after[key]
+		switch {
+		case hadOld && hasNew:
+			parts = append(parts, fmt.Sprintf("%s: %s → %s", key, formatAuditValue(oldValue), formatAuditValue(newValue)))
+		case hadOld:
+			parts = append(parts, fmt.Sprintf("%s: %s", key, formatAuditValue(oldValue)))
+		default:
+			parts = append(parts, fmt.Sprintf("%s → %s", key, formatAuditValue(newValue)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatAuditValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "none"
+	case string:
+		if runes := []rune(v); len(runes) > 60 {
+			return fmt.Sprintf("%q", string(runes[:57])+"...")
+		}
+		return fmt.Sprintf("%q", v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
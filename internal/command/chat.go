@@ -29,6 +29,8 @@ func NewChatCmd() *cobra.Command {
 			showUpdatesFlag, _ := cmd.Flags().GetBool("show-updates")
 			archived, _ := cmd.Flags().GetBool("archived")
 			force, _ := cmd.Flags().GetBool("force")
+			themeName, _ := cmd.Flags().GetString("theme")
+			raw, _ := cmd.Flags().GetBool("raw")
 
 			var ctx *CommandContext
 			var err error
@@ -107,6 +109,8 @@ func NewChatCmd() *cobra.Command {
 				Last:            last,
 				ShowUpdates:     showUpdates,
 				IncludeArchived: archived,
+				Theme:           themeName,
+				RawMarkdown:     raw,
 			}
 
 			return chat.Run(options)
@@ -118,6 +122,8 @@ func NewChatCmd() *cobra.Command {
 	cmd.Flags().Bool("show-events", false, "show event messages")
 	cmd.Flags().Bool("show-updates", false, "include event messages (deprecated)")
 	cmd.Flags().Bool("archived", false, "include archived messages")
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
+	cmd.Flags().String("theme", "", "chat theme: dark, light, high-contrast, or a theme from chat.json")
 
 	return cmd
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
@@ -39,6 +40,9 @@ func NewClaimCmd() *cobra.Command {
 			if _, err := db.PruneExpiredClaims(ctx.DB); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			if _, err := releaseClosedIssueClaims(ctx, core.IssueProviders(ctx.Project.Root)); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			ttl, _ := cmd.Flags().GetString("ttl")
 			reason, _ := cmd.Flags().GetString("reason")
@@ -57,7 +61,7 @@ func NewClaimCmd() *cobra.Command {
 				return writeCommandError(cmd, err)
 			}
 			if len(claims) == 0 {
-				return writeCommandError(cmd, fmt.Errorf("no claims specified. Use --file, --files, --bd, or --issue"))
+				return writeCommandError(cmd, fmt.Errorf("no claims specified. Use --file, --files, --bd, --issue, or --branch"))
 			}
 
 			created := make([]types.Claim, 0, len(claims))
@@ -89,6 +93,11 @@ func NewClaimCmd() *cobra.Command {
 			if err := db.AppendMessage(ctx.Project.DBPath, createdMsg); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			for _, claim := range created {
+				if err := db.SetClaimMessage(ctx.DB, claim.ID, createdMsg.ID); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
 
 			if ctx.JSONMode {
 				payload := map[string]any{
@@ -121,6 +130,7 @@ func NewClaimCmd() *cobra.Command {
 	cmd.Flags().String("files", "", "claim multiple files (comma-separated globs)")
 	cmd.Flags().String("bd", "", "claim a beads issue")
 	cmd.Flags().String("issue", "", "claim a GitHub issue")
+	cmd.Flags().String("branch", "", "claim a git branch (glob allowed)")
 	cmd.Flags().String("ttl", "", "expiration time (e.g., 2h, 30m, 1d)")
 	cmd.Flags().String("reason", "", "reason for claim")
 
@@ -132,6 +142,7 @@ func collectClaims(cmd *cobra.Command) ([]types.ClaimInput, error) {
 	files, _ := cmd.Flags().GetString("files")
 	bd, _ := cmd.Flags().GetString("bd")
 	issue, _ := cmd.Flags().GetString("issue")
+	branch, _ := cmd.Flags().GetString("branch")
 
 	claims := []types.ClaimInput{}
 	if file != "" {
@@ -148,6 +159,9 @@ func collectClaims(cmd *cobra.Command) ([]types.ClaimInput, error) {
 	if issue != "" {
 		claims = append(claims, types.ClaimInput{ClaimType: types.ClaimTypeIssue, Pattern: stripHash(issue)})
 	}
+	if branch != "" {
+		claims = append(claims, types.ClaimInput{ClaimType: types.ClaimTypeBranch, Pattern: branch})
+	}
 
 	return claims, nil
 }
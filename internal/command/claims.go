@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
@@ -28,6 +29,10 @@ func NewClaimsCmd() *cobra.Command {
 			if _, err := db.PruneExpiredClaims(ctx.DB); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			providers := core.IssueProviders(ctx.Project.Root)
+			if _, err := releaseClosedIssueClaims(ctx, providers); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			var claims []types.Claim
 			if len(args) > 0 {
@@ -54,7 +59,11 @@ func NewClaimsCmd() *cobra.Command {
 			}
 
 			if ctx.JSONMode {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(claims)
+				payload := make([]claimWithIssue, 0, len(claims))
+				for _, claim := range claims {
+					payload = append(payload, claimWithIssue{Claim: claim, Issue: lookupClaimIssue(providers, claim)})
+				}
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
 			}
 
 			out := cmd.OutOrStdout()
@@ -99,7 +108,11 @@ func NewClaimsCmd() *cobra.Command {
 					if claim.Reason != nil && *claim.Reason != "" {
 						reason = " - " + *claim.Reason
 					}
-					fmt.Fprintf(out, "    %s%s (%s)%s%s\n", typePrefix, claim.Pattern, age, expiry, reason)
+					issue := ""
+					if info := lookupClaimIssue(providers, claim); info != nil {
+						issue = fmt.Sprintf(" [%s] %s", info.Status, info.Title)
+					}
+					fmt.Fprintf(out, "    %s%s%s (%s)%s%s\n", typePrefix, claim.Pattern, issue, age, expiry, reason)
 				}
 			}
 
@@ -107,6 +120,50 @@ func NewClaimsCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().String("type", "", "filter by claim type (file, bd, issue)")
+	cmd.Flags().String("type", "", "filter by claim type (file, bd, issue, branch)")
+	cmd.AddCommand(newClaimsCheckCmd())
 	return cmd
 }
+
+// claimWithIssue is the JSON shape for claims, with tracker details when known.
+type claimWithIssue struct {
+	types.Claim
+	Issue *core.Issue `json:"issue,omitempty"`
+}
+
+func lookupClaimIssue(providers []core.IssueProvider, claim types.Claim) *core.Issue {
+	provider := core.FindIssueProvider(providers, string(claim.ClaimType))
+	if provider == nil {
+		return nil
+	}
+	issue, err := provider.LookupIssue(claim.Pattern)
+	if err != nil {
+		return nil
+	}
+	return issue
+}
+
+// releaseClosedIssueClaims drops claims on closed tracker issues and posts an
+// event for each so the room sees why the claim disappeared.
+func releaseClosedIssueClaims(ctx *CommandContext, providers []core.IssueProvider) ([]types.Claim, error) {
+	released, err := db.PruneClosedIssueClaims(ctx.DB, providers)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range released {
+		msg, err := db.CreateMessage(ctx.DB, types.Message{
+			TS:        time.Now().Unix(),
+			FromAgent: claim.AgentID,
+			Body:      fmt.Sprintf("released %s:%s (issue closed)", claim.ClaimType, claim.Pattern),
+			Mentions:  []string{},
+			Type:      types.MessageTypeEvent,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := db.AppendMessage(ctx.Project.DBPath, msg); err != nil {
+			return nil, err
+		}
+	}
+	return released, nil
+}
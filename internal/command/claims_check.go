@@ -0,0 +1,208 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/command/hooks"
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/notify"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+)
+
+// claimConflict describes a claim held by another agent that matches checked paths or branches.
+type claimConflict struct {
+	AgentID   string          `json:"agent_id"`
+	ClaimType types.ClaimType `json:"claim_type"`
+	Pattern   string          `json:"pattern"`
+	Reason    *string         `json:"reason,omitempty"`
+	Matches   []string        `json:"matches"`
+}
+
+func newClaimsCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [paths...]",
+		Short: "Fail when paths or a diff range touch files claimed by another agent",
+		Long: `Check paths, a git diff range, or a branch against active claims.
+
+Exits non-zero when any claim held by another agent matches. Intended for CI:
+
+  fray claims check --diff origin/main..HEAD --branch "$BRANCH" --as alice --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			diffRange, _ := cmd.Flags().GetString("diff")
+			branch, _ := cmd.Flags().GetString("branch")
+			asRef, _ := cmd.Flags().GetString("as")
+
+			if len(args) == 0 && diffRange == "" && branch == "" {
+				return writeCommandError(cmd, fmt.Errorf("nothing to check. Pass paths, --diff <rev..rev>, or --branch <name>"))
+			}
+
+			excludeAgent := os.Getenv("FRAY_AGENT_ID")
+			if asRef != "" {
+				excludeAgent, err = resolveAgentRef(ctx, asRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+
+			paths := append([]string{}, args...)
+			if diffRange != "" {
+				diffFiles, err := gitDiffFiles(ctx.Project.Root, diffRange)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				paths = append(paths, diffFiles...)
+			}
+			paths = hooks.UniqueSorted(paths)
+
+			if _, err := db.PruneExpiredClaims(ctx.DB); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			fileClaims, err := db.FindConflictingFileClaims(ctx.DB, paths, excludeAgent)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			conflicts := buildClaimConflicts(fileClaims, paths)
+
+			if branch != "" {
+				branchClaims, err := db.FindConflictingBranchClaims(ctx.DB, []string{branch}, excludeAgent)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				conflicts = append(conflicts, buildClaimConflicts(branchClaims, []string{branch})...)
+			}
+
+			if ctx.JSONMode {
+				payload := map[string]any{
+					"checked_files": len(paths),
+					"branch":        branch,
+					"conflicts":     conflicts,
+				}
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(payload); err != nil {
+					return err
+				}
+			} else {
+				printClaimConflicts(cmd, conflicts, len(paths))
+			}
+
+			if len(conflicts) > 0 {
+				if err := notifyClaimConflicts(ctx, excludeAgent, conflicts); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+				}
+				return writeCommandError(cmd, fmt.Errorf("%d claim conflict(s) detected", len(conflicts)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("diff", "", "git revision range to check (e.g., origin/main..HEAD)")
+	cmd.Flags().String("branch", "", "branch name to check against branch claims")
+	cmd.Flags().String("as", "", "agent whose own claims are ignored (default: $FRAY_AGENT_ID)")
+	return cmd
+}
+
+func buildClaimConflicts(claims []types.Claim, values []string) []claimConflict {
+	conflicts := make([]claimConflict, 0, len(claims))
+	for _, claim := range claims {
+		conflicts = append(conflicts, claimConflict{
+			AgentID:   claim.AgentID,
+			ClaimType: claim.ClaimType,
+			Pattern:   claim.Pattern,
+			Reason:    claim.Reason,
+			Matches:   matchClaimPattern(claim.Pattern, values),
+		})
+	}
+	return conflicts
+}
+
+func matchClaimPattern(pattern string, values []string) []string {
+	matcher, err := glob.Compile(pattern)
+	if err != nil {
+		return []string{}
+	}
+	matched := []string{}
+	for _, value := range values {
+		if matcher.Match(value) {
+			matched = append(matched, value)
+		}
+	}
+	return matched
+}
+
+// notifyClaimConflicts sends a claim_conflict event to the user's notification
+// rules. Nothing is loaded unless a rule listens for claim conflicts.
+func notifyClaimConflicts(ctx *CommandContext, agentID string, conflicts []claimConflict) error {
+	notifier, err := notify.Load(notify.Options{})
+	if err != nil || !notifier.Wants(core.NotifyClaimConflict) {
+		return err
+	}
+	project := GetProjectName(ctx.Project.Root)
+	lines := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		lines = append(lines, fmt.Sprintf("@%s holds %s", conflict.AgentID, conflict.Pattern))
+	}
+	title := fmt.Sprintf("%s · %d claim conflict(s)", project, len(conflicts))
+	if agentID != "" {
+		title = fmt.Sprintf("%s · @%s: %d claim conflict(s)", project, agentID, len(conflicts))
+	}
+	return notifier.Notify(notify.Event{
+		Kinds:   []string{core.NotifyClaimConflict},
+		Project: project,
+		From:    conflicts[0].AgentID,
+		Title:   title,
+		Body:    strings.Join(lines, "\n"),
+	})
+}
+
+func printClaimConflicts(cmd *cobra.Command, conflicts []claimConflict, checked int) {
+	out := cmd.OutOrStdout()
+	if len(conflicts) == 0 {
+		fmt.Fprintf(out, "No claim conflicts (%d files checked)\n", checked)
+		return
+	}
+
+	byAgent := map[string][]claimConflict{}
+	for _, conflict := range conflicts {
+		byAgent[conflict.AgentID] = append(byAgent[conflict.AgentID], conflict)
+	}
+	agents := make([]string, 0, len(byAgent))
+	for agentID := range byAgent {
+		agents = append(agents, agentID)
+	}
+	sort.Strings(agents)
+
+	fmt.Fprintf(out, "CLAIM CONFLICTS (%d):\n", len(conflicts))
+	for _, agentID := range agents {
+		fmt.Fprintf(out, "\n  @%s:\n", agentID)
+		for _, conflict := range byAgent[agentID] {
+			typePrefix := ""
+			if conflict.ClaimType != types.ClaimTypeFile {
+				typePrefix = fmt.Sprintf("%s:", conflict.ClaimType)
+			}
+			for _, match := range conflict.Matches {
+				fmt.Fprintf(out, "    %s (claimed via %s%s)\n", match, typePrefix, conflict.Pattern)
+			}
+		}
+	}
+}
+
+func gitDiffFiles(root, diffRange string) ([]string, error) {
+	output, err := runGitCommand(root, "diff", "--name-only", diffRange)
+	if err != nil {
+		return nil, err
+	}
+	return hooks.SplitGitLines(output), nil
+}
@@ -38,6 +38,7 @@ func NewClearCmd() *cobra.Command {
 			file, _ := cmd.Flags().GetString("file")
 			bd, _ := cmd.Flags().GetString("bd")
 			issue, _ := cmd.Flags().GetString("issue")
+			branch, _ := cmd.Flags().GetString("branch")
 
 			cleared := int64(0)
 			clearedItems := []string{}
@@ -74,8 +75,18 @@ func NewClearCmd() *cobra.Command {
 					clearedItems = append(clearedItems, fmt.Sprintf("issue:%s", pattern))
 				}
 			}
+			if branch != "" {
+				deleted, err := db.DeleteClaim(ctx.DB, types.ClaimTypeBranch, branch)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if deleted {
+					cleared++
+					clearedItems = append(clearedItems, fmt.Sprintf("branch:%s", branch))
+				}
+			}
 
-			if file == "" && bd == "" && issue == "" {
+			if file == "" && bd == "" && issue == "" && branch == "" {
 				existing, err := db.GetClaimsByAgent(ctx.DB, agentID)
 				if err != nil {
 					return writeCommandError(cmd, err)
@@ -138,5 +149,6 @@ func NewClearCmd() *cobra.Command {
 	cmd.Flags().String("file", "", "clear a specific file claim")
 	cmd.Flags().String("bd", "", "clear a specific beads issue claim")
 	cmd.Flags().String("issue", "", "clear a specific GitHub issue claim")
+	cmd.Flags().String("branch", "", "clear a specific branch claim")
 	return cmd
 }
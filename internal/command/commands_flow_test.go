@@ -2,13 +2,19 @@ package command
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
 )
 
 func TestInitNewPostFlow(t *testing.T) {
@@ -377,6 +383,200 @@ func TestCrossThreadReplyAutoAdd(t *testing.T) {
 	}
 }
 
+func TestHandoffFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	for _, args := range [][]string{
+		{"init", "--defaults"},
+		{"new", "alice", "hello"},
+		{"new", "bob", "hello"},
+		{"new", "carol", "hello"},
+		{"thread", "auth"},
+		{"thread", "perms", "auth", "--owner", "alice"},
+		{"thread", "ops"},
+		{"claim", "@alice", "--file", "src/auth.go", "--bd", "fray-1"},
+	} {
+		p.run(args...)
+	}
+
+	// Only the sender or the thread's owner may hand off.
+	if _, err := executeCommand(NewRootCmd("test"), "handoff", "alice", "carol", "--thread", "auth", "--claims", "--as", "carol"); err == nil {
+		t.Fatal("expected carol to be refused handing off alice's work")
+	}
+
+	p.run("handoff", "alice", "bob", "--thread", "auth", "--claims", "--cursor", "refresh flow half done", "--as", "alice")
+
+	// An unowned thread keeps no owner.
+	if output := p.run("handoff", "bob", "alice", "--thread", "ops"); !strings.Contains(output, "no owner, not transferred") {
+		t.Fatalf("expected ops ownership to stay unset, got %s", output)
+	}
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+
+	claims, err := db.GetClaimsByAgent(dbConn, "bob")
+	if err != nil {
+		t.Fatalf("get claims: %v", err)
+	}
+	if len(claims) != 2 {
+		t.Fatalf("expected 2 claims transferred to bob, got %d", len(claims))
+	}
+
+	thread, err := db.GetThreadByName(dbConn, "auth", nil)
+	if err != nil || thread == nil {
+		t.Fatalf("get thread: %v", err)
+	}
+	if thread.OwnerAgent == nil || *thread.OwnerAgent != "bob" {
+		t.Fatalf("expected bob to own thread, got %v", thread.OwnerAgent)
+	}
+
+	cursor, err := db.GetGhostCursor(dbConn, "bob", thread.GUID)
+	if err != nil || cursor == nil {
+		t.Fatalf("expected ghost cursor for bob: %v", err)
+	}
+	if !cursor.MustRead {
+		t.Fatal("expected must-read ghost cursor")
+	}
+
+	handoff, err := db.GetHandoffByMessage(dbConn, cursor.MessageGUID)
+	if err != nil || handoff == nil {
+		t.Fatalf("expected handoff for message %s: %v", cursor.MessageGUID, err)
+	}
+	if handoff.FromAgent != "alice" || handoff.ToAgent != "bob" || !handoff.OwnerTransferred {
+		t.Fatalf("unexpected handoff: %#v", handoff)
+	}
+
+	handoffs, err := db.ReadHandoffs(projectDir)
+	if err != nil {
+		t.Fatalf("read handoffs: %v", err)
+	}
+	if len(handoffs) != 2 || len(handoffs[0].Claims) != 2 || handoffs[1].OwnerTransferred {
+		t.Fatalf("expected auth handoff with 2 claims and an ops handoff without ownership, got %#v", handoffs)
+	}
+
+	// Ownership and the cursor survive a rebuild.
+	if err := db.RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	thread, err = db.GetThread(dbConn, thread.GUID)
+	if err != nil || thread == nil || thread.OwnerAgent == nil || *thread.OwnerAgent != "bob" {
+		t.Fatalf("expected bob to own thread after rebuild, got %#v (err %v)", thread, err)
+	}
+	cursor, err = db.GetGhostCursor(dbConn, "bob", thread.GUID)
+	if err != nil || cursor == nil || !cursor.MustRead {
+		t.Fatalf("expected must-read cursor after rebuild: %#v (err %v)", cursor, err)
+	}
+
+	// A later ownership change survives rebuild.
+	p.run("thread", "perms", "auth", "--clear-owner")
+	if err := db.RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	thread, err = db.GetThread(dbConn, thread.GUID)
+	if err != nil || thread == nil || thread.OwnerAgent != nil {
+		t.Fatalf("expected no owner after clear-owner and rebuild, got %#v (err %v)", thread, err)
+	}
+}
+
+func TestThreadPermsFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	for _, args := range [][]string{
+		{"init", "--defaults"},
+		{"new", "alice", "hello"},
+		{"new", "bob", "hello"},
+		{"new", "carol", "hello"},
+		{"thread", "design"},
+		{"thread", "perms", "design", "--owner", "alice", "--add-writer", "bob"},
+	} {
+		p.run(args...)
+	}
+
+	p.run("post", "design", "from bob", "--as", "bob")
+
+	_, err := executeCommand(NewRootCmd("test"), "post", "design", "from carol", "--as", "carol")
+	var permErr *threadPermissionError
+	if !errors.As(err, &permErr) || permErr.AgentID != "carol" {
+		t.Fatalf("expected permission error for carol, got %v", err)
+	}
+
+	// Writers cannot curate an owned thread without curators.
+	if _, err := executeCommand(NewRootCmd("test"), "thread", "rename", "design", "design-v2", "--as", "bob"); !errors.As(err, &permErr) {
+		t.Fatalf("expected rename to be denied for bob, got %v", err)
+	}
+	for name, newCmd := range map[string]func() *cobra.Command{
+		"archive": NewThreadArchiveCmd,
+		"restore": NewThreadRestoreCmd,
+		"add":     NewThreadAddCmd,
+		"remove":  NewThreadRemoveCmd,
+	} {
+		args := []string{"design", "--as", "carol"}
+		if name == "add" || name == "remove" {
+			args = []string{"design", "msg-none", "--as", "carol"}
+		}
+		if _, err := executeCommand(newCmd(), args...); !errors.As(err, &permErr) {
+			t.Fatalf("expected thread %s to be denied for carol, got %v", name, err)
+		}
+	}
+	if _, err := executeCommand(NewRootCmd("test"), "thread", "perms", "design", "--add-curator", "bob", "--as", "bob"); !errors.As(err, &permErr) {
+		t.Fatalf("expected perms change to be denied for bob, got %v", err)
+	}
+	p.run("thread", "perms", "design", "--add-curator", "bob", "--as", "alice")
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+
+	// A curator edit keeps the author, records the editor, and drops the signature.
+	design, err := db.GetThreadByName(dbConn, "design", nil)
+	if err != nil || design == nil {
+		t.Fatalf("get thread: %v", err)
+	}
+	messages, err := db.GetThreadMessages(dbConn, design.GUID)
+	if err != nil {
+		t.Fatalf("get messages: %v", err)
+	}
+	var fromBob string
+	for _, msg := range messages {
+		if msg.Body == "from bob" {
+			fromBob = msg.ID
+		}
+	}
+	p.run("edit", fromBob, "tidied by alice", "--as", "alice")
+	edited, err := db.GetMessage(dbConn, fromBob)
+	if err != nil || edited == nil || edited.FromAgent != "bob" || edited.Signature != nil || edited.SignatureStatus == types.SignatureVerified {
+		t.Fatalf("unexpected curator-edited message: %#v (err %v)", edited, err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".fray", "messages.jsonl"))
+	if err != nil || !strings.Contains(string(data), `"edited_by":"alice"`) {
+		t.Fatalf("expected edited_by in message_update (%v)", err)
+	}
+
+	p.run("archive", "design", "--as", "bob")
+
+	// ACLs are replayed from threads.jsonl on rebuild.
+	if err := db.RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	thread, err := db.GetThreadByName(dbConn, "design", nil)
+	if err != nil || thread == nil {
+		t.Fatalf("get thread: %v", err)
+	}
+	if thread.OwnerAgent == nil || *thread.OwnerAgent != "alice" {
+		t.Fatalf("expected alice to own thread, got %v", thread.OwnerAgent)
+	}
+	if len(thread.Writers) != 1 || thread.Writers[0] != "bob" {
+		t.Fatalf("expected writers [bob], got %v", thread.Writers)
+	}
+	if len(thread.Curators) != 1 || thread.Curators[0] != "bob" {
+		t.Fatalf("expected curators [bob], got %v", thread.Curators)
+	}
+	if thread.Status != types.ThreadStatusArchived {
+		t.Fatalf("expected archived thread, got %s", thread.Status)
+	}
+}
+
 func openProjectDB(t *testing.T, projectDir string) *sql.DB {
 	t.Helper()
 
@@ -408,3 +608,758 @@ func findRoomMessageByBody(t *testing.T, dbConn *sql.DB, body string) string {
 	t.Fatalf("message not found: %s", body)
 	return ""
 }
+
+// flowProject is a temp project directory the test runs inside, with HOME
+// and FRAY_AGENT_ID isolated. Tests run "fray init" themselves.
+type flowProject struct {
+	t   *testing.T
+	Dir string
+}
+
+func newFlowProject(t *testing.T) *flowProject {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("FRAY_AGENT_ID", "")
+
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+	})
+	return &flowProject{t: t, Dir: dir}
+}
+
+// in returns a project in the named subdirectory, for tests that link
+// several projects together.
+func (p *flowProject) in(name string) *flowProject {
+	p.t.Helper()
+	dir := filepath.Join(p.Dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		p.t.Fatalf("mkdir: %v", err)
+	}
+	return &flowProject{t: p.t, Dir: dir}
+}
+
+// run executes a fray command from the project directory, failing the test
+// on error.
+func (p *flowProject) run(args ...string) string {
+	p.t.Helper()
+	if err := os.Chdir(p.Dir); err != nil {
+		p.t.Fatalf("chdir: %v", err)
+	}
+	output, err := executeCommand(NewRootCmd("test"), args...)
+	if err != nil {
+		p.t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return output
+}
+
+// post posts a message and returns its ID.
+func (p *flowProject) post(args ...string) string {
+	p.t.Helper()
+	var msg struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(p.run(append([]string{"post", "--json"}, args...)...)), &msg); err != nil {
+		p.t.Fatalf("decode post: %v", err)
+	}
+	return msg.ID
+}
+
+// git runs git in the project, skipping the test when git can't be used.
+func (p *flowProject) git(args ...string) {
+	p.t.Helper()
+	gitCmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	gitCmd.Dir = p.Dir
+	if output, err := gitCmd.CombinedOutput(); err != nil {
+		p.t.Skipf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+func TestLinkedProjectsFlow(t *testing.T) {
+	p := newFlowProject(t)
+	api := p.in("api")
+	web := p.in("web")
+
+	api.run("init", "--defaults")
+	api.run("new", "bob", "api online")
+	api.run("post", "--as", "bob", "@alice the api is ready")
+	web.run("init", "--defaults")
+	web.run("new", "alice", "web online")
+
+	web.run("link", "api", "../api")
+	var links []map[string]any
+	if err := json.Unmarshal([]byte(web.run("links", "--json")), &links); err != nil {
+		t.Fatalf("decode links: %v", err)
+	}
+	if len(links) != 1 || links[0]["alias"] != "api" || links[0]["channel"] != "api" {
+		t.Fatalf("unexpected links: %#v", links)
+	}
+
+	var got struct {
+		Channels     []string         `json:"channels"`
+		RoomMessages []channelMessage `json:"room_messages"`
+		Mentions     []channelMessage `json:"mentions"`
+	}
+	if err := json.Unmarshal([]byte(web.run("get", "--all-linked", "--as", "alice", "--hide-events", "--json")), &got); err != nil {
+		t.Fatalf("decode get: %v", err)
+	}
+	if strings.Join(got.Channels, ",") != "web,api" {
+		t.Fatalf("expected web and api channels, got %v", got.Channels)
+	}
+	fromAPI := 0
+	for _, msg := range got.RoomMessages {
+		if !strings.HasPrefix(msg.ID, msg.Channel+":msg-") {
+			t.Fatalf("expected channel-qualified id, got %q", msg.ID)
+		}
+		if msg.Channel == "api" {
+			fromAPI++
+		}
+	}
+	if fromAPI != 2 || len(got.RoomMessages) != 3 {
+		t.Fatalf("expected 2 api and 1 web room messages, got %#v", got.RoomMessages)
+	}
+
+	var here struct {
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(web.run("here", "--all-linked", "--json")), &here); err != nil {
+		t.Fatalf("decode here: %v", err)
+	}
+	if here.Total != 2 {
+		t.Fatalf("expected alice and bob across channels, got %d", here.Total)
+	}
+
+	api.run("ask", "ship it?", "--to", "alice", "--as", "bob")
+	var questions []map[string]any
+	if err := json.Unmarshal([]byte(web.run("questions", "--all-linked", "--json")), &questions); err != nil {
+		t.Fatalf("decode questions: %v", err)
+	}
+	if len(questions) != 1 || questions[0]["channel"] != "api" || !strings.HasPrefix(questions[0]["guid"].(string), "api:") {
+		t.Fatalf("unexpected linked questions: %#v", questions)
+	}
+
+	// Links live only in the cache, so rebuild must carry them over
+	web.run("rebuild")
+	if output := web.run("links"); !strings.Contains(output, "api") {
+		t.Fatalf("expected link to survive rebuild, got %q", output)
+	}
+
+	web.run("unlink", "api")
+	if output := web.run("links"); !strings.Contains(output, "No linked projects") {
+		t.Fatalf("expected no links after unlink, got %q", output)
+	}
+}
+
+func TestChannelMentionForwardingFlow(t *testing.T) {
+	p := newFlowProject(t)
+	api := p.in("api")
+	web := p.in("web")
+
+	readMessages := func(dir string) []db.MessageJSONLRecord {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, ".fray", "messages.jsonl"))
+		if err != nil {
+			t.Fatalf("read messages: %v", err)
+		}
+		var records []db.MessageJSONLRecord
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var record db.MessageJSONLRecord
+			if err := json.Unmarshal([]byte(line), &record); err == nil && record.Type == "message" {
+				records = append(records, record)
+			}
+		}
+		return records
+	}
+
+	api.run("init", "--defaults")
+	api.run("new", "bob", "api online")
+	web.run("init", "--defaults")
+	web.run("new", "alice", "web online")
+
+	var posted struct {
+		ID        string `json:"id"`
+		Forwarded []struct {
+			Channel string `json:"channel"`
+		} `json:"forwarded"`
+	}
+	if err := json.Unmarshal([]byte(web.run("post", "--as", "alice", "@API:bob can you check the schema?", "--json")), &posted); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if len(posted.Forwarded) != 1 || posted.Forwarded[0].Channel != "api" {
+		t.Fatalf("expected forward to api, got %+v", posted.Forwarded)
+	}
+
+	var local struct {
+		Forwarded []json.RawMessage `json:"forwarded"`
+	}
+	if err := json.Unmarshal([]byte(web.run("post", "--as", "alice", "staging is on @host:8080 and @db:postgres", "--json")), &local); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if len(local.Forwarded) != 0 {
+		t.Fatalf("expected no forward for unlinked channels, got %s", local.Forwarded)
+	}
+
+	var copyMsg *db.MessageJSONLRecord
+	for _, record := range readMessages(api.Dir) {
+		if record.FromAgent == "web:alice" {
+			copyMsg = &record
+		}
+	}
+	if copyMsg == nil {
+		t.Fatalf("expected linked copy in api channel")
+	}
+	if copyMsg.References == nil || *copyMsg.References != "web:"+posted.ID {
+		t.Fatalf("expected back-reference to web:%s, got %v", posted.ID, copyMsg.References)
+	}
+	if copyMsg.Body != "@bob can you check the schema?" || len(copyMsg.Mentions) != 1 || copyMsg.Mentions[0] != "bob" {
+		t.Fatalf("expected copy mentioning @bob, got %q %v", copyMsg.Body, copyMsg.Mentions)
+	}
+
+	output := web.run("surface", posted.ID, "@bob see thread", "--to", "api", "--as", "alice")
+	if !strings.Contains(output, "to #api as api:msg-") {
+		t.Fatalf("unexpected surface output: %s", output)
+	}
+	var surfaced *db.MessageJSONLRecord
+	for _, record := range readMessages(api.Dir) {
+		if record.MsgType == types.MessageTypeSurface {
+			surfaced = &record
+		}
+	}
+	if surfaced == nil || !strings.Contains(surfaced.Body, "> @alice in #web:") || *surfaced.References != "web:"+posted.ID {
+		t.Fatalf("expected quoted surface in api channel, got %+v", surfaced)
+	}
+	backlinked := false
+	for _, record := range readMessages(web.Dir) {
+		if record.SurfaceMessage != nil && *record.SurfaceMessage == "api:"+surfaced.ID {
+			backlinked = true
+		}
+	}
+	if !backlinked {
+		t.Fatalf("expected backlink event in web channel")
+	}
+}
+
+func TestAuditFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("post", "--as", "alice", "before the rename")
+	p.run("thread", "design")
+	p.run("archive", "design")
+
+	t.Setenv("FRAY_AGENT_ID", "admin")
+	p.run("rename", "alice", "ally")
+
+	var entries []db.AuditJSONLRecord
+	if err := json.Unmarshal([]byte(p.run("audit", "--actor", "admin", "--json")), &entries); err != nil {
+		t.Fatalf("decode audit: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != db.AuditAgentRename || entries[0].Target != "@alice" {
+		t.Fatalf("expected one rename by admin, got %+v", entries)
+	}
+	if entries[0].Before["agent_id"] != "alice" || entries[0].After["agent_id"] != "ally" {
+		t.Fatalf("unexpected before/after: %+v", entries[0])
+	}
+
+	output := p.run("audit", "--action", "thread")
+	if !strings.Contains(output, "thread.archive") || !strings.Contains(output, `status: "open" → "archived"`) {
+		t.Fatalf("expected archive entry, got:\n%s", output)
+	}
+
+	// A merge moves reactions too, and rebuild must replay that as well
+	dbConn := openProjectDB(t, projectDir)
+	renamedID := findRoomMessageByBody(t, dbConn, "before the rename")
+	dbConn.Close()
+	p.run("new", "carol", "hi")
+	p.run("react", "🎉", renamedID, "--as", "carol")
+	p.run("merge", "carol", "ally")
+
+	// Without an audit entry rebuild would undo a rename, so it must not happen
+	auditPath := filepath.Join(projectDir, ".fray", "audit.jsonl")
+	if err := os.Rename(auditPath, auditPath+".bak"); err != nil {
+		t.Fatalf("move audit log: %v", err)
+	}
+	if err := os.Mkdir(auditPath, 0o755); err != nil {
+		t.Fatalf("block audit log: %v", err)
+	}
+	if output, err := executeCommand(NewRootCmd("test"), "rename", "ally", "allie"); err == nil {
+		t.Fatalf("expected rename to fail without an audit log, got:\n%s", output)
+	}
+	_ = os.Remove(auditPath)
+	if err := os.Rename(auditPath+".bak", auditPath); err != nil {
+		t.Fatalf("restore audit log: %v", err)
+	}
+	dbConn = openProjectDB(t, projectDir)
+	if agent, _ := db.GetAgent(dbConn, "allie"); agent != nil {
+		t.Fatalf("expected rename to be refused, found @allie")
+	}
+	dbConn.Close()
+
+	// Rebuild twice: the rename must survive and replay must not repeat.
+	for i := 0; i < 2; i++ {
+		p.run("rebuild")
+		dbConn := openProjectDB(t, projectDir)
+		msg, err := db.GetMessage(dbConn, renamedID)
+		if err != nil {
+			t.Fatalf("get message: %v", err)
+		}
+		reactions, err := db.GetReactionsForMessage(dbConn, renamedID)
+		dbConn.Close()
+		if err != nil {
+			t.Fatalf("get reactions: %v", err)
+		}
+		if msg.FromAgent != "ally" {
+			t.Fatalf("rebuild %d: expected message from ally, got %s", i+1, msg.FromAgent)
+		}
+		if len(reactions["🎉"]) != 1 || reactions["🎉"][0].AgentID != "ally" {
+			t.Fatalf("rebuild %d: expected carol's reaction merged into ally, got %+v", i+1, reactions)
+		}
+	}
+
+	all, err := db.ReadAudit(filepath.Join(projectDir, ".fray"))
+	if err != nil {
+		t.Fatalf("read audit: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected rebuild not to write audit entries, got %d", len(all))
+	}
+
+	p.run("thread", "ops")
+	p.run("thread", "perms", "ops", "--add-writer", "ally")
+	output = p.run("audit", "--action", "thread.perms")
+	if !strings.Contains(output, "thread.perms") || !strings.Contains(output, "ally") {
+		t.Fatalf("expected perms entry, got:\n%s", output)
+	}
+
+	// Links only live in the cache, so rebuild restores them from the log
+	other := &flowProject{t: t, Dir: t.TempDir()}
+	other.run("init", "--defaults")
+	p.run("link", "other", other.Dir)
+	dbConn = openProjectDB(t, projectDir)
+	if _, err := dbConn.Exec("DELETE FROM fray_linked_projects"); err != nil {
+		t.Fatalf("clear links: %v", err)
+	}
+	dbConn.Close()
+	p.run("rebuild")
+	dbConn = openProjectDB(t, projectDir)
+	linked, err := db.GetLinkedProject(dbConn, "other")
+	dbConn.Close()
+	if err != nil || linked == nil {
+		t.Fatalf("expected rebuild to restore the link, got %+v (err %v)", linked, err)
+	}
+	p.run("unlink", "other")
+	p.run("rebuild")
+	dbConn = openProjectDB(t, projectDir)
+	linked, err = db.GetLinkedProject(dbConn, "other")
+	dbConn.Close()
+	if err != nil || linked != nil {
+		t.Fatalf("expected the unlink to survive rebuild, got %+v (err %v)", linked, err)
+	}
+	output = p.run("audit", "--action", "project")
+	if !strings.Contains(output, db.AuditProjectLink) || !strings.Contains(output, db.AuditProjectUnlink) {
+		t.Fatalf("expected link and unlink entries, got:\n%s", output)
+	}
+}
+
+func TestReactionMeaningsFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("new", "bob", "hello")
+	p.run("new", "carol", "hello")
+	p.run("ask", "target market?", "--as", "alice", "--to", "bob")
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+
+	questions, err := db.GetQuestions(dbConn, &types.QuestionQueryOptions{})
+	if err != nil || len(questions) != 1 || questions[0].AskedIn == nil {
+		t.Fatalf("expected one asked question, got %v (%v)", questions, err)
+	}
+	source := *questions[0].AskedIn
+
+	// 👀 acks the message for bob; carol's ack finds it taken.
+	output := p.run("react", "👀", source, "--as", "bob")
+	if !strings.Contains(output, "ack: acked for 30m") {
+		t.Fatalf("unexpected ack output: %s", output)
+	}
+	claim, err := db.GetClaim(dbConn, types.ClaimTypeMessage, source)
+	if err != nil || claim == nil || claim.AgentID != "bob" || claim.ExpiresAt == nil {
+		t.Fatalf("expected expiring ack claim for bob, got %+v (%v)", claim, err)
+	}
+	if output := p.run("react", "👀", source, "--as", "carol"); !strings.Contains(output, "already acked by @bob") {
+		t.Fatalf("expected carol to see bob's ack: %s", output)
+	}
+
+	// ✅ answers questions asked in the message.
+	var reacted struct {
+		Effect db.ReactionEffect `json:"effect"`
+	}
+	if err := json.Unmarshal([]byte(p.run("react", "✅", source, "--as", "bob", "--json")), &reacted); err != nil {
+		t.Fatalf("decode react: %v", err)
+	}
+	if len(reacted.Effect.Answered) != 1 || reacted.Effect.Answered[0] != questions[0].GUID {
+		t.Fatalf("expected question answered, got %+v", reacted.Effect)
+	}
+	updated, err := db.GetQuestion(dbConn, questions[0].GUID)
+	if err != nil || updated.Status != types.QuestionStatusAnswered {
+		t.Fatalf("expected answered status, got %+v (%v)", updated, err)
+	}
+
+	// Remapping turns the default off and gives another reaction the meaning.
+	p.run("reactions", "map", "👀", "none")
+	p.run("reactions", "map", "👍", "ack")
+	if output := p.run("react", "👀", source, "--as", "carol"); strings.Contains(output, "ack:") {
+		t.Fatalf("expected 👀 to have no meaning after remap: %s", output)
+	}
+	if output := p.run("reactions", "map"); !strings.Contains(output, "👍  ack") || strings.Contains(output, "👀") {
+		t.Fatalf("unexpected reaction table:\n%s", output)
+	}
+}
+
+func TestTaskFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("new", "bob", "hello")
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+
+	p.run("task", "add", "write the migration", "--as", "alice")
+	tasks, err := db.GetTasks(dbConn, nil)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected one task, got %v (%v)", tasks, err)
+	}
+	migration := tasks[0].GUID
+	p.run("task", "add", "deploy", "--as", "alice", "--to", "bob", "--after", migration)
+	tasks, _ = db.GetTasks(dbConn, nil)
+	deploy := tasks[1].GUID
+	if len(tasks[1].DependsOn) != 1 || tasks[1].DependsOn[0] != migration {
+		t.Fatalf("expected deploy to depend on migration, got %v", tasks[1].DependsOn)
+	}
+
+	// deploy waits on the migration; a dependency cycle is refused.
+	if output, err := executeCommand(NewRootCmd("test"), "task", "start", deploy, "--as", "bob"); err == nil {
+		t.Fatalf("expected start to be refused, got %s", output)
+	}
+	if output, err := executeCommand(NewRootCmd("test"), "task", "block", migration, "--on", deploy); err == nil {
+		t.Fatalf("expected cycle to be refused, got %s", output)
+	}
+
+	p.run("task", "start", migration, "--as", "alice")
+	p.run("task", "block", migration, "--reason", "waiting on creds")
+	output := p.run("tasks")
+	if !strings.Contains(output, "BLOCKED\n  @alice\n    "+migration+"  write the migration  (waiting on creds)") {
+		t.Fatalf("unexpected board:\n%s", output)
+	}
+	if !strings.Contains(output, "TODO\n  @bob\n    "+deploy+"  deploy  (after "+migration+")") {
+		t.Fatalf("unexpected board:\n%s", output)
+	}
+
+	output = p.run("task", "done", migration)
+	if !strings.Contains(output, "ready: "+deploy) {
+		t.Fatalf("expected deploy to be ready: %s", output)
+	}
+	p.run("task", "start", deploy, "--as", "bob")
+
+	// State is replayed from tasks.jsonl on rebuild.
+	if err := db.RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	first, err := db.GetTask(dbConn, migration)
+	if err != nil || first == nil || first.Status != types.TaskStatusDone || first.BlockedReason != nil {
+		t.Fatalf("expected migration done after rebuild, got %+v (%v)", first, err)
+	}
+	second, err := db.GetTask(dbConn, deploy)
+	if err != nil || second == nil || second.Status != types.TaskStatusInProgress || second.Assignee == nil || *second.Assignee != "bob" {
+		t.Fatalf("expected deploy in progress for bob after rebuild, got %+v (%v)", second, err)
+	}
+
+	output = p.run("tasks", "@alice")
+	if output != "No tasks\n" {
+		t.Fatalf("expected alice's board empty without --all, got:\n%s", output)
+	}
+	if output := p.run("tasks", "--all"); !strings.Contains(output, "DONE\n  @alice\n    "+migration) {
+		t.Fatalf("expected done section with --all:\n%s", output)
+	}
+}
+
+func TestThreadSummaryFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("thread", "design")
+	p.run("post", "design", "use sqlite", "--as", "alice")
+	p.run("post", "design", "agreed", "--as", "alice")
+
+	// Counts transcript message lines and echoes the thread it was given.
+	p.run("thread", "summarizer", "--command", `echo "$(grep -c '^\[' ) messages in $FRAY_THREAD ($FRAY_SUMMARY_TRIGGER)"`)
+
+	output := p.run("archive", "design")
+	if !strings.Contains(output, "anchored ") || !strings.Contains(output, "summary of 2 messages") {
+		t.Fatalf("expected archive to anchor a summary: %s", output)
+	}
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+
+	thread, err := db.GetThreadByName(dbConn, "design", nil)
+	if err != nil || thread == nil || thread.AnchorMessageGUID == nil {
+		t.Fatalf("expected anchored thread, got %+v (%v)", thread, err)
+	}
+	anchor, err := db.GetMessage(dbConn, *thread.AnchorMessageGUID)
+	if err != nil || anchor == nil || anchor.Body != "2 messages in design (archive)" {
+		t.Fatalf("unexpected anchor: %+v (%v)", anchor, err)
+	}
+
+	// Provenance survives a rebuild.
+	if err := db.RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	summary, err := db.GetThreadSummaryByMessage(dbConn, anchor.ID)
+	if err != nil || summary == nil || summary.Source != "command" || summary.Trigger != "archive" || summary.MessageCount != 2 {
+		t.Fatalf("unexpected summary after rebuild: %+v (%v)", summary, err)
+	}
+
+	// Prune summarizes what it removes from each thread, folding in the
+	// previous anchor.
+	p.run("restore", "design")
+	for i := 0; i < 3; i++ {
+		p.run("post", "design", fmt.Sprintf("follow-up %d", i), "--as", "alice")
+	}
+	p.run("post", "--as", "alice", "room chatter")
+	p.git("init", "-q")
+	p.git("add", ".fray")
+	p.git("commit", "-q", "-m", "fray")
+
+	output = p.run("prune", "--keep", "1")
+	if !strings.Contains(output, "summary of 5 messages") || !strings.Contains(output, "on prune") {
+		t.Fatalf("expected prune to summarize design: %s", output)
+	}
+	thread, _ = db.GetThread(dbConn, thread.GUID)
+	anchor, err = db.GetMessage(dbConn, *thread.AnchorMessageGUID)
+	if err != nil || anchor == nil || anchor.Body != "5 messages in design (prune)" {
+		t.Fatalf("unexpected anchor after prune: %+v (%v)", anchor, err)
+	}
+	summaries, err := db.GetThreadSummaries(dbConn, thread.GUID)
+	if err != nil || len(summaries) != 2 {
+		t.Fatalf("expected two summaries, got %v (%v)", summaries, err)
+	}
+}
+
+func TestPrunePolicyFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("post", "--as", "alice", "r1")
+	p.run("post", "--as", "alice", "r2")
+	p.run("post", "--as", "alice", "r3")
+	p.run("thread", "keep")
+	p.run("post", "keep", "k1", "--as", "alice")
+	p.run("thread", "scratch")
+	p.run("post", "scratch", "old scratch", "--as", "alice")
+	p.run("post", "scratch", "new scratch", "--as", "alice")
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+	r1 := findRoomMessageByBody(t, dbConn, "r1")
+	p.run("fave", r1, "--as", "alice")
+	// r2 lives in the room but was added to the forever thread
+	p.run("add", "keep", findRoomMessageByBody(t, dbConn, "r2"))
+
+	// Age one scratch message past the rule's max_age.
+	messagesPath := filepath.Join(projectDir, ".fray", "messages.jsonl")
+	data, err := os.ReadFile(messagesPath)
+	if err != nil {
+		t.Fatalf("read messages: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record["body"] != "old scratch" {
+			continue
+		}
+		record["ts"] = record["ts"].(float64) - 3*86400
+		updated, _ := json.Marshal(record)
+		lines[i] = string(updated)
+	}
+	if err := os.WriteFile(messagesPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write messages: %v", err)
+	}
+
+	if _, err := db.UpdateProjectConfig(projectDir, db.ProjectConfig{Retention: &core.RetentionConfig{
+		Rules: []core.RetentionRule{
+			{Thread: "keep", Forever: true},
+			{Thread: "room", Keep: 1, Exempt: []string{core.RetentionExemptFaved}},
+			{MaxAge: "1d"},
+		},
+	}}); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	output := p.run("prune", "--policy", "--dry-run")
+	for _, want := range []string{"rule 1 (forever)              keep 2", "keep 1", "prune 2 of 5 (1 exempt)", "prune 1 of 2", "Would prune 3 messages"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("dry run missing %q:\n%s", want, output)
+		}
+	}
+	if after, _ := os.ReadFile(messagesPath); string(after) != strings.Join(lines, "\n")+"\n" {
+		t.Fatalf("dry run changed messages.jsonl")
+	}
+
+	p.git("init", "-q")
+	p.git("add", ".fray")
+	p.git("commit", "-q", "-m", "fray")
+
+	output = p.run("prune", "--policy")
+	if !strings.Contains(output, "Pruned 3 messages") {
+		t.Fatalf("unexpected prune output:\n%s", output)
+	}
+
+	messages, err := db.ReadMessages(projectDir)
+	if err != nil {
+		t.Fatalf("read messages: %v", err)
+	}
+	var bodies []string
+	for _, msg := range messages {
+		bodies = append(bodies, msg.Body)
+	}
+	if got := strings.Join(bodies, ","); got != "r1,r2,r3,k1,new scratch" {
+		t.Fatalf("unexpected kept messages: %s", got)
+	}
+	history, err := os.ReadFile(filepath.Join(projectDir, ".fray", "history.jsonl"))
+	if err != nil || !strings.Contains(string(history), "old scratch") {
+		t.Fatalf("expected pruned messages in history.jsonl (%v)", err)
+	}
+
+	// The daemon's scheduled runs go ahead with uncommitted changes in .fray
+	if output, err := executeCommand(NewRootCmd("test"), "prune", "--policy"); err == nil || !strings.Contains(output, "uncommitted changes") {
+		t.Fatalf("expected guardrail failure, got %v:\n%s", err, output)
+	}
+	if output := p.run("prune", "--policy", "--scheduled"); !strings.Contains(output, "Nothing to prune") {
+		t.Fatalf("unexpected scheduled prune output:\n%s", output)
+	}
+}
+
+func TestHistoryRestoreFlow(t *testing.T) {
+	p := newFlowProject(t)
+	projectDir := p.Dir
+
+	p.run("init", "--defaults")
+	p.run("new", "alice", "hello")
+	p.run("new", "bob", "hello")
+	p.run("new", "carol", "hello")
+	p.run("thread", "design")
+	d1 := p.post("design", "d1 schema draft", "--as", "alice")
+	d2 := p.post("design", "d2 migration plan with rollback steps for the schema change", "--as", "alice", "--reply-to", d1)
+	p.post("design", "d3 latest", "--as", "alice")
+	p.run("react", "👍", d1, "--as", "alice")
+	p.run("pin", d1)
+
+	if _, err := db.UpdateProjectConfig(projectDir, db.ProjectConfig{Retention: &core.RetentionConfig{
+		Rules: []core.RetentionRule{{Thread: "design", Keep: 1, Exempt: []string{core.RetentionExemptFaved}}},
+	}}); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	p.git("init", "-q")
+	p.git("add", ".fray")
+	p.git("commit", "-q", "-m", "fray")
+
+	if output := p.run("prune", "--policy"); !strings.Contains(output, "Pruned 2 messages") {
+		t.Fatalf("unexpected prune output:\n%s", output)
+	}
+
+	output := p.run("history")
+	if !strings.Contains(output, "d1 schema draft") || !strings.Contains(output, "d2 migration plan") {
+		t.Fatalf("history missing pruned messages:\n%s", output)
+	}
+	if strings.Contains(output, "d3 latest") || strings.Contains(output, "hello") {
+		t.Fatalf("history shows live messages:\n%s", output)
+	}
+	if output := p.run("history", "--search", "MIGRATION"); strings.Contains(output, "d1 schema draft") || !strings.Contains(output, "d2 migration plan") {
+		t.Fatalf("unexpected search output:\n%s", output)
+	}
+	var archived []types.Message
+	if err := json.Unmarshal([]byte(p.run("history", "--thread", "design", "--json")), &archived); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(archived) != 2 || archived[0].ID != d1 || len(archived[0].Reactions["👍"]) != 1 {
+		t.Fatalf("unexpected archived messages: %+v", archived)
+	}
+
+	if _, err := executeCommand(NewRootCmd("test"), "restore-messages", d2); err == nil {
+		t.Fatalf("expected restore to require committed .fray/")
+	}
+	p.git("add", ".fray")
+	p.git("commit", "-q", "-m", "prune")
+
+	// Archived messages keep their thread's ACL.
+	p.run("thread", "perms", "design", "--owner", "alice", "--add-reader", "bob")
+	p.git("add", ".fray")
+	p.git("commit", "-q", "-m", "perms")
+	var permErr *threadPermissionError
+	t.Setenv("FRAY_AGENT_ID", "carol")
+	if output := p.run("history"); !strings.Contains(output, "No archived messages") {
+		t.Fatalf("expected carol to see no archived design messages:\n%s", output)
+	}
+	if _, err := executeCommand(NewRootCmd("test"), "history", "--thread", "design"); !errors.As(err, &permErr) {
+		t.Fatalf("expected history --thread to be denied for carol, got %v", err)
+	}
+	t.Setenv("FRAY_AGENT_ID", "bob")
+	if output := p.run("history"); !strings.Contains(output, "d2 migration plan") {
+		t.Fatalf("expected bob to read archived design messages:\n%s", output)
+	}
+	if _, err := executeCommand(NewRootCmd("test"), "restore-messages", d2); !errors.As(err, &permErr) || permErr.Action != threadActionCurate {
+		t.Fatalf("expected restore to need curation for bob, got %v", err)
+	}
+	t.Setenv("FRAY_AGENT_ID", "")
+
+	if output := p.run("restore-messages", d2); !strings.Contains(output, "Restored 2 messages (including reply parents)") {
+		t.Fatalf("unexpected restore output:\n%s", output)
+	}
+
+	messages, err := db.ReadMessages(projectDir)
+	if err != nil {
+		t.Fatalf("read messages: %v", err)
+	}
+	restoredIDs := map[string]bool{}
+	for _, msg := range messages {
+		restoredIDs[msg.ID] = true
+	}
+	if len(messages) != 9 || !restoredIDs[d1] || !restoredIDs[d2] {
+		t.Fatalf("unexpected messages after restore: %+v", messages)
+	}
+
+	dbConn := openProjectDB(t, projectDir)
+	defer dbConn.Close()
+	reactions, err := db.GetReactionsForMessage(dbConn, d1)
+	if err != nil || len(reactions["👍"]) != 1 {
+		t.Fatalf("expected restored reaction, got %v (%v)", reactions, err)
+	}
+	pins, err := db.ReadMessagePins(projectDir)
+	if err != nil || len(pins) != 1 || pins[0].MessageGUID != d1 {
+		t.Fatalf("expected restored pin, got %+v (%v)", pins, err)
+	}
+	if output := p.run("history"); !strings.Contains(output, "No archived messages") {
+		t.Fatalf("expected empty history after restore:\n%s", output)
+	}
+}
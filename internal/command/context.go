@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
@@ -74,6 +75,7 @@ func GetContext(cmd *cobra.Command) (*CommandContext, error) {
 			_ = linkedDB.Close()
 			return nil, err
 		}
+		deliverScheduled(linkedDB, project)
 
 		return &CommandContext{
 			DB:            linkedDB,
@@ -96,6 +98,7 @@ func GetContext(cmd *cobra.Command) (*CommandContext, error) {
 		_ = conn.Close()
 		return nil, err
 	}
+	deliverScheduled(conn, ctx.Project)
 
 	return &CommandContext{
 		DB:            conn,
@@ -108,6 +111,12 @@ func GetContext(cmd *cobra.Command) (*CommandContext, error) {
 	}, nil
 }
 
+// deliverScheduled posts scheduled messages that came due while no daemon was
+// running. Failures are left for the next invocation to retry.
+func deliverScheduled(conn *sql.DB, project core.Project) {
+	_, _ = db.DeliverDueScheduled(conn, project.DBPath, time.Now())
+}
+
 // ChannelContext describes resolved channel references.
 type ChannelContext struct {
 	Project       core.Project
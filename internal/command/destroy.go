@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
 	"github.com/spf13/cobra"
 )
 
@@ -73,6 +74,16 @@ func NewDestroyCmd() *cobra.Command {
 				return writeCommandError(cmd, err)
 			}
 
+			// The channel's own audit log is gone with it
+			if _, err := db.AppendGlobalAudit(db.AuditJSONLRecord{
+				Actor:  auditActor(cmd, nil),
+				Action: "channel.destroy",
+				Target: "#" + channel.Name,
+				Before: map[string]any{"channel_id": id, "path": channel.Path},
+			}); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not write audit log: %v\n", err)
+			}
+
 			if jsonMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(destroyResult{
 					ID:      id,
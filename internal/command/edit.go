@@ -53,7 +53,23 @@ func NewEditCmd() *cobra.Command {
 			msgID := msg.ID
 			newBody := strings.Join(args[1:], " ")
 
-			if err := db.EditMessage(ctx.DB, msgID, newBody, agentID); err != nil {
+			// Thread owners and curators may edit other agents' messages. The
+			// author stays the author; the edit records who made it.
+			curatorEdit := false
+			if msg.FromAgent != agentID && msg.Home != "" && msg.Home != "room" {
+				thread, err := db.GetThread(ctx.DB, msg.Home)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				curatorEdit = isThreadCurator(thread, agentID)
+			}
+
+			if curatorEdit {
+				err = db.EditMessageAsCurator(ctx.DB, msgID, newBody)
+			} else {
+				err = db.EditMessage(ctx.DB, msgID, newBody, agentID)
+			}
+			if err != nil {
 				return writeCommandError(cmd, err)
 			}
 
@@ -65,15 +81,22 @@ func NewEditCmd() *cobra.Command {
 				return writeCommandError(cmd, fmt.Errorf("message %s not found", msgID))
 			}
 
-			update := db.MessageUpdateJSONLRecord{ID: updated.ID, EditedAt: updated.EditedAt}
+			update := db.MessageUpdateJSONLRecord{ID: updated.ID, EditedAt: updated.EditedAt, Signature: updated.Signature}
 			if reason != "" {
 				update.Reason = &reason
 			}
+			if curatorEdit {
+				update.EditedBy = &agentID
+			}
 			body := updated.Body
 			update.Body = &body
 			if err := db.AppendMessageUpdate(ctx.Project.DBPath, update); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			if curatorEdit {
+				recordAudit(cmd, ctx, "message.edit", updated.ID,
+					map[string]any{"from": msg.FromAgent, "home": msg.Home, "body": msg.Body}, map[string]any{"body": updated.Body, "edited_by": agentID})
+			}
 
 			totalCount, err := getTotalMessageCount(ctx.DB)
 			if err != nil {
@@ -108,9 +131,16 @@ func NewEditCmd() *cobra.Command {
 
 			if ctx.JSONMode {
 				payload := map[string]any{"id": updated.ID, "edited": true}
+				if curatorEdit {
+					payload["edited_by"] = agentID
+				}
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
 			}
 
+			if curatorEdit {
+				fmt.Fprintf(cmd.OutOrStdout(), "Edited @%s's message #%s as curator\n", updated.FromAgent, updated.ID)
+				return nil
+			}
 			fmt.Fprintf(cmd.OutOrStdout(), "Edited message #%s\n", updated.ID)
 			return nil
 		},
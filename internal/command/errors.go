@@ -1,6 +1,8 @@
 package command
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,6 +10,13 @@ import (
 )
 
 func writeCommandError(cmd *cobra.Command, err error) error {
+	// With --json, report the error as JSON too, like init does.
+	if jsonMode, _ := cmd.Flags().GetBool("json"); jsonMode {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintln(cmd.ErrOrStderr(), string(data))
+		return err
+	}
+
 	fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err.Error())
 
 	// Check for schema errors and suggest rebuild
@@ -15,6 +24,11 @@ func writeCommandError(cmd *cobra.Command, err error) error {
 		fmt.Fprintln(cmd.ErrOrStderr(), "Hint: This looks like a schema mismatch. Try: fray rebuild")
 	}
 
+	var permErr *threadPermissionError
+	if errors.As(err, &permErr) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Hint: See who may do what with: fray thread perms %s\n", permErr.Thread)
+	}
+
 	return err
 }
 
@@ -28,3 +42,23 @@ func isSchemaError(err error) bool {
 		strings.Contains(msg, "no such table") ||
 		strings.Contains(msg, "has no column")
 }
+
+// threadPermissionError reports an action blocked by a thread's ACL.
+type threadPermissionError struct {
+	AgentID string
+	Action  threadAction
+	Thread  string
+	Allowed []string
+}
+
+func (e *threadPermissionError) Error() string {
+	msg := fmt.Sprintf("@%s cannot %s thread %s", e.AgentID, e.Action, e.Thread)
+	if len(e.Allowed) == 0 {
+		return msg
+	}
+	mentions := make([]string, len(e.Allowed))
+	for i, agentID := range e.Allowed {
+		mentions[i] = "@" + agentID
+	}
+	return fmt.Sprintf("%s (allowed: %s)", msg, strings.Join(mentions, ", "))
+}
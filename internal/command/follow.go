@@ -328,6 +328,13 @@ Examples:
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			addedBy := "system"
 			if asRef, _ := cmd.Flags().GetString("as"); asRef != "" {
@@ -415,6 +422,13 @@ Examples:
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			removedBy := "system"
 			if asRef, _ := cmd.Flags().GetString("as"); asRef != "" {
@@ -478,11 +492,13 @@ func NewArchiveCmd() *cobra.Command {
 		Short: "Archive a thread",
 		Long: `Archive a thread.
 
-Accepts thread GUID, name, or path.
+Accepts thread GUID, name, or path. If a summarizer is configured (see fray
+thread summarizer), the thread is summarized into its anchor first.
 
 Examples:
   fray archive design-thread
-  fray archive opus/notes --as opus`,
+  fray archive opus/notes --as opus
+  fray archive scratch --no-summary`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return updateThreadStatusTopLevel(cmd, args[0], "archived")
@@ -490,6 +506,7 @@ Examples:
 	}
 
 	cmd.Flags().String("as", "", "agent performing the archive (for attribution)")
+	cmd.Flags().Bool("no-summary", false, "skip the configured thread summarizer")
 
 	return cmd
 }
@@ -529,6 +546,19 @@ func updateThreadStatusTopLevel(cmd *cobra.Command, ref string, status string) e
 	if err != nil {
 		return writeCommandError(cmd, err)
 	}
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	// Summarize before archiving so context is compressed rather than lost
+	var summary *types.ThreadSummary
+	if status == string(types.ThreadStatusArchived) && thread.Status != types.ThreadStatusArchived {
+		summary = summarizeOnArchive(cmd, ctx, thread)
+	}
 
 	updated, err := db.UpdateThread(ctx.DB, thread.GUID, db.ThreadUpdates{
 		Status: types.OptionalString{Set: true, Value: &status},
@@ -543,6 +573,7 @@ func updateThreadStatusTopLevel(cmd *cobra.Command, ref string, status string) e
 	}); err != nil {
 		return writeCommandError(cmd, err)
 	}
+	recordThreadStatusAudit(cmd, ctx, thread, status)
 
 	if ctx.JSONMode {
 		return json.NewEncoder(cmd.OutOrStdout()).Encode(updated)
@@ -553,6 +584,9 @@ func updateThreadStatusTopLevel(cmd *cobra.Command, ref string, status string) e
 		path = thread.GUID
 	}
 	fmt.Fprintf(cmd.OutOrStdout(), "Thread %s set to %s\n", path, updated.Status)
+	if summary != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "  anchored %s: %s\n", summary.MessageGUID, formatSummaryProvenance(*summary))
+	}
 	return nil
 }
 
@@ -12,6 +12,8 @@ import (
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
 )
 
 const maxDisplayLines = 60
@@ -35,6 +37,13 @@ var (
 	cyan      = ansiCode("\x1b[36m")
 )
 
+// MarkdownRender controls markdown rendering of message bodies. The zero value
+// prints bodies as written, which is what pipes and agents should get.
+type MarkdownRender struct {
+	Enabled bool
+	Width   int // wrap width; 0 leaves lines unwrapped
+}
+
 var colorPairs = []struct {
 	Bright string
 	Dim    string
@@ -58,25 +67,28 @@ func GetProjectName(projectRoot string) string {
 }
 
 // FormatMessage formats a message for display.
-func FormatMessage(msg types.Message, projectName string, agentBases map[string]struct{}) string {
-	return formatMessageWithOptions(msg, projectName, agentBases, true, nil)
+func FormatMessage(msg types.Message, projectName string, agentBases map[string]struct{}, render MarkdownRender) string {
+	return formatMessageWithOptions(msg, projectName, agentBases, true, nil, render)
 }
 
 // FormatMessageFull formats a message without truncation (for anchors).
-func FormatMessageFull(msg types.Message, projectName string, agentBases map[string]struct{}) string {
-	return formatMessageWithOptions(msg, projectName, agentBases, false, nil)
+func FormatMessageFull(msg types.Message, projectName string, agentBases map[string]struct{}, render MarkdownRender) string {
+	return formatMessageWithOptions(msg, projectName, agentBases, false, nil, render)
 }
 
 // FormatMessageWithQuote formats a message with an optional quoted message for inline display.
-func FormatMessageWithQuote(msg types.Message, projectName string, agentBases map[string]struct{}, quotedMsg *types.Message) string {
-	return formatMessageWithOptions(msg, projectName, agentBases, true, quotedMsg)
+func FormatMessageWithQuote(msg types.Message, projectName string, agentBases map[string]struct{}, quotedMsg *types.Message, render MarkdownRender) string {
+	return formatMessageWithOptions(msg, projectName, agentBases, true, quotedMsg, render)
 }
 
-func formatMessageWithOptions(msg types.Message, projectName string, agentBases map[string]struct{}, truncate bool, quotedMsg *types.Message) string {
+func formatMessageWithOptions(msg types.Message, projectName string, agentBases map[string]struct{}, truncate bool, quotedMsg *types.Message, render MarkdownRender) string {
 	editedSuffix := ""
 	if msg.Edited || msg.EditCount > 0 || msg.EditedAt != nil {
 		editedSuffix = " (edited)"
 	}
+	if label := core.SignatureLabel(msg.SignatureStatus); label != "" {
+		editedSuffix += " " + label
+	}
 	idBlock := fmt.Sprintf("%s[%s#%s%s %s]%s", dim, bold, projectName, reset, dim+msg.ID+editedSuffix, reset)
 
 	// Check for answer message format
@@ -89,6 +101,9 @@ func formatMessageWithOptions(msg types.Message, projectName string, agentBases
 	if truncate {
 		displayBody = truncateForDisplay(msg.Body, msg.ID)
 	}
+	if render.Enabled {
+		displayBody = core.RenderMarkdown(displayBody, core.MarkdownOptions{Width: render.Width, Color: !noColor})
+	}
 
 	// Format quote block if present
 	quoteBlock := ""
@@ -101,6 +116,7 @@ func formatMessageWithOptions(msg types.Message, projectName string, agentBases
 	if reactionSuffix != "" {
 		reactionSuffix = " " + gray + "(" + reactionSuffix + ")" + reset
 	}
+	reactionSuffix += formatAttachmentChips(msg.Attachments)
 
 	if color != "" {
 		coloredBody := colorizeBody(displayBody, color, agentBases)
@@ -365,6 +381,25 @@ func colorizeBody(body, senderColor string, agentBases map[string]struct{}) stri
 	return out.String()
 }
 
+// markdownFor decides body rendering for a command's output: rendered on a
+// terminal unless --raw is set, as written when piped.
+func markdownFor(cmd *cobra.Command) MarkdownRender {
+	if cmd.Flags().Lookup("raw") != nil {
+		if raw, _ := cmd.Flags().GetBool("raw"); raw {
+			return MarkdownRender{}
+		}
+	}
+	file, ok := cmd.OutOrStdout().(*os.File)
+	if !ok || !term.IsTerminal(file.Fd()) {
+		return MarkdownRender{}
+	}
+	render := MarkdownRender{Enabled: true}
+	if width, _, err := term.GetSize(file.Fd()); err == nil {
+		render.Width = width
+	}
+	return render
+}
+
 func truncateForDisplay(body, msgID string) string {
 	lines := strings.Split(body, "\n")
 	if len(lines) <= maxDisplayLines {
@@ -409,6 +444,7 @@ type AccordionOptions struct {
 	ProjectName  string
 	AgentBases   map[string]struct{}
 	QuotedMsgs   map[string]*types.Message // Map of message ID -> quoted message for inline display
+	Markdown     MarkdownRender
 }
 
 // FormatMessageListAccordion formats a list of messages with accordion collapsing.
@@ -437,7 +473,7 @@ func FormatMessageListAccordion(messages []types.Message, opts AccordionOptions)
 		if msg.QuoteMessageGUID != nil && opts.QuotedMsgs != nil {
 			quotedMsg = opts.QuotedMsgs[*msg.QuoteMessageGUID]
 		}
-		return formatMessageWithOptions(msg, opts.ProjectName, opts.AgentBases, true, quotedMsg)
+		return formatMessageWithOptions(msg, opts.ProjectName, opts.AgentBases, true, quotedMsg, opts.Markdown)
 	}
 
 	// If ShowAll or under threshold, format all messages normally
@@ -506,3 +542,12 @@ func formatReactionSummary(reactions map[string][]types.ReactionEntry) string {
 	}
 	return strings.Join(parts, " · ")
 }
+
+// formatAttachmentChips renders one dim chip line per attachment.
+func formatAttachmentChips(attachments []types.Attachment) string {
+	var builder strings.Builder
+	for _, attachment := range attachments {
+		builder.WriteString("\n  " + dim + "[📎 " + core.AttachmentLabel(attachment) + "]" + reset)
+	}
+	return builder.String()
+}
@@ -19,7 +19,7 @@ func TestFormatMessageColors(t *testing.T) {
 		Type:      types.MessageTypeAgent,
 	}
 	bases := map[string]struct{}{"alice": {}, "bob": {}}
-	output := FormatMessage(msg, "demo", bases)
+	output := FormatMessage(msg, "demo", bases, MarkdownRender{})
 
 	if !strings.Contains(output, "\x1b[") {
 		t.Fatalf("expected ANSI output, got %q", output)
@@ -42,7 +42,7 @@ func TestFormatMessageTruncation(t *testing.T) {
 		Body:      body,
 		Type:      types.MessageTypeAgent,
 	}
-	output := FormatMessage(msg, "demo", nil)
+	output := FormatMessage(msg, "demo", nil, MarkdownRender{})
 	if !strings.Contains(output, "fray get "+msg.ID) {
 		t.Fatalf("expected truncation hint, got %q", output)
 	}
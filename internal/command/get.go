@@ -3,6 +3,8 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +29,10 @@ Paths:
   fray get design-thread      Specific thread by name
   fray get notifs             Notifications only (@mentions + followed threads)
   fray get msg-abc            Specific message (shorthand: fray msg-abc)
+  fray get msg-abc --attachment build.log   Print an attachment
+
+Linked projects:
+  fray get --all-linked --as alice   Room + @mentions across linked projects
 
 Legacy (deprecated):
   fray get <agent>            Still works for agent-based room + mentions`,
@@ -52,10 +58,19 @@ Legacy (deprecated):
 			showEvents, _ := cmd.Flags().GetBool("show-events")
 			showAllMessages, _ := cmd.Flags().GetBool("show-all")
 			asRef, _ := cmd.Flags().GetString("as")
+			allLinked, _ := cmd.Flags().GetBool("all-linked")
+			render := markdownFor(cmd)
 			if showEvents {
 				hideEvents = false
 			}
 
+			if allLinked {
+				if len(args) > 0 {
+					return writeCommandError(cmd, fmt.Errorf("--all-linked reads rooms across projects; it does not take a path"))
+				}
+				return getAllLinked(cmd, ctx, last, room, mentions, asRef, hideEvents, showAllMessages)
+			}
+
 			projectName := GetProjectName(ctx.Project.Root)
 			var agentBases map[string]struct{}
 			if !ctx.JSONMode {
@@ -80,6 +95,13 @@ Legacy (deprecated):
 			if target != "" && !strings.HasPrefix(target, "msg-") {
 				thread, err := resolveThreadRef(ctx.DB, target)
 				if err == nil && thread != nil {
+					reader, err := resolveActingAgent(cmd, ctx)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					if err := checkThreadPermission(ctx, thread, reader, threadActionRead); err != nil {
+						return writeCommandError(cmd, err)
+					}
 					pinnedOnly, _ := cmd.Flags().GetBool("pinned")
 					byAgent, _ := cmd.Flags().GetString("by")
 					withText, _ := cmd.Flags().GetString("with")
@@ -192,6 +214,7 @@ Legacy (deprecated):
 					ShowAll:     showAllMessages,
 					ProjectName: projectName,
 					AgentBases:  agentBases,
+					Markdown:    render,
 				})
 				for _, line := range lines {
 					fmt.Fprintln(out, line)
@@ -366,6 +389,7 @@ Legacy (deprecated):
 						ShowAll:     showAllMessages,
 						ProjectName: projectName,
 						AgentBases:  agentBases,
+						Markdown:    render,
 					})
 					for _, line := range lines {
 						fmt.Fprintln(out, line)
@@ -396,7 +420,7 @@ Legacy (deprecated):
 					if len(direct) > 0 {
 						fmt.Fprintf(out, "Recent @%s:\n", agentBase)
 						for _, msg := range direct {
-							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 							for _, reactionLine := range formatReactionEvents(msg) {
 								fmt.Fprintf(out, "  %s\n", reactionLine)
 							}
@@ -409,7 +433,7 @@ Legacy (deprecated):
 						}
 						fmt.Fprintln(out, "You were FYI'd here:")
 						for _, msg := range fyi {
-							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 						}
 					}
 
@@ -487,6 +511,9 @@ Legacy (deprecated):
 	cmd.Flags().Bool("show-all", false, "disable accordion, show all messages fully")
 	cmd.Flags().String("as", "", "agent identity (uses FRAY_AGENT_ID if not set)")
 	cmd.Flags().Bool("replies", false, "show message with reply chain")
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
+	cmd.Flags().String("attachment", "", "print a message attachment by name (or hash prefix)")
+	cmd.Flags().Bool("all-linked", false, "read the room and @mentions across this and all linked projects")
 
 	// Within-thread filters
 	cmd.Flags().Bool("pinned", false, "show only pinned messages (threads only)")
@@ -497,8 +524,134 @@ Legacy (deprecated):
 	return cmd
 }
 
+// getAllLinked merges recent room messages, and @mentions of the --as agent,
+// from this project and every linked project. Read state is left untouched.
+func getAllLinked(cmd *cobra.Command, ctx *CommandContext, last, room, mentions, asRef string, hideEvents, showAll bool) error {
+	render := markdownFor(cmd)
+	roomLimit := parseOptionalInt(room, 10)
+	if last != "" {
+		limit, err := strconv.Atoi(last)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("invalid --last value"))
+		}
+		roomLimit = limit
+	}
+	mentionsLimit := parseOptionalInt(mentions, 3)
+
+	agentBase := strings.TrimPrefix(asRef, "@")
+	if agentBase == "" {
+		agentBase = os.Getenv("FRAY_AGENT_ID")
+	}
+	if idx := strings.LastIndex(agentBase, "."); idx > 0 {
+		agentBase = agentBase[:idx]
+	}
+
+	channels, closeAll, err := openLinkedChannels(cmd, ctx)
+	defer closeAll()
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	var roomMessages, mentionMessages []channelMessage
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, channel.Name)
+		messages, err := db.GetMessages(channel.DB, &types.MessageQueryOptions{Limit: roomLimit})
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		messages, err = db.ApplyMessageEditCounts(channel.Project.DBPath, messages)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		if hideEvents {
+			messages = filterEventMessages(messages)
+		}
+		seen := make(map[string]struct{}, len(messages))
+		for _, msg := range messages {
+			seen[msg.ID] = struct{}{}
+			roomMessages = append(roomMessages, channelMessage{Channel: channel.Name, Message: msg})
+		}
+
+		if agentBase == "" {
+			continue
+		}
+		allHomes := ""
+		mentioned, err := db.GetMessagesWithMention(channel.DB, agentBase, &types.MessageQueryOptions{
+			Limit:                 mentionsLimit + len(messages),
+			IncludeRepliesToAgent: agentBase,
+			AgentPrefix:           agentBase,
+			Home:                  &allHomes,
+		})
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		if hideEvents {
+			mentioned = filterEventMessages(mentioned)
+		}
+		count := 0
+		for _, msg := range mentioned {
+			if _, ok := seen[msg.ID]; ok {
+				continue
+			}
+			mentionMessages = append(mentionMessages, channelMessage{Channel: channel.Name, Message: msg})
+			if count++; count == mentionsLimit {
+				break
+			}
+		}
+	}
+	sortChannelMessages(roomMessages)
+	sortChannelMessages(mentionMessages)
+
+	if ctx.JSONMode {
+		for i := range roomMessages {
+			roomMessages[i].ID = qualifyID(roomMessages[i].Channel, roomMessages[i].ID)
+		}
+		for i := range mentionMessages {
+			mentionMessages[i].ID = qualifyID(mentionMessages[i].Channel, mentionMessages[i].ID)
+		}
+		payload := map[string]any{
+			"channels":      names,
+			"room_messages": roomMessages,
+		}
+		if agentBase != "" {
+			payload["mentions"] = mentionMessages
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "ROOMS (%s):\n", strings.Join(names, ", "))
+	if len(roomMessages) == 0 {
+		fmt.Fprintln(out, "(no messages yet)")
+	}
+	for _, msg := range roomMessages {
+		if showAll {
+			fmt.Fprintln(out, FormatMessageFull(msg.Message, msg.Channel, nil, render))
+		} else {
+			fmt.Fprintln(out, FormatMessage(msg.Message, msg.Channel, nil, render))
+		}
+	}
+
+	if agentBase != "" {
+		fmt.Fprintln(out, "")
+		fmt.Fprintln(out, "---")
+		fmt.Fprintln(out, "")
+		if len(mentionMessages) == 0 {
+			fmt.Fprintf(out, "@%s: (no additional mentions)\n", agentBase)
+		} else {
+			fmt.Fprintf(out, "@%s across channels:\n", agentBase)
+			for _, msg := range mentionMessages {
+				fmt.Fprintln(out, FormatMessage(msg.Message, msg.Channel, nil, render))
+			}
+		}
+	}
+	return nil
+}
+
 // getThread displays messages from a thread.
 func getThread(cmd *cobra.Command, ctx *CommandContext, thread *types.Thread, last, since string, showAll bool, projectName string, agentBases map[string]struct{}, hideEvents bool, pinnedOnly bool, byAgent, withText string, reactionsOnly bool) error {
+	render := markdownFor(cmd)
 	var messages []types.Message
 	var err error
 
@@ -612,6 +765,7 @@ func getThread(cmd *cobra.Command, ctx *CommandContext, thread *types.Thread, la
 		ProjectName: projectName,
 		AgentBases:  agentBases,
 		QuotedMsgs:  quotedMsgs,
+		Markdown:    render,
 	})
 	for _, line := range lines {
 		fmt.Fprintln(out, line)
@@ -621,7 +775,25 @@ func getThread(cmd *cobra.Command, ctx *CommandContext, thread *types.Thread, la
 
 // getMessage displays a single message.
 func getMessage(cmd *cobra.Command, ctx *CommandContext, msg *types.Message, projectName string, agentBases map[string]struct{}) error {
+	render := markdownFor(cmd)
+	if msg.Home != "" && msg.Home != "room" {
+		thread, err := db.GetThread(ctx.DB, msg.Home)
+		if err != nil {
+			return writeCommandError(cmd, err)
+		}
+		reader, err := resolveActingAgent(cmd, ctx)
+		if err != nil {
+			return writeCommandError(cmd, err)
+		}
+		if err := checkThreadPermission(ctx, thread, reader, threadActionRead); err != nil {
+			return writeCommandError(cmd, err)
+		}
+	}
+
 	showReplies, _ := cmd.Flags().GetBool("replies")
+	if name, _ := cmd.Flags().GetString("attachment"); name != "" {
+		return getAttachment(cmd, ctx, msg, name)
+	}
 
 	if ctx.JSONMode {
 		if showReplies {
@@ -636,7 +808,7 @@ func getMessage(cmd *cobra.Command, ctx *CommandContext, msg *types.Message, pro
 	}
 
 	out := cmd.OutOrStdout()
-	fmt.Fprintln(out, FormatMessageFull(*msg, projectName, agentBases))
+	fmt.Fprintln(out, FormatMessageFull(*msg, projectName, agentBases, render))
 
 	if showReplies {
 		replies, err := db.GetReplies(ctx.DB, msg.ID)
@@ -646,15 +818,47 @@ func getMessage(cmd *cobra.Command, ctx *CommandContext, msg *types.Message, pro
 		if len(replies) > 0 {
 			fmt.Fprintln(out, "\nReplies:")
 			for _, reply := range replies {
-				fmt.Fprintln(out, FormatMessage(reply, projectName, agentBases))
+				fmt.Fprintln(out, FormatMessage(reply, projectName, agentBases, render))
 			}
 		}
 	}
 	return nil
 }
 
+// getAttachment writes an attachment's content to stdout (metadata with --json).
+func getAttachment(cmd *cobra.Command, ctx *CommandContext, msg *types.Message, name string) error {
+	attachment, err := db.FindAttachment(*msg, name)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	if ctx.JSONMode {
+		path, err := db.BlobPath(ctx.Project.DBPath, attachment.Hash)
+		if err != nil {
+			return writeCommandError(cmd, err)
+		}
+		payload := map[string]any{
+			"message":    msg.ID,
+			"attachment": attachment,
+			"path":       path,
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
+	}
+
+	blob, err := db.OpenBlob(ctx.Project.DBPath, attachment.Hash)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	defer blob.Close()
+	if _, err := io.Copy(cmd.OutOrStdout(), blob); err != nil {
+		return writeCommandError(cmd, err)
+	}
+	return nil
+}
+
 // getNotifications displays notifications for an agent.
 func getNotifications(cmd *cobra.Command, ctx *CommandContext, asRef, projectName string, agentBases map[string]struct{}, showAll bool) error {
+	render := markdownFor(cmd)
 	agentID, err := resolveSubscriptionAgent(ctx, asRef)
 	if err != nil {
 		return writeCommandError(cmd, fmt.Errorf("--as is required for notifications"))
@@ -753,7 +957,7 @@ func getNotifications(cmd *cobra.Command, ctx *CommandContext, asRef, projectNam
 		if len(direct) > 0 {
 			fmt.Fprintf(out, "Recent @%s:\n", agentBase)
 			for _, msg := range direct {
-				fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+				fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 			}
 		}
 
@@ -763,7 +967,7 @@ func getNotifications(cmd *cobra.Command, ctx *CommandContext, asRef, projectNam
 			}
 			fmt.Fprintln(out, "You were FYI'd here:")
 			for _, msg := range fyi {
-				fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+				fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 			}
 		}
 
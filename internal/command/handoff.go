@@ -0,0 +1,298 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// NewHandoffCmd creates the handoff command.
+func NewHandoffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "handoff <from> <to> [summary]",
+		Short: "Hand work from one agent to another",
+		Long: `Hand work from one agent to another in a single step.
+
+Posts a handoff message addressed to the recipient and records one
+agent_handoff event. Optional parts of the handoff:
+
+  --thread <ref>  post in a thread; if the sender owns it, ownership moves
+                  to the recipient
+  --claims        transfer all of the sender's claims to the recipient
+  --cursor        set a must-read ghost cursor for the recipient
+
+Only the sender or the owner of --thread may run a handoff. Managed
+recipients are woken by the daemon.
+
+Examples:
+  fray handoff alice bob --thread auth --claims --cursor "token refresh is half done, see tests"
+  fray handoff alice bob --cursor --since msg-abc123 "picking up from the design discussion"`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			fromID, err := resolveAgentRef(ctx, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			toID, err := resolveAgentRef(ctx, args[1])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if fromID == toID {
+				return writeCommandError(cmd, fmt.Errorf("cannot hand off to the same agent: @%s", fromID))
+			}
+			fromAgent, err := db.GetAgent(ctx.DB, fromID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if fromAgent == nil {
+				return writeCommandError(cmd, fmt.Errorf("agent not found: @%s", fromID))
+			}
+			toAgent, err := db.GetAgent(ctx.DB, toID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if toAgent == nil {
+				return writeCommandError(cmd, fmt.Errorf("agent not found: @%s", toID))
+			}
+
+			summary := ""
+			if len(args) > 2 {
+				summary = strings.TrimSpace(args[2])
+			}
+			threadRef, _ := cmd.Flags().GetString("thread")
+			transferClaims, _ := cmd.Flags().GetBool("claims")
+			setCursor, _ := cmd.Flags().GetBool("cursor")
+			sinceRef, _ := cmd.Flags().GetString("since")
+			if sinceRef != "" && !setCursor {
+				return writeCommandError(cmd, fmt.Errorf("--since requires --cursor"))
+			}
+
+			home := "room"
+			var thread *types.Thread
+			if threadRef != "" {
+				thread, err = resolveThreadRef(ctx.DB, threadRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				home = thread.GUID
+			}
+
+			actorID, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkHandoffActor(ctx, actorID, fromID, thread); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			var since *types.Message
+			if sinceRef != "" {
+				since, err = resolveMessageRef(ctx.DB, sinceRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+
+			if _, err := db.PruneExpiredClaims(ctx.DB); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			var claimItems []string
+			if transferClaims {
+				claims, err := db.GetClaimsByAgent(ctx.DB, fromID)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				for _, claim := range claims {
+					claimItems = append(claimItems, formatClaimItem(claim))
+				}
+			}
+
+			ownerTransferred := thread != nil && thread.OwnerAgent != nil && *thread.OwnerAgent == fromID
+
+			now := time.Now().Unix()
+			message, err := db.CreateMessage(ctx.DB, types.Message{
+				TS:        now,
+				FromAgent: fromID,
+				Body:      buildHandoffBody(fromID, toID, summary, thread, claimItems),
+				Mentions:  []string{toID},
+				Home:      home,
+				Type:      types.MessageTypeAgent,
+			})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			handoffGUID, err := core.GenerateGUID("hoff")
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			var cursorGUID *string
+			if setCursor {
+				target := message.ID
+				if since != nil {
+					target = since.ID
+				}
+				cursorGUID = &target
+			}
+			handoff := types.Handoff{
+				GUID:              handoffGUID,
+				FromAgent:         fromID,
+				ToAgent:           toID,
+				Home:              home,
+				MessageGUID:       message.ID,
+				Summary:           summary,
+				Claims:            claimItems,
+				CursorMessageGUID: cursorGUID,
+				OwnerTransferred:  ownerTransferred,
+				CreatedAt:         now,
+			}
+
+			// Record the handoff in JSONL before the remaining cache writes, so a
+			// failure part way through is repaired by the next rebuild.
+			if err := db.AppendMessage(ctx.Project.DBPath, message); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if ownerTransferred {
+				if err := db.AppendThreadUpdate(ctx.Project.DBPath, db.ThreadUpdateJSONLRecord{
+					GUID:       thread.GUID,
+					OwnerAgent: &toID,
+				}); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			if err := db.AppendHandoff(ctx.Project.DBPath, handoff); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			auditAfter := map[string]any{"agent": toID, "home": home, "claims": claimItems}
+			if ownerTransferred {
+				auditAfter["owner"] = toID
+			}
+			recordAudit(cmd, ctx, "agent.handoff", "@"+fromID, map[string]any{"agent": fromID}, auditAfter)
+
+			if transferClaims && len(claimItems) > 0 {
+				if err := db.UpdateClaimsAgentID(ctx.DB, fromID, toID); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			if cursorGUID != nil {
+				if err := db.SetGhostCursor(ctx.DB, types.GhostCursor{
+					AgentID:     toID,
+					Home:        home,
+					MessageGUID: *cursorGUID,
+					MustRead:    true,
+					SetAt:       time.Now().UnixMilli(),
+				}); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			if ownerTransferred {
+				if _, err := db.UpdateThread(ctx.DB, thread.GUID, db.ThreadUpdates{
+					OwnerAgent: types.OptionalString{Set: true, Value: &toID},
+				}); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			if thread != nil {
+				if err := subscribeAgentToThread(ctx, thread.GUID, toID, now, "handoff"); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			handoff, err = db.CreateHandoff(ctx.DB, handoff)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"handoff": handoff,
+					"message": message,
+					"woken":   toAgent.Managed,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "@%s handed off to @%s [%s]\n", fromID, toID, message.ID)
+			if thread != nil {
+				switch {
+				case ownerTransferred:
+					fmt.Fprintf(out, "  thread: %s (owner: @%s)\n", thread.Name, toID)
+				case thread.OwnerAgent == nil:
+					fmt.Fprintf(out, "  thread: %s (no owner, not transferred)\n", thread.Name)
+				default:
+					fmt.Fprintf(out, "  thread: %s (owned by @%s, not transferred)\n", thread.Name, *thread.OwnerAgent)
+				}
+			}
+			if transferClaims {
+				if len(claimItems) == 0 {
+					fmt.Fprintf(out, "  claims: none held by @%s\n", fromID)
+				} else {
+					fmt.Fprintf(out, "  claims: %s\n", strings.Join(claimItems, ", "))
+				}
+			}
+			if cursorGUID != nil {
+				fmt.Fprintf(out, "  cursor: #%s (must-read)\n", *cursorGUID)
+			}
+			if toAgent.Managed {
+				fmt.Fprintf(out, "  @%s is daemon-managed and will be woken\n", toID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("thread", "", "thread to hand off (posts there and transfers ownership)")
+	cmd.Flags().Bool("claims", false, "transfer all of the sender's claims")
+	cmd.Flags().Bool("cursor", false, "set a must-read ghost cursor for the recipient")
+	cmd.Flags().String("since", "", "message the ghost cursor starts from (default: the handoff message)")
+	cmd.Flags().String("as", "", "agent running the handoff (default: $FRAY_AGENT_ID)")
+
+	return cmd
+}
+
+// checkHandoffActor allows the sender or the thread's owner to hand off.
+// Humans (no agent, or the stored username) are exempt.
+func checkHandoffActor(ctx *CommandContext, actorID, fromID string, thread *types.Thread) error {
+	if actorID == "" || actorID == fromID {
+		return nil
+	}
+	if thread != nil && thread.OwnerAgent != nil && *thread.OwnerAgent == actorID {
+		return nil
+	}
+	if username, _ := db.GetConfig(ctx.DB, "username"); username != "" && username == actorID {
+		return nil
+	}
+	return fmt.Errorf("@%s cannot hand off @%s's work: only @%s or the thread's owner can", actorID, fromID, fromID)
+}
+
+func buildHandoffBody(fromID, toID, summary string, thread *types.Thread, claimItems []string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "@%s handoff from @%s", toID, fromID)
+	if summary != "" {
+		fmt.Fprintf(&builder, ": %s", summary)
+	}
+	if thread != nil {
+		fmt.Fprintf(&builder, "\nthread: %s", thread.Name)
+	}
+	if len(claimItems) > 0 {
+		fmt.Fprintf(&builder, "\nclaims: %s", strings.Join(claimItems, ", "))
+	}
+	return builder.String()
+}
+
+func formatClaimItem(claim types.Claim) string {
+	if claim.ClaimType == types.ClaimTypeFile {
+		return claim.Pattern
+	}
+	return fmt.Sprintf("%s:%s", claim.ClaimType, claim.Pattern)
+}
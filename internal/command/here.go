@@ -1,8 +1,10 @@
 package command
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -24,27 +26,14 @@ func NewHereCmd() *cobra.Command {
 			defer ctx.DB.Close()
 
 			includeAll, _ := cmd.Flags().GetBool("all")
+			allLinked, _ := cmd.Flags().GetBool("all-linked")
+			if allLinked {
+				return hereAllLinked(cmd, ctx, includeAll)
+			}
 
-			var agents []types.Agent
-			if includeAll {
-				all, err := db.GetAllAgents(ctx.DB)
-				if err != nil {
-					return writeCommandError(cmd, err)
-				}
-				for _, agent := range all {
-					if agent.LeftAt == nil {
-						agents = append(agents, agent)
-					}
-				}
-			} else {
-				staleHours := 4
-				if value, err := db.GetConfig(ctx.DB, "stale_hours"); err == nil && value != "" {
-					staleHours = parseInt(value, staleHours)
-				}
-				agents, err = db.GetActiveAgents(ctx.DB, staleHours)
-				if err != nil {
-					return writeCommandError(cmd, err)
-				}
+			agents, err := hereAgents(ctx.DB, includeAll)
+			if err != nil {
+				return writeCommandError(cmd, err)
 			}
 
 			claimCounts, err := db.GetClaimCountsByAgent(ctx.DB)
@@ -78,25 +67,7 @@ func NewHereCmd() *cobra.Command {
 
 			fmt.Fprintf(out, "ACTIVE AGENTS (%d):\n", len(agents))
 			for _, agent := range agents {
-				claimCount := claimCounts[agent.AgentID]
-				claimInfo := ""
-				if claimCount > 0 {
-					plural := "s"
-					if claimCount == 1 {
-						plural = ""
-					}
-					claimInfo = fmt.Sprintf(" (%d claim%s)", claimCount, plural)
-				}
-				status := ""
-				if agent.Status != nil && *agent.Status != "" {
-					status = " - " + *agent.Status
-				}
-				roleInfo := ""
-				if roles := allRoles[agent.AgentID]; roles != nil {
-					roleInfo = formatRoleInfo(roles)
-				}
-				fmt.Fprintf(out, "  @%s%s%s%s\n", agent.AgentID, roleInfo, claimInfo, status)
-				fmt.Fprintf(out, "    last seen: %s\n", formatRelative(agent.LastSeen))
+				printHereAgent(out, "  ", agent, claimCounts, allRoles)
 			}
 
 			return nil
@@ -104,9 +75,125 @@ func NewHereCmd() *cobra.Command {
 	}
 
 	cmd.Flags().Bool("all", false, "include stale agents")
+	cmd.Flags().Bool("all-linked", false, "list agents across this and all linked projects")
 	return cmd
 }
 
+// hereAgents returns agents that have not left, or only recently seen ones
+// unless includeAll is set.
+func hereAgents(conn *sql.DB, includeAll bool) ([]types.Agent, error) {
+	if includeAll {
+		all, err := db.GetAllAgents(conn)
+		if err != nil {
+			return nil, err
+		}
+		var agents []types.Agent
+		for _, agent := range all {
+			if agent.LeftAt == nil {
+				agents = append(agents, agent)
+			}
+		}
+		return agents, nil
+	}
+	staleHours := 4
+	if value, err := db.GetConfig(conn, "stale_hours"); err == nil && value != "" {
+		staleHours = parseInt(value, staleHours)
+	}
+	return db.GetActiveAgents(conn, staleHours)
+}
+
+func printHereAgent(out io.Writer, indent string, agent types.Agent, claimCounts map[string]int64, allRoles map[string]*types.AgentRoles) {
+	claimCount := claimCounts[agent.AgentID]
+	claimInfo := ""
+	if claimCount > 0 {
+		plural := "s"
+		if claimCount == 1 {
+			plural = ""
+		}
+		claimInfo = fmt.Sprintf(" (%d claim%s)", claimCount, plural)
+	}
+	status := ""
+	if agent.Status != nil && *agent.Status != "" {
+		status = " - " + *agent.Status
+	}
+	roleInfo := ""
+	if roles := allRoles[agent.AgentID]; roles != nil {
+		roleInfo = formatRoleInfo(roles)
+	}
+	fmt.Fprintf(out, "%s@%s%s%s%s\n", indent, agent.AgentID, roleInfo, claimInfo, status)
+	fmt.Fprintf(out, "%s  last seen: %s\n", indent, formatRelative(agent.LastSeen))
+}
+
+// hereAllLinked lists active agents grouped by channel across linked projects.
+func hereAllLinked(cmd *cobra.Command, ctx *CommandContext, includeAll bool) error {
+	channels, closeAll, err := openLinkedChannels(cmd, ctx)
+	defer closeAll()
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	type channelAgents struct {
+		name        string
+		agents      []types.Agent
+		claimCounts map[string]int64
+		allRoles    map[string]*types.AgentRoles
+	}
+	var groups []channelAgents
+	payload := []map[string]any{}
+	total := 0
+	for _, channel := range channels {
+		agents, err := hereAgents(channel.DB, includeAll)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		claimCounts, err := db.GetClaimCountsByAgent(channel.DB)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		allRoles, err := db.GetAllAgentRoles(channel.DB)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		if ctx.JSONMode {
+			messageCounts, err := getMessageCounts(channel.DB)
+			if err != nil {
+				return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+			}
+			for _, entry := range buildHerePayload(agents, claimCounts, messageCounts, allRoles) {
+				entry["channel"] = channel.Name
+				entry["agent_id"] = qualifyID(channel.Name, entry["agent_id"].(string))
+				payload = append(payload, entry)
+			}
+		}
+		total += len(agents)
+		groups = append(groups, channelAgents{name: channel.Name, agents: agents, claimCounts: claimCounts, allRoles: allRoles})
+	}
+
+	if ctx.JSONMode {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+			"agents": payload,
+			"total":  total,
+		})
+	}
+
+	out := cmd.OutOrStdout()
+	if total == 0 {
+		fmt.Fprintln(out, "No active agents in any linked channel")
+		return nil
+	}
+	fmt.Fprintf(out, "ACTIVE AGENTS (%d):\n", total)
+	for _, group := range groups {
+		if len(group.agents) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "  #%s (%d):\n", group.name, len(group.agents))
+		for _, agent := range group.agents {
+			printHereAgent(out, "    ", agent, group.claimCounts, group.allRoles)
+		}
+	}
+	return nil
+}
+
 func buildHerePayload(agents []types.Agent, claimCounts map[string]int64, messageCounts map[string]int64, allRoles map[string]*types.AgentRoles) []map[string]any {
 	payload := make([]map[string]any, 0, len(agents))
 	for _, agent := range agents {
@@ -0,0 +1,603 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates the history command.
+func NewHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Browse messages archived by prune",
+		Long: `Browse and search the messages prune archived to history.jsonl.
+
+Only messages no longer in messages.jsonl are shown, formatted as fray get
+shows them, and only from threads you can read. Bring them back with fray
+restore-messages.
+
+Examples:
+  fray history                       # The last 20 archived messages
+  fray history --thread design       # Archived from a thread
+  fray history --search "migration"  # Archived messages mentioning migration
+  fray history --from alice --since 2w --last 0`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			last, _ := cmd.Flags().GetInt("last")
+			showAllMessages, _ := cmd.Flags().GetBool("show-all")
+			render := markdownFor(cmd)
+			filter, err := parseHistoryFilter(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			reader, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, filter.thread, reader, threadActionRead); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			archived, err := readArchivedMessages(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			records, err := filter.apply(ctx, archived)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			// Archived messages keep their threads' ACLs
+			records, err = readableHistory(ctx, reader, records)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if last > 0 && len(records) > last {
+				records = records[len(records)-last:]
+			}
+			messages, err := historyMessages(ctx.Project.DBPath, records)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(messages)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(messages) == 0 {
+				fmt.Fprintln(out, "No archived messages")
+				return nil
+			}
+			agentBases, err := db.GetAgentBases(ctx.DB)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			lines := FormatMessageListAccordion(messages, AccordionOptions{
+				ShowAll:     showAllMessages,
+				ProjectName: GetProjectName(ctx.Project.Root),
+				AgentBases:  agentBases,
+				Markdown:    render,
+			})
+			for _, line := range lines {
+				fmt.Fprintln(out, line)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("thread", "", "only messages in this thread")
+	cmd.Flags().String("since", "", "only messages after time (e.g. 1h, 2d, today)")
+	cmd.Flags().String("before", "", "only messages before time")
+	cmd.Flags().String("from", "", "only messages from this agent")
+	cmd.Flags().String("search", "", "only messages whose body contains text")
+	cmd.Flags().Int("last", 20, "show the last N matches (0 for all)")
+	cmd.Flags().Bool("show-all", false, "disable accordion, show all messages in full")
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
+	return cmd
+}
+
+// NewRestoreMessagesCmd creates the restore-messages command.
+func NewRestoreMessagesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-messages [ids...]",
+		Short: "Restore archived messages from history.jsonl",
+		Long: `Bring messages prune archived back into messages.jsonl.
+
+Select messages by id, or by --thread and --since. Restored messages keep
+their edits, reactions, pins, and thread memberships, and the messages they
+reply to come back with them. Like prune, this requires .fray/ to be
+committed and the branch in sync with its upstream. Agents restoring into a
+thread with permissions need to be able to write to it for their own messages
+and curate it for anyone else's.
+
+Examples:
+  fray restore-messages msg-abc123 msg-def456
+  fray restore-messages --thread design
+  fray restore-messages --thread design --since 2026-01-01`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			filter, err := parseHistoryFilter(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if len(args) == 0 && filter.thread == nil && filter.sinceTS == 0 {
+				return writeCommandError(cmd, fmt.Errorf("give message ids, --thread, or --since"))
+			}
+			if len(args) > 0 && (filter.thread != nil || filter.sinceTS != 0) {
+				return writeCommandError(cmd, fmt.Errorf("give message ids or --thread/--since, not both"))
+			}
+
+			if err := checkPruneGuardrails(ctx.Project.Root); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			archived, err := readArchivedMessages(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			var selected []db.MessageJSONLRecord
+			if len(args) > 0 {
+				seen := make(map[string]struct{}, len(args))
+				for _, ref := range args {
+					record, err := resolveHistoryRef(archived, ref)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					if _, ok := seen[record.ID]; !ok {
+						seen[record.ID] = struct{}{}
+						selected = append(selected, record)
+					}
+				}
+			} else if selected, err = filter.apply(ctx, archived); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			restoring := withReplyParents(archived, selected)
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkRestorePermissions(ctx, actor, restoring); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			unlock, err := db.LockJSONLForRewrite(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			restored, err := restoreHistoryMessages(ctx.Project.DBPath, restoring)
+			if err == nil {
+				err = db.RebuildDatabaseFromJSONL(ctx.DB, ctx.Project.DBPath)
+			}
+			unlock()
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			ids := make([]string, 0, len(restored))
+			for _, record := range restored {
+				ids = append(ids, record.ID)
+			}
+			if len(ids) > 0 {
+				recordAudit(cmd, ctx, "messages.restore", "messages.jsonl", nil, map[string]any{"restored": ids})
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{"restored": ids})
+			}
+
+			out := cmd.OutOrStdout()
+			if len(restored) == 0 {
+				fmt.Fprintln(out, "No archived messages matched")
+				return nil
+			}
+			if len(restored) > len(selected) {
+				fmt.Fprintf(out, "Restored %d messages (including reply parents)\n", len(restored))
+			} else {
+				fmt.Fprintf(out, "Restored %d messages\n", len(restored))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("thread", "", "restore messages in this thread")
+	cmd.Flags().String("since", "", "restore messages after time (e.g. 1h, 2d, today)")
+	return cmd
+}
+
+// historyFilter narrows archived messages for history and restore-messages.
+type historyFilter struct {
+	thread   *types.Thread
+	sinceTS  int64
+	beforeTS int64
+	from     string
+	search   string
+}
+
+func parseHistoryFilter(cmd *cobra.Command, ctx *CommandContext) (historyFilter, error) {
+	var filter historyFilter
+	if ref, _ := cmd.Flags().GetString("thread"); ref != "" {
+		thread, err := resolveThreadRef(ctx.DB, ref)
+		if err != nil {
+			return filter, err
+		}
+		filter.thread = thread
+	}
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		cursor, err := core.ParseTimeExpression(ctx.DB, since, "since")
+		if err != nil {
+			return filter, err
+		}
+		filter.sinceTS = cursor.TS
+	}
+	if cmd.Flags().Lookup("before") != nil {
+		if before, _ := cmd.Flags().GetString("before"); before != "" {
+			cursor, err := core.ParseTimeExpression(ctx.DB, before, "before")
+			if err != nil {
+				return filter, err
+			}
+			filter.beforeTS = cursor.TS
+		}
+	}
+	if cmd.Flags().Lookup("from") != nil {
+		from, _ := cmd.Flags().GetString("from")
+		filter.from = core.NormalizeAgentRef(from)
+	}
+	if cmd.Flags().Lookup("search") != nil {
+		search, _ := cmd.Flags().GetString("search")
+		filter.search = strings.ToLower(search)
+	}
+	return filter, nil
+}
+
+func (f historyFilter) apply(ctx *CommandContext, records []db.MessageJSONLRecord) ([]db.MessageJSONLRecord, error) {
+	var matched []db.MessageJSONLRecord
+	for _, record := range records {
+		if f.sinceTS > 0 && record.TS < f.sinceTS {
+			continue
+		}
+		if f.beforeTS > 0 && record.TS >= f.beforeTS {
+			continue
+		}
+		if f.from != "" && record.FromAgent != f.from && !strings.HasPrefix(record.FromAgent, f.from+".") {
+			continue
+		}
+		if f.search != "" && !strings.Contains(strings.ToLower(record.Body), f.search) {
+			continue
+		}
+		if f.thread != nil && record.Home != f.thread.GUID {
+			// Thread memberships outlive prune, so the cache still has them
+			member, err := db.IsMessageInThread(ctx.DB, f.thread.GUID, record.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !member {
+				continue
+			}
+		}
+		matched = append(matched, record)
+	}
+	return matched, nil
+}
+
+// readArchivedMessages returns history.jsonl messages that aren't in
+// messages.jsonl, oldest first.
+func readArchivedMessages(projectPath string) ([]db.MessageJSONLRecord, error) {
+	history, err := db.ReadHistoryMessages(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	live, err := db.ReadMessages(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	liveIDs := make(map[string]struct{}, len(live))
+	for _, record := range live {
+		liveIDs[record.ID] = struct{}{}
+	}
+	archived := make([]db.MessageJSONLRecord, 0, len(history))
+	for _, record := range history {
+		if _, ok := liveIDs[record.ID]; !ok {
+			archived = append(archived, record)
+		}
+	}
+	sort.SliceStable(archived, func(i, j int) bool { return archived[i].TS < archived[j].TS })
+	return archived, nil
+}
+
+// historyMessages converts archived records for display, with the reactions
+// history.jsonl recorded for them.
+func historyMessages(projectPath string, records []db.MessageJSONLRecord) ([]types.Message, error) {
+	reactions, err := db.ReadHistoryReactions(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	byMessage := make(map[string][]db.ReactionJSONLRecord)
+	seen := make(map[db.ReactionJSONLRecord]struct{})
+	for _, reaction := range reactions {
+		if _, ok := seen[reaction]; ok {
+			continue
+		}
+		seen[reaction] = struct{}{}
+		byMessage[reaction.MessageGUID] = append(byMessage[reaction.MessageGUID], reaction)
+	}
+
+	messages := make([]types.Message, 0, len(records))
+	for _, record := range records {
+		msgType := record.MsgType
+		if msgType == "" {
+			msgType = types.MessageTypeAgent
+		}
+		msg := types.Message{
+			ID:               record.ID,
+			TS:               record.TS,
+			ChannelID:        record.ChannelID,
+			Home:             record.Home,
+			FromAgent:        record.FromAgent,
+			Body:             record.Body,
+			Mentions:         record.Mentions,
+			Reactions:        db.ConvertLegacyReactions(record.Reactions),
+			Type:             msgType,
+			References:       record.References,
+			SurfaceMessage:   record.SurfaceMessage,
+			ReplyTo:          record.ReplyTo,
+			QuoteMessageGUID: record.QuoteMessageGUID,
+			EditedAt:         record.EditedAt,
+			Edited:           record.EditedAt != nil,
+			ArchivedAt:       record.ArchivedAt,
+			Attachments:      record.Attachments,
+			Signature:        record.Signature,
+		}
+		for _, reaction := range byMessage[record.ID] {
+			msg.Reactions[reaction.Emoji] = append(msg.Reactions[reaction.Emoji], types.ReactionEntry{
+				AgentID:   reaction.AgentID,
+				ReactedAt: reaction.ReactedAt,
+			})
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// resolveHistoryRef finds an archived message by id or unique id prefix.
+func resolveHistoryRef(records []db.MessageJSONLRecord, ref string) (db.MessageJSONLRecord, error) {
+	id := strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	if !strings.HasPrefix(id, "msg-") {
+		id = "msg-" + id
+	}
+	var matches []db.MessageJSONLRecord
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+		if strings.HasPrefix(record.ID, id) {
+			matches = append(matches, record)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return db.MessageJSONLRecord{}, fmt.Errorf("no archived message matches %s", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return db.MessageJSONLRecord{}, fmt.Errorf("ambiguous message id %s: matches %d archived messages", ref, len(matches))
+	}
+}
+
+// withReplyParents adds the archived messages selected replies reply to, as
+// prune keeps replies with their parents. It returns them oldest first.
+func withReplyParents(archived, selected []db.MessageJSONLRecord) []db.MessageJSONLRecord {
+	byID := make(map[string]db.MessageJSONLRecord, len(archived))
+	for _, record := range archived {
+		byID[record.ID] = record
+	}
+	restoreIDs := make(map[string]struct{}, len(selected))
+	for _, record := range selected {
+		restoreIDs[record.ID] = struct{}{}
+		for parentID := record.ReplyTo; parentID != nil && *parentID != ""; {
+			parent, ok := byID[*parentID]
+			if !ok {
+				break
+			}
+			if _, restoring := restoreIDs[parent.ID]; restoring {
+				break
+			}
+			restoreIDs[parent.ID] = struct{}{}
+			parentID = parent.ReplyTo
+		}
+	}
+	var restoring []db.MessageJSONLRecord
+	for _, record := range archived {
+		if _, ok := restoreIDs[record.ID]; ok {
+			restoring = append(restoring, record)
+		}
+	}
+	return restoring
+}
+
+// archivedThreads resolves the threads archived messages live in: their home
+// and every thread they were added to. The room is left out.
+type archivedThreads struct {
+	ctx     *CommandContext
+	members map[string][]string
+	threads map[string]*types.Thread
+}
+
+func newArchivedThreads(ctx *CommandContext) (*archivedThreads, error) {
+	members, err := collectThreadMembers(ctx.Project.DBPath)
+	if err != nil {
+		return nil, err
+	}
+	return &archivedThreads{ctx: ctx, members: members, threads: make(map[string]*types.Thread)}, nil
+}
+
+func (a *archivedThreads) of(record db.MessageJSONLRecord) ([]*types.Thread, error) {
+	guids := a.members[record.ID]
+	if record.Home != "" && record.Home != "room" {
+		guids = append([]string{record.Home}, guids...)
+	}
+	var threads []*types.Thread
+	for _, guid := range guids {
+		thread, ok := a.threads[guid]
+		if !ok {
+			var err error
+			if thread, err = db.GetThread(a.ctx.DB, guid); err != nil {
+				return nil, err
+			}
+			a.threads[guid] = thread
+		}
+		if thread != nil {
+			threads = append(threads, thread)
+		}
+	}
+	return threads, nil
+}
+
+// readableHistory drops archived messages in any thread reader may not read.
+func readableHistory(ctx *CommandContext, reader string, records []db.MessageJSONLRecord) ([]db.MessageJSONLRecord, error) {
+	if reader == "" {
+		return records, nil
+	}
+	lookup, err := newArchivedThreads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var readable []db.MessageJSONLRecord
+	for _, record := range records {
+		threads, err := lookup.of(record)
+		if err != nil {
+			return nil, err
+		}
+		allowed := true
+		for _, thread := range threads {
+			if checkThreadPermission(ctx, thread, reader, threadActionRead) != nil {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			readable = append(readable, record)
+		}
+	}
+	return readable, nil
+}
+
+// checkRestorePermissions requires actor to be able to write to every thread
+// a restored message goes back into, and to curate it when the message is
+// someone else's.
+func checkRestorePermissions(ctx *CommandContext, actor string, records []db.MessageJSONLRecord) error {
+	if actor == "" {
+		return nil
+	}
+	lookup, err := newArchivedThreads(ctx)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		threads, err := lookup.of(record)
+		if err != nil {
+			return err
+		}
+		action := threadActionCurate
+		if record.FromAgent == actor {
+			action = threadActionWrite
+		}
+		for _, thread := range threads {
+			if err := checkThreadPermission(ctx, thread, actor, action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreHistoryMessages writes archived messages back into messages.jsonl
+// along with their events from history.jsonl. It returns them oldest first.
+func restoreHistoryMessages(projectPath string, restored []db.MessageJSONLRecord) ([]db.MessageJSONLRecord, error) {
+	if len(restored) == 0 {
+		return nil, nil
+	}
+	frayDir := resolveFrayDir(projectPath)
+	messagesPath := filepath.Join(frayDir, "messages.jsonl")
+	restoreIDs := make(map[string]struct{}, len(restored))
+	for _, record := range restored {
+		restoreIDs[record.ID] = struct{}{}
+	}
+
+	live, err := db.ReadMessages(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	liveLines, err := readJSONLLines(messagesPath)
+	if err != nil {
+		return nil, err
+	}
+	historyLines, err := readJSONLLines(filepath.Join(frayDir, "history.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	merged := append(append([]db.MessageJSONLRecord{}, live...), restored...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].TS < merged[j].TS })
+
+	var builder strings.Builder
+	for _, record := range merged {
+		record.Type = "message"
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		builder.Write(data)
+		builder.WriteByte('\n')
+	}
+
+	// Keep every live event, then add the restored messages' events. Each
+	// prune archived a full copy, so history repeats lines.
+	written := make(map[string]struct{}, len(liveLines))
+	for _, line := range liveLines {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil || envelope.Type == "message" {
+			continue
+		}
+		written[line] = struct{}{}
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+	for _, line := range historyLines {
+		if _, ok := restoreIDs[messageEventTarget(line)]; !ok {
+			continue
+		}
+		if _, ok := written[line]; ok {
+			continue
+		}
+		written[line] = struct{}{}
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(messagesPath, []byte(builder.String()), 0o644); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
@@ -24,6 +24,23 @@ func NewHookInstallCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			precommit, _ := cmd.Flags().GetBool("precommit")
+			prepush, _ := cmd.Flags().GetBool("prepush")
+			postcheckout, _ := cmd.Flags().GetBool("postcheckout")
+			postcommit, _ := cmd.Flags().GetBool("postcommit")
+
+			var gitHooks []gitHookSpec
+			if precommit {
+				gitHooks = append(gitHooks, precommitHookSpec)
+			}
+			if prepush {
+				gitHooks = append(gitHooks, prepushHookSpec)
+			}
+			if postcheckout {
+				gitHooks = append(gitHooks, postcheckoutHookSpec)
+			}
+			if postcommit {
+				gitHooks = append(gitHooks, postcommitHookSpec)
+			}
 
 			projectDir := os.Getenv("CLAUDE_PROJECT_DIR")
 			if projectDir == "" {
@@ -84,8 +101,8 @@ func NewHookInstallCmd() *cobra.Command {
 				fmt.Fprintf(out, "Would write to: %s\n", settingsPath)
 				data, _ := json.MarshalIndent(hooksConfig, "", "  ")
 				fmt.Fprintln(out, string(data))
-				if precommit {
-					installPrecommitHook(projectDir, true, out)
+				for _, spec := range gitHooks {
+					installGitHook(projectDir, spec, true, out)
 				}
 				return nil
 			}
@@ -121,8 +138,8 @@ func NewHookInstallCmd() *cobra.Command {
 			fmt.Fprintln(out, "  UserPromptSubmit - injects room messages and @mentions before each prompt")
 			fmt.Fprintln(out, "  PreCompact - reminds to preserve work before context compaction")
 
-			if precommit {
-				installPrecommitHook(projectDir, false, out)
+			for _, spec := range gitHooks {
+				installGitHook(projectDir, spec, false, out)
 			}
 
 			fmt.Fprintln(out, "")
@@ -133,51 +150,85 @@ func NewHookInstallCmd() *cobra.Command {
 
 	cmd.Flags().Bool("dry-run", false, "show what would be written without writing")
 	cmd.Flags().Bool("precommit", false, "also install git pre-commit hook for claim conflict detection")
+	cmd.Flags().Bool("prepush", false, "also install git pre-push hook for file and branch claim conflict detection")
+	cmd.Flags().Bool("postcheckout", false, "also install git post-checkout hook for branch claim warnings")
+	cmd.Flags().Bool("postcommit", false, "also install git post-commit hook that posts commits to fray")
 
 	return cmd
 }
 
-func installPrecommitHook(projectDir string, dryRun bool, outWriter io.Writer) {
+// gitHookSpec describes a git hook that runs a fray subcommand.
+type gitHookSpec struct {
+	Name        string // git hook file name, e.g. "pre-commit"
+	Command     string // fray command line the hook runs
+	Description string // shown after installation
+}
+
+var (
+	precommitHookSpec = gitHookSpec{
+		Name:        "pre-commit",
+		Command:     "fray hook-precommit",
+		Description: "Warns on file claim conflicts when committing",
+	}
+	prepushHookSpec = gitHookSpec{
+		Name:        "pre-push",
+		Command:     `fray hook-prepush "$@"`,
+		Description: "Warns on file and branch claim conflicts in pushed commits",
+	}
+	postcommitHookSpec = gitHookSpec{
+		Name:        "post-commit",
+		Command:     "fray hook-postcommit",
+		Description: "Posts commits as events threaded under fray:msg trailers or your current claim/status",
+	}
+	postcheckoutHookSpec = gitHookSpec{
+		Name:        "post-checkout",
+		Command:     `fray hook-postcheckout "$@"`,
+		Description: "Warns when checking out a branch claimed by another agent",
+	}
+)
+
+func installGitHook(projectDir string, spec gitHookSpec, dryRun bool, outWriter io.Writer) {
 	gitRoot, err := gitRootDir(projectDir)
 	if err != nil {
 		fmt.Fprintln(outWriter, "")
-		fmt.Fprintln(outWriter, "WARNING: Not in a git repository, skipping pre-commit hook installation")
+		fmt.Fprintf(outWriter, "WARNING: Not in a git repository, skipping %s hook installation\n", spec.Name)
 		return
 	}
 
 	hooksDir := filepath.Join(gitRoot, ".git", "hooks")
-	precommitPath := filepath.Join(hooksDir, "pre-commit")
+	hookPath := filepath.Join(hooksDir, spec.Name)
+	marker := strings.Fields(spec.Command)[1]
 
 	hookScript := strings.Join([]string{
 		"#!/bin/sh",
-		"# fray pre-commit hook - detects file claim conflicts",
-		"# Installed by: fray hook-install --precommit",
+		fmt.Sprintf("# fray %s hook", spec.Name),
+		"# Installed by: fray hook-install",
 		"",
-		"fray hook-precommit",
+		spec.Command,
 		"",
 	}, "\n")
 
 	if dryRun {
 		fmt.Fprintln(outWriter, "")
-		fmt.Fprintf(outWriter, "Would write git pre-commit hook to: %s\n", precommitPath)
+		fmt.Fprintf(outWriter, "Would write git %s hook to: %s\n", spec.Name, hookPath)
 		fmt.Fprintln(outWriter, hookScript)
 		return
 	}
 
-	if data, err := os.ReadFile(precommitPath); err == nil {
-		if strings.Contains(string(data), "fray hook-precommit") {
+	if data, err := os.ReadFile(hookPath); err == nil {
+		if strings.Contains(string(data), "fray "+marker) {
 			fmt.Fprintln(outWriter, "")
-			fmt.Fprintln(outWriter, "Git pre-commit hook already installed")
+			fmt.Fprintf(outWriter, "Git %s hook already installed\n", spec.Name)
 			return
 		}
-		updated := strings.TrimRight(string(data), "\n") + "\n\n# fray file claim conflict detection\nmm hook-precommit\n"
-		if err := os.WriteFile(precommitPath, []byte(updated), 0o755); err != nil {
+		updated := strings.TrimRight(string(data), "\n") + "\n\n# fray " + spec.Name + " hook\n" + spec.Command + "\n"
+		if err := os.WriteFile(hookPath, []byte(updated), 0o755); err != nil {
 			fmt.Fprintln(outWriter, "")
-			fmt.Fprintf(outWriter, "Failed to update pre-commit hook: %v\n", err)
+			fmt.Fprintf(outWriter, "Failed to update %s hook: %v\n", spec.Name, err)
 			return
 		}
 		fmt.Fprintln(outWriter, "")
-		fmt.Fprintf(outWriter, "Added fray hook to existing pre-commit hook at %s\n", precommitPath)
+		fmt.Fprintf(outWriter, "Added fray hook to existing %s hook at %s\n", spec.Name, hookPath)
 		return
 	}
 
@@ -187,16 +238,16 @@ func installPrecommitHook(projectDir string, dryRun bool, outWriter io.Writer) {
 		return
 	}
 
-	if err := os.WriteFile(precommitPath, []byte(hookScript), 0o755); err != nil {
+	if err := os.WriteFile(hookPath, []byte(hookScript), 0o755); err != nil {
 		fmt.Fprintln(outWriter, "")
-		fmt.Fprintf(outWriter, "Failed to write pre-commit hook: %v\n", err)
+		fmt.Fprintf(outWriter, "Failed to write %s hook: %v\n", spec.Name, err)
 		return
 	}
-	_ = os.Chmod(precommitPath, 0o755)
+	_ = os.Chmod(hookPath, 0o755)
 
 	fmt.Fprintln(outWriter, "")
-	fmt.Fprintf(outWriter, "Git pre-commit hook installed at %s\n", precommitPath)
-	fmt.Fprintln(outWriter, "  Warns on file claim conflicts when committing")
+	fmt.Fprintf(outWriter, "Git %s hook installed at %s\n", spec.Name, hookPath)
+	fmt.Fprintf(outWriter, "  %s\n", spec.Description)
 }
 
 func gitRootDir(startDir string) (string, error) {
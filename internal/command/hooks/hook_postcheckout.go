@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"os"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// NewHookPostcheckoutCmd implements git post-checkout branch claim warnings.
+func NewHookPostcheckoutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook-postcheckout [prev-head] [new-head] [branch-flag]",
+		Short: "Git post-checkout hook for branch claim conflict detection",
+		Args:  cobra.MaximumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runHookPostcheckout(cmd, args)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// runHookPostcheckout warns when switching to a branch claimed by another
+// agent. Git ignores the exit status of post-checkout, so this is advisory only.
+func runHookPostcheckout(cmd *cobra.Command, args []string) {
+	// The third argument is 1 for branch checkouts and 0 for file checkouts.
+	if len(args) == 3 && args[2] != "1" {
+		return
+	}
+
+	agentID := os.Getenv("FRAY_AGENT_ID")
+
+	projectPath := os.Getenv("CLAUDE_PROJECT_DIR")
+	project, err := core.DiscoverProject(projectPath)
+	if err != nil {
+		return
+	}
+
+	branch, err := gitCurrentBranch(project.Root)
+	if err != nil || branch == "" {
+		return
+	}
+
+	dbConn, err := db.OpenDatabase(project)
+	if err != nil {
+		return
+	}
+	defer dbConn.Close()
+	if err := db.InitSchema(dbConn); err != nil {
+		return
+	}
+
+	conflicts, err := db.FindConflictingBranchClaims(dbConn, []string{branch}, agentID)
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+
+	printBranchClaimConflicts(cmd.ErrOrStderr(), conflicts, []string{branch})
+}
@@ -0,0 +1,235 @@
+package hooks
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// commitInfo is the subset of commit metadata posted to fray.
+type commitInfo struct {
+	SHA     string
+	Subject string
+	Body    string
+	Branch  string
+	Files   []string
+}
+
+// NewHookPostcommitCmd implements the git post-commit hook that posts commits to fray.
+func NewHookPostcommitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook-postcommit",
+		Short: "Git post-commit hook that links commits to fray messages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			msg, commit := runHookPostcommit()
+			if msg != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "[fray] posted commit %s as #%s\n", shortSHA(commit.SHA), msg.ID)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// runHookPostcommit posts the HEAD commit as an event message attributed to
+// FRAY_AGENT_ID. Failures are silent so commits are never disrupted.
+func runHookPostcommit() (*types.Message, commitInfo) {
+	var commit commitInfo
+	agentID := os.Getenv("FRAY_AGENT_ID")
+	if agentID == "" {
+		return nil, commit
+	}
+
+	projectPath := os.Getenv("CLAUDE_PROJECT_DIR")
+	project, err := core.DiscoverProject(projectPath)
+	if err != nil {
+		return nil, commit
+	}
+
+	commit, err = gitHeadCommit(project.Root)
+	if err != nil {
+		return nil, commit
+	}
+
+	dbConn, err := db.OpenDatabase(project)
+	if err != nil {
+		return nil, commit
+	}
+	defer dbConn.Close()
+	if err := db.InitSchema(dbConn); err != nil {
+		return nil, commit
+	}
+
+	agent, err := db.GetAgent(dbConn, agentID)
+	if err != nil || agent == nil {
+		return nil, commit
+	}
+
+	refs := resolveCommitRefs(dbConn, core.ExtractMessageTrailers(commit.Body))
+	parent := findCommitParent(dbConn, agent, commit, refs)
+
+	message := types.Message{
+		TS:        time.Now().Unix(),
+		FromAgent: agentID,
+		Body:      buildCommitEventBody(commit, refs),
+		Mentions:  []string{},
+		Type:      types.MessageTypeEvent,
+	}
+	if parent != nil {
+		message.ReplyTo = &parent.ID
+		message.Home = parent.Home
+	}
+
+	created, err := db.CreateMessage(dbConn, message)
+	if err != nil {
+		return nil, commit
+	}
+	if err := db.AppendMessage(project.DBPath, created); err != nil {
+		return nil, commit
+	}
+	return &created, commit
+}
+
+// findCommitParent picks the message a commit is threaded under: the first
+// fray:msg trailer, otherwise the message that announced the agent's claim on
+// the commit, otherwise its latest status announcement.
+func findCommitParent(dbConn *sql.DB, agent *types.Agent, commit commitInfo, refs []types.Message) *types.Message {
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+
+	if claims, err := db.GetClaimsByAgent(dbConn, agent.AgentID); err == nil {
+		if claim := pickCommitClaim(claims, commit); claim != nil {
+			if msg, err := db.GetMessage(dbConn, *claim.MessageGUID); err == nil && msg != nil {
+				return msg
+			}
+		}
+	}
+
+	if agent.Status == nil || *agent.Status == "" {
+		return nil
+	}
+	recent, err := db.GetRecentMessagesByAgent(dbConn, agent.AgentID, 50)
+	if err != nil {
+		return nil
+	}
+	for i := range recent {
+		if recent[i].Type != types.MessageTypeEvent && recent[i].Body == *agent.Status {
+			return &recent[i]
+		}
+	}
+	return nil
+}
+
+// pickCommitClaim returns the newest announced claim covering the commit's
+// files or branch, falling back to the newest announced claim. Claims are
+// ordered by creation time.
+func pickCommitClaim(claims []types.Claim, commit commitInfo) *types.Claim {
+	var covering, latest *types.Claim
+	for i := range claims {
+		claim := &claims[i]
+		if claim.MessageGUID == nil {
+			continue
+		}
+		latest = claim
+		switch claim.ClaimType {
+		case types.ClaimTypeFile:
+			if len(matchClaimFiles(claim.Pattern, commit.Files)) > 0 {
+				covering = claim
+			}
+		case types.ClaimTypeBranch:
+			if commit.Branch != "" && len(matchClaimFiles(claim.Pattern, []string{commit.Branch})) > 0 {
+				covering = claim
+			}
+		}
+	}
+	if covering != nil {
+		return covering
+	}
+	return latest
+}
+
+func resolveCommitRefs(dbConn *sql.DB, refs []string) []types.Message {
+	resolved := make([]types.Message, 0, len(refs))
+	for _, ref := range refs {
+		msg, err := db.GetMessage(dbConn, ref)
+		if err != nil {
+			continue
+		}
+		if msg == nil {
+			msg, err = db.GetMessageByPrefix(dbConn, ref)
+			if err != nil || msg == nil {
+				continue
+			}
+		}
+		resolved = append(resolved, *msg)
+	}
+	return resolved
+}
+
+func buildCommitEventBody(commit commitInfo, refs []types.Message) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "committed %s", shortSHA(commit.SHA))
+	if commit.Branch != "" {
+		fmt.Fprintf(&builder, " on %s", commit.Branch)
+	}
+	fmt.Fprintf(&builder, ": %s", commit.Subject)
+	if len(commit.Files) > 0 {
+		plural := "s"
+		if len(commit.Files) == 1 {
+			plural = ""
+		}
+		fmt.Fprintf(&builder, " (%d file%s)", len(commit.Files), plural)
+	}
+	if len(refs) > 1 {
+		ids := make([]string, 0, len(refs)-1)
+		for _, ref := range refs[1:] {
+			ids = append(ids, "#"+ref.ID)
+		}
+		fmt.Fprintf(&builder, "\nrefs %s", strings.Join(ids, ", "))
+	}
+	return builder.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func gitHeadCommit(projectRoot string) (commitInfo, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%H%x00%s%x00%B")
+	cmd.Dir = projectRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return commitInfo{}, err
+	}
+	parts := strings.SplitN(string(output), "\x00", 3)
+	if len(parts) != 3 {
+		return commitInfo{}, fmt.Errorf("unexpected git log output")
+	}
+	commit := commitInfo{
+		SHA:     strings.TrimSpace(parts[0]),
+		Subject: strings.TrimSpace(parts[1]),
+		Body:    strings.TrimSpace(parts[2]),
+	}
+
+	commit.Branch, _ = gitCurrentBranch(projectRoot)
+
+	filesCmd := exec.Command("git", "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commit.SHA)
+	filesCmd.Dir = projectRoot
+	if filesOutput, err := filesCmd.Output(); err == nil {
+		commit.Files = SplitGitLines(string(filesOutput))
+	}
+	return commit, nil
+}
@@ -0,0 +1,53 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+func TestPickCommitClaim(t *testing.T) {
+	authMsg := "msg-auth"
+	docsMsg := "msg-docs"
+	branchMsg := "msg-branch"
+	claims := []types.Claim{
+		{ClaimType: types.ClaimTypeFile, Pattern: "src/auth/*.go", CreatedAt: 1, MessageGUID: &authMsg},
+		{ClaimType: types.ClaimTypeBranch, Pattern: "feature/*", CreatedAt: 2, MessageGUID: &branchMsg},
+		{ClaimType: types.ClaimTypeFile, Pattern: "docs/*.md", CreatedAt: 3, MessageGUID: &docsMsg},
+		{ClaimType: types.ClaimTypeBD, Pattern: "fray-1", CreatedAt: 4},
+	}
+	cases := []struct {
+		name   string
+		commit commitInfo
+		want   string
+	}{
+		{"file claim", commitInfo{Files: []string{"src/auth/login.go"}}, authMsg},
+		{"branch claim", commitInfo{Branch: "feature/login", Files: []string{"Makefile"}}, branchMsg},
+		{"newest covering", commitInfo{Branch: "feature/login", Files: []string{"docs/auth.md"}}, docsMsg},
+		{"latest announced", commitInfo{Files: []string{"Makefile"}}, docsMsg},
+	}
+	for _, tc := range cases {
+		claim := pickCommitClaim(claims, tc.commit)
+		if claim == nil || *claim.MessageGUID != tc.want {
+			t.Fatalf("%s: expected %s, got %+v", tc.name, tc.want, claim)
+		}
+	}
+	if claim := pickCommitClaim(claims[3:], commitInfo{}); claim != nil {
+		t.Fatalf("expected no claim without an announcement, got %+v", claim)
+	}
+}
+
+func TestBuildCommitEventBody(t *testing.T) {
+	commit := commitInfo{
+		SHA:     "abcdef1234567890",
+		Subject: "fix auth",
+		Branch:  "main",
+		Files:   []string{"a.go"},
+	}
+	refs := []types.Message{{ID: "msg-aaa"}, {ID: "msg-bbb"}}
+	body := buildCommitEventBody(commit, refs)
+	expected := "committed abcdef1 on main: fix auth (1 file)\nrefs #msg-bbb"
+	if body != expected {
+		t.Fatalf("unexpected body: %q", body)
+	}
+}
@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"os"
@@ -58,14 +59,9 @@ func runHookPrecommit(cmd *cobra.Command) int {
 	}
 
 	byAgent := groupClaimsByAgent(conflicts, stagedFiles)
-	printPrecommitConflicts(cmd.ErrOrStderr(), byAgent)
+	printFileClaimConflicts(cmd.ErrOrStderr(), byAgent, "staged files", "committing")
 
-	strictMode := false
-	if raw, err := db.GetConfig(dbConn, "precommit_strict"); err == nil {
-		strictMode = raw == "true"
-	}
-
-	if strictMode {
+	if isStrictClaimMode(dbConn) {
 		fmt.Fprintln(cmd.ErrOrStderr(), "Commit blocked (precommit_strict mode enabled).")
 		fmt.Fprintln(cmd.ErrOrStderr(), "Use \"fray config precommit_strict false\" to disable strict mode.")
 		fmt.Fprintln(cmd.ErrOrStderr(), "")
@@ -109,11 +105,11 @@ func matchClaimFiles(pattern string, files []string) []string {
 	return matched
 }
 
-func printPrecommitConflicts(errOut io.Writer, byAgent map[string][]claimMatch) {
+func printFileClaimConflicts(errOut io.Writer, byAgent map[string][]claimMatch, subject, action string) {
 	fmt.Fprintln(errOut, "")
 	fmt.Fprintln(errOut, "FILE CLAIM CONFLICTS DETECTED")
 	fmt.Fprintln(errOut, "")
-	fmt.Fprintln(errOut, "The following staged files are claimed by other agents:")
+	fmt.Fprintf(errOut, "The following %s are claimed by other agents:\n", subject)
 	fmt.Fprintln(errOut, "")
 
 	for agent, claims := range byAgent {
@@ -130,11 +126,20 @@ func printPrecommitConflicts(errOut io.Writer, byAgent map[string][]claimMatch)
 	}
 
 	fmt.Fprintln(errOut, "")
-	fmt.Fprintln(errOut, "Consider coordinating with these agents before committing.")
+	fmt.Fprintf(errOut, "Consider coordinating with these agents before %s.\n", action)
 	fmt.Fprintln(errOut, "Use \"fray claims\" to see all active claims.")
 	fmt.Fprintln(errOut, "")
 }
 
+// isStrictClaimMode reports whether claim conflicts should block git operations.
+func isStrictClaimMode(dbConn *sql.DB) bool {
+	raw, err := db.GetConfig(dbConn, "precommit_strict")
+	if err != nil {
+		return false
+	}
+	return raw == "true"
+}
+
 func gitStagedFiles(projectRoot string) ([]string, error) {
 	cmd := exec.Command("git", "diff", "--cached", "--name-only")
 	cmd.Dir = projectRoot
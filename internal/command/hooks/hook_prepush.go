@@ -0,0 +1,217 @@
+package hooks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// pushRef is a single ref update reported to the git pre-push hook on stdin.
+type pushRef struct {
+	LocalRef  string
+	LocalSHA  string
+	RemoteRef string
+	RemoteSHA string
+}
+
+// NewHookPrepushCmd implements git pre-push claim checks.
+func NewHookPrepushCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook-prepush [remote] [url]",
+		Short: "Git pre-push hook for file and branch claim conflict detection",
+		Args:  cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exitCode := runHookPrepush(cmd, cmd.InOrStdin())
+			os.Exit(exitCode)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func runHookPrepush(cmd *cobra.Command, stdin io.Reader) int {
+	agentID := os.Getenv("FRAY_AGENT_ID")
+
+	refs := parsePushRefs(stdin)
+	if len(refs) == 0 {
+		return 0
+	}
+
+	projectPath := os.Getenv("CLAUDE_PROJECT_DIR")
+	project, err := core.DiscoverProject(projectPath)
+	if err != nil {
+		return 0
+	}
+
+	dbConn, err := db.OpenDatabase(project)
+	if err != nil {
+		return 0
+	}
+	defer dbConn.Close()
+	if err := db.InitSchema(dbConn); err != nil {
+		return 0
+	}
+
+	var files []string
+	var branches []string
+	for _, ref := range refs {
+		pushed, err := gitPushedFiles(project.Root, ref)
+		if err == nil {
+			files = append(files, pushed...)
+		}
+		branches = append(branches, pushBranchNames(ref)...)
+	}
+	files = UniqueSorted(files)
+	branches = UniqueSorted(branches)
+
+	fileConflicts, err := db.FindConflictingFileClaims(dbConn, files, agentID)
+	if err != nil {
+		return 0
+	}
+	branchConflicts, err := db.FindConflictingBranchClaims(dbConn, branches, agentID)
+	if err != nil {
+		return 0
+	}
+	if len(fileConflicts) == 0 && len(branchConflicts) == 0 {
+		return 0
+	}
+
+	errOut := cmd.ErrOrStderr()
+	if len(fileConflicts) > 0 {
+		printFileClaimConflicts(errOut, groupClaimsByAgent(fileConflicts, files), "pushed files", "pushing")
+	}
+	if len(branchConflicts) > 0 {
+		printBranchClaimConflicts(errOut, branchConflicts, branches)
+	}
+
+	if isStrictClaimMode(dbConn) {
+		fmt.Fprintln(errOut, "Push blocked (precommit_strict mode enabled).")
+		fmt.Fprintln(errOut, "Use \"fray config precommit_strict false\" to disable strict mode.")
+		fmt.Fprintln(errOut, "")
+		return 1
+	}
+
+	fmt.Fprintln(errOut, "Proceeding with push (advisory mode).")
+	fmt.Fprintln(errOut, "Use \"fray config precommit_strict true\" to block pushes with conflicts.")
+	fmt.Fprintln(errOut, "")
+	return 0
+}
+
+// parsePushRefs reads "<local ref> <local sha> <remote ref> <remote sha>" lines.
+func parsePushRefs(r io.Reader) []pushRef {
+	var refs []pushRef
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		ref := pushRef{LocalRef: fields[0], LocalSHA: fields[1], RemoteRef: fields[2], RemoteSHA: fields[3]}
+		if ref.LocalSHA == zeroSHA {
+			// Branch deletion: nothing new is being pushed.
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func pushBranchNames(ref pushRef) []string {
+	var names []string
+	for _, name := range []string{ref.LocalRef, ref.RemoteRef} {
+		if !strings.HasPrefix(name, "refs/heads/") {
+			continue
+		}
+		names = append(names, strings.TrimPrefix(name, "refs/heads/"))
+	}
+	return names
+}
+
+// gitPushedFiles lists files touched by commits in the push that the remote
+// does not have yet.
+func gitPushedFiles(projectRoot string, ref pushRef) ([]string, error) {
+	args := []string{"log", "--name-only", "--pretty=format:", ref.LocalSHA}
+	if ref.RemoteSHA == zeroSHA {
+		args = append(args, "--not", "--remotes")
+	} else {
+		args = append(args, "^"+ref.RemoteSHA)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return SplitGitLines(string(output)), nil
+}
+
+func gitCurrentBranch(projectRoot string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = projectRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+func printBranchClaimConflicts(errOut io.Writer, conflicts []types.Claim, branches []string) {
+	fmt.Fprintln(errOut, "")
+	fmt.Fprintln(errOut, "BRANCH CLAIM CONFLICTS DETECTED")
+	fmt.Fprintln(errOut, "")
+	for _, claim := range conflicts {
+		matched := matchClaimFiles(claim.Pattern, branches)
+		if len(matched) == 0 {
+			fmt.Fprintf(errOut, "  @%s: branch:%s\n", claim.AgentID, claim.Pattern)
+			continue
+		}
+		for _, branch := range matched {
+			fmt.Fprintf(errOut, "  @%s: %s (claimed via branch:%s)\n", claim.AgentID, branch, claim.Pattern)
+		}
+	}
+	fmt.Fprintln(errOut, "")
+}
+
+// SplitGitLines splits git output into trimmed, non-empty lines.
+func SplitGitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return lines
+}
+
+// UniqueSorted returns the distinct values in sorted order.
+func UniqueSorted(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		unique = append(unique, value)
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,36 @@
+package hooks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePushRefs(t *testing.T) {
+	input := strings.Join([]string{
+		"refs/heads/feat/auth abc123 refs/heads/feat/auth " + zeroSHA,
+		"(delete) " + zeroSHA + " refs/heads/old def456",
+		"malformed line",
+	}, "\n")
+	refs := parsePushRefs(strings.NewReader(input))
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 ref, got %#v", refs)
+	}
+	if refs[0].LocalSHA != "abc123" || refs[0].RemoteSHA != zeroSHA {
+		t.Fatalf("unexpected ref: %#v", refs[0])
+	}
+}
+
+func TestPushBranchNames(t *testing.T) {
+	ref := pushRef{LocalRef: "refs/heads/feat/auth", RemoteRef: "refs/heads/main"}
+	names := pushBranchNames(ref)
+	expected := []string{"feat/auth", "main"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("unexpected branch names: %#v", names)
+	}
+
+	tagRef := pushRef{LocalRef: "refs/tags/v1", RemoteRef: "refs/tags/v1"}
+	if names := pushBranchNames(tagRef); len(names) != 0 {
+		t.Fatalf("expected no branch names for tags, got %#v", names)
+	}
+}
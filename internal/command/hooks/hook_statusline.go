@@ -63,6 +63,11 @@ func buildStatusline(dbConn *sql.DB, agentID string) string {
 		parts = append(parts, strings.Join(qParts, " "))
 	}
 
+	// Open tasks: the agent's own, or everyone's without an agent
+	if tasksSummary := buildTasksSummary(dbConn, agentID); tasksSummary != "" {
+		parts = append(parts, tasksSummary)
+	}
+
 	// Claims by agent
 	claimsSummary := buildClaimsSummary(dbConn, agentID)
 	if claimsSummary != "" {
@@ -95,6 +100,35 @@ func countQuestions(dbConn *sql.DB) (asked, wondered int) {
 	return
 }
 
+func buildTasksSummary(dbConn *sql.DB, agentID string) string {
+	opts := &types.TaskQueryOptions{
+		Statuses: []types.TaskStatus{types.TaskStatusInProgress, types.TaskStatusTodo, types.TaskStatusBlocked},
+	}
+	if agentID != "" {
+		opts.Assignee = &agentID
+	}
+	tasks, err := db.GetTasks(dbConn, opts)
+	if err != nil || len(tasks) == 0 {
+		return ""
+	}
+
+	counts := make(map[types.TaskStatus]int)
+	for _, task := range tasks {
+		counts[task.Status]++
+	}
+	var tParts []string
+	if n := counts[types.TaskStatusInProgress]; n > 0 {
+		tParts = append(tParts, fmt.Sprintf("doing:%d", n))
+	}
+	if n := counts[types.TaskStatusTodo]; n > 0 {
+		tParts = append(tParts, fmt.Sprintf("todo:%d", n))
+	}
+	if n := counts[types.TaskStatusBlocked]; n > 0 {
+		tParts = append(tParts, fmt.Sprintf("blocked:%d", n))
+	}
+	return "tasks: " + strings.Join(tParts, " ")
+}
+
 func buildClaimsSummary(dbConn *sql.DB, currentAgent string) string {
 	claims, err := db.GetAllClaims(dbConn)
 	if err != nil || len(claims) == 0 {
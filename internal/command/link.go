@@ -0,0 +1,295 @@
+package command
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// NewLinkCmd creates the link command.
+func NewLinkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link <alias> <path>",
+		Short: "Link another fray project under an alias",
+		Long: `Link another fray project so it can be read from this one.
+
+The path may be the project directory (or any directory inside it), its .fray
+directory, or its fray.db. Linked projects are reachable with --project <alias>
+and are included by --all-linked on get, questions, and here.
+
+Examples:
+  fray link api ../api              # Link a sibling repo
+  fray --project api get --last 5   # Read its room
+  fray get --all-linked --as alice  # Watch every linked channel at once`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			alias := strings.TrimPrefix(strings.TrimSpace(args[0]), "#")
+			if alias == "" || strings.ContainsAny(alias, ": /\t") {
+				return writeCommandError(cmd, fmt.Errorf("invalid alias %q: use letters, digits, dashes, or dots", args[0]))
+			}
+
+			project, err := resolveLinkTarget(args[1])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if project.DBPath == ctx.Project.DBPath {
+				return writeCommandError(cmd, fmt.Errorf("cannot link a project to itself"))
+			}
+
+			// Create the target's cache so --project can open it right away
+			linkedDB, err := db.OpenDatabase(project)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			err = db.InitSchema(linkedDB)
+			linkedDB.Close()
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			existing, err := db.GetLinkedProject(ctx.DB, alias)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			var before map[string]any
+			if existing != nil {
+				before = map[string]any{"path": existing.Path}
+			}
+			if err := writeAudit(cmd, ctx, db.AuditProjectLink, alias, before, map[string]any{"path": project.DBPath}); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := db.LinkProject(ctx.DB, alias, project.DBPath); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			channelName := GetProjectName(project.Root)
+			if config, err := db.ReadProjectConfig(project.DBPath); err == nil && config != nil && config.ChannelName != "" {
+				channelName = config.ChannelName
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"alias":   alias,
+					"path":    project.DBPath,
+					"channel": channelName,
+					"updated": existing != nil,
+				})
+			}
+			verb := "Linked"
+			if existing != nil {
+				verb = "Updated link"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s → %s (#%s)\n", verb, alias, project.Root, channelName)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewUnlinkCmd creates the unlink command.
+func NewUnlinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlink <alias>",
+		Short: "Remove a linked project",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			alias := strings.TrimPrefix(args[0], "#")
+			existing, err := db.GetLinkedProject(ctx.DB, alias)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if existing == nil {
+				return writeCommandError(cmd, fmt.Errorf("linked project '%s' not found", alias))
+			}
+			if err := writeAudit(cmd, ctx, db.AuditProjectUnlink, alias, map[string]any{"path": existing.Path}, nil); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if _, err := db.UnlinkProject(ctx.DB, alias); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{"alias": alias, "removed": true})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Unlinked %s\n", alias)
+			return nil
+		},
+	}
+}
+
+// NewLinksCmd creates the links command.
+func NewLinksCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "links",
+		Short: "List linked projects",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			links, err := db.GetLinkedProjects(ctx.DB)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			type linkInfo struct {
+				types.LinkedProject
+				Channel string `json:"channel,omitempty"`
+				Missing bool   `json:"missing,omitempty"`
+			}
+			infos := make([]linkInfo, 0, len(links))
+			for _, link := range links {
+				info := linkInfo{LinkedProject: link}
+				if _, err := os.Stat(link.Path); err != nil {
+					info.Missing = true
+				} else if config, err := db.ReadProjectConfig(link.Path); err == nil && config != nil {
+					info.Channel = config.ChannelName
+				}
+				infos = append(infos, info)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(infos)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(infos) == 0 {
+				fmt.Fprintln(out, "No linked projects. Use 'fray link <alias> <path>' to add one")
+				return nil
+			}
+			for _, info := range infos {
+				suffix := ""
+				if info.Missing {
+					suffix = " (missing)"
+				} else if info.Channel != "" {
+					suffix = " #" + info.Channel
+				}
+				fmt.Fprintf(out, "  %s → %s%s\n", info.Alias, info.Path, suffix)
+			}
+			return nil
+		},
+	}
+}
+
+// resolveLinkTarget finds the fray project for a link path.
+func resolveLinkTarget(path string) (core.Project, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return core.Project{}, err
+	}
+	if !info.IsDir() {
+		// A fray.db path: start from the directory holding .fray
+		path = strings.TrimSuffix(path, string(os.PathSeparator)+"fray.db")
+	}
+	if strings.HasSuffix(strings.TrimRight(path, string(os.PathSeparator)), ".fray") {
+		path = strings.TrimSuffix(strings.TrimRight(path, string(os.PathSeparator)), ".fray")
+		if path == "" {
+			path = "."
+		}
+	}
+	project, err := core.DiscoverProject(path)
+	if err != nil {
+		return core.Project{}, fmt.Errorf("no fray project at %s: %w", path, err)
+	}
+	return project, nil
+}
+
+// linkedChannel is an open project taking part in an --all-linked read.
+type linkedChannel struct {
+	Name    string // alias for linked projects, channel name for the current one
+	Project core.Project
+	DB      *sql.DB
+	Config  *db.ProjectConfig
+}
+
+// openLinkedChannels returns the current project followed by every linked
+// project that can be opened. Unreachable links are reported on stderr and
+// skipped. The returned func closes the linked databases.
+func openLinkedChannels(cmd *cobra.Command, ctx *CommandContext) ([]linkedChannel, func(), error) {
+	localName := ctx.ChannelName
+	if localName == "" && ctx.ProjectConfig != nil {
+		localName = ctx.ProjectConfig.ChannelName
+	}
+	if localName == "" {
+		localName = GetProjectName(ctx.Project.Root)
+	}
+	channels := []linkedChannel{{Name: localName, Project: ctx.Project, DB: ctx.DB, Config: ctx.ProjectConfig}}
+
+	var opened []*sql.DB
+	closeAll := func() {
+		for _, conn := range opened {
+			conn.Close()
+		}
+	}
+
+	links, err := db.GetLinkedProjects(ctx.DB)
+	if err != nil {
+		return nil, closeAll, err
+	}
+	for _, link := range links {
+		if link.Path == ctx.Project.DBPath {
+			continue
+		}
+		project, err := projectFromDBPath(link.Path)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping linked project %s: %v\n", link.Alias, err)
+			continue
+		}
+		conn, err := db.OpenDatabase(project)
+		if err == nil {
+			err = db.InitSchema(conn)
+			if err != nil {
+				conn.Close()
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping linked project %s: %v\n", link.Alias, err)
+			continue
+		}
+		opened = append(opened, conn)
+		config, _ := db.ReadProjectConfig(project.DBPath)
+		channels = append(channels, linkedChannel{Name: link.Alias, Project: project, DB: conn, Config: config})
+	}
+	return channels, closeAll, nil
+}
+
+// qualifyID prefixes an ID with its channel for federated output.
+func qualifyID(channel, id string) string {
+	return channel + ":" + id
+}
+
+// channelMessage is a message tagged with the channel it was read from.
+type channelMessage struct {
+	Channel string `json:"channel"`
+	types.Message
+}
+
+// sortChannelMessages orders federated messages by time.
+func sortChannelMessages(messages []channelMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].TS < messages[j].TS
+	})
+}
@@ -50,6 +50,11 @@ func NewMergeCmd() *cobra.Command {
 				return writeCommandError(cmd, fmt.Errorf("agent not found: @%s", toID))
 			}
 
+			if err := writeAudit(cmd, ctx, db.AuditAgentMerge, "@"+fromID,
+				map[string]any{"agent_id": fromID}, map[string]any{"agent_id": toID}); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
 			moved, err := db.MergeAgentHistory(ctx.DB, fromID, toID)
 			if err != nil {
 				return writeCommandError(cmd, err)
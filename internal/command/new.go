@@ -489,6 +489,7 @@ func ensureAgentHierarchy(ctx *CommandContext, agentID string) error {
 			Name:         agentID,
 			ParentThread: &metaThread.GUID,
 			Type:         types.ThreadTypeKnowledge,
+			OwnerAgent:   &agentID,
 		})
 		if err != nil {
 			return err
@@ -513,6 +514,8 @@ func ensureAgentHierarchy(ctx *CommandContext, agentID string) error {
 			Name:         "notes",
 			ParentThread: &agentThread.GUID,
 			Type:         types.ThreadTypeSystem,
+			OwnerAgent:   &agentID,
+			Writers:      []string{agentID},
 		})
 		if err != nil {
 			return err
@@ -535,6 +538,8 @@ func ensureAgentHierarchy(ctx *CommandContext, agentID string) error {
 			Name:         "jrnl",
 			ParentThread: &agentThread.GUID,
 			Type:         types.ThreadTypeSystem,
+			OwnerAgent:   &agentID,
+			Writers:      []string{agentID},
 		})
 		if err != nil {
 			return err
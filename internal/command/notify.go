@@ -0,0 +1,216 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+// NewNotifyCmd creates the notify command tree.
+func NewNotifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Show notification rules, sinks, and quiet hours",
+		Long: `Show the notification rules from ~/.config/fray/notify.json.
+
+Rules match events (message, mention, fyi, reply, question, claim_conflict,
+daemon_error) by keyword, author, and thread, and send them to sinks: "os"
+desktop notifications, shell "command" hooks, or "webhook" URLs. Digest rules
+and notifications during quiet hours are queued and sent together later.
+Without notify.json, chat notifies on direct mentions and replies.
+
+Examples:
+  fray notify                                # Show rules and sinks
+  fray notify test --sink hook               # Send a test notification to one sink
+  fray notify test --kind message --from alice --thread design/api "deploy failed"
+  fray notify flush                          # Send queued digests now
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			notifier, err := notify.Load(notify.Options{})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			config := notifier.Config()
+			path, _ := core.NotifyConfigPath()
+			pending, _ := notifier.Pending()
+
+			if jsonMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"path":    path,
+					"config":  config,
+					"pending": pending,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Config: %s\n", path)
+			fmt.Fprintln(out, "Rules:")
+			for _, rule := range config.Rules {
+				fmt.Fprintf(out, "  %s\n", describeNotifyRule(rule))
+			}
+			fmt.Fprintf(out, "Sinks: %s\n", strings.Join(notifier.SinkNames(), ", "))
+			if config.QuietHours != nil {
+				fmt.Fprintf(out, "Quiet hours: %s-%s\n", config.QuietHours.Start, config.QuietHours.End)
+			}
+			interval, _ := config.Digest()
+			fmt.Fprintf(out, "Digest interval: %s (%d queued)\n", interval, pending)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newNotifyTestCmd(), newNotifyFlushCmd())
+	return cmd
+}
+
+func newNotifyTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [body]",
+		Short: "Send a test notification",
+		Long: `Send a test notification.
+
+With --sink, the notification goes straight to that sink. Otherwise a test
+event runs through the rules, honoring quiet hours and digests, and the
+matching rules are listed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			sinkName, _ := cmd.Flags().GetString("sink")
+			kind, _ := cmd.Flags().GetString("kind")
+			from, _ := cmd.Flags().GetString("from")
+			thread, _ := cmd.Flags().GetString("thread")
+
+			notifier, err := notify.Load(notify.Options{})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			body := "Test notification from fray"
+			if len(args) > 0 {
+				body = args[0]
+			}
+			event := notify.Event{
+				Kinds:      []string{kind},
+				From:       strings.TrimPrefix(from, "@"),
+				ThreadPath: thread,
+				Title:      "fray · test",
+				Body:       body,
+			}
+			if thread != "" && thread != "room" {
+				event.ThreadGUID = thread
+			}
+
+			if sinkName != "" {
+				sink, ok := notifier.Sink(sinkName)
+				if !ok {
+					return writeCommandError(cmd, fmt.Errorf("unknown sink: %s", sinkName))
+				}
+				if err := sink.Send(notify.Notification{Rule: "test", Title: event.Title, Body: event.Body, Events: []notify.Event{event}}); err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if jsonMode {
+					return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{"sink": sinkName, "sent": true})
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Sent test notification to %s\n", sinkName)
+				return nil
+			}
+
+			switch kind {
+			case core.NotifyMention, core.NotifyFYI, core.NotifyReply:
+				// Chat tags these as messages too.
+				event.Kinds = append(event.Kinds, core.NotifyMessage)
+			}
+			matched := notifier.Match(event)
+			if err := notifier.Notify(event); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			names := make([]string, 0, len(matched))
+			for _, rule := range matched {
+				names = append(names, rule.Name)
+			}
+			if jsonMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{"matched": names})
+			}
+			if len(matched) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No rules matched")
+				return nil
+			}
+			for _, rule := range matched {
+				fmt.Fprintf(cmd.OutOrStdout(), "Matched %s\n", describeNotifyRule(rule))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("sink", "", "send directly to this sink, bypassing rules")
+	cmd.Flags().String("kind", core.NotifyMention, "event kind to test")
+	cmd.Flags().String("from", "", "event author")
+	cmd.Flags().String("thread", "", "thread path for the event (or room)")
+	return cmd
+}
+
+func newNotifyFlushCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flush",
+		Short: "Send queued digest and quiet-hours notifications now",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			notifier, err := notify.Load(notify.Options{})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			pending, err := notifier.Pending()
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := notifier.FlushNow(); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if jsonMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{"flushed": pending})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Flushed %d queued notification(s)\n", pending)
+			return nil
+		},
+	}
+}
+
+func describeNotifyRule(rule core.NotifyRule) string {
+	var parts []string
+	if len(rule.On) > 0 {
+		parts = append(parts, "on "+strings.Join(rule.On, ","))
+	} else {
+		parts = append(parts, "on any event")
+	}
+	if len(rule.Keywords) > 0 {
+		parts = append(parts, "keywords "+strings.Join(rule.Keywords, ","))
+	}
+	if len(rule.From) > 0 {
+		parts = append(parts, "from "+strings.Join(rule.From, ","))
+	}
+	if len(rule.Threads) > 0 {
+		parts = append(parts, "in "+strings.Join(rule.Threads, ","))
+	}
+	if rule.Ignore {
+		parts = append(parts, "→ ignore")
+	} else {
+		sinks := rule.Sinks
+		if len(sinks) == 0 {
+			sinks = []string{core.NotifySinkOS}
+		}
+		parts = append(parts, "→ "+strings.Join(sinks, ","))
+	}
+	if rule.Digest {
+		parts = append(parts, "(digest)")
+	}
+	if rule.Urgent {
+		parts = append(parts, "(urgent)")
+	}
+	return rule.Name + ": " + strings.Join(parts, " ")
+}
@@ -25,7 +25,16 @@ Paths:
   fray post meta "msg"               Post to project meta
   fray post opus/notes "msg"         Post to agent's notes
   fray post design-thread "msg"      Post to thread by name
-  fray post roles/architect/keys "msg"  Post to role's keys`,
+  fray post roles/architect/keys "msg"  Post to role's keys
+
+Attachments are stored content-addressed in .fray/blobs/ and shown as chips:
+  fray post "test output" --attach build.log --attach diff.patch
+  fray get <msg> --attachment build.log
+
+Scheduled sends are queued and delivered by the daemon or the next fray command
+once due (list and cancel with 'fray scheduled'):
+  fray post "standup in 5" --as alice --at 9:55am
+  fray post design-thread "ping again?" --as alice --at 2h`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := GetContext(cmd)
@@ -40,6 +49,11 @@ Paths:
 			answerRef, _ := cmd.Flags().GetString("answer")
 			quoteRef, _ := cmd.Flags().GetString("quote")
 			silent, _ := cmd.Flags().GetBool("silent")
+			attachPaths, _ := cmd.Flags().GetStringArray("attach")
+			sendAtRef, _ := cmd.Flags().GetString("at")
+			if sendAtRef != "" && (replyTo != "" || answerRef != "" || quoteRef != "" || len(attachPaths) > 0) {
+				return writeCommandError(cmd, fmt.Errorf("--at cannot be combined with --reply-to, --answer, --quote or --attach"))
+			}
 
 			// Determine path and message body
 			var messageBody string
@@ -138,6 +152,43 @@ Paths:
 				if err != nil {
 					return writeCommandError(cmd, err)
 				}
+				if !isHumanUser {
+					if err := checkThreadPermission(ctx, thread, agentID, threadActionWrite); err != nil {
+						return writeCommandError(cmd, err)
+					}
+				}
+			}
+
+			if sendAtRef != "" {
+				sendAt, err := core.ParseSendTime(sendAtRef, time.Now())
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				msgType := types.MessageTypeAgent
+				if isHumanUser {
+					msgType = types.MessageTypeUser
+				}
+				scheduled := types.ScheduledMessage{
+					FromAgent: agentID,
+					Body:      messageBody,
+					Type:      msgType,
+					SendAt:    sendAt.Unix(),
+				}
+				if thread != nil {
+					scheduled.Home = thread.GUID
+				}
+				scheduled, err = db.AppendScheduledMessage(ctx.Project.DBPath, scheduled)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if silent {
+					return nil
+				}
+				if ctx.JSONMode {
+					return json.NewEncoder(cmd.OutOrStdout()).Encode(scheduled)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "[%s] Scheduled as @%s for %s\n", scheduled.GUID, agentID, sendAt.Format("Mon Jan 2 15:04"))
+				return nil
 			}
 
 			var replyID *string
@@ -161,7 +212,7 @@ Paths:
 			}
 
 			reactionText := ""
-			if replyID != nil && answerRef == "" {
+			if replyID != nil && answerRef == "" && len(attachPaths) == 0 {
 				if reaction, ok := core.NormalizeReactionText(messageBody); ok {
 					reactionText = reaction
 				}
@@ -178,6 +229,11 @@ Paths:
 					return writeCommandError(cmd, err)
 				}
 
+				effect, err := db.ApplyReactionMeaning(ctx.DB, ctx.Project.DBPath, *updated, agentID, reactionText, reactedAt)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+
 				if !isHumanUser {
 					now := time.Now().Unix()
 					updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
@@ -197,10 +253,16 @@ Paths:
 						"reaction":   reactionText,
 						"reacted_at": reactedAt,
 					}
+					if effect != nil {
+						payload["effect"] = effect
+					}
 					return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
 				}
 
 				fmt.Fprintf(cmd.OutOrStdout(), "Reacted %q to #%s\n", reactionText, updated.ID)
+				if summary := effect.Summary(); summary != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "  %s: %s\n", effect.Meaning, summary)
+				}
 				return nil
 			}
 
@@ -225,6 +287,17 @@ Paths:
 			if isHumanUser {
 				msgType = types.MessageTypeUser
 			}
+			var attachments []types.Attachment
+			for _, path := range attachPaths {
+				attachment, err := db.StoreBlob(ctx.Project.DBPath, path)
+				if err != nil {
+					return writeCommandError(cmd, fmt.Errorf("attach %s: %w", path, err))
+				}
+				if hasAttachment(attachments, attachment) {
+					continue
+				}
+				attachments = append(attachments, attachment)
+			}
 			created, err := db.CreateMessage(ctx.DB, types.Message{
 				TS:               now,
 				FromAgent:        agentID,
@@ -234,6 +307,7 @@ Paths:
 				ReplyTo:          replyID,
 				QuoteMessageGUID: quoteID,
 				Type:             msgType,
+				Attachments:      attachments,
 			})
 			if err != nil {
 				return writeCommandError(cmd, err)
@@ -243,6 +317,12 @@ Paths:
 				return writeCommandError(cmd, err)
 			}
 
+			// Deliver linked copies for @channel:agent mentions
+			forwarded, err := db.ForwardChannelMentions(ctx.Project.DBPath, created)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			}
+
 			// Implicit subscription: posting to a thread subscribes the poster
 			if thread != nil {
 				if err := subscribeAgentToThread(ctx, thread.GUID, agentID, now, "post"); err != nil {
@@ -417,6 +497,12 @@ Paths:
 					"reply_to": replyID,
 					"unread":   len(filtered),
 				}
+				if len(attachments) > 0 {
+					payload["attachments"] = attachments
+				}
+				if len(forwarded) > 0 {
+					payload["forwarded"] = forwarded
+				}
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
 			}
 
@@ -426,6 +512,12 @@ Paths:
 				replyInfo = fmt.Sprintf(" (reply to #%s)", *replyID)
 			}
 			fmt.Fprintf(out, "[%s] Posted as @%s%s\n", created.ID, agentID, replyInfo)
+			for _, attachment := range attachments {
+				fmt.Fprintf(out, "  attached %s\n", core.AttachmentLabel(attachment))
+			}
+			for _, fwd := range forwarded {
+				fmt.Fprintf(out, "  forwarded to #%s as %s\n", fwd.Channel, qualifyID(fwd.Channel, fwd.Message.ID))
+			}
 
 			if len(filtered) > 0 {
 				fmt.Fprintf(out, "\n%d unread @%s:\n", len(filtered), agentBase)
@@ -493,8 +585,19 @@ Paths:
 	cmd.Flags().String("answer", "", "answer a question by guid or text")
 	cmd.Flags().StringP("quote", "q", "", "quote message GUID (inline quote)")
 	cmd.Flags().BoolP("silent", "s", false, "suppress output including unread mentions")
+	cmd.Flags().StringArray("attach", nil, "attach a file (repeatable)")
+	cmd.Flags().String("at", "", "schedule for later (30m, 2h, 17:30, tomorrow 9am, 2006-01-02 15:04)")
 
 	_ = cmd.MarkFlagRequired("as")
 
 	return cmd
 }
+
+func hasAttachment(attachments []types.Attachment, attachment types.Attachment) bool {
+	for _, existing := range attachments {
+		if existing.Name == attachment.Name && existing.Hash == attachment.Hash {
+			return true
+		}
+	}
+	return false
+}
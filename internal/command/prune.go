@@ -7,10 +7,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +22,36 @@ func NewPruneCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "prune",
 		Short: "Archive old messages with cold storage guardrails",
+		Long: `Archive old messages to history.jsonl, keeping the last N.
+
+With --policy, the retention rules in fray-config.json decide instead. Rules
+are checked in order and the first that matches the room or a thread applies;
+scopes no rule matches are left alone:
+
+  "retention": {
+    "every": "1d",
+    "rules": [
+      {"type": "knowledge", "forever": true},
+      {"thread": "notes/**", "keep": 200},
+      {"thread": "room", "max_age": "30d", "keep": 50},
+      {"max_age": "14d", "exempt": ["pinned"]}
+    ]
+  }
+
+thread is a path glob ("room" for the room, "a/**" for a subtree) and type a
+thread type. max_age prunes older messages, keep always keeps the newest N per
+scope, and forever keeps everything. Pinned, faved, and question-linked
+messages are exempt unless exempt lists a subset. A message added to a thread
+is also in that thread's scope and is kept if any of its scopes keeps it. fray
+daemon runs the policy every interval if "every" is set; those runs allow
+uncommitted .fray changes (the daemon's own writes) but still require the
+branch to be in sync. Other fray processes wait while messages.jsonl is
+rewritten.
+
+Examples:
+  fray prune --keep 100
+  fray prune --policy --dry-run    # Show what each rule would prune
+  fray prune --policy`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := GetContext(cmd)
 			if err != nil {
@@ -28,6 +61,21 @@ func NewPruneCmd() *cobra.Command {
 
 			keep, _ := cmd.Flags().GetInt("keep")
 			pruneAll, _ := cmd.Flags().GetBool("all")
+			policy, _ := cmd.Flags().GetBool("policy")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if policy {
+				if pruneAll || cmd.Flags().Changed("keep") {
+					return writeCommandError(cmd, fmt.Errorf("--policy can't be combined with --keep or --all"))
+				}
+				return runPrunePolicy(cmd, ctx, dryRun)
+			}
+			if scheduled, _ := cmd.Flags().GetBool("scheduled"); scheduled {
+				return writeCommandError(cmd, fmt.Errorf("--scheduled requires --policy"))
+			}
+			if dryRun {
+				return writeCommandError(cmd, fmt.Errorf("--dry-run requires --policy"))
+			}
 
 			if keep < 0 {
 				return writeCommandError(cmd, fmt.Errorf("invalid --keep value: %d", keep))
@@ -37,20 +85,36 @@ func NewPruneCmd() *cobra.Command {
 				return writeCommandError(cmd, err)
 			}
 
-			result, err := pruneMessages(ctx.Project.DBPath, keep, pruneAll)
+			unlock, err := db.LockJSONLForRewrite(ctx.Project.DBPath)
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
-
-			if err := db.RebuildDatabaseFromJSONL(ctx.DB, ctx.Project.DBPath); err != nil {
+			result, err := pruneMessages(ctx.Project.DBPath, keep, pruneAll)
+			if err == nil {
+				err = db.RebuildDatabaseFromJSONL(ctx.DB, ctx.Project.DBPath)
+			}
+			unlock()
+			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "messages.prune", "messages.jsonl",
+				map[string]any{"messages": result.Kept + len(result.Removed)},
+				map[string]any{"kept": result.Kept, "pruned": len(result.Removed), "history_cleared": result.ClearedHistory})
+
+			// Summarize what left each thread; history.jsonl keeps the originals
+			var summaries []types.ThreadSummary
+			if noSummary, _ := cmd.Flags().GetBool("no-summary"); !noSummary {
+				summaries = summarizePrunedThreads(cmd, ctx, result.Removed)
+			}
 
 			if ctx.JSONMode {
 				payload := map[string]any{
 					"kept":     result.Kept,
 					"archived": result.Archived,
 				}
+				if len(summaries) > 0 {
+					payload["summaries"] = summaries
+				}
 				if result.ClearedHistory {
 					payload["history"] = nil
 				} else {
@@ -62,23 +126,133 @@ func NewPruneCmd() *cobra.Command {
 			out := cmd.OutOrStdout()
 			if result.ClearedHistory {
 				fmt.Fprintf(out, "Pruned to last %d messages. history.jsonl cleared.\n", result.Kept)
-				return nil
+			} else {
+				fmt.Fprintf(out, "Pruned to last %d messages. Archived to history.jsonl\n", result.Kept)
+			}
+			for _, summary := range summaries {
+				fmt.Fprintf(out, "  anchored %s: %s\n", summary.MessageGUID, formatSummaryProvenance(summary))
 			}
-			fmt.Fprintf(out, "Pruned to last %d messages. Archived to history.jsonl\n", result.Kept)
 			return nil
 		},
 	}
 
 	cmd.Flags().Int("keep", 20, "number of recent messages to keep")
 	cmd.Flags().Bool("all", false, "delete history.jsonl before pruning")
+	cmd.Flags().Bool("no-summary", false, "skip the configured thread summarizer")
+	cmd.Flags().Bool("policy", false, "prune by the retention rules in fray-config.json")
+	cmd.Flags().Bool("dry-run", false, "with --policy, report without pruning")
+	cmd.Flags().Bool("scheduled", false, "with --policy, run as fray daemon does: allow uncommitted .fray changes")
+	_ = cmd.Flags().MarkHidden("scheduled")
 	return cmd
 }
 
+// runPrunePolicy prunes by the project's retention rules.
+func runPrunePolicy(cmd *cobra.Command, ctx *CommandContext, dryRun bool) error {
+	config, err := db.ReadProjectConfig(ctx.Project.DBPath)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if config == nil || config.Retention == nil || len(config.Retention.Rules) == 0 {
+		return writeCommandError(cmd, fmt.Errorf("no retention rules in fray-config.json (see fray prune --help)"))
+	}
+	rules := config.Retention.Rules
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return writeCommandError(cmd, fmt.Errorf("retention rule %d: %w", i+1, err))
+		}
+	}
+
+	if dryRun {
+		plan, err := planRetention(ctx, rules, time.Now())
+		if err != nil {
+			return writeCommandError(cmd, err)
+		}
+		return writeRetentionResult(cmd, ctx, plan, true, nil)
+	}
+
+	// The daemon's own writes keep .fray dirty, so scheduled runs only require
+	// the branch to be in sync; originals still go to history.jsonl first.
+	scheduled, _ := cmd.Flags().GetBool("scheduled")
+	guardrails := checkPruneGuardrails
+	if scheduled {
+		guardrails = checkBranchSynced
+	}
+	if err := guardrails(ctx.Project.Root); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	// Appends from other processes wait until messages.jsonl is rewritten and
+	// re-cached, so none are lost between planning and the rewrite.
+	unlock, err := db.LockJSONLForRewrite(ctx.Project.DBPath)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	plan, err := planRetention(ctx, rules, time.Now())
+	if err == nil && len(plan.Removed) > 0 {
+		err = applyRetentionPlan(ctx.Project.DBPath, plan)
+		if err == nil {
+			err = db.RebuildDatabaseFromJSONL(ctx.DB, ctx.Project.DBPath)
+		}
+	}
+	unlock()
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	var summaries []types.ThreadSummary
+	if len(plan.Removed) > 0 {
+		recordAudit(cmd, ctx, "messages.prune", "messages.jsonl",
+			map[string]any{"messages": len(plan.Kept) + len(plan.Removed)},
+			map[string]any{"kept": len(plan.Kept), "pruned": len(plan.Removed), "policy": true, "scheduled": scheduled})
+		if noSummary, _ := cmd.Flags().GetBool("no-summary"); !noSummary {
+			summaries = summarizePrunedThreads(cmd, ctx, plan.Removed)
+		}
+	}
+	return writeRetentionResult(cmd, ctx, plan, false, summaries)
+}
+
+// writeRetentionResult prints the outcome of a policy prune or dry run.
+func writeRetentionResult(cmd *cobra.Command, ctx *CommandContext, plan *retentionPlan, dryRun bool, summaries []types.ThreadSummary) error {
+	if ctx.JSONMode {
+		payload := map[string]any{
+			"dry_run": dryRun,
+			"scopes":  plan.Scopes,
+			"kept":    len(plan.Kept),
+			"pruned":  len(plan.Removed),
+		}
+		if len(summaries) > 0 {
+			payload["summaries"] = summaries
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
+	}
+
+	out := cmd.OutOrStdout()
+	if dryRun {
+		fmt.Fprintln(out, "Retention plan (dry run):")
+	} else {
+		fmt.Fprintln(out, "Retention:")
+	}
+	writeRetentionReport(out, plan.Scopes)
+	switch {
+	case dryRun:
+		fmt.Fprintf(out, "Would prune %d messages, keeping %d.\n", len(plan.Removed), len(plan.Kept))
+	case len(plan.Removed) == 0:
+		fmt.Fprintln(out, "Nothing to prune.")
+	default:
+		fmt.Fprintf(out, "Pruned %d messages, kept %d. Archived to history.jsonl\n", len(plan.Removed), len(plan.Kept))
+	}
+	for _, summary := range summaries {
+		fmt.Fprintf(out, "  anchored %s: %s\n", summary.MessageGUID, formatSummaryProvenance(summary))
+	}
+	return nil
+}
+
 type pruneResult struct {
 	Kept           int
 	Archived       int
 	HistoryPath    string
 	ClearedHistory bool
+	Removed        []db.MessageJSONLRecord // messages no longer in messages.jsonl
 }
 
 func pruneMessages(projectPath string, keep int, pruneAll bool) (pruneResult, error) {
@@ -210,18 +384,58 @@ func pruneMessages(projectPath string, keep int, pruneAll bool) (pruneResult, er
 		return pruneResult{}, err
 	}
 
+	var removed []db.MessageJSONLRecord
+	for _, msg := range messages {
+		if _, ok := keptIDSet[msg.ID]; !ok {
+			removed = append(removed, msg)
+		}
+	}
+
 	archived := 0
 	if !pruneAll {
 		archived = len(messages)
 	}
 
-	return pruneResult{Kept: len(kept), Archived: archived, HistoryPath: historyPath, ClearedHistory: pruneAll}, nil
+	return pruneResult{Kept: len(kept), Archived: archived, HistoryPath: historyPath, ClearedHistory: pruneAll, Removed: removed}, nil
 }
 
-// collectRequiredMessageIDs gathers message IDs that must be preserved for data integrity.
+// collectRequiredMessageIDs gathers message IDs that must be preserved for data
+// integrity: linked messages, thread members, pins, and the messages questions
+// were asked or answered in. prune --keep has always kept all of these;
+// retention policies can narrow the pin and question exemptions.
 func collectRequiredMessageIDs(projectPath string) (map[string]struct{}, error) {
+	required, err := collectLinkedMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	members, err := collectThreadMembers(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := collectPinnedMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	questioned, err := collectQuestionMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for id := range members {
+		required[id] = struct{}{}
+	}
+	for id := range pinned {
+		required[id] = struct{}{}
+	}
+	for id := range questioned {
+		required[id] = struct{}{}
+	}
+	return required, nil
+}
+
+// collectLinkedMessageIDs gathers messages other records point at: thread
+// anchors, references, and surfaced messages.
+func collectLinkedMessageIDs(projectPath string) (map[string]struct{}, error) {
 	required := make(map[string]struct{})
-	frayDir := resolveFrayDir(projectPath)
 
 	// Read threads for anchor messages
 	threads, _, _, err := db.ReadThreads(projectPath)
@@ -234,7 +448,65 @@ func collectRequiredMessageIDs(projectPath string) (map[string]struct{}, error)
 		}
 	}
 
-	// Read message pins
+	// Read messages for surface references
+	messages, err := db.ReadMessages(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	// Collect all references targets and surface_message links
+	for _, msg := range messages {
+		if msg.References != nil && *msg.References != "" {
+			required[*msg.References] = struct{}{}
+		}
+		if msg.SurfaceMessage != nil && *msg.SurfaceMessage != "" {
+			required[*msg.SurfaceMessage] = struct{}{}
+		}
+	}
+
+	return required, nil
+}
+
+// collectThreadMembers maps messages added to threads (thread playlist
+// entries) to the threads they are currently in.
+func collectThreadMembers(projectPath string) (map[string][]string, error) {
+	members := make(map[string][]string)
+	threadsPath := filepath.Join(resolveFrayDir(projectPath), "threads.jsonl")
+	threadLines, err := readJSONLLines(threadsPath)
+	if err != nil {
+		return members, nil
+	}
+	for _, line := range threadLines {
+		var envelope struct {
+			Type        string `json:"type"`
+			ThreadGUID  string `json:"thread_guid"`
+			MessageGUID string `json:"message_guid"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			continue
+		}
+		switch envelope.Type {
+		case "thread_message":
+			members[envelope.MessageGUID] = append(withoutThread(members[envelope.MessageGUID], envelope.ThreadGUID), envelope.ThreadGUID)
+		case "thread_message_remove":
+			if threads := withoutThread(members[envelope.MessageGUID], envelope.ThreadGUID); len(threads) > 0 {
+				members[envelope.MessageGUID] = threads
+			} else {
+				delete(members, envelope.MessageGUID)
+			}
+		}
+	}
+	return members, nil
+}
+
+// withoutThread returns a copy of threads without guid.
+func withoutThread(threads []string, guid string) []string {
+	return slices.DeleteFunc(slices.Clone(threads), func(candidate string) bool {
+		return candidate == guid
+	})
+}
+
+// collectPinnedMessageIDs gathers messages that are currently pinned in a thread.
+func collectPinnedMessageIDs(projectPath string) (map[string]struct{}, error) {
 	pinEvents, err := db.ReadMessagePins(projectPath)
 	if err != nil {
 		return nil, err
@@ -249,67 +521,32 @@ func collectRequiredMessageIDs(projectPath string) (map[string]struct{}, error)
 			delete(pinnedMessages, key)
 		}
 	}
+	pinned := make(map[string]struct{}, len(pinnedMessages))
 	for key := range pinnedMessages {
 		parts := strings.SplitN(key, "|", 2)
 		if len(parts) > 0 {
-			required[parts[0]] = struct{}{}
+			pinned[parts[0]] = struct{}{}
 		}
 	}
+	return pinned, nil
+}
 
-	// Read questions for message references
+// collectQuestionMessageIDs gathers messages questions were asked or answered in.
+func collectQuestionMessageIDs(projectPath string) (map[string]struct{}, error) {
 	questions, err := db.ReadQuestions(projectPath)
 	if err != nil {
 		return nil, err
 	}
+	linked := make(map[string]struct{})
 	for _, q := range questions {
 		if q.AskedIn != nil && *q.AskedIn != "" {
-			required[*q.AskedIn] = struct{}{}
+			linked[*q.AskedIn] = struct{}{}
 		}
 		if q.AnsweredIn != nil && *q.AnsweredIn != "" {
-			required[*q.AnsweredIn] = struct{}{}
-		}
-	}
-
-	// Read messages for surface references
-	messages, err := db.ReadMessages(projectPath)
-	if err != nil {
-		return nil, err
-	}
-	// Collect all references targets and surface_message links
-	for _, msg := range messages {
-		if msg.References != nil && *msg.References != "" {
-			required[*msg.References] = struct{}{}
-		}
-		if msg.SurfaceMessage != nil && *msg.SurfaceMessage != "" {
-			required[*msg.SurfaceMessage] = struct{}{}
+			linked[*q.AnsweredIn] = struct{}{}
 		}
 	}
-
-	// Read thread_message events to preserve messages added to threads
-	threadsPath := filepath.Join(frayDir, "threads.jsonl")
-	if threadLines, err := readJSONLLines(threadsPath); err == nil {
-		threadMsgs := make(map[string]struct{})
-		for _, line := range threadLines {
-			var envelope struct {
-				Type        string `json:"type"`
-				MessageGUID string `json:"message_guid"`
-			}
-			if err := json.Unmarshal([]byte(line), &envelope); err != nil {
-				continue
-			}
-			switch envelope.Type {
-			case "thread_message":
-				threadMsgs[envelope.MessageGUID] = struct{}{}
-			case "thread_message_remove":
-				delete(threadMsgs, envelope.MessageGUID)
-			}
-		}
-		for id := range threadMsgs {
-			required[id] = struct{}{}
-		}
-	}
-
-	return required, nil
+	return linked, nil
 }
 
 // readJSONLLines reads all non-empty lines from a JSONL file.
@@ -363,53 +600,36 @@ func writeMessagesWithEvents(path string, messages []db.MessageJSONLRecord, keep
 
 	// Write events for kept messages
 	for _, line := range originalLines {
-		var envelope struct {
-			Type string `json:"type"`
-			ID   string `json:"id"`
-		}
-		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
-			continue
-		}
-
-		switch envelope.Type {
-		case "message":
-			// Already written above
-			continue
-		case "message_update":
-			// Check if the updated message is being kept
-			if _, ok := keepIDs[envelope.ID]; ok {
-				builder.WriteString(line)
-				builder.WriteByte('\n')
-			}
-		case "message_pin", "message_unpin":
-			// These use message_guid instead of id
-			var pinEvent struct {
-				MessageGUID string `json:"message_guid"`
-			}
-			if err := json.Unmarshal([]byte(line), &pinEvent); err != nil {
-				continue
-			}
-			if _, ok := keepIDs[pinEvent.MessageGUID]; ok {
-				builder.WriteString(line)
-				builder.WriteByte('\n')
-			}
-		case "message_move":
-			var moveEvent struct {
-				MessageGUID string `json:"message_guid"`
-			}
-			if err := json.Unmarshal([]byte(line), &moveEvent); err != nil {
-				continue
-			}
-			if _, ok := keepIDs[moveEvent.MessageGUID]; ok {
-				builder.WriteString(line)
-				builder.WriteByte('\n')
-			}
+		if _, ok := keepIDs[messageEventTarget(line)]; ok {
+			builder.WriteString(line)
+			builder.WriteByte('\n')
 		}
 	}
 
 	return os.WriteFile(path, []byte(builder.String()), 0o644)
 }
 
+// messageEventTarget returns the message a messages.jsonl event applies to, or
+// "" for message records and other lines.
+func messageEventTarget(line string) string {
+	var envelope struct {
+		Type        string `json:"type"`
+		ID          string `json:"id"`
+		MessageGUID string `json:"message_guid"`
+	}
+	if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+		return ""
+	}
+	switch envelope.Type {
+	case "message_update":
+		return envelope.ID
+	case "message_pin", "message_unpin", "message_move", "reaction":
+		// These use message_guid instead of id
+		return envelope.MessageGUID
+	}
+	return ""
+}
+
 func resolveFrayDir(projectPath string) string {
 	if strings.HasSuffix(projectPath, ".db") {
 		return filepath.Dir(projectPath)
@@ -462,8 +682,13 @@ func checkPruneGuardrails(root string) error {
 	if strings.TrimSpace(status) != "" {
 		return fmt.Errorf("uncommitted changes in .fray/. Commit first")
 	}
+	return checkBranchSynced(root)
+}
 
-	_, err = runGitCommand(root, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+// checkBranchSynced refuses when the branch has an upstream it is ahead of or
+// behind, so a prune never has to be merged with someone else's history.
+func checkBranchSynced(root string) error {
+	_, err := runGitCommand(root, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
 	if err != nil {
 		return nil
 	}
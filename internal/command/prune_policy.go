@@ -0,0 +1,268 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+)
+
+// retentionScopeReport is one line of the retention plan: what a rule does to
+// the room or a thread.
+type retentionScopeReport struct {
+	Scope  string `json:"scope"` // "room" or the thread path
+	Thread string `json:"thread,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Rule   int    `json:"rule"` // 1-based; 0 when no rule matched
+	Policy string `json:"policy"`
+	Total  int    `json:"total"`
+	Pruned int    `json:"pruned"`
+	Exempt int    `json:"exempt"` // would be pruned but kept by an exemption or link
+}
+
+type retentionPlan struct {
+	Scopes  []retentionScopeReport
+	Kept    []db.MessageJSONLRecord
+	Removed []db.MessageJSONLRecord
+}
+
+// planRetention evaluates the retention rules against messages.jsonl.
+// Scopes no rule matches are kept whole.
+func planRetention(ctx *CommandContext, rules []core.RetentionRule, now time.Time) (*retentionPlan, error) {
+	projectPath := ctx.Project.DBPath
+	messages, err := db.ReadMessages(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := collectLinkedMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	members, err := collectThreadMembers(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := collectPinnedMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	questioned, err := collectQuestionMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	faved, err := collectFavedMessageIDs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// A message is in its home's scope and in the scope of every thread it
+	// was added to; it is kept if any of those scopes keeps it.
+	var homes []string
+	byHome := make(map[string][]db.MessageJSONLRecord)
+	addToScope := func(home string, msg db.MessageJSONLRecord) {
+		if _, ok := byHome[home]; !ok {
+			homes = append(homes, home)
+		}
+		byHome[home] = append(byHome[home], msg)
+	}
+	for _, msg := range messages {
+		home := msg.Home
+		if home == "" {
+			home = "room"
+		}
+		addToScope(home, msg)
+		for _, thread := range members[msg.ID] {
+			if thread != home {
+				addToScope(thread, msg)
+			}
+		}
+	}
+
+	plan := &retentionPlan{}
+	keepIDs := make(map[string]struct{}, len(messages))
+	for _, home := range homes {
+		scopeMessages := byHome[home]
+		report := retentionScopeReport{Scope: "room", Total: len(scopeMessages), Policy: "no rule"}
+		scope := core.RetentionScope{Path: "room"}
+		if home != "room" {
+			report.Thread = home
+			report.Scope = home
+			if thread, _ := db.GetThread(ctx.DB, home); thread != nil {
+				if path, err := buildThreadPath(ctx.DB, thread); err == nil && path != "" {
+					report.Scope = path
+				}
+				report.Type = string(thread.Type)
+			}
+			scope = core.RetentionScope{Path: report.Scope, Type: report.Type}
+		}
+
+		index := core.MatchRetentionRule(rules, scope)
+		if index < 0 || rules[index].Forever {
+			if index >= 0 {
+				report.Rule, report.Policy = index+1, rules[index].Describe()
+			}
+			for _, msg := range scopeMessages {
+				keepIDs[msg.ID] = struct{}{}
+			}
+			plan.Scopes = append(plan.Scopes, report)
+			continue
+		}
+
+		rule := rules[index]
+		report.Rule, report.Policy = index+1, rule.Describe()
+		var cutoff int64
+		if rule.MaxAge != "" {
+			age, _ := core.ParseAge(rule.MaxAge)
+			cutoff = now.Add(-age).Unix()
+		}
+		newest := len(scopeMessages) - rule.Keep
+		for i, msg := range scopeMessages {
+			keep := i >= newest || (cutoff > 0 && msg.TS >= cutoff)
+			if !keep {
+				_, isLinked := linked[msg.ID]
+				_, isPinned := pinned[msg.ID]
+				_, isQuestioned := questioned[msg.ID]
+				_, isFaved := faved[msg.ID]
+				if isLinked ||
+					(isPinned && rule.Exempts(core.RetentionExemptPinned)) ||
+					(isQuestioned && rule.Exempts(core.RetentionExemptQuestions)) ||
+					(isFaved && rule.Exempts(core.RetentionExemptFaved)) {
+					keep = true
+					report.Exempt++
+				}
+			}
+			if keep {
+				keepIDs[msg.ID] = struct{}{}
+			}
+		}
+		plan.Scopes = append(plan.Scopes, report)
+	}
+
+	// Replies keep their parents so threads stay readable
+	byID := make(map[string]db.MessageJSONLRecord, len(messages))
+	for _, msg := range messages {
+		byID[msg.ID] = msg
+	}
+	for _, msg := range messages {
+		if _, ok := keepIDs[msg.ID]; !ok {
+			continue
+		}
+		for parentID := msg.ReplyTo; parentID != nil && *parentID != ""; {
+			parent, ok := byID[*parentID]
+			if !ok {
+				break
+			}
+			if _, kept := keepIDs[parent.ID]; kept {
+				break
+			}
+			keepIDs[parent.ID] = struct{}{}
+			parentID = parent.ReplyTo
+		}
+	}
+
+	scopeIndex := make(map[string]int, len(plan.Scopes))
+	for i, report := range plan.Scopes {
+		key := report.Thread
+		if key == "" {
+			key = "room"
+		}
+		scopeIndex[key] = i
+	}
+	for _, msg := range messages {
+		if _, ok := keepIDs[msg.ID]; ok {
+			plan.Kept = append(plan.Kept, msg)
+			continue
+		}
+		plan.Removed = append(plan.Removed, msg)
+		home := msg.Home
+		if home == "" {
+			home = "room"
+		}
+		plan.Scopes[scopeIndex[home]].Pruned++
+	}
+	return plan, nil
+}
+
+// applyRetentionPlan archives messages.jsonl to history.jsonl and rewrites it
+// with the kept messages and their events.
+func applyRetentionPlan(projectPath string, plan *retentionPlan) error {
+	frayDir := resolveFrayDir(projectPath)
+	messagesPath := filepath.Join(frayDir, "messages.jsonl")
+	historyPath := filepath.Join(frayDir, "history.jsonl")
+
+	data, err := os.ReadFile(messagesPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if strings.TrimSpace(string(data)) != "" {
+		if err := appendFile(historyPath, data); err != nil {
+			return err
+		}
+	}
+
+	keptIDs := make(map[string]struct{}, len(plan.Kept))
+	for _, msg := range plan.Kept {
+		keptIDs[msg.ID] = struct{}{}
+	}
+	return writeMessagesWithEvents(messagesPath, plan.Kept, keptIDs)
+}
+
+// collectFavedMessageIDs gathers messages some agent currently faves.
+func collectFavedMessageIDs(projectPath string) (map[string]struct{}, error) {
+	events, err := db.ReadFaves(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	faves := make(map[string]struct{})
+	for _, event := range events {
+		if event.ItemType != "message" {
+			continue
+		}
+		key := event.AgentID + "|" + event.ItemGUID
+		if event.Type == "agent_fave" {
+			faves[key] = struct{}{}
+		} else {
+			delete(faves, key)
+		}
+	}
+	faved := make(map[string]struct{}, len(faves))
+	for key := range faves {
+		faved[strings.SplitN(key, "|", 2)[1]] = struct{}{}
+	}
+	return faved, nil
+}
+
+func writeRetentionReport(out io.Writer, scopes []retentionScopeReport) {
+	width := 0
+	for _, report := range scopes {
+		if label := retentionScopeLabel(report); len(label) > width {
+			width = len(label)
+		}
+	}
+	for _, report := range scopes {
+		rule := "no rule"
+		if report.Rule > 0 {
+			rule = fmt.Sprintf("rule %d (%s)", report.Rule, report.Policy)
+		}
+		result := fmt.Sprintf("keep %d", report.Total)
+		if report.Pruned > 0 {
+			result = fmt.Sprintf("prune %d of %d", report.Pruned, report.Total)
+		}
+		if report.Exempt > 0 {
+			result += fmt.Sprintf(" (%d exempt)", report.Exempt)
+		}
+		fmt.Fprintf(out, "  %-*s  %-28s  %s\n", width, retentionScopeLabel(report), rule, result)
+	}
+}
+
+func retentionScopeLabel(report retentionScopeReport) string {
+	if report.Type == "" {
+		return report.Scope
+	}
+	return fmt.Sprintf("%s [%s]", report.Scope, report.Type)
+}
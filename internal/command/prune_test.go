@@ -0,0 +1,37 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCollectThreadMembersIgnoresOtherRecords(t *testing.T) {
+	projectDir := t.TempDir()
+	frayDir := filepath.Join(projectDir, ".fray")
+	if err := os.MkdirAll(frayDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	lines := []string{
+		`{"type":"thread_message","thread_guid":"thrd-a","message_guid":"msg-1"}`,
+		`{"type":"thread_message","thread_guid":"thrd-b","message_guid":"msg-1"}`,
+		`{"type":"thread_message","thread_guid":"thrd-a","message_guid":"msg-2"}`,
+		// Records that carry the same thread and message must not touch membership
+		`{"type":"thread_summary","thread_guid":"thrd-a","message_guid":"msg-1"}`,
+		`{"type":"thread_message_remove","thread_guid":"thrd-b","message_guid":"msg-1"}`,
+		`{"type":"thread_message_remove","thread_guid":"thrd-a","message_guid":"msg-2"}`,
+	}
+	if err := os.WriteFile(filepath.Join(frayDir, "threads.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write threads: %v", err)
+	}
+
+	members, err := collectThreadMembers(projectDir)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if len(members) != 1 || !slices.Equal(members["msg-1"], []string{"thrd-a"}) {
+		t.Fatalf("expected msg-1 only in thrd-a, got %v", members)
+	}
+}
@@ -1,8 +1,11 @@
 package command
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/adamavenir/fray/internal/db"
@@ -28,10 +31,14 @@ func NewQuestionsCmd() *cobra.Command {
 			all, _ := cmd.Flags().GetBool("all")
 			room, _ := cmd.Flags().GetBool("room")
 			threadRef, _ := cmd.Flags().GetString("thread")
+			allLinked, _ := cmd.Flags().GetBool("all-linked")
 
 			if room && threadRef != "" {
 				return writeCommandError(cmd, fmt.Errorf("--room cannot be combined with --thread"))
 			}
+			if allLinked && threadRef != "" {
+				return writeCommandError(cmd, fmt.Errorf("--all-linked cannot be combined with --thread"))
+			}
 
 			statuses := make([]types.QuestionStatus, 0)
 			if all {
@@ -65,6 +72,14 @@ func NewQuestionsCmd() *cobra.Command {
 				options.RoomOnly = true
 			}
 
+			if allLinked {
+				agentRef := ""
+				if len(args) == 1 {
+					agentRef = strings.TrimPrefix(args[0], "@")
+				}
+				return questionsAllLinked(cmd, ctx, options, agentRef)
+			}
+
 			if len(args) == 1 {
 				agentRef := strings.TrimPrefix(args[0], "@")
 				resolved := ResolveAgentRef(agentRef, ctx.ProjectConfig)
@@ -88,25 +103,7 @@ func NewQuestionsCmd() *cobra.Command {
 
 			fmt.Fprintln(out, "Questions:")
 			for _, question := range questions {
-				threadLabel := "room"
-				if question.ThreadGUID != nil {
-					thread, _ := db.GetThread(ctx.DB, *question.ThreadGUID)
-					if thread != nil {
-						if path, err := buildThreadPath(ctx.DB, thread); err == nil && path != "" {
-							threadLabel = path
-						} else {
-							threadLabel = thread.GUID
-						}
-					} else {
-						threadLabel = *question.ThreadGUID
-					}
-				}
-				toAgent := "--"
-				if question.ToAgent != nil {
-					toAgent = "@" + *question.ToAgent
-				}
-				fmt.Fprintf(out, "  [%s] %s @%s → %s (%s)\n", question.GUID, question.Status, question.FromAgent, toAgent, threadLabel)
-				fmt.Fprintf(out, "    %s\n", question.Re)
+				printQuestionLine(out, question.GUID, question, questionThreadLabel(ctx.DB, question))
 			}
 			return nil
 		},
@@ -117,6 +114,85 @@ func NewQuestionsCmd() *cobra.Command {
 	cmd.Flags().Bool("all", false, "show all questions")
 	cmd.Flags().Bool("room", false, "show room-level questions only")
 	cmd.Flags().String("thread", "", "filter by thread")
+	cmd.Flags().Bool("all-linked", false, "list questions across this and all linked projects")
 
 	return cmd
 }
+
+// questionThreadLabel returns the thread path a question was asked in, or room.
+func questionThreadLabel(conn *sql.DB, question types.Question) string {
+	if question.ThreadGUID == nil {
+		return "room"
+	}
+	thread, _ := db.GetThread(conn, *question.ThreadGUID)
+	if thread == nil {
+		return *question.ThreadGUID
+	}
+	if path, err := buildThreadPath(conn, thread); err == nil && path != "" {
+		return path
+	}
+	return thread.GUID
+}
+
+func printQuestionLine(out io.Writer, id string, question types.Question, threadLabel string) {
+	toAgent := "--"
+	if question.ToAgent != nil {
+		toAgent = "@" + *question.ToAgent
+	}
+	fmt.Fprintf(out, "  [%s] %s @%s → %s (%s)\n", id, question.Status, question.FromAgent, toAgent, threadLabel)
+	fmt.Fprintf(out, "    %s\n", question.Re)
+}
+
+// questionsAllLinked lists questions from this and every linked project, with
+// channel-qualified IDs.
+func questionsAllLinked(cmd *cobra.Command, ctx *CommandContext, options types.QuestionQueryOptions, agentRef string) error {
+	channels, closeAll, err := openLinkedChannels(cmd, ctx)
+	defer closeAll()
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	type channelQuestion struct {
+		Channel string `json:"channel"`
+		types.Question
+		threadLabel string
+	}
+	var questions []channelQuestion
+	for _, channel := range channels {
+		channelOptions := options
+		if agentRef != "" {
+			resolved := ResolveAgentRef(agentRef, channel.Config)
+			channelOptions.ToAgent = &resolved
+		}
+		found, err := db.GetQuestions(channel.DB, &channelOptions)
+		if err != nil {
+			return writeCommandError(cmd, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+		for _, question := range found {
+			label := questionThreadLabel(channel.DB, question)
+			question.GUID = qualifyID(channel.Name, question.GUID)
+			questions = append(questions, channelQuestion{Channel: channel.Name, Question: question, threadLabel: label})
+		}
+	}
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].CreatedAt < questions[j].CreatedAt
+	})
+
+	if ctx.JSONMode {
+		if questions == nil {
+			questions = []channelQuestion{}
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(questions)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(questions) == 0 {
+		fmt.Fprintln(out, "No questions found")
+		return nil
+	}
+	fmt.Fprintln(out, "Questions:")
+	for _, question := range questions {
+		printQuestionLine(out, question.GUID, question.Question, question.threadLabel)
+	}
+	return nil
+}
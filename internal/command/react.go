@@ -16,8 +16,17 @@ func NewReactCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "react <emoji> <message>",
 		Short: "React to a message with an emoji",
-		Long:  "Add a reaction to a message. Optionally chain a reply with --reply.",
-		Args:  cobra.ExactArgs(2),
+		Long: `Add a reaction to a message. Optionally chain a reply with --reply.
+
+Some reactions carry workflow meaning (see 'fray reactions map'):
+  ✅ answer  marks questions asked in the message as answered
+  👀 ack     claims the message as "I'm on it" for 30 minutes
+  🚫 block   flags the message as blocked; the daemon wakes its author
+
+Examples:
+  fray react 👀 msg-abc123 --as alice
+  fray react ✅ msg-abc123 --as alice --reply "done, merged"`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := GetContext(cmd)
 			if err != nil {
@@ -71,6 +80,11 @@ func NewReactCmd() *cobra.Command {
 				return writeCommandError(cmd, err)
 			}
 
+			effect, err := db.ApplyReactionMeaning(ctx.DB, ctx.Project.DBPath, *msg, agentID, reaction, reactedAt)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
 			now := time.Now().Unix()
 			updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
 			if err := db.UpdateAgent(ctx.DB, agentID, updates); err != nil {
@@ -113,11 +127,17 @@ func NewReactCmd() *cobra.Command {
 				if replyMsg != nil {
 					payload["reply_id"] = replyMsg.ID
 				}
+				if effect != nil {
+					payload["effect"] = effect
+				}
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(payload)
 			}
 
 			out := cmd.OutOrStdout()
 			fmt.Fprintf(out, "Reacted %s to #%s\n", reaction, msg.ID)
+			if summary := effect.Summary(); summary != "" {
+				fmt.Fprintf(out, "  %s: %s\n", effect.Meaning, summary)
+			}
 			if replyMsg != nil {
 				fmt.Fprintf(out, "  Reply: [%s] %s\n", replyMsg.ID, replyText)
 			}
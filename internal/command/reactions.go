@@ -130,9 +130,86 @@ Examples:
 	cmd.Flags().String("to", "", "show reactions on an agent's messages")
 	cmd.Flags().Int("last", 20, "limit results")
 
+	cmd.AddCommand(NewReactionsMapCmd())
+
 	return cmd
 }
 
+// NewReactionsMapCmd creates the reactions map command.
+func NewReactionsMapCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "map [reaction] [meaning]",
+		Short: "Show or set what reactions mean",
+		Long: `Show or set the project's reaction meanings, stored in fray-config.json.
+
+Meanings:
+  answer  marks questions asked in the message as answered
+  ack     claims the message as "I'm on it" (expires after 30 minutes)
+  block   flags the message as blocked; the daemon wakes its author
+  none    no workflow effect
+
+Examples:
+  fray reactions map              # Show the table
+  fray reactions map 👍 ack        # 👍 also acks
+  fray reactions map 👀 none       # Turn off the default 👀 ack`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			if len(args) == 1 {
+				return writeCommandError(cmd, fmt.Errorf("usage: fray reactions map <reaction> <meaning>"))
+			}
+			if len(args) == 2 {
+				reaction, ok := core.NormalizeReactionText(args[0])
+				if !ok {
+					return writeCommandError(cmd, fmt.Errorf("invalid reaction: %q", args[0]))
+				}
+				meaning, ok := core.ParseReactionMeaning(args[1])
+				if !ok {
+					return writeCommandError(cmd, fmt.Errorf("unknown meaning %q: use answer, ack, block, or none", args[1]))
+				}
+				if _, err := db.UpdateProjectConfig(ctx.Project.DBPath, db.ProjectConfig{
+					Reactions: map[string]string{reaction: string(meaning)},
+				}); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+
+			meanings, err := db.ReadReactionMeanings(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(meanings)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(meanings) == 0 {
+				fmt.Fprintln(out, "No reaction meanings")
+				return nil
+			}
+			reactions := make([]string, 0, len(meanings))
+			for reaction := range meanings {
+				reactions = append(reactions, reaction)
+			}
+			sort.Slice(reactions, func(i, j int) bool {
+				if meanings[reactions[i]] != meanings[reactions[j]] {
+					return meanings[reactions[i]] < meanings[reactions[j]]
+				}
+				return reactions[i] < reactions[j]
+			})
+			for _, reaction := range reactions {
+				fmt.Fprintf(out, "  %s  %s\n", reaction, meanings[reaction])
+			}
+			return nil
+		},
+	}
+}
+
 func truncateBody(body string, maxLen int) string {
 	body = strings.TrimSpace(body)
 	if idx := strings.Index(body, "\n"); idx > 0 && idx < maxLen {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
 )
 
@@ -42,8 +43,9 @@ Use this command when:
 
 			dbPath := project.DBPath
 
-			// Shelve read state before deleting DB (local state we want to preserve)
+			// Shelve read state and links before deleting DB (local state we want to preserve)
 			readState := shelveReadState(dbPath)
+			links := shelveLinkedProjects(dbPath)
 
 			// Delete existing db files
 			os.Remove(dbPath)
@@ -57,8 +59,9 @@ Use this command when:
 			}
 			defer newDB.Close()
 
-			// Restore read state
+			// Restore read state and links
 			restoreReadState(newDB, readState)
+			restoreLinkedProjects(newDB, links)
 
 			jsonMode, _ := cmd.Flags().GetBool("json")
 			if jsonMode {
@@ -107,3 +110,26 @@ func restoreReadState(newDB *sql.DB, records []readToRecord) {
 		`, r.AgentID, r.Home, r.MessageGUID, r.MessageTS, r.SetAt)
 	}
 }
+
+// shelveLinkedProjects extracts project links from the old database. Links are
+// per-checkout and have no JSONL record, so the cache is their only copy.
+func shelveLinkedProjects(dbPath string) []types.LinkedProject {
+	oldDB, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil
+	}
+	defer oldDB.Close()
+
+	links, err := db.GetLinkedProjects(oldDB)
+	if err != nil {
+		return nil
+	}
+	return links
+}
+
+// restoreLinkedProjects re-creates preserved project links in the new database.
+func restoreLinkedProjects(newDB *sql.DB, links []types.LinkedProject) {
+	for _, link := range links {
+		_ = db.LinkProject(newDB, link.Alias, link.Path)
+	}
+}
@@ -69,6 +69,11 @@ func NewRenameCmd() *cobra.Command {
 				))
 			}
 
+			if err := writeAudit(cmd, ctx, db.AuditAgentRename, "@"+oldID,
+				map[string]any{"agent_id": oldID}, map[string]any{"agent_id": newID}); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
 			if err := db.RenameAgent(ctx.DB, oldID, newID); err != nil {
 				return writeCommandError(cmd, err)
 			}
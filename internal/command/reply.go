@@ -67,18 +67,21 @@ func NewReplyCmd() *cobra.Command {
 			}
 
 			projectName := GetProjectName(ctx.Project.Root)
+			render := markdownFor(cmd)
 			for _, row := range thread {
 				prefix := ""
 				if row.ID != messageID {
 					prefix = "  ↳ "
 				}
-				fmt.Fprintln(out, prefix+FormatMessage(row, projectName, bases))
+				fmt.Fprintln(out, prefix+FormatMessage(row, projectName, bases, render))
 			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
+
 	return cmd
 }
 
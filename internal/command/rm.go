@@ -79,6 +79,14 @@ func deleteMessage(cmd *cobra.Command, ctx *CommandContext, input string) error
 		return writeCommandError(cmd, fmt.Errorf("message not found: %s", input))
 	}
 
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := checkMessageCuration(ctx, msg, actor); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
 	if err := db.DeleteMessage(ctx.DB, msg.ID); err != nil {
 		return writeCommandError(cmd, err)
 	}
@@ -97,6 +105,8 @@ func deleteMessage(cmd *cobra.Command, ctx *CommandContext, input string) error
 	if err := db.AppendMessageUpdate(ctx.Project.DBPath, update); err != nil {
 		return writeCommandError(cmd, err)
 	}
+	recordAudit(cmd, ctx, "message.delete", updated.ID,
+		map[string]any{"from": msg.FromAgent, "home": msg.Home, "body": msg.Body}, map[string]any{"body": updated.Body})
 
 	if ctx.JSONMode {
 		payload := map[string]any{"id": updated.ID, "deleted": true}
@@ -116,6 +126,14 @@ func deleteThread(cmd *cobra.Command, ctx *CommandContext, threadGUID string) er
 		return writeCommandError(cmd, fmt.Errorf("thread not found: %s", threadGUID))
 	}
 
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
 	// Archive the thread (soft delete)
 	status := string(types.ThreadStatusArchived)
 	updated, err := db.UpdateThread(ctx.DB, thread.GUID, db.ThreadUpdates{
@@ -131,6 +149,7 @@ func deleteThread(cmd *cobra.Command, ctx *CommandContext, threadGUID string) er
 	}); err != nil {
 		return writeCommandError(cmd, err)
 	}
+	recordThreadStatusAudit(cmd, ctx, thread, status)
 
 	if ctx.JSONMode {
 		payload := map[string]any{"id": updated.GUID, "deleted": true, "type": "thread"}
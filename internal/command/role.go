@@ -124,6 +124,7 @@ func newRoleAddCmd() *cobra.Command {
 			if err := db.AppendRoleHold(ctx.Project.DBPath, agentID, roleName, now); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "role.hold", "@"+agentID, nil, map[string]any{"role": roleName})
 
 			if ctx.JSONMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
@@ -184,6 +185,7 @@ func newRoleDropCmd() *cobra.Command {
 			if err := db.AppendRoleDrop(ctx.Project.DBPath, agentID, roleName, now); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "role.drop", "@"+agentID, map[string]any{"role": roleName}, nil)
 
 			if ctx.JSONMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
@@ -254,6 +256,7 @@ func newRolePlayCmd() *cobra.Command {
 			if err := db.AppendRolePlay(ctx.Project.DBPath, agentID, roleName, sessionID, now); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "role.play", "@"+agentID, nil, map[string]any{"role": roleName, "session_id": sessionID})
 
 			if ctx.JSONMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
@@ -314,6 +317,7 @@ func newRoleStopCmd() *cobra.Command {
 			if err := db.AppendRoleStop(ctx.Project.DBPath, agentID, roleName, now); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "role.stop", "@"+agentID, map[string]any{"role": roleName}, nil)
 
 			if ctx.JSONMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
@@ -77,6 +77,8 @@ func NewRootCmd(version string) *cobra.Command {
 		NewAskCmd(),
 		NewQuestionsCmd(),
 		NewQuestionCmd(),
+		NewTasksCmd(),
+		NewTaskCmd(),
 		NewAnswerCmd(),
 		NewSurfaceCmd(),
 		NewReactCmd(),
@@ -87,11 +89,14 @@ func NewRootCmd(version string) *cobra.Command {
 		NewChatCmd(),
 		NewWatchCmd(),
 		NewPruneCmd(),
+		NewHistoryCmd(),
+		NewRestoreMessagesCmd(),
 		NewConfigCmd(),
 		NewRosterCmd(),
 		NewInfoCmd(),
 		NewRenameCmd(),
 		NewMergeCmd(),
+		NewAuditCmd(),
 		NewVersionsCmd(),
 		NewFilterCmd(),
 		NewLsCmd(),
@@ -102,11 +107,21 @@ func NewRootCmd(version string) *cobra.Command {
 		NewHeartbeatCmd(),
 		NewClockCmd(),
 		NewCursorCmd(),
+		NewHandoffCmd(),
+		NewScheduledCmd(),
+		NewWebCmd(),
+		NewNotifyCmd(),
 		NewInstallNotifierCmd(),
+		NewLinkCmd(),
+		NewUnlinkCmd(),
+		NewLinksCmd(),
 		hooks.NewHookInstallCmd(),
 		hooks.NewHookSessionCmd(),
 		hooks.NewHookPromptCmd(),
 		hooks.NewHookPrecommitCmd(),
+		hooks.NewHookPrepushCmd(),
+		hooks.NewHookPostcheckoutCmd(),
+		hooks.NewHookPostcommitCmd(),
 		hooks.NewHookPrecompactCmd(),
 		hooks.NewHookStatuslineCmd(),
 	)
@@ -0,0 +1,140 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// NewScheduledCmd creates the scheduled command tree.
+func NewScheduledCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scheduled",
+		Short: "List pending scheduled messages",
+		Long: `List messages queued with 'fray post --at' or /later in chat.
+
+Scheduled messages are delivered by the daemon, or by the next fray command
+run after they come due.
+
+Examples:
+  fray scheduled                    # All pending sends
+  fray scheduled --as alice         # Only alice's
+  fray scheduled cancel sched-ab12  # Cancel one (prefix ok)
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			pending, err := db.ReadPendingScheduled(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if agentRef, _ := cmd.Flags().GetString("as"); agentRef != "" {
+				agentID, err := resolveAgentRef(ctx, agentRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				filtered := make([]types.ScheduledMessage, 0, len(pending))
+				for _, scheduled := range pending {
+					if scheduled.FromAgent == agentID {
+						filtered = append(filtered, scheduled)
+					}
+				}
+				pending = filtered
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(pending)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(pending) == 0 {
+				fmt.Fprintln(out, "No scheduled messages")
+				return nil
+			}
+			for _, scheduled := range pending {
+				preview := strings.ReplaceAll(scheduled.Body, "\n", " ")
+				if len(preview) > 60 {
+					preview = preview[:60] + "..."
+				}
+				sendAt := time.Unix(scheduled.SendAt, 0).Format("Mon Jan 2 15:04")
+				fmt.Fprintf(out, "[%s] %s @%s → %s: %s\n", scheduled.GUID, sendAt, scheduled.FromAgent, scheduledHomeLabel(ctx, scheduled.Home), preview)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("as", "", "only show messages scheduled by this agent")
+	cmd.AddCommand(newScheduledCancelCmd())
+
+	return cmd
+}
+
+func newScheduledCancelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a pending scheduled message",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			pending, err := db.ReadPendingScheduled(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			scheduled, err := db.MatchScheduled(pending, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			cancelledBy := ""
+			if agentRef, _ := cmd.Flags().GetString("as"); agentRef != "" {
+				if cancelledBy, err = resolveAgentRef(ctx, agentRef); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+			if err := db.CancelScheduledMessage(ctx.Project.DBPath, scheduled.GUID, cancelledBy); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"id":     scheduled.GUID,
+					"action": "cancelled",
+				})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Cancelled %s\n", scheduled.GUID)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("as", "", "agent cancelling the message")
+	return cmd
+}
+
+func scheduledHomeLabel(ctx *CommandContext, home string) string {
+	if home == "" || home == "room" {
+		return "room"
+	}
+	thread, err := db.GetThread(ctx.DB, home)
+	if err != nil || thread == nil {
+		return home
+	}
+	if path, err := buildThreadPath(ctx.DB, thread); err == nil && path != "" {
+		return path
+	}
+	return thread.GUID
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/spf13/cobra"
@@ -108,6 +109,9 @@ func NewStatusCmd() *cobra.Command {
 			if _, err := db.PruneExpiredClaims(ctx.DB); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			if _, err := releaseClosedIssueClaims(ctx, core.IssueProviders(ctx.Project.Root)); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			ttl, _ := cmd.Flags().GetString("ttl")
 			var expiresAt *int64
@@ -179,6 +183,11 @@ func NewStatusCmd() *cobra.Command {
 			if err := db.AppendMessage(ctx.Project.DBPath, createdMsg); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			for _, claim := range created {
+				if err := db.SetClaimMessage(ctx.DB, claim.ID, createdMsg.ID); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
 
 			if ctx.JSONMode {
 				payload := map[string]any{
@@ -219,6 +228,7 @@ func NewStatusCmd() *cobra.Command {
 	cmd.Flags().String("files", "", "claim multiple files (comma-separated globs)")
 	cmd.Flags().String("bd", "", "claim a beads issue")
 	cmd.Flags().String("issue", "", "claim a GitHub issue")
+	cmd.Flags().String("branch", "", "claim a git branch (glob allowed)")
 	cmd.Flags().String("ttl", "", "expiration time for claims (e.g., 2h, 30m, 1d)")
 	cmd.Flags().Bool("clear", false, "clear all claims and reset status")
 
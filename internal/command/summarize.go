@@ -0,0 +1,395 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/daemon"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	summaryTriggerArchive = "archive"
+	summaryTriggerPrune   = "prune"
+	summaryTriggerManual  = "manual"
+
+	defaultSummarizerTimeout = 120 * time.Second
+)
+
+// summarizeThread runs the project's summarizer over messages and posts the
+// result as the thread's anchor, recording where it came from and the span it
+// covers. It returns nil when no summarizer is configured or there is nothing
+// to summarize.
+func summarizeThread(ctx *CommandContext, thread *types.Thread, messages []types.Message, trigger string) (*types.ThreadSummary, error) {
+	config, err := db.ReadProjectConfig(ctx.Project.DBPath)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || config.Summarizer == nil {
+		return nil, nil
+	}
+	cfg := config.Summarizer
+
+	// The current anchor feeds in as the previous summary rather than a message
+	var previous string
+	if thread.AnchorMessageGUID != nil {
+		if anchor, _ := db.GetMessage(ctx.DB, *thread.AnchorMessageGUID); anchor != nil {
+			previous = anchor.Body
+		}
+		messages = filterMessage(messages, *thread.AnchorMessageGUID)
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	path, _ := buildThreadPath(ctx.DB, thread)
+	if path == "" {
+		path = thread.GUID
+	}
+	transcript := buildSummaryTranscript(path, previous, messages)
+
+	timeout := defaultSummarizerTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	runCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	summary := types.ThreadSummary{
+		ThreadGUID:       thread.GUID,
+		Trigger:          trigger,
+		FirstMessageGUID: messages[0].ID,
+		LastMessageGUID:  messages[len(messages)-1].ID,
+		MessageCount:     len(messages),
+		FromTS:           messages[0].TS,
+		ToTS:             messages[len(messages)-1].TS,
+	}
+	author := "system"
+	var output string
+	if cfg.Command != "" {
+		summary.Source, summary.Summarizer = "command", cfg.Command
+		output, err = runSummaryCommand(runCtx, ctx.Project.Root, cfg.Command, transcript, thread.GUID, path, trigger)
+	} else {
+		agentID, resolveErr := resolveAgentRef(ctx, cfg.Agent)
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+		agent, getErr := db.GetAgent(ctx.DB, agentID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if agent == nil {
+			return nil, fmt.Errorf("summarizer agent not found: @%s", agentID)
+		}
+		summary.Source, summary.Summarizer = "agent", agentID
+		author = agentID
+		output, err = daemon.RunOnce(runCtx, filepath.Dir(ctx.Project.DBPath), *agent, buildSummaryPrompt(path)+"\n\n"+transcript)
+	}
+	if err != nil {
+		return nil, err
+	}
+	summary.Body = strings.TrimSpace(output)
+	if summary.Body == "" {
+		return nil, fmt.Errorf("summarizer returned nothing")
+	}
+
+	// Mentions aren't extracted so a summary doesn't wake anyone
+	now := time.Now().Unix()
+	created, err := db.CreateMessage(ctx.DB, types.Message{
+		TS:        now,
+		Home:      thread.GUID,
+		FromAgent: author,
+		Body:      summary.Body,
+		Type:      types.MessageTypeAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AppendMessage(ctx.Project.DBPath, created); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.UpdateThread(ctx.DB, thread.GUID, db.ThreadUpdates{
+		AnchorMessageGUID: types.OptionalString{Set: true, Value: &created.ID},
+	}); err != nil {
+		return nil, err
+	}
+	if err := db.AppendThreadUpdate(ctx.Project.DBPath, db.ThreadUpdateJSONLRecord{
+		GUID:              thread.GUID,
+		AnchorMessageGUID: &created.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	summary.MessageGUID = created.ID
+	summary.CreatedAt = now
+	summary, err = db.CreateThreadSummary(ctx.DB, summary)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AppendThreadSummary(ctx.Project.DBPath, summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// summarizeOnArchive summarizes a thread that is being archived. Failures are
+// reported but do not stop the archive.
+func summarizeOnArchive(cmd *cobra.Command, ctx *CommandContext, thread *types.Thread) *types.ThreadSummary {
+	if noSummary, _ := cmd.Flags().GetBool("no-summary"); noSummary {
+		return nil
+	}
+	messages, err := db.GetThreadMessages(ctx.DB, thread.GUID)
+	if err == nil {
+		var summary *types.ThreadSummary
+		summary, err = summarizeThread(ctx, thread, messages, summaryTriggerArchive)
+		if err == nil {
+			return summary
+		}
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not summarize %s: %v\n", thread.Name, err)
+	return nil
+}
+
+// summarizePrunedThreads summarizes, per thread, the messages prune removed.
+// Failures are reported per thread; the pruned messages stay in history.jsonl.
+func summarizePrunedThreads(cmd *cobra.Command, ctx *CommandContext, removed []db.MessageJSONLRecord) []types.ThreadSummary {
+	var homes []string
+	byHome := make(map[string][]types.Message)
+	for _, record := range removed {
+		if record.Home == "" || record.Home == "room" {
+			continue
+		}
+		if _, ok := byHome[record.Home]; !ok {
+			homes = append(homes, record.Home)
+		}
+		byHome[record.Home] = append(byHome[record.Home], types.Message{
+			ID:        record.ID,
+			TS:        record.TS,
+			Home:      record.Home,
+			FromAgent: record.FromAgent,
+			Body:      record.Body,
+		})
+	}
+
+	var summaries []types.ThreadSummary
+	for _, home := range homes {
+		thread, err := db.GetThread(ctx.DB, home)
+		if err != nil || thread == nil {
+			continue
+		}
+		summary, err := summarizeThread(ctx, thread, byHome[home], summaryTriggerPrune)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not summarize %s: %v\n", thread.Name, err)
+			continue
+		}
+		if summary != nil {
+			summaries = append(summaries, *summary)
+		}
+	}
+	return summaries
+}
+
+func buildSummaryTranscript(path, previous string, messages []types.Message) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Thread: %s\n", path)
+	if previous != "" {
+		fmt.Fprintf(&builder, "\nPrevious summary:\n%s\n", previous)
+	}
+	builder.WriteString("\nMessages:\n")
+	for _, msg := range messages {
+		when := time.Unix(msg.TS, 0).Format("2006-01-02 15:04")
+		fmt.Fprintf(&builder, "[%s] @%s: %s\n", when, msg.FromAgent, msg.Body)
+	}
+	return builder.String()
+}
+
+func buildSummaryPrompt(path string) string {
+	return fmt.Sprintf(`Summarize the fray thread %s below for someone who wasn't there: decisions made, open questions, and where things stand. Fold in the previous summary if there is one. Reply with only the summary; don't run fray commands or post anything.`, path)
+}
+
+// runSummaryCommand runs a summarizer command through the shell with the
+// transcript on stdin and returns its stdout.
+func runSummaryCommand(ctx context.Context, root, command, transcript, threadGUID, path, trigger string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(transcript)
+	cmd.Env = append(os.Environ(),
+		"FRAY_THREAD="+path,
+		"FRAY_THREAD_GUID="+threadGUID,
+		"FRAY_SUMMARY_TRIGGER="+trigger,
+	)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("summarizer timed out")
+		}
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%w: %s", err, detail)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// formatSummaryProvenance describes where a summary anchor came from.
+func formatSummaryProvenance(summary types.ThreadSummary) string {
+	by := "command"
+	if summary.Source == "agent" {
+		by = "@" + summary.Summarizer
+	}
+	return fmt.Sprintf("summary of %d messages (%s … %s) by %s on %s",
+		summary.MessageCount, summary.FirstMessageGUID, summary.LastMessageGUID, by, summary.Trigger)
+}
+
+// NewThreadSummarizeCmd creates the thread summarize command.
+func NewThreadSummarizeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summarize <thread>",
+		Short: "Summarize a thread into its anchor",
+		Long: `Run the project's summarizer over a thread and post the result as its anchor.
+
+The same summarizer runs automatically on fray archive and fray prune. It is
+configured with fray thread summarizer: either a shell command that reads the
+thread transcript on stdin and prints a summary, or a managed agent run once
+through its driver. The current anchor is passed in as the previous summary.
+
+Examples:
+  fray thread summarize design
+  fray thread summarizer --command "llm -s 'Summarize this thread'"
+  fray thread summarizer --agent scribe
+  fray thread summarizer --off`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			thread, err := resolveThreadRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			messages, err := db.GetThreadMessages(ctx.DB, thread.GUID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			summary, err := summarizeThread(ctx, thread, messages, summaryTriggerManual)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if summary == nil {
+				config, _ := db.ReadProjectConfig(ctx.Project.DBPath)
+				if config == nil || config.Summarizer == nil {
+					return writeCommandError(cmd, fmt.Errorf("no summarizer configured (see fray thread summarizer)"))
+				}
+				return writeCommandError(cmd, fmt.Errorf("nothing to summarize in %s", thread.Name))
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(summary)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Anchored %s: %s\n", summary.MessageGUID, formatSummaryProvenance(*summary))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewThreadSummarizerCmd creates the thread summarizer command.
+func NewThreadSummarizerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summarizer",
+		Short: "Show or set the thread summarizer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			command, _ := cmd.Flags().GetString("command")
+			agentRef, _ := cmd.Flags().GetString("agent")
+			timeout, _ := cmd.Flags().GetInt("timeout")
+			off, _ := cmd.Flags().GetBool("off")
+			if command != "" && agentRef != "" {
+				return writeCommandError(cmd, fmt.Errorf("use --command or --agent, not both"))
+			}
+			if timeout < 0 {
+				return writeCommandError(cmd, fmt.Errorf("invalid --timeout value: %d", timeout))
+			}
+
+			config, err := db.ReadProjectConfig(ctx.Project.DBPath)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			var current *db.SummarizerConfig
+			if config != nil {
+				current = config.Summarizer
+			}
+
+			changed := off || command != "" || agentRef != "" || cmd.Flags().Changed("timeout")
+			if changed {
+				next := &db.SummarizerConfig{}
+				if !off {
+					if current != nil {
+						*next = *current
+					}
+					if command != "" {
+						next.Command, next.Agent = command, ""
+					}
+					if agentRef != "" {
+						agentID, err := resolveAgentRef(ctx, agentRef)
+						if err != nil {
+							return writeCommandError(cmd, err)
+						}
+						next.Command, next.Agent = "", agentID
+					}
+					if cmd.Flags().Changed("timeout") {
+						next.Timeout = timeout
+					}
+					if next.Command == "" && next.Agent == "" {
+						return writeCommandError(cmd, fmt.Errorf("--command or --agent is required"))
+					}
+				}
+				updated, err := db.UpdateProjectConfig(ctx.Project.DBPath, db.ProjectConfig{Summarizer: next})
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				current = updated.Summarizer
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(current)
+			}
+			out := cmd.OutOrStdout()
+			switch {
+			case current == nil:
+				fmt.Fprintln(out, "No summarizer configured")
+			case current.Command != "":
+				fmt.Fprintf(out, "Summarizer: command %q\n", current.Command)
+			default:
+				fmt.Fprintf(out, "Summarizer: @%s\n", current.Agent)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("command", "", "shell command reading the transcript on stdin")
+	cmd.Flags().String("agent", "", "managed agent to run once through its driver")
+	cmd.Flags().Int("timeout", 0, "seconds before the summarizer is stopped (default 120)")
+	cmd.Flags().Bool("off", false, "turn summaries off")
+	return cmd
+}
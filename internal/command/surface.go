@@ -3,6 +3,7 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/adamavenir/fray/internal/core"
@@ -16,7 +17,17 @@ func NewSurfaceCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "surface <message> <comment>",
 		Short: "Surface a message to the room with a backlink",
-		Args:  cobra.ExactArgs(2),
+		Long: `Surface a message to the room with a backlink.
+
+With --to, the comment and a quote of the message are posted into another
+channel from the global config instead. The copy is sent as
+<this channel>:<agent> and references the original as <this channel>:<msg-id>;
+a backlink event is left where the original lives.
+
+Examples:
+  fray surface msg-abc123 "worth a look" --as alice
+  fray surface msg-abc123 "@bob this affects the API" --to api --as alice`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := GetContext(cmd)
 			if err != nil {
@@ -49,6 +60,11 @@ func NewSurfaceCmd() *cobra.Command {
 				return writeCommandError(cmd, err)
 			}
 
+			toRef, _ := cmd.Flags().GetString("to")
+			if toRef != "" {
+				return surfaceToChannel(cmd, ctx, agentID, original, args[1], toRef)
+			}
+
 			bases, err := db.GetAgentBases(ctx.DB)
 			if err != nil {
 				return writeCommandError(cmd, err)
@@ -114,7 +130,73 @@ func NewSurfaceCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("as", "", "agent ID to surface as")
+	cmd.Flags().String("to", "", "surface into another channel (name or ID from the global config)")
 	_ = cmd.MarkFlagRequired("as")
 
 	return cmd
 }
+
+// surfaceToChannel quotes original into another channel's room and leaves a
+// backlink event beside the original.
+func surfaceToChannel(cmd *cobra.Command, ctx *CommandContext, agentID string, original *types.Message, comment, toRef string) error {
+	target, err := db.ResolveChannelTarget(strings.TrimPrefix(toRef, "#"))
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if target.SameChannel(ctx.Project.DBPath) {
+		return writeCommandError(cmd, fmt.Errorf("#%s is this channel; surface without --to", target.Name))
+	}
+
+	sourceChannel, _ := db.LocalChannel(ctx.Project.DBPath)
+	quoted := strings.ReplaceAll(strings.TrimSpace(original.Body), "\n", "\n> ")
+	body := fmt.Sprintf("%s\n\n> @%s in #%s:\n> %s", strings.TrimSpace(comment), original.FromAgent, sourceChannel, quoted)
+
+	surfaceMessage, err := db.ForwardMessage(target, db.ForwardInput{
+		SourceChannel: sourceChannel,
+		Source:        *original,
+		FromAgent:     agentID,
+		Body:          body,
+		Type:          types.MessageTypeSurface,
+	})
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	now := time.Now().Unix()
+	updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
+	if err := db.UpdateAgent(ctx.DB, agentID, updates); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	home := original.Home
+	if home == "" {
+		home = "room"
+	}
+	reference := original.ID
+	surfaceRef := qualifyID(target.Name, surfaceMessage.ID)
+	eventMessage, err := db.CreateMessage(ctx.DB, types.Message{
+		TS:             now,
+		FromAgent:      "system",
+		Body:           fmt.Sprintf("surface: @%s surfaced #%s to #%s", agentID, original.ID, target.Name),
+		Type:           types.MessageTypeEvent,
+		References:     &reference,
+		SurfaceMessage: &surfaceRef,
+		Home:           home,
+	})
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := db.AppendMessage(ctx.Project.DBPath, eventMessage); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
+	if ctx.JSONMode {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+			"surface": surfaceMessage,
+			"source":  original.ID,
+			"channel": target.Name,
+		})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Surfaced message %s to #%s as %s\n", original.ID, target.Name, surfaceRef)
+	return nil
+}
@@ -0,0 +1,645 @@
+package command
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// NewTaskCmd creates the task command.
+func NewTaskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task <id>",
+		Short: "View or change a task",
+		Long: `Track work items alongside questions.
+
+Tasks move todo → in_progress → done, or to blocked with a reason. A task can
+depend on other tasks; it cannot be started until they are done. Tasks are
+stored in .fray/tasks.jsonl and can link to a thread and the message they
+came from.
+
+Examples:
+  fray task add "write the migration" --as alice --thread db
+  fray task add "deploy" --after tsk-abc1 --to bob
+  fray task start tsk-abc1 --as alice
+  fray task block tsk-def2 --reason "waiting on creds"
+  fray task done tsk-abc1
+  fray tasks                      # Board by status and agent`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			task, err := resolveTaskRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(task)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Task %s\n", task.GUID)
+			fmt.Fprintf(out, "  title: %s\n", task.Title)
+			fmt.Fprintf(out, "  status: %s\n", task.Status)
+			fmt.Fprintf(out, "  created_by: @%s\n", task.CreatedBy)
+			if task.Assignee != nil {
+				fmt.Fprintf(out, "  assignee: @%s\n", *task.Assignee)
+			}
+			if task.BlockedReason != nil {
+				fmt.Fprintf(out, "  blocked: %s\n", *task.BlockedReason)
+			}
+			if task.ThreadGUID != nil {
+				thread, _ := db.GetThread(ctx.DB, *task.ThreadGUID)
+				if thread != nil {
+					path, _ := buildThreadPath(ctx.DB, thread)
+					fmt.Fprintf(out, "  thread: %s (%s)\n", path, thread.GUID)
+				} else {
+					fmt.Fprintf(out, "  thread: %s\n", *task.ThreadGUID)
+				}
+			}
+			if task.MessageGUID != nil {
+				fmt.Fprintf(out, "  message: %s\n", *task.MessageGUID)
+			}
+			for _, guid := range task.DependsOn {
+				status := "missing"
+				if dep, _ := db.GetTask(ctx.DB, guid); dep != nil {
+					status = string(dep.Status)
+				}
+				fmt.Fprintf(out, "  after: %s (%s)\n", guid, status)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		NewTaskAddCmd(),
+		NewTaskAssignCmd(),
+		NewTaskStartCmd(),
+		NewTaskDoneCmd(),
+		NewTaskBlockCmd(),
+	)
+
+	return cmd
+}
+
+// NewTaskAddCmd creates the task add command.
+func NewTaskAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <title>",
+		Short: "Add a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			title := strings.TrimSpace(args[0])
+			if title == "" {
+				return writeCommandError(cmd, fmt.Errorf("task title is required"))
+			}
+			actor, err := resolveTaskActor(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			task := types.Task{Title: title, CreatedBy: actor}
+
+			if to, _ := cmd.Flags().GetString("to"); to != "" {
+				assignee, err := resolveAgentRef(ctx, to)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				task.Assignee = &assignee
+			}
+			if threadRef, _ := cmd.Flags().GetString("thread"); threadRef != "" {
+				thread, err := resolveThreadRef(ctx.DB, threadRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				task.ThreadGUID = &thread.GUID
+			}
+			if msgRef, _ := cmd.Flags().GetString("msg"); msgRef != "" {
+				msg, err := resolveMessageRef(ctx.DB, msgRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				task.MessageGUID = &msg.ID
+				if task.ThreadGUID == nil && msg.Home != "" && msg.Home != "room" {
+					home := msg.Home
+					task.ThreadGUID = &home
+				}
+			}
+			after, _ := cmd.Flags().GetStringSlice("after")
+			for _, ref := range after {
+				dep, err := resolveTaskRef(ctx.DB, ref)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				task.DependsOn = appendUnique(task.DependsOn, dep.GUID)
+			}
+
+			created, err := db.CreateTask(ctx.DB, task)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := db.AppendTask(ctx.Project.DBPath, created); err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(created)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added task %s: %s\n", created.GUID, created.Title)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("as", "", "agent creating the task (uses FRAY_AGENT_ID if not set)")
+	cmd.Flags().String("to", "", "assign to an agent")
+	cmd.Flags().String("thread", "", "link to a thread")
+	cmd.Flags().String("msg", "", "link to the message the task came from")
+	cmd.Flags().StringSlice("after", nil, "tasks that must be done first (repeatable)")
+
+	return cmd
+}
+
+// NewTaskAssignCmd creates the task assign command.
+func NewTaskAssignCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "assign <task> <agent>",
+		Short: "Assign a task",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			task, err := resolveTaskRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			assignee, err := resolveAgentRef(ctx, args[1])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			updated, err := updateTask(ctx, task.GUID, db.TaskUpdates{
+				Assignee: types.OptionalString{Set: true, Value: &assignee},
+			})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			return writeTaskResult(cmd, ctx, updated, fmt.Sprintf("Assigned %s to @%s", updated.GUID, assignee))
+		},
+	}
+}
+
+// NewTaskStartCmd creates the task start command.
+func NewTaskStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start <task>",
+		Short: "Start working on a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			task, err := resolveTaskRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				open, err := db.GetOpenDependencies(ctx.DB, *task)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if len(open) > 0 {
+					return writeCommandError(cmd, fmt.Errorf("%s is waiting on %s (use --force to start anyway)", task.GUID, formatTaskRefs(open)))
+				}
+			}
+
+			status := string(types.TaskStatusInProgress)
+			updates := db.TaskUpdates{
+				Status:        types.OptionalString{Set: true, Value: &status},
+				BlockedReason: types.OptionalString{Set: true},
+			}
+			if task.Assignee == nil {
+				actor, err := resolveActingAgent(cmd, ctx)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if actor != "" {
+					updates.Assignee = types.OptionalString{Set: true, Value: &actor}
+				}
+			}
+
+			updated, err := updateTask(ctx, task.GUID, updates)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			return writeTaskResult(cmd, ctx, updated, fmt.Sprintf("Started %s: %s", updated.GUID, updated.Title))
+		},
+	}
+
+	cmd.Flags().String("as", "", "agent taking the task if unassigned (uses FRAY_AGENT_ID if not set)")
+	cmd.Flags().Bool("force", false, "start even if dependencies are not done")
+
+	return cmd
+}
+
+// NewTaskDoneCmd creates the task done command.
+func NewTaskDoneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "done <task>",
+		Short: "Mark a task done",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			task, err := resolveTaskRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			status := string(types.TaskStatusDone)
+			updated, err := updateTask(ctx, task.GUID, db.TaskUpdates{
+				Status:        types.OptionalString{Set: true, Value: &status},
+				BlockedReason: types.OptionalString{Set: true},
+			})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			// Tasks whose last open dependency was this one are ready now
+			unblocked, err := readyDependents(ctx.DB, updated.GUID)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"task":      updated,
+					"unblocked": unblocked,
+				})
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Done %s: %s\n", updated.GUID, updated.Title)
+			if len(unblocked) > 0 {
+				fmt.Fprintf(out, "  ready: %s\n", formatTaskRefs(unblocked))
+			}
+			return nil
+		},
+	}
+}
+
+// NewTaskBlockCmd creates the task block command.
+func NewTaskBlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "block <task>",
+		Short: "Mark a task blocked",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			task, err := resolveTaskRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			reason, _ := cmd.Flags().GetString("reason")
+			onRefs, _ := cmd.Flags().GetStringSlice("on")
+			if strings.TrimSpace(reason) == "" && len(onRefs) == 0 {
+				return writeCommandError(cmd, fmt.Errorf("--reason or --on is required"))
+			}
+
+			status := string(types.TaskStatusBlocked)
+			updates := db.TaskUpdates{Status: types.OptionalString{Set: true, Value: &status}}
+			if reason = strings.TrimSpace(reason); reason != "" {
+				updates.BlockedReason = types.OptionalString{Set: true, Value: &reason}
+			}
+			if len(onRefs) > 0 {
+				depends := append([]string{}, task.DependsOn...)
+				for _, ref := range onRefs {
+					dep, err := resolveTaskRef(ctx.DB, ref)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					if dep.GUID == task.GUID {
+						return writeCommandError(cmd, fmt.Errorf("a task cannot depend on itself"))
+					}
+					cycle, err := db.TaskDependsOn(ctx.DB, *dep, task.GUID)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					if cycle {
+						return writeCommandError(cmd, fmt.Errorf("%s already depends on %s", dep.GUID, task.GUID))
+					}
+					depends = appendUnique(depends, dep.GUID)
+				}
+				updates.DependsOn = &depends
+			}
+
+			updated, err := updateTask(ctx, task.GUID, updates)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			return writeTaskResult(cmd, ctx, updated, fmt.Sprintf("Blocked %s: %s", updated.GUID, updated.Title))
+		},
+	}
+
+	cmd.Flags().String("reason", "", "why the task is blocked")
+	cmd.Flags().StringSlice("on", nil, "tasks this one now waits on (repeatable)")
+
+	return cmd
+}
+
+// NewTasksCmd creates the tasks board command.
+func NewTasksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tasks [@agent]",
+		Short: "Show the task board",
+		Long: `Show tasks grouped by status and assignee. Done tasks are hidden unless --all.
+
+Examples:
+  fray tasks                # Open tasks
+  fray tasks @alice         # Alice's tasks
+  fray tasks --thread db    # Tasks linked to a thread
+  fray tasks --all          # Include done`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			all, _ := cmd.Flags().GetBool("all")
+			threadRef, _ := cmd.Flags().GetString("thread")
+
+			opts := types.TaskQueryOptions{}
+			if !all {
+				opts.Statuses = []types.TaskStatus{types.TaskStatusTodo, types.TaskStatusInProgress, types.TaskStatusBlocked}
+			}
+			if len(args) == 1 {
+				agentID, err := resolveAgentRef(ctx, args[0])
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				opts.Assignee = &agentID
+			}
+			if threadRef != "" {
+				thread, err := resolveThreadRef(ctx.DB, threadRef)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				opts.ThreadGUID = &thread.GUID
+			}
+
+			tasks, err := db.GetTasks(ctx.DB, &opts)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if ctx.JSONMode {
+				if tasks == nil {
+					tasks = []types.Task{}
+				}
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(tasks)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(tasks) == 0 {
+				fmt.Fprintln(out, "No tasks")
+				return nil
+			}
+
+			statuses := []types.TaskStatus{types.TaskStatusInProgress, types.TaskStatusBlocked, types.TaskStatusTodo, types.TaskStatusDone}
+			for _, status := range statuses {
+				byAgent := map[string][]types.Task{}
+				var agents []string
+				for _, task := range tasks {
+					if task.Status != status {
+						continue
+					}
+					assignee := ""
+					if task.Assignee != nil {
+						assignee = *task.Assignee
+					}
+					if _, ok := byAgent[assignee]; !ok {
+						agents = append(agents, assignee)
+					}
+					byAgent[assignee] = append(byAgent[assignee], task)
+				}
+				if len(agents) == 0 {
+					continue
+				}
+				sortAgentsUnassignedLast(agents)
+
+				fmt.Fprintf(out, "%s\n", strings.ToUpper(strings.ReplaceAll(string(status), "_", " ")))
+				for _, agent := range agents {
+					label := "unassigned"
+					if agent != "" {
+						label = "@" + agent
+					}
+					fmt.Fprintf(out, "  %s\n", label)
+					for _, task := range byAgent[agent] {
+						fmt.Fprintf(out, "    %s\n", formatTaskLine(ctx.DB, task))
+					}
+				}
+				fmt.Fprintln(out)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "include done tasks")
+	cmd.Flags().String("thread", "", "only tasks linked to a thread")
+
+	return cmd
+}
+
+func resolveTaskRef(dbConn *sql.DB, ref string) (*types.Task, error) {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(ref, "#"))
+	if trimmed == "" {
+		return nil, fmt.Errorf("task reference is required")
+	}
+
+	task, err := db.GetTask(dbConn, trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if task != nil {
+		return task, nil
+	}
+
+	if !strings.HasPrefix(strings.ToLower(trimmed), "tsk-") {
+		task, err = db.GetTaskByPrefix(dbConn, "tsk-"+trimmed)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			return task, nil
+		}
+	}
+
+	task, err = db.GetTaskByPrefix(dbConn, trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", ref)
+	}
+	return task, nil
+}
+
+// resolveTaskActor names who is creating a task: --as, FRAY_AGENT_ID, or the
+// stored username.
+func resolveTaskActor(cmd *cobra.Command, ctx *CommandContext) (string, error) {
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil || actor != "" {
+		return actor, err
+	}
+	if username, _ := db.GetConfig(ctx.DB, "username"); username != "" {
+		return username, nil
+	}
+	return "", fmt.Errorf("--as is required")
+}
+
+// updateTask applies updates and records them in tasks.jsonl.
+func updateTask(ctx *CommandContext, guid string, updates db.TaskUpdates) (*types.Task, error) {
+	updated, err := db.UpdateTask(ctx.DB, guid, updates)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, fmt.Errorf("task not found: %s", guid)
+	}
+
+	record := db.TaskUpdateJSONLRecord{GUID: guid, UpdatedAt: updated.UpdatedAt}
+	record.Status = taskUpdateValue(updates.Status)
+	record.Assignee = taskUpdateValue(updates.Assignee)
+	record.ThreadGUID = taskUpdateValue(updates.ThreadGUID)
+	record.MessageGUID = taskUpdateValue(updates.MessageGUID)
+	record.BlockedReason = taskUpdateValue(updates.BlockedReason)
+	record.DependsOn = updates.DependsOn
+	if err := db.AppendTaskUpdate(ctx.Project.DBPath, record); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// taskUpdateValue encodes a set-to-nil field as "" so replay clears it.
+func taskUpdateValue(value types.OptionalString) *string {
+	if !value.Set {
+		return nil
+	}
+	if value.Value == nil {
+		empty := ""
+		return &empty
+	}
+	return value.Value
+}
+
+func writeTaskResult(cmd *cobra.Command, ctx *CommandContext, task *types.Task, line string) error {
+	if ctx.JSONMode {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(task)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), line)
+	return nil
+}
+
+// readyDependents returns not-done tasks depending on guid that have no other
+// open dependencies.
+func readyDependents(dbConn *sql.DB, guid string) ([]types.Task, error) {
+	tasks, err := db.GetTasks(dbConn, &types.TaskQueryOptions{
+		Statuses: []types.TaskStatus{types.TaskStatusTodo, types.TaskStatusBlocked},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ready []types.Task
+	for _, task := range tasks {
+		if !containsString(task.DependsOn, guid) {
+			continue
+		}
+		open, err := db.GetOpenDependencies(dbConn, task)
+		if err != nil {
+			return nil, err
+		}
+		if len(open) == 0 {
+			ready = append(ready, task)
+		}
+	}
+	return ready, nil
+}
+
+func formatTaskLine(dbConn *sql.DB, task types.Task) string {
+	line := fmt.Sprintf("%s  %s", task.GUID, task.Title)
+	var notes []string
+	if task.BlockedReason != nil {
+		notes = append(notes, *task.BlockedReason)
+	}
+	if task.Status != types.TaskStatusDone {
+		if open, err := db.GetOpenDependencies(dbConn, task); err == nil && len(open) > 0 {
+			notes = append(notes, "after "+formatTaskRefs(open))
+		}
+	}
+	if len(notes) > 0 {
+		line += "  (" + strings.Join(notes, "; ") + ")"
+	}
+	return line
+}
+
+func formatTaskRefs(tasks []types.Task) string {
+	refs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		refs = append(refs, task.GUID)
+	}
+	return strings.Join(refs, ", ")
+}
+
+func sortAgentsUnassignedLast(agents []string) {
+	for i := 1; i < len(agents); i++ {
+		for j := i; j > 0 && agentSortsBefore(agents[j], agents[j-1]); j-- {
+			agents[j], agents[j-1] = agents[j-1], agents[j]
+		}
+	}
+}
+
+func agentSortsBefore(a, b string) bool {
+	if a == "" || b == "" {
+		return b == "" && a != ""
+	}
+	return a < b
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
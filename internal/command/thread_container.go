@@ -46,11 +46,19 @@ Examples:
 				// Thread doesn't exist - create it
 				return createThreadFromPath(cmd, ctx, args)
 			}
+			reader, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, reader, threadActionRead); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			pinnedOnly, _ := cmd.Flags().GetBool("pinned")
 			lastStr, _ := cmd.Flags().GetString("last")
 			sinceStr, _ := cmd.Flags().GetString("since")
 			showAllMessages, _ := cmd.Flags().GetBool("show-all")
+			render := markdownFor(cmd)
 
 			var messages []types.Message
 			if pinnedOnly {
@@ -132,7 +140,7 @@ Examples:
 			if anchorMsg != nil {
 				fmt.Fprintln(out)
 				fmt.Fprintf(out, "%sANCHOR:%s\n", dim, reset)
-				fmt.Fprintln(out, FormatMessageFull(*anchorMsg, projectName, bases))
+				fmt.Fprintln(out, FormatMessageFull(*anchorMsg, projectName, bases, render))
 
 				// Build thread metadata tree
 				participants := collectParticipants(messages)
@@ -146,6 +154,9 @@ Examples:
 					fmt.Fprintf(out, "  └── %d messages\n", len(messages))
 				}
 				fmt.Fprintf(out, "  └── last: %s\n", lastActivity)
+				if summary, err := db.GetThreadSummaryByMessage(ctx.DB, anchorMsg.ID); err == nil && summary != nil {
+					fmt.Fprintf(out, "  └── %s\n", formatSummaryProvenance(*summary))
+				}
 				fmt.Fprintln(out)
 
 				// Filter anchor from messages to avoid duplication
@@ -164,6 +175,7 @@ Examples:
 				ProjectName: projectName,
 				AgentBases:  bases,
 				QuotedMsgs:  quotedMsgs,
+				Markdown:    render,
 			})
 			for _, line := range lines {
 				fmt.Fprintln(out, line)
@@ -176,6 +188,7 @@ Examples:
 	cmd.Flags().String("last", "", "show last N messages")
 	cmd.Flags().String("since", "", "show messages after time or GUID")
 	cmd.Flags().Bool("show-all", false, "disable accordion, show all messages fully")
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
 	cmd.Flags().String("as", "", "agent to attribute anchor message (for creation)")
 	cmd.Flags().String("subscribe", "", "comma-separated agent list to subscribe (for creation)")
 
@@ -183,6 +196,9 @@ Examples:
 		NewThreadRenameCmd(),
 		NewThreadPinCmd(),
 		NewThreadUnpinCmd(),
+		NewThreadPermsCmd(),
+		NewThreadSummarizeCmd(),
+		NewThreadSummarizerCmd(),
 	)
 
 	return cmd
@@ -768,6 +784,13 @@ func NewThreadAddCmd() *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			addedBy := "system"
 			if asRef, _ := cmd.Flags().GetString("as"); asRef != "" {
@@ -844,6 +867,13 @@ func NewThreadRemoveCmd() *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			removedBy := "system"
 			if asRef, _ := cmd.Flags().GetString("as"); asRef != "" {
@@ -1013,6 +1043,7 @@ func NewThreadArchiveCmd() *cobra.Command {
 			return updateThreadStatus(cmd, args[0], types.ThreadStatusArchived)
 		},
 	}
+	cmd.Flags().String("as", "", "agent performing the archive (uses FRAY_AGENT_ID if not set)")
 	return cmd
 }
 
@@ -1026,6 +1057,7 @@ func NewThreadRestoreCmd() *cobra.Command {
 			return updateThreadStatus(cmd, args[0], types.ThreadStatusOpen)
 		},
 	}
+	cmd.Flags().String("as", "", "agent performing the restore (uses FRAY_AGENT_ID if not set)")
 	return cmd
 }
 
@@ -1040,6 +1072,13 @@ func updateThreadStatus(cmd *cobra.Command, ref string, status types.ThreadStatu
 	if err != nil {
 		return writeCommandError(cmd, err)
 	}
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+		return writeCommandError(cmd, err)
+	}
 
 	statusValue := string(status)
 	updated, err := db.UpdateThread(ctx.DB, thread.GUID, db.ThreadUpdates{
@@ -1055,6 +1094,7 @@ func updateThreadStatus(cmd *cobra.Command, ref string, status types.ThreadStatu
 	}); err != nil {
 		return writeCommandError(cmd, err)
 	}
+	recordThreadStatusAudit(cmd, ctx, thread, statusValue)
 
 	if ctx.JSONMode {
 		return json.NewEncoder(cmd.OutOrStdout()).Encode(updated)
@@ -1081,6 +1121,13 @@ func NewThreadRenameCmd() *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, err)
 			}
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+				return writeCommandError(cmd, err)
+			}
 
 			name := strings.TrimSpace(args[1])
 			if err := validateThreadName(name); err != nil {
@@ -1127,6 +1174,8 @@ func NewThreadRenameCmd() *cobra.Command {
 			}); err != nil {
 				return writeCommandError(cmd, err)
 			}
+			recordAudit(cmd, ctx, "thread.rename", thread.GUID,
+				map[string]any{"name": thread.Name}, map[string]any{"name": name})
 
 			if ctx.JSONMode {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(updated)
@@ -1137,5 +1186,7 @@ func NewThreadRenameCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().String("as", "", "agent performing the rename (uses FRAY_AGENT_ID if not set)")
+
 	return cmd
 }
@@ -380,6 +380,11 @@ Examples:
 			destRef := args[len(args)-1]
 			messageRefs := args[:len(args)-1]
 
+			actor, err := resolveActingAgent(cmd, ctx)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
 			// Resolve destination
 			var newHome string
 			if strings.ToLower(destRef) == "room" {
@@ -389,6 +394,9 @@ Examples:
 				if err != nil {
 					return writeCommandError(cmd, err)
 				}
+				if err := checkThreadPermission(ctx, thread, actor, threadActionCurate); err != nil {
+					return writeCommandError(cmd, err)
+				}
 				newHome = thread.GUID
 			}
 
@@ -425,6 +433,9 @@ Examples:
 					if m.Home == newHome {
 						continue
 					}
+					if err := checkMessageCuration(ctx, &m, actor); err != nil {
+						return writeCommandError(cmd, err)
+					}
 
 					oldHome := m.Home
 					if err := db.MoveMessage(ctx.DB, m.ID, newHome); err != nil {
@@ -440,6 +451,8 @@ Examples:
 					}); err != nil {
 						return writeCommandError(cmd, err)
 					}
+					recordAudit(cmd, ctx, "message.move", m.ID,
+						map[string]any{"home": oldHome}, map[string]any{"home": newHome})
 					moved++
 				}
 			}
@@ -498,6 +511,14 @@ func runThreadReparent(cmd *cobra.Command, ctx *CommandContext, args []string, s
 		}
 	}
 
+	actor, err := resolveActingAgent(cmd, ctx)
+	if err != nil {
+		return writeCommandError(cmd, err)
+	}
+	if err := checkThreadPermission(ctx, sourceThread, actor, threadActionCurate); err != nil {
+		return writeCommandError(cmd, err)
+	}
+
 	// Resolve destination (new parent)
 	var newParentGUID *string
 	destLower := strings.ToLower(destRef)
@@ -509,6 +530,9 @@ func runThreadReparent(cmd *cobra.Command, ctx *CommandContext, args []string, s
 		if err != nil {
 			return writeCommandError(cmd, fmt.Errorf("destination thread not found: %s", destRef))
 		}
+		if err := checkThreadPermission(ctx, destThread, actor, threadActionCurate); err != nil {
+			return writeCommandError(cmd, err)
+		}
 		newParentGUID = &destThread.GUID
 
 		// Check for cycles: can't move thread under one of its descendants
@@ -546,7 +570,7 @@ func runThreadReparent(cmd *cobra.Command, ctx *CommandContext, args []string, s
 	now := time.Now().Unix()
 
 	// Update thread parent
-	_, err := db.UpdateThread(ctx.DB, sourceThread.GUID, db.ThreadUpdates{
+	_, err = db.UpdateThread(ctx.DB, sourceThread.GUID, db.ThreadUpdates{
 		ParentThread: types.OptionalString{Set: true, Value: newParentGUID},
 	})
 	if err != nil {
@@ -560,6 +584,8 @@ func runThreadReparent(cmd *cobra.Command, ctx *CommandContext, args []string, s
 	}); err != nil {
 		return writeCommandError(cmd, err)
 	}
+	recordAudit(cmd, ctx, "thread.move", sourceThread.GUID,
+		map[string]any{"parent": auditNullable(currentParent)}, map[string]any{"parent": auditNullable(targetParent)})
 
 	// If anchor text provided, create anchor message
 	if anchorText != "" {
@@ -0,0 +1,364 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/command/hooks"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// threadAction is an operation gated by a thread's ACL.
+type threadAction string
+
+const (
+	threadActionRead   threadAction = "read"
+	threadActionWrite  threadAction = "write to"
+	threadActionCurate threadAction = "curate"
+	// threadActionChangePerms is reserved for the owner.
+	threadActionChangePerms threadAction = "change permissions on"
+)
+
+// threadPermits reports whether agentID may perform action on thread.
+//
+// The owner may do anything. Writers and curators may post; readers, writers
+// and curators may read. An empty list leaves that action open, except that
+// curation of an owned thread without curators is reserved for the owner.
+func threadPermits(thread types.Thread, agentID string, action threadAction) bool {
+	if thread.OwnerAgent != nil && agentMatches(*thread.OwnerAgent, agentID) {
+		return true
+	}
+	switch action {
+	case threadActionRead:
+		if len(thread.Readers) == 0 {
+			return true
+		}
+		return agentInList(thread.Readers, agentID) ||
+			agentInList(thread.Writers, agentID) ||
+			agentInList(thread.Curators, agentID)
+	case threadActionWrite:
+		if len(thread.Writers) == 0 {
+			return true
+		}
+		return agentInList(thread.Writers, agentID) || agentInList(thread.Curators, agentID)
+	case threadActionCurate:
+		if len(thread.Curators) == 0 {
+			return thread.OwnerAgent == nil
+		}
+		return agentInList(thread.Curators, agentID)
+	}
+	return false
+}
+
+// allowedAgents lists who may perform action, for error messages.
+func allowedAgents(thread types.Thread, action threadAction) []string {
+	var allowed []string
+	if thread.OwnerAgent != nil {
+		allowed = append(allowed, *thread.OwnerAgent)
+	}
+	switch action {
+	case threadActionRead:
+		allowed = append(allowed, thread.Readers...)
+		allowed = append(allowed, thread.Writers...)
+		allowed = append(allowed, thread.Curators...)
+	case threadActionWrite:
+		allowed = append(allowed, thread.Writers...)
+		allowed = append(allowed, thread.Curators...)
+	case threadActionCurate:
+		allowed = append(allowed, thread.Curators...)
+	}
+	return hooks.UniqueSorted(allowed)
+}
+
+// agentMatches matches exact IDs and sub-agents (alice matches alice.1).
+func agentMatches(entry, agentID string) bool {
+	return entry == agentID || strings.HasPrefix(agentID, entry+".")
+}
+
+func agentInList(list []string, agentID string) bool {
+	for _, entry := range list {
+		if agentMatches(entry, agentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveActingAgent returns the agent a command acts as: --as when the command
+// has that flag, otherwise FRAY_AGENT_ID. An empty result means a human at the
+// terminal, who is not subject to thread ACLs.
+func resolveActingAgent(cmd *cobra.Command, ctx *CommandContext) (string, error) {
+	ref := ""
+	if cmd.Flags().Lookup("as") != nil {
+		ref, _ = cmd.Flags().GetString("as")
+	}
+	if ref == "" {
+		ref = os.Getenv("FRAY_AGENT_ID")
+	}
+	if ref == "" {
+		return "", nil
+	}
+	return resolveAgentRef(ctx, ref)
+}
+
+// checkThreadPermission returns a threadPermissionError when agentID may not
+// perform action on thread. Humans (no agent, or the stored username) are exempt.
+func checkThreadPermission(ctx *CommandContext, thread *types.Thread, agentID string, action threadAction) error {
+	if thread == nil || agentID == "" || agentID == "system" {
+		return nil
+	}
+	if username, _ := db.GetConfig(ctx.DB, "username"); username != "" && username == agentID {
+		return nil
+	}
+	if threadPermits(*thread, agentID, action) {
+		return nil
+	}
+	path, err := buildThreadPath(ctx.DB, thread)
+	if err != nil || path == "" {
+		path = thread.GUID
+	}
+	return &threadPermissionError{
+		AgentID: agentID,
+		Action:  action,
+		Thread:  path,
+		Allowed: allowedAgents(*thread, action),
+	}
+}
+
+// checkMessageCuration guards acting on another agent's message: the actor
+// must be able to curate the thread the message lives in.
+func checkMessageCuration(ctx *CommandContext, msg *types.Message, agentID string) error {
+	if msg == nil || agentID == "" || msg.FromAgent == agentID {
+		return nil
+	}
+	if msg.Home == "" || msg.Home == "room" {
+		return nil
+	}
+	thread, err := db.GetThread(ctx.DB, msg.Home)
+	if err != nil {
+		return err
+	}
+	return checkThreadPermission(ctx, thread, agentID, threadActionCurate)
+}
+
+// isThreadCurator reports whether agentID was explicitly given curation rights
+// on thread, as owner or curator. Editing other agents' messages requires this
+// even on open threads.
+func isThreadCurator(thread *types.Thread, agentID string) bool {
+	if thread == nil || agentID == "" {
+		return false
+	}
+	if thread.OwnerAgent != nil && agentMatches(*thread.OwnerAgent, agentID) {
+		return true
+	}
+	return agentInList(thread.Curators, agentID)
+}
+
+// checkPermsChange allows the owner (or anyone while the thread is unowned)
+// to change a thread's permissions.
+func checkPermsChange(ctx *CommandContext, thread *types.Thread, agentID string) error {
+	if thread.OwnerAgent == nil {
+		return nil
+	}
+	return checkThreadPermission(ctx, thread, agentID, threadActionChangePerms)
+}
+
+// NewThreadPermsCmd creates the thread perms command.
+func NewThreadPermsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "perms <path>",
+		Short: "Show or change thread permissions",
+		Long: `Show or change who may read, post to, and curate a thread.
+
+  owner     may do anything, including changing permissions
+  writers   may post (empty = anyone)
+  readers   may read (empty = anyone)
+  curators  may move, add/remove, rename, archive, and edit or delete
+            other agents' messages (empty = owner, or anyone if unowned)
+
+Humans are not restricted. Changing permissions requires the owner (or anyone
+while the thread has no owner). readers is enforced by fray get and fray thread;
+search, mentions, fray chat, and fray web don't check it, so treat it as
+advisory there.
+
+Examples:
+  fray thread perms opus/notes
+  fray thread perms design --owner opus --add-writer designer --add-curator pm
+  fray thread perms design --remove-writer designer --as opus
+  fray thread perms design --clear-owner`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			thread, err := resolveThreadRef(ctx.DB, args[0])
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			ownerRef, _ := cmd.Flags().GetString("owner")
+			clearOwner, _ := cmd.Flags().GetBool("clear-owner")
+			changes := map[string][]string{}
+			for _, name := range []string{"add-writer", "remove-writer", "add-reader", "remove-reader", "add-curator", "remove-curator"} {
+				values, _ := cmd.Flags().GetStringSlice(name)
+				for _, value := range values {
+					agentID, err := resolveAgentRef(ctx, value)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					changes[name] = append(changes[name], agentID)
+				}
+			}
+
+			changing := ownerRef != "" || clearOwner || len(changes) > 0
+			if changing {
+				actor, err := resolveActingAgent(cmd, ctx)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if err := checkPermsChange(ctx, thread, actor); err != nil {
+					return writeCommandError(cmd, err)
+				}
+
+				updates := db.ThreadUpdates{}
+				record := db.ThreadUpdateJSONLRecord{GUID: thread.GUID}
+				if clearOwner {
+					updates.OwnerAgent = types.OptionalString{Set: true, Value: nil}
+					empty := ""
+					record.OwnerAgent = &empty
+				} else if ownerRef != "" {
+					owner, err := resolveAgentRef(ctx, ownerRef)
+					if err != nil {
+						return writeCommandError(cmd, err)
+					}
+					updates.OwnerAgent = types.OptionalString{Set: true, Value: &owner}
+					record.OwnerAgent = &owner
+				}
+				if writers, ok := applyListChanges(thread.Writers, changes["add-writer"], changes["remove-writer"]); ok {
+					updates.Writers = types.OptionalStrings{Set: true, Value: writers}
+					record.Writers = &writers
+				}
+				if readers, ok := applyListChanges(thread.Readers, changes["add-reader"], changes["remove-reader"]); ok {
+					updates.Readers = types.OptionalStrings{Set: true, Value: readers}
+					record.Readers = &readers
+				}
+				if curators, ok := applyListChanges(thread.Curators, changes["add-curator"], changes["remove-curator"]); ok {
+					updates.Curators = types.OptionalStrings{Set: true, Value: curators}
+					record.Curators = &curators
+				}
+
+				before := threadPermsAudit(thread)
+				thread, err = db.UpdateThread(ctx.DB, thread.GUID, updates)
+				if err != nil {
+					return writeCommandError(cmd, err)
+				}
+				if err := db.AppendThreadUpdate(ctx.Project.DBPath, record); err != nil {
+					return writeCommandError(cmd, err)
+				}
+				recordAudit(cmd, ctx, "thread.perms", thread.GUID, before, threadPermsAudit(thread))
+			}
+
+			path, err := buildThreadPath(ctx.DB, thread)
+			if err != nil || path == "" {
+				path = thread.GUID
+			}
+
+			if ctx.JSONMode {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"thread":   thread.GUID,
+					"path":     path,
+					"owner":    thread.OwnerAgent,
+					"writers":  nonNilList(thread.Writers),
+					"readers":  nonNilList(thread.Readers),
+					"curators": nonNilList(thread.Curators),
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			owner := "(none)"
+			if thread.OwnerAgent != nil {
+				owner = "@" + *thread.OwnerAgent
+			}
+			curatorsDefault := "anyone"
+			if thread.OwnerAgent != nil {
+				curatorsDefault = "owner only"
+			}
+			fmt.Fprintf(out, "%s (%s)\n", path, thread.GUID)
+			fmt.Fprintf(out, "  owner:    %s\n", owner)
+			fmt.Fprintf(out, "  writers:  %s\n", formatACLList(thread.Writers, "anyone"))
+			fmt.Fprintf(out, "  readers:  %s\n", formatACLList(thread.Readers, "anyone"))
+			fmt.Fprintf(out, "  curators: %s\n", formatACLList(thread.Curators, curatorsDefault))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("owner", "", "set the thread owner")
+	cmd.Flags().Bool("clear-owner", false, "remove the thread owner")
+	cmd.Flags().StringSlice("add-writer", nil, "allow agent(s) to post")
+	cmd.Flags().StringSlice("remove-writer", nil, "remove agent(s) from writers")
+	cmd.Flags().StringSlice("add-reader", nil, "allow agent(s) to read")
+	cmd.Flags().StringSlice("remove-reader", nil, "remove agent(s) from readers")
+	cmd.Flags().StringSlice("add-curator", nil, "allow agent(s) to curate")
+	cmd.Flags().StringSlice("remove-curator", nil, "remove agent(s) from curators")
+	cmd.Flags().String("as", "", "agent changing permissions (uses FRAY_AGENT_ID if not set)")
+
+	return cmd
+}
+
+// applyListChanges returns the updated list and whether anything was requested.
+// threadPermsAudit is a thread's owner and ACLs as recorded in the audit log.
+func threadPermsAudit(thread *types.Thread) map[string]any {
+	owner := ""
+	if thread.OwnerAgent != nil {
+		owner = *thread.OwnerAgent
+	}
+	return map[string]any{
+		"owner":    auditNullable(owner),
+		"writers":  nonNilList(thread.Writers),
+		"readers":  nonNilList(thread.Readers),
+		"curators": nonNilList(thread.Curators),
+	}
+}
+
+func applyListChanges(current, add, remove []string) ([]string, bool) {
+	if len(add) == 0 && len(remove) == 0 {
+		return current, false
+	}
+	removeSet := make(map[string]struct{}, len(remove))
+	for _, agentID := range remove {
+		removeSet[agentID] = struct{}{}
+	}
+	result := []string{}
+	for _, agentID := range append(append([]string{}, current...), add...) {
+		if _, ok := removeSet[agentID]; ok {
+			continue
+		}
+		result = append(result, agentID)
+	}
+	return hooks.UniqueSorted(result), true
+}
+
+func formatACLList(list []string, empty string) string {
+	if len(list) == 0 {
+		return "(" + empty + ")"
+	}
+	mentions := make([]string, len(list))
+	for i, agentID := range list {
+		mentions[i] = "@" + agentID
+	}
+	return strings.Join(mentions, ", ")
+}
+
+func nonNilList(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
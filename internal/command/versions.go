@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/charmbracelet/lipgloss"
@@ -90,7 +91,11 @@ func printVersions(cmd *cobra.Command, history *types.MessageVersionHistory, las
 		version := versions[i]
 		label := versionLabel(version)
 		timestamp := formatVersionTime(version.Timestamp)
-		fmt.Fprintf(out, "v%d%s %s\n", version.Version, label, timestamp)
+		signature := ""
+		if marker := core.SignatureLabel(version.SignatureStatus); marker != "" {
+			signature = " " + marker
+		}
+		fmt.Fprintf(out, "v%d%s %s%s\n", version.Version, label, timestamp, signature)
 		if version.Version > 1 && version.Reason != "" {
 			fmt.Fprintf(out, "  \"%s\"\n", version.Reason)
 		}
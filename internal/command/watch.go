@@ -31,6 +31,7 @@ func NewWatchCmd() *cobra.Command {
 			last, _ := cmd.Flags().GetInt("last")
 			includeArchived, _ := cmd.Flags().GetBool("archived")
 			asAgent, _ := cmd.Flags().GetString("as")
+			render := markdownFor(cmd)
 
 			// Resolve agent filter - use --as flag or fall back to FRAY_AGENT_ID env var
 			var filterAgent string
@@ -95,7 +96,7 @@ func NewWatchCmd() *cobra.Command {
 						}
 					} else {
 						for _, msg := range recent {
-							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 						}
 						watchLabel := "watching"
 						if filterAgent != "" {
@@ -226,7 +227,7 @@ func NewWatchCmd() *cobra.Command {
 						}
 					} else {
 						for _, msg := range newMessages {
-							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases))
+							fmt.Fprintln(out, FormatMessage(msg, projectName, agentBases, render))
 						}
 					}
 
@@ -274,6 +275,7 @@ func NewWatchCmd() *cobra.Command {
 
 	cmd.Flags().Int("last", 10, "show last N messages before streaming")
 	cmd.Flags().Bool("archived", false, "include archived messages")
+	cmd.Flags().Bool("raw", false, "show message bodies without markdown rendering")
 	cmd.Flags().String("as", "", "filter to agent-relevant events (mentions, reactions, replies)")
 	return cmd
 }
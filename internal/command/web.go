@@ -0,0 +1,124 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/web"
+	"github.com/spf13/cobra"
+)
+
+// NewWebCmd creates the web command.
+func NewWebCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "web",
+		Short: "Serve the chat UI in a local browser",
+		Long: `Serve a browser UI for this project on localhost.
+
+The page mirrors 'fray chat': the room, the thread tree, questions, faves,
+reactions and agent presence, updating live as messages arrive. Messages are
+posted as the chat username (from 'fray config username').
+
+The server only listens on 127.0.0.1 and requires the access token in the
+printed URL, so only the local user can reach it.
+
+Examples:
+  fray web                # Serve on http://127.0.0.1:7777
+  fray web --port 0       # Pick a free port
+  fray web --open         # Also open the page in a browser
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := GetContext(cmd)
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			defer ctx.DB.Close()
+
+			port, _ := cmd.Flags().GetInt("port")
+			openBrowser, _ := cmd.Flags().GetBool("open")
+
+			username, err := db.GetConfig(ctx.DB, "username")
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			if username == "" {
+				if !isTTY(os.Stdin) {
+					return writeCommandError(cmd, fmt.Errorf("username is required: set it with 'fray config username <name>'"))
+				}
+				username = promptUsername()
+				if username == "" {
+					return writeCommandError(cmd, fmt.Errorf("username is required"))
+				}
+				if err := db.SetConfig(ctx.DB, "username", username); err != nil {
+					return writeCommandError(cmd, err)
+				}
+			}
+
+			server, err := web.NewServer(web.Options{
+				DB:          ctx.DB,
+				Project:     ctx.Project,
+				ProjectName: GetProjectName(ctx.Project.Root),
+				Username:    username,
+			})
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+
+			listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+			if err != nil {
+				return writeCommandError(cmd, err)
+			}
+			url := fmt.Sprintf("http://%s/?token=%s", listener.Addr().String(), server.Token())
+
+			if ctx.JSONMode {
+				_ = json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]any{
+					"url":      url,
+					"username": username,
+				})
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Serving fray web as @%s\n", username)
+				fmt.Fprintf(cmd.OutOrStdout(), "Open %s\n", url)
+				fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop")
+			}
+			if openBrowser {
+				if err := openURL(url); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Could not open browser: %v\n", err)
+				}
+			}
+
+			serveCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := server.Serve(serveCtx, listener); err != nil {
+				return writeCommandError(cmd, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("port", 7777, "port to listen on (0 picks a free port)")
+	cmd.Flags().Bool("open", false, "open the page in the default browser")
+
+	return cmd
+}
+
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// AttachmentLabel renders an attachment as "name · mime · size" for chips.
+func AttachmentLabel(attachment types.Attachment) string {
+	return fmt.Sprintf("%s · %s · %s", attachment.Name, attachment.Mime, FormatSize(attachment.Size))
+}
+
+// FormatSize renders a byte count in B/KB/MB/GB.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit || suffix == "GB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChatConfig stores per-user chat preferences (~/.config/fray/chat.json).
+type ChatConfig struct {
+	Theme  string               `json:"theme,omitempty"`  // theme used when --theme is not given
+	Themes map[string]ChatTheme `json:"themes,omitempty"` // custom themes by name
+	Keys   map[string]string    `json:"keys,omitempty"`   // action -> key, e.g. "search": "ctrl+f"
+}
+
+// ChatTheme overrides colors of a built-in base theme. Colors accept 256-color
+// codes ("111") or truecolor hex ("#7aa2f7").
+type ChatTheme struct {
+	Base        string            `json:"base,omitempty"`         // built-in theme to start from (default: dark)
+	Colors      map[string]string `json:"colors,omitempty"`       // text, meta, status, user, input_bg, ...
+	AgentColors []string          `json:"agent_colors,omitempty"` // palette for agent bylines
+	ChromaStyle string            `json:"chroma_style,omitempty"` // code block style, e.g. "github"
+}
+
+// ChatConfigPath returns the chat config file path.
+func ChatConfigPath() (string, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "chat.json"), nil
+}
+
+// ReadChatConfig reads the chat config file if present.
+func ReadChatConfig() (*ChatConfig, error) {
+	path, err := ChatConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var config ChatConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ChatLayout is a saved split-pane arrangement in chat.
+type ChatLayout struct {
+	Split string           `json:"split"` // "horizontal" (stacked) or "vertical" (side by side)
+	Panes []ChatLayoutPane `json:"panes"`
+	Focus int              `json:"focus,omitempty"`
+}
+
+// ChatLayoutPane binds a pane to the room, a thread, or a pseudo-thread
+// (optionally scoped to a thread).
+type ChatLayoutPane struct {
+	Thread string `json:"thread,omitempty"` // thread GUID; empty = room
+	Pseudo string `json:"pseudo,omitempty"` // e.g. "open-qs"
+}
+
+// chatLayoutsFile maps project root -> username -> layout.
+type chatLayoutsFile map[string]map[string]ChatLayout
+
+// ChatLayoutsPath returns the saved chat layouts file path.
+func ChatLayoutsPath() (string, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "chat-layouts.json"), nil
+}
+
+func readChatLayouts() (chatLayoutsFile, error) {
+	path, err := ChatLayoutsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return chatLayoutsFile{}, nil
+		}
+		return nil, err
+	}
+	layouts := chatLayoutsFile{}
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return layouts, nil
+}
+
+// ReadChatLayout returns the saved layout for a user in a project, or nil.
+func ReadChatLayout(projectRoot, username string) (*ChatLayout, error) {
+	layouts, err := readChatLayouts()
+	if err != nil {
+		return nil, err
+	}
+	layout, ok := layouts[projectRoot][username]
+	if !ok {
+		return nil, nil
+	}
+	return &layout, nil
+}
+
+// WriteChatLayout saves (or, when layout is nil, clears) a user's layout for a project.
+func WriteChatLayout(projectRoot, username string, layout *ChatLayout) error {
+	layouts, err := readChatLayouts()
+	if err != nil {
+		return err
+	}
+	if layout == nil {
+		if _, ok := layouts[projectRoot][username]; !ok {
+			return nil
+		}
+		delete(layouts[projectRoot], username)
+		if len(layouts[projectRoot]) == 0 {
+			delete(layouts, projectRoot)
+		}
+	} else {
+		if layouts[projectRoot] == nil {
+			layouts[projectRoot] = map[string]ChatLayout{}
+		}
+		layouts[projectRoot][username] = *layout
+	}
+
+	if _, err := ensureConfigDir(); err != nil {
+		return err
+	}
+	path, err := ChatLayoutsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(layouts, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o644)
+}
+
+// chatDraftsFile maps project root -> username -> home ("room" or thread GUID) -> draft.
+type chatDraftsFile map[string]map[string]map[string]string
+
+// ChatDraftsPath returns the unsent chat drafts file path.
+func ChatDraftsPath() (string, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "chat-drafts.json"), nil
+}
+
+func readChatDrafts() (chatDraftsFile, error) {
+	path, err := ChatDraftsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return chatDraftsFile{}, nil
+		}
+		return nil, err
+	}
+	drafts := chatDraftsFile{}
+	if err := json.Unmarshal(data, &drafts); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return drafts, nil
+}
+
+// ReadChatDrafts returns a user's unsent drafts in a project, keyed by home.
+func ReadChatDrafts(projectRoot, username string) (map[string]string, error) {
+	drafts, err := readChatDrafts()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for home, draft := range drafts[projectRoot][username] {
+		result[home] = draft
+	}
+	return result, nil
+}
+
+// WriteChatDrafts replaces a user's drafts for a project. Empty drafts are dropped.
+func WriteChatDrafts(projectRoot, username string, drafts map[string]string) error {
+	all, err := readChatDrafts()
+	if err != nil {
+		return err
+	}
+	kept := map[string]string{}
+	for home, draft := range drafts {
+		if strings.TrimSpace(draft) != "" {
+			kept[home] = draft
+		}
+	}
+	if len(kept) == 0 {
+		if _, ok := all[projectRoot][username]; !ok {
+			return nil
+		}
+		delete(all[projectRoot], username)
+		if len(all[projectRoot]) == 0 {
+			delete(all, projectRoot)
+		}
+	} else {
+		if all[projectRoot] == nil {
+			all[projectRoot] = map[string]map[string]string{}
+		}
+		all[projectRoot][username] = kept
+	}
+
+	if _, err := ensureConfigDir(); err != nil {
+		return err
+	}
+	path, err := ChatDraftsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,65 @@
+package core
+
+import "testing"
+
+func TestChatLayoutRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	layout := &ChatLayout{
+		Split: "vertical",
+		Panes: []ChatLayoutPane{{}, {Thread: "thrd-abc"}, {Pseudo: "open-qs"}},
+		Focus: 1,
+	}
+	if err := WriteChatLayout("/proj", "adam", layout); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := WriteChatLayout("/other", "adam", &ChatLayout{Split: "horizontal"}); err != nil {
+		t.Fatalf("write other: %v", err)
+	}
+
+	got, err := ReadChatLayout("/proj", "adam")
+	if err != nil || got == nil {
+		t.Fatalf("read: %v %v", got, err)
+	}
+	if got.Split != "vertical" || got.Focus != 1 || len(got.Panes) != 3 || got.Panes[1].Thread != "thrd-abc" {
+		t.Fatalf("unexpected layout: %+v", got)
+	}
+	if other, _ := ReadChatLayout("/proj", "bob"); other != nil {
+		t.Fatalf("expected no layout for another user")
+	}
+
+	if err := WriteChatLayout("/proj", "adam", nil); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if cleared, _ := ReadChatLayout("/proj", "adam"); cleared != nil {
+		t.Fatalf("expected layout cleared, got %+v", cleared)
+	}
+	if kept, _ := ReadChatLayout("/other", "adam"); kept == nil {
+		t.Fatalf("expected other project's layout kept")
+	}
+}
+
+func TestChatDraftsRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := WriteChatDrafts("/proj", "adam", map[string]string{"room": "half a thought", "thrd-abc": "  "}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	drafts, err := ReadChatDrafts("/proj", "adam")
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(drafts) != 1 || drafts["room"] != "half a thought" {
+		t.Fatalf("expected only the non-blank draft, got %v", drafts)
+	}
+	if other, _ := ReadChatDrafts("/proj", "bob"); len(other) != 0 {
+		t.Fatalf("expected no drafts for another user")
+	}
+
+	if err := WriteChatDrafts("/proj", "adam", nil); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if cleared, _ := ReadChatDrafts("/proj", "adam"); len(cleared) != 0 {
+		t.Fatalf("expected drafts cleared, got %v", cleared)
+	}
+}
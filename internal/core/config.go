@@ -27,6 +27,15 @@ func globalConfigPath() (string, error) {
 	return filepath.Join(configDir, "fray-config.json"), nil
 }
 
+// GlobalConfigDir returns the directory holding the global config.
+func GlobalConfigDir() (string, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
 func ensureConfigDir() (string, error) {
 	path, err := globalConfigPath()
 	if err != nil {
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// Agent signing keys live outside any repo, in ~/.config/fray/keys/<agent>.key,
+// so checking out a project never hands out the ability to post as its agents.
+
+// AgentKeyPath returns where an agent's private key is stored.
+func AgentKeyPath(agentID string) (string, error) {
+	if !IsValidAgentID(agentID) {
+		return "", fmt.Errorf("invalid agent name: %s", agentID)
+	}
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "keys", agentID+".key"), nil
+}
+
+// LoadAgentKey returns the local private key for an agent, or nil when this
+// machine has none.
+func LoadAgentKey(agentID string) (ed25519.PrivateKey, error) {
+	if !IsValidAgentID(agentID) {
+		return nil, nil
+	}
+	path, err := AgentKeyPath(agentID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("malformed key file %s", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// GenerateAgentKey creates and stores a new private key for an agent,
+// replacing any existing one.
+func GenerateAgentKey(agentID string) (ed25519.PrivateKey, error) {
+	path, err := AgentKeyPath(agentID)
+	if err != nil {
+		return nil, err
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(key.Seed()) + "\n"
+	if err := os.WriteFile(path, []byte(encoded), 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncodePublicKey renders a public key as stored in fray-config.json.
+func EncodePublicKey(key ed25519.PublicKey) string {
+	return "ed25519:" + base64.StdEncoding.EncodeToString(key)
+}
+
+// ParsePublicKey reads a key written by EncodePublicKey.
+func ParsePublicKey(value string) (ed25519.PublicKey, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(value), "ed25519:")
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key: %s", value)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("malformed public key: %s", value)
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// messageSigningPayload is what a message signature covers: who posted which
+// message with what body. Edits are signed over the same fields with the new
+// body.
+func messageSigningPayload(messageID, fromAgent, body string) []byte {
+	return []byte("fray-message-v1\n" + messageID + "\n" + fromAgent + "\n" + body)
+}
+
+// PublicKeyID is the short ID a signature uses to name its signing key.
+func PublicKeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SignMessage signs a message body with the author's private key. The
+// signature is "<key id>:<base64>", so it can still be checked against the
+// right key after the author rotates keys.
+func SignMessage(key ed25519.PrivateKey, messageID, fromAgent, body string) string {
+	signature := ed25519.Sign(key, messageSigningPayload(messageID, fromAgent, body))
+	return PublicKeyID(key.Public().(ed25519.PublicKey)) + ":" + base64.StdEncoding.EncodeToString(signature)
+}
+
+// VerifyMessage checks a message signature against the author's registered
+// public keys, current and retired (none when the author has no key). A
+// signature naming a key that isn't registered is unverified; signatures
+// without a key ID are checked against every key.
+func VerifyMessage(publicKeys []ed25519.PublicKey, messageID, fromAgent, body string, signature *string) types.SignatureStatus {
+	if signature == nil || *signature == "" {
+		if len(publicKeys) == 0 {
+			return ""
+		}
+		return types.SignatureUnverified
+	}
+	candidates := publicKeys
+	encoded := *signature
+	if keyID, value, ok := strings.Cut(*signature, ":"); ok {
+		encoded = value
+		candidates = nil
+		for _, key := range publicKeys {
+			if PublicKeyID(key) == keyID {
+				candidates = append(candidates, key)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return types.SignatureUnverified
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return types.SignatureInvalid
+	}
+	payload := messageSigningPayload(messageID, fromAgent, body)
+	for _, key := range candidates {
+		if ed25519.Verify(key, payload, raw) {
+			return types.SignatureVerified
+		}
+	}
+	return types.SignatureInvalid
+}
+
+// SignatureLabel is the short marker shown next to a message's ID.
+func SignatureLabel(status types.SignatureStatus) string {
+	switch status {
+	case types.SignatureVerified:
+		return "✓ verified"
+	case types.SignatureUnverified:
+		return "? unverified"
+	case types.SignatureInvalid:
+		return "✗ invalid signature"
+	default:
+		return ""
+	}
+}
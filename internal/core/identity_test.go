@@ -0,0 +1,70 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+func TestAgentKeySignAndVerify(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if key, err := LoadAgentKey("alice"); err != nil || key != nil {
+		t.Fatalf("expected no key yet, got %v (err %v)", key, err)
+	}
+	key, err := GenerateAgentKey("alice")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	loaded, err := LoadAgentKey("alice")
+	if err != nil || !loaded.Equal(key) {
+		t.Fatalf("expected stored key to load back (err %v)", err)
+	}
+
+	publicKey, err := ParsePublicKey(EncodePublicKey(key.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+	signature := SignMessage(key, "msg-abc", "alice", "hello")
+	if !strings.HasPrefix(signature, PublicKeyID(publicKey)+":") {
+		t.Fatalf("expected signature to name its key, got %s", signature)
+	}
+	_, legacy, _ := strings.Cut(signature, ":")
+
+	rotated, err := GenerateAgentKey("alice")
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	rotatedPublic := rotated.Public().(ed25519.PublicKey)
+	history := []ed25519.PublicKey{rotatedPublic, publicKey}
+
+	cases := []struct {
+		name      string
+		keys      []ed25519.PublicKey
+		from      string
+		body      string
+		signature *string
+		want      types.SignatureStatus
+	}{
+		{"verified", []ed25519.PublicKey{publicKey}, "alice", "hello", &signature, types.SignatureVerified},
+		{"signed before rotation", history, "alice", "hello", &signature, types.SignatureVerified},
+		{"signing key not registered", []ed25519.PublicKey{rotatedPublic}, "alice", "hello", &signature, types.SignatureUnverified},
+		{"no key id", history, "alice", "hello", &legacy, types.SignatureVerified},
+		{"body changed", history, "alice", "goodbye", &signature, types.SignatureInvalid},
+		{"author rewritten", []ed25519.PublicKey{publicKey}, "bob", "hello", &signature, types.SignatureInvalid},
+		{"unsigned from keyed agent", []ed25519.PublicKey{publicKey}, "alice", "hello", nil, types.SignatureUnverified},
+		{"signed without registered key", nil, "alice", "hello", &signature, types.SignatureUnverified},
+		{"no key, no signature", nil, "alice", "hello", nil, ""},
+	}
+	for _, tc := range cases {
+		if got := VerifyMessage(tc.keys, "msg-abc", tc.from, tc.body, tc.signature); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	if key, err := LoadAgentKey("web:alice"); err != nil || key != nil {
+		t.Fatalf("expected qualified names to have no key, got %v (err %v)", key, err)
+	}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Issue is an external tracker issue resolved from a claim or reference.
+type Issue struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Provider string `json:"provider"`
+}
+
+// IsClosed reports whether the issue is finished in its tracker.
+func (i Issue) IsClosed() bool {
+	switch strings.ToLower(i.Status) {
+	case "closed", "done", "resolved":
+		return true
+	}
+	return false
+}
+
+// IssueProvider resolves issue IDs against an external tracker.
+type IssueProvider interface {
+	// Name matches the claim type handled by the provider (e.g. "bd").
+	Name() string
+	// LookupIssue returns nil without error when the issue is unknown.
+	LookupIssue(id string) (*Issue, error)
+}
+
+// IssueProviders returns the issue providers available for a project.
+func IssueProviders(projectRoot string) []IssueProvider {
+	var providers []IssueProvider
+	if beads := NewBeadsProvider(projectRoot); beads.Available() {
+		providers = append(providers, beads)
+	}
+	return providers
+}
+
+// FindIssueProvider returns the provider with the given name, or nil.
+func FindIssueProvider(providers []IssueProvider, name string) IssueProvider {
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider
+		}
+	}
+	return nil
+}
+
+// LookupIssue asks each provider in turn and returns the first match.
+func LookupIssue(providers []IssueProvider, id string) *Issue {
+	id = strings.TrimPrefix(strings.TrimSpace(id), "#")
+	if id == "" {
+		return nil
+	}
+	for _, provider := range providers {
+		issue, err := provider.LookupIssue(id)
+		if err == nil && issue != nil {
+			return issue
+		}
+	}
+	return nil
+}
+
+// BeadsProvider reads issues from the local beads store (.beads/issues.jsonl).
+// The file is re-read whenever its modification time changes.
+type BeadsProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	issues  map[string]Issue
+}
+
+type beadsIssueRecord struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// NewBeadsProvider creates a provider for the beads store under projectRoot.
+func NewBeadsProvider(projectRoot string) *BeadsProvider {
+	return &BeadsProvider{path: filepath.Join(projectRoot, ".beads", "issues.jsonl")}
+}
+
+// Name returns the claim type handled by beads.
+func (p *BeadsProvider) Name() string {
+	return "bd"
+}
+
+// Available reports whether the beads store exists.
+func (p *BeadsProvider) Available() bool {
+	_, err := os.Stat(p.path)
+	return err == nil
+}
+
+// LookupIssue returns the beads issue with the given ID.
+func (p *BeadsProvider) LookupIssue(id string) (*Issue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	issue, ok := p.issues[strings.ToLower(id)]
+	if !ok {
+		return nil, nil
+	}
+	return &issue, nil
+}
+
+func (p *BeadsProvider) refresh() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			p.issues = map[string]Issue{}
+			return nil
+		}
+		return err
+	}
+	if p.issues != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+
+	file, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	issues := map[string]Issue{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record beadsIssueRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record.ID == "" {
+			continue
+		}
+		// Later lines win so appended updates override earlier state.
+		issues[strings.ToLower(record.ID)] = Issue{
+			ID:       record.ID,
+			Title:    record.Title,
+			Status:   record.Status,
+			Provider: p.Name(),
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.issues = issues
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	return nil
+}
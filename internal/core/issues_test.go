@@ -0,0 +1,61 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBeadsProviderLookup(t *testing.T) {
+	root := t.TempDir()
+	beadsDir := filepath.Join(root, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	path := filepath.Join(beadsDir, "issues.jsonl")
+	data := `{"id":"fray-001","title":"First","status":"open"}
+not json
+{"id":"fray-002","title":"Second","status":"in_progress"}
+{"id":"fray-001","title":"First","status":"closed"}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	providers := IssueProviders(root)
+	if len(providers) != 1 || providers[0].Name() != "bd" {
+		t.Fatalf("expected beads provider, got %#v", providers)
+	}
+
+	issue := LookupIssue(providers, "#FRAY-001")
+	if issue == nil || issue.Title != "First" || !issue.IsClosed() {
+		t.Fatalf("expected closed fray-001, got %#v", issue)
+	}
+	issue = LookupIssue(providers, "fray-002")
+	if issue == nil || issue.IsClosed() {
+		t.Fatalf("expected open fray-002, got %#v", issue)
+	}
+	if issue := LookupIssue(providers, "fray-999"); issue != nil {
+		t.Fatalf("expected unknown issue, got %#v", issue)
+	}
+
+	// Appended updates are picked up without recreating the provider.
+	later := time.Now().Add(2 * time.Second)
+	appendData := data + `{"id":"fray-002","title":"Second","status":"closed"}` + "\n"
+	if err := os.WriteFile(path, []byte(appendData), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if issue := LookupIssue(providers, "fray-002"); issue == nil || !issue.IsClosed() {
+		t.Fatalf("expected reloaded fray-002 to be closed, got %#v", issue)
+	}
+}
+
+func TestIssueProvidersWithoutBeads(t *testing.T) {
+	if providers := IssueProviders(t.TempDir()); len(providers) != 0 {
+		t.Fatalf("expected no providers, got %#v", providers)
+	}
+}
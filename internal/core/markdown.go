@@ -0,0 +1,346 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// MarkdownOptions controls RenderMarkdown.
+type MarkdownOptions struct {
+	Width int  // wrap width in cells (0 = no wrapping)
+	Color bool // emit ANSI attributes; when false, emphasis markers are left as written
+}
+
+// ANSI attributes only (no colors or full resets), so rendered markdown keeps
+// whatever foreground color the caller wraps it in.
+const (
+	sgrBold         = "\x1b[1m"
+	sgrFaint        = "\x1b[2m"
+	sgrNormal       = "\x1b[22m" // ends bold and faint
+	sgrItalic       = "\x1b[3m"
+	sgrItalicOff    = "\x1b[23m"
+	sgrUnderline    = "\x1b[4m"
+	sgrUnderlineOff = "\x1b[24m"
+	sgrStrike       = "\x1b[9m"
+	sgrStrikeOff    = "\x1b[29m"
+)
+
+const markdownRuleWidth = 40
+
+var (
+	mdHeadingRe    = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	mdRuleRe       = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdListRe       = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mdTaskRe       = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdQuoteRe      = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdTableSepRe   = regexp.MustCompile(`^\s*:?-+:?\s*$`)
+	mdLinkRe       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdAutolinkRe   = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	mdBoldStarRe   = regexp.MustCompile(`\*\*([^*\s](?:[^*]*[^*\s])?)\*\*`)
+	mdBoldUnderRe  = regexp.MustCompile(`(^|[^\p{L}\p{N}_])__([^_\s](?:[^_]*[^_\s])?)__($|[^\p{L}\p{N}_])`)
+	mdStrikeRe     = regexp.MustCompile(`~~([^~\s](?:[^~]*[^~\s])?)~~`)
+	mdItalicStarRe = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	mdItalicUndRe  = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\s](?:[^_]*[^_\s])?)_($|[^\p{L}\p{N}_])`)
+)
+
+// RenderMarkdown renders a message body for the terminal: headings, emphasis,
+// lists, tables, links, blockquotes and rules. Fenced code blocks are passed
+// through verbatim (callers highlight them). Line breaks are preserved, since
+// messages are usually written line by line.
+func RenderMarkdown(body string, opts MarkdownOptions) string {
+	if body == "" {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if fence, ok := markdownFence(line); ok {
+			end := i + 1
+			for end < len(lines) && !isMarkdownClosingFence(lines[end], fence) {
+				end++
+			}
+			if end >= len(lines) {
+				end = len(lines) - 1
+			}
+			out = append(out, lines[i:end+1]...)
+			i = end
+			continue
+		}
+		if i+1 < len(lines) && strings.Contains(line, "|") && isTableSeparator(lines[i+1]) {
+			end := i + 2
+			for end < len(lines) && strings.Contains(lines[end], "|") && strings.TrimSpace(lines[end]) != "" {
+				end++
+			}
+			out = append(out, renderMarkdownTable(lines[i:end], opts)...)
+			i = end - 1
+			continue
+		}
+		out = append(out, renderMarkdownLine(line, opts.Width, opts)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+func renderMarkdownLine(line string, width int, opts MarkdownOptions) []string {
+	if strings.TrimSpace(line) == "" {
+		return []string{line}
+	}
+	if mdRuleRe.MatchString(line) {
+		ruleWidth := width
+		if ruleWidth <= 0 || ruleWidth > markdownRuleWidth {
+			ruleWidth = markdownRuleWidth
+		}
+		return []string{markdownAttr(strings.Repeat("─", ruleWidth), sgrFaint, sgrNormal, opts)}
+	}
+	if match := mdHeadingRe.FindStringSubmatch(line); match != nil {
+		if !opts.Color {
+			return wrapMarkdown(renderMarkdownInline(line, opts), width, "")
+		}
+		text := renderMarkdownInline(match[2], opts)
+		text = sgrBold + text + sgrNormal
+		if len(match[1]) == 1 {
+			text = sgrUnderline + text + sgrUnderlineOff
+		}
+		return wrapMarkdown(text, width, "")
+	}
+	if match := mdQuoteRe.FindStringSubmatch(line); match != nil {
+		bar := markdownAttr("│", sgrFaint, sgrNormal, opts) + " "
+		innerWidth := width
+		if innerWidth > 0 {
+			innerWidth = max(innerWidth-2, 1)
+		}
+		inner := renderMarkdownLine(match[1], innerWidth, opts)
+		for i := range inner {
+			inner[i] = bar + inner[i]
+		}
+		return inner
+	}
+	if match := mdListRe.FindStringSubmatch(line); match != nil {
+		indent := strings.Repeat("  ", len(strings.ReplaceAll(match[1], "\t", "  "))/2)
+		marker := match[2]
+		content := match[3]
+		if marker == "-" || marker == "*" || marker == "+" {
+			marker = "•"
+			if task := mdTaskRe.FindStringSubmatch(content); task != nil {
+				marker = "☐"
+				if task[1] != " " {
+					marker = "☑"
+				}
+				content = task[2]
+			}
+		}
+		prefix := indent + marker + " "
+		return wrapMarkdown(renderMarkdownInline(content, opts), width, prefix)
+	}
+	return wrapMarkdown(renderMarkdownInline(line, opts), width, "")
+}
+
+// wrapMarkdown wraps text to width after prefix, indenting continuation lines
+// to line up with the text.
+func wrapMarkdown(text string, width int, prefix string) []string {
+	prefixWidth := ansi.StringWidth(prefix)
+	if width <= 0 || width-prefixWidth < 1 {
+		return []string{prefix + text}
+	}
+	wrapped := strings.Split(ansi.Wrap(text, width-prefixWidth, ""), "\n")
+	indent := strings.Repeat(" ", prefixWidth)
+	for i := range wrapped {
+		if i == 0 {
+			wrapped[i] = prefix + wrapped[i]
+		} else {
+			wrapped[i] = indent + wrapped[i]
+		}
+	}
+	return wrapped
+}
+
+// renderMarkdownInline renders links and emphasis outside of code spans.
+func renderMarkdownInline(text string, opts MarkdownOptions) string {
+	parts := strings.Split(text, "`")
+	if len(parts)%2 == 0 {
+		// Unbalanced backticks: treat the trailing one literally.
+		parts[len(parts)-2] += "`" + parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = renderMarkdownSpan(parts[i], opts)
+	}
+	return strings.Join(parts, "`")
+}
+
+func renderMarkdownSpan(text string, opts MarkdownOptions) string {
+	text = mdLinkRe.ReplaceAllStringFunc(text, func(match string) string {
+		sub := mdLinkRe.FindStringSubmatch(match)
+		label, url := sub[1], sub[2]
+		if label == url {
+			return markdownAttr(url, sgrUnderline, sgrUnderlineOff, opts)
+		}
+		return markdownAttr(label, sgrUnderline, sgrUnderlineOff, opts) + markdownAttr(" ("+url+")", sgrFaint, sgrNormal, opts)
+	})
+	text = mdAutolinkRe.ReplaceAllStringFunc(text, func(match string) string {
+		return markdownAttr(mdAutolinkRe.FindStringSubmatch(match)[1], sgrUnderline, sgrUnderlineOff, opts)
+	})
+	if !opts.Color {
+		return text
+	}
+	text = mdBoldStarRe.ReplaceAllString(text, sgrBold+"$1"+sgrNormal)
+	text = mdBoldUnderRe.ReplaceAllString(text, "${1}"+sgrBold+"${2}"+sgrNormal+"${3}")
+	text = mdStrikeRe.ReplaceAllString(text, sgrStrike+"$1"+sgrStrikeOff)
+	text = mdItalicStarRe.ReplaceAllString(text, sgrItalic+"$1"+sgrItalicOff)
+	text = mdItalicUndRe.ReplaceAllString(text, "${1}"+sgrItalic+"${2}"+sgrItalicOff+"${3}")
+	return text
+}
+
+func markdownAttr(text, on, off string, opts MarkdownOptions) string {
+	if !opts.Color {
+		return text
+	}
+	return on + text + off
+}
+
+type tableAlign int
+
+const (
+	alignLeft tableAlign = iota
+	alignCenter
+	alignRight
+)
+
+func isTableSeparator(line string) bool {
+	if !strings.Contains(line, "-") {
+		return false
+	}
+	cells := splitTableRow(line)
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		if !mdTableSepRe.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitTableRow(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	trimmed = strings.TrimSuffix(trimmed, "|")
+	cells := strings.Split(trimmed, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// renderMarkdownTable aligns a pipe table (header, separator, rows), shrinking
+// the widest columns when it would overflow width.
+func renderMarkdownTable(lines []string, opts MarkdownOptions) []string {
+	separator := splitTableRow(lines[1])
+	aligns := make([]tableAlign, len(separator))
+	for i, cell := range separator {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			aligns[i] = alignCenter
+		case strings.HasSuffix(cell, ":"):
+			aligns[i] = alignRight
+		}
+	}
+
+	rows := [][]string{splitTableRow(lines[0])}
+	for _, line := range lines[2:] {
+		rows = append(rows, splitTableRow(line))
+	}
+	columns := len(aligns)
+	widths := make([]int, columns)
+	for r, row := range rows {
+		cells := make([]string, columns)
+		for c := 0; c < columns && c < len(row); c++ {
+			cells[c] = renderMarkdownInline(row[c], opts)
+			widths[c] = max(widths[c], ansi.StringWidth(cells[c]))
+		}
+		rows[r] = cells
+	}
+
+	if opts.Width > 0 {
+		total := func() int {
+			sum := 3 * (columns - 1)
+			for _, w := range widths {
+				sum += w
+			}
+			return sum
+		}
+		for total() > opts.Width {
+			widest := 0
+			for c := range widths {
+				if widths[c] > widths[widest] {
+					widest = c
+				}
+			}
+			if widths[widest] <= 3 {
+				break
+			}
+			widths[widest]--
+		}
+	}
+
+	divider := markdownAttr(" │ ", sgrFaint, sgrNormal, opts)
+	out := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		cells := make([]string, columns)
+		for c := range row {
+			cell := ansi.Truncate(row[c], widths[c], "…")
+			cells[c] = padTableCell(cell, widths[c], aligns[c])
+			if r == 0 {
+				cells[c] = markdownAttr(cells[c], sgrBold, sgrNormal, opts)
+			}
+		}
+		out = append(out, strings.TrimRight(strings.Join(cells, divider), " "))
+		if r == 0 {
+			rules := make([]string, columns)
+			for c := range rules {
+				rules[c] = strings.Repeat("─", widths[c])
+			}
+			out = append(out, markdownAttr(strings.Join(rules, "─┼─"), sgrFaint, sgrNormal, opts))
+		}
+	}
+	return out
+}
+
+func padTableCell(cell string, width int, align tableAlign) string {
+	gap := width - ansi.StringWidth(cell)
+	if gap <= 0 {
+		return cell
+	}
+	switch align {
+	case alignRight:
+		return strings.Repeat(" ", gap) + cell
+	case alignCenter:
+		return strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+	default:
+		return cell + strings.Repeat(" ", gap)
+	}
+}
+
+// markdownFence returns the fence marker when line opens a fenced code block.
+func markdownFence(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", false
+	}
+	count := 0
+	for count < len(trimmed) && trimmed[count] == trimmed[0] {
+		count++
+	}
+	if count < 3 {
+		return "", false
+	}
+	return trimmed[:count], true
+}
+
+func isMarkdownClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
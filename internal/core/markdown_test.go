@@ -0,0 +1,81 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown_Structure(t *testing.T) {
+	body := strings.Join([]string{
+		"- first",
+		"  - nested",
+		"- [x] done",
+		"> quoted",
+		"see [docs](https://example.com/docs)",
+		"---",
+	}, "\n")
+
+	got := RenderMarkdown(body, MarkdownOptions{})
+	want := strings.Join([]string{
+		"• first",
+		"  • nested",
+		"☑ done",
+		"│ quoted",
+		"see docs (https://example.com/docs)",
+		strings.Repeat("─", markdownRuleWidth),
+	}, "\n")
+	if got != want {
+		t.Fatalf("unexpected render:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdown_Table(t *testing.T) {
+	body := "| name | count |\n|------|------:|\n| a | 1 |\n| longer | 20 |"
+	got := RenderMarkdown(body, MarkdownOptions{})
+	want := strings.Join([]string{
+		"name   │ count",
+		"───────┼──────",
+		"a      │     1",
+		"longer │    20",
+	}, "\n")
+	if got != want {
+		t.Fatalf("unexpected table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdown_CodeUntouched(t *testing.T) {
+	body := "```go\n- not a list **x**\n```\nuse `**literal**` here"
+	got := RenderMarkdown(body, MarkdownOptions{Color: true})
+	if !strings.HasPrefix(got, "```go\n- not a list **x**\n```\n") {
+		t.Fatalf("expected fenced block verbatim, got %q", got)
+	}
+	if !strings.Contains(got, "`**literal**`") {
+		t.Fatalf("expected code span verbatim, got %q", got)
+	}
+}
+
+func TestRenderMarkdown_Emphasis(t *testing.T) {
+	got := RenderMarkdown("# Plan\n**bold** and *italic*, snake_case_name", MarkdownOptions{Color: true})
+	if strings.Contains(got, "# Plan") || !strings.Contains(got, sgrBold+"Plan"+sgrNormal) {
+		t.Fatalf("expected styled heading, got %q", got)
+	}
+	if !strings.Contains(got, sgrBold+"bold"+sgrNormal) || !strings.Contains(got, sgrItalic+"italic"+sgrItalicOff) {
+		t.Fatalf("expected emphasis, got %q", got)
+	}
+	if !strings.Contains(got, "snake_case_name") {
+		t.Fatalf("expected intraword underscores untouched, got %q", got)
+	}
+
+	plain := RenderMarkdown("**bold**", MarkdownOptions{})
+	if plain != "**bold**" {
+		t.Fatalf("expected markers kept without color, got %q", plain)
+	}
+}
+
+func TestRenderMarkdown_WrapsListItems(t *testing.T) {
+	got := RenderMarkdown("- one two three four", MarkdownOptions{Width: 10})
+	want := "• one two\n  three\n  four"
+	if got != want {
+		t.Fatalf("unexpected wrap: %q", got)
+	}
+}
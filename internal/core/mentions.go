@@ -7,10 +7,18 @@ import (
 )
 
 var (
-	mentionRe  = regexp.MustCompile(`@([a-z][a-z0-9]*(?:[-\.][a-z0-9]+)*)`)
-	issueRefRe = regexp.MustCompile(`@([a-z]+-[a-zA-Z0-9]+)`)
+	mentionRe        = regexp.MustCompile(`@([a-z][a-z0-9]*(?:[-\.][a-z0-9]+)*)`)
+	issueRefRe       = regexp.MustCompile(`@([a-z]+-[a-zA-Z0-9]+)`)
+	messageTrailerRe = regexp.MustCompile(`(?i)\bfray:\s*#?(msg-[a-z0-9]+)`)
+	channelMentionRe = regexp.MustCompile(`@([A-Za-z][A-Za-z0-9]*(?:[-_\.][A-Za-z0-9]+)*):([a-z][a-z0-9]*(?:[-\.][a-z0-9]+)*)`)
 )
 
+// ChannelMention is an @channel:agent mention of an agent in another channel.
+type ChannelMention struct {
+	Channel string
+	Agent   string
+}
+
 // ExtractMentions returns mention targets without @ prefix.
 func ExtractMentions(body string, agentBases map[string]struct{}) []string {
 	matches := mentionRe.FindAllStringSubmatchIndex(body, -1)
@@ -71,6 +79,54 @@ func ExtractIssueRefs(body string) []string {
 	return refs
 }
 
+// ExtractMessageTrailers finds fray:msg-xxxx references (e.g. commit trailers),
+// in order of appearance and without duplicates.
+func ExtractMessageTrailers(body string) []string {
+	matches := messageTrailerRe.FindAllStringSubmatch(body, -1)
+	seen := map[string]struct{}{}
+	refs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if len(match) < 2 {
+			continue
+		}
+		ref := lower(match[1])
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// ExtractChannelMentions returns @channel:agent mentions in order of
+// appearance, without duplicates. Only mentions of the given channels (names
+// or IDs, matched case-insensitively) count, so text like @host:8080 or
+// @db:postgres is not taken for a mention.
+func ExtractChannelMentions(body string, channels []string) []ChannelMention {
+	matches := channelMentionRe.FindAllStringSubmatchIndex(body, -1)
+	seen := map[ChannelMention]struct{}{}
+	var mentions []ChannelMention
+	for _, match := range matches {
+		if match[0] > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(body[:match[0]])
+			if isAlphaNum(prev) {
+				continue
+			}
+		}
+		mention := ChannelMention{Channel: body[match[2]:match[3]], Agent: body[match[4]:match[5]]}
+		if !containsFold(channels, mention.Channel) {
+			continue
+		}
+		if _, ok := seen[mention]; ok {
+			continue
+		}
+		seen[mention] = struct{}{}
+		mentions = append(mentions, mention)
+	}
+	return mentions
+}
+
 // MatchesMention reports whether a mention matches an agent ID.
 func MatchesMention(agentID, mentionPrefix string) bool {
 	return MatchesPrefix(agentID, mentionPrefix)
@@ -130,6 +186,15 @@ func containsDot(value string) bool {
 	return false
 }
 
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if lower(candidate) == lower(value) {
+			return true
+		}
+	}
+	return false
+}
+
 func lower(value string) string {
 	buf := []rune(value)
 	for i, r := range buf {
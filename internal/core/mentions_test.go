@@ -19,6 +19,44 @@ func TestExtractMentionsWithBases(t *testing.T) {
 	assertMention(t, mentions, "all")
 }
 
+func TestExtractMessageTrailers(t *testing.T) {
+	body := "Fix auth refresh\n\nfray:msg-ab12cd34 follow-up\nFray: #MSG-ef56\nfray:msg-ab12cd34"
+	refs := ExtractMessageTrailers(body)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 refs, got %#v", refs)
+	}
+	if refs[0] != "msg-ab12cd34" || refs[1] != "msg-ef56" {
+		t.Fatalf("unexpected refs: %#v", refs)
+	}
+	if refs := ExtractMessageTrailers("no refs here, msg-ab12 alone"); len(refs) != 0 {
+		t.Fatalf("expected no refs, got %#v", refs)
+	}
+}
+
+func TestExtractChannelMentions(t *testing.T) {
+	body := "@backend:bob can you check? cc @Web-App:alice.2, @backend:bob again; @alice: not one, x@backend:bob neither"
+	mentions := ExtractChannelMentions(body, []string{"backend", "web-app"})
+	want := []ChannelMention{{Channel: "backend", Agent: "bob"}, {Channel: "Web-App", Agent: "alice.2"}}
+	if len(mentions) != len(want) {
+		t.Fatalf("expected %v, got %v", want, mentions)
+	}
+	for i := range want {
+		if mentions[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, mentions)
+		}
+	}
+}
+
+func TestExtractChannelMentionsIgnoresUnknownChannels(t *testing.T) {
+	body := "served on @host:8080, try @localhost:dev or @db:postgres"
+	if mentions := ExtractChannelMentions(body, []string{"backend"}); len(mentions) != 0 {
+		t.Fatalf("expected no channel mentions, got %v", mentions)
+	}
+	if mentions := ExtractChannelMentions(body, []string{"db"}); len(mentions) != 1 || mentions[0].Channel != "db" {
+		t.Fatalf("expected only the configured channel, got %v", mentions)
+	}
+}
+
 func assertMention(t *testing.T, mentions []string, value string) {
 	t.Helper()
 	for _, mention := range mentions {
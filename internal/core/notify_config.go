@@ -0,0 +1,204 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Notification event kinds matched by NotifyRule.On.
+const (
+	NotifyMessage       = "message"        // any new message
+	NotifyMention       = "mention"        // message starting with @you
+	NotifyFYI           = "fyi"            // @you elsewhere in a message
+	NotifyReply         = "reply"          // reply to one of your messages
+	NotifyQuestion      = "question"       // question asked of you
+	NotifyClaimConflict = "claim_conflict" // claims check found another agent's claim
+	NotifyDaemonError   = "daemon_error"   // daemon failed to run a managed agent or scheduled retention
+)
+
+// Notification sink types.
+const (
+	NotifySinkOS      = "os"      // desktop notification
+	NotifySinkCommand = "command" // shell command, event JSON on stdin
+	NotifySinkWebhook = "webhook" // HTTP POST of event JSON
+)
+
+// NotifyConfig stores per-user notification rules (~/.config/fray/notify.json).
+type NotifyConfig struct {
+	Rules          []NotifyRule          `json:"rules"`
+	Sinks          map[string]NotifySink `json:"sinks,omitempty"`           // by name; "os" is always available
+	QuietHours     *QuietHours           `json:"quiet_hours,omitempty"`     // non-urgent notifications wait until quiet hours end
+	DigestInterval string                `json:"digest_interval,omitempty"` // how often digests are sent (default 15m)
+}
+
+// NotifyRule matches events and routes them to sinks. Every filter that is set
+// must match; within a filter any entry may match.
+type NotifyRule struct {
+	Name     string   `json:"name,omitempty"`
+	On       []string `json:"on,omitempty"`       // event kinds; empty matches all
+	Keywords []string `json:"keywords,omitempty"` // case-insensitive substrings of the body
+	From     []string `json:"from,omitempty"`     // agent IDs (prefix match, like mentions)
+	Threads  []string `json:"threads,omitempty"`  // thread paths (globs ok), GUIDs, or "room"
+	Sinks    []string `json:"sinks,omitempty"`    // default: ["os"]
+	Digest   bool     `json:"digest,omitempty"`   // batch into periodic digests
+	Urgent   bool     `json:"urgent,omitempty"`   // deliver during quiet hours
+	Ignore   bool     `json:"ignore,omitempty"`   // suppress matching events for later rules
+}
+
+// NotifySink configures where notifications go.
+type NotifySink struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command,omitempty"` // command sinks: run with sh -c
+	URL     string            `json:"url,omitempty"`     // webhook sinks
+	Headers map[string]string `json:"headers,omitempty"` // webhook sinks
+}
+
+// QuietHours is a daily local-time window, e.g. 22:00 to 08:00.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// DefaultNotifyConfig notifies on direct mentions and replies, which is what
+// chat does when no notify.json exists.
+func DefaultNotifyConfig() *NotifyConfig {
+	return &NotifyConfig{
+		Rules: []NotifyRule{{
+			Name: "mentions",
+			On:   []string{NotifyMention, NotifyReply},
+		}},
+	}
+}
+
+// NotifyConfigPath returns the notification config file path.
+func NotifyConfigPath() (string, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "notify.json"), nil
+}
+
+// ReadNotifyConfig reads and validates the notification config, returning the
+// default config when none exists.
+func ReadNotifyConfig() (*NotifyConfig, error) {
+	path, err := NotifyConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultNotifyConfig(), nil
+		}
+		return nil, err
+	}
+	var config NotifyConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks event kinds, sink references, quiet hours and the digest
+// interval.
+func (c *NotifyConfig) Validate() error {
+	for name, sink := range c.Sinks {
+		switch sink.Type {
+		case NotifySinkOS:
+		case NotifySinkCommand:
+			if sink.Command == "" {
+				return fmt.Errorf("sink %q: command is required", name)
+			}
+		case NotifySinkWebhook:
+			if sink.URL == "" {
+				return fmt.Errorf("sink %q: url is required", name)
+			}
+		default:
+			return fmt.Errorf("sink %q: unknown type %q (use os, command, or webhook)", name, sink.Type)
+		}
+	}
+	for i, rule := range c.Rules {
+		label := rule.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		for _, kind := range rule.On {
+			switch kind {
+			case NotifyMessage, NotifyMention, NotifyFYI, NotifyReply, NotifyQuestion, NotifyClaimConflict, NotifyDaemonError:
+			default:
+				return fmt.Errorf("rule %s: unknown event %q", label, kind)
+			}
+		}
+		for _, sink := range rule.Sinks {
+			if _, ok := c.Sinks[sink]; !ok && sink != NotifySinkOS {
+				return fmt.Errorf("rule %s: unknown sink %q", label, sink)
+			}
+		}
+		for _, pattern := range rule.Threads {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: bad thread pattern %q", label, pattern)
+			}
+		}
+	}
+	if c.QuietHours != nil {
+		if _, err := parseClockMinutes(c.QuietHours.Start); err != nil {
+			return fmt.Errorf("quiet_hours.start: %w", err)
+		}
+		if _, err := parseClockMinutes(c.QuietHours.End); err != nil {
+			return fmt.Errorf("quiet_hours.end: %w", err)
+		}
+	}
+	if _, err := c.Digest(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Digest returns the digest interval.
+func (c *NotifyConfig) Digest() (time.Duration, error) {
+	if c.DigestInterval == "" {
+		return 15 * time.Minute, nil
+	}
+	interval, err := time.ParseDuration(c.DigestInterval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("digest_interval: invalid duration %q", c.DigestInterval)
+	}
+	return interval, nil
+}
+
+// InQuietHours reports whether t falls inside the quiet hours window. Windows
+// that cross midnight (22:00 to 08:00) are supported.
+func (c *NotifyConfig) InQuietHours(t time.Time) bool {
+	if c.QuietHours == nil {
+		return false
+	}
+	start, err := parseClockMinutes(c.QuietHours.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClockMinutes(c.QuietHours.End)
+	if err != nil || start == end {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func parseClockMinutes(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
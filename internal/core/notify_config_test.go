@@ -0,0 +1,60 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadNotifyConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config, err := ReadNotifyConfig()
+	if err != nil {
+		t.Fatalf("read default: %v", err)
+	}
+	if len(config.Rules) != 1 || config.Rules[0].Name != "mentions" {
+		t.Fatalf("expected default mentions rule, got %+v", config.Rules)
+	}
+
+	path := filepath.Join(home, ".config", "fray", "notify.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(body string) {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"rules":[{"on":["mention"],"sinks":["slack"]}]}`)
+	if _, err := ReadNotifyConfig(); err == nil || !strings.Contains(err.Error(), `unknown sink "slack"`) {
+		t.Fatalf("expected unknown sink error, got %v", err)
+	}
+	write(`{"rules":[{"on":["shout"]}]}`)
+	if _, err := ReadNotifyConfig(); err == nil || !strings.Contains(err.Error(), `unknown event "shout"`) {
+		t.Fatalf("expected unknown event error, got %v", err)
+	}
+	write(`{"rules":[],"quiet_hours":{"start":"10pm","end":"07:00"}}`)
+	if _, err := ReadNotifyConfig(); err == nil || !strings.Contains(err.Error(), "quiet_hours.start") {
+		t.Fatalf("expected quiet hours error, got %v", err)
+	}
+
+	write(`{"rules":[{"on":["message"],"sinks":["slack"],"digest":true}],"sinks":{"slack":{"type":"webhook","url":"http://127.0.0.1:1"}},"quiet_hours":{"start":"22:00","end":"07:00"},"digest_interval":"5m"}`)
+	config, err = ReadNotifyConfig()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if interval, _ := config.Digest(); interval != 5*time.Minute {
+		t.Fatalf("expected 5m digest interval, got %s", interval)
+	}
+	for clock, want := range map[string]bool{"21:59": false, "22:00": true, "03:00": true, "06:59": true, "07:00": false} {
+		at, _ := time.Parse("15:04", clock)
+		if got := config.InQuietHours(at); got != want {
+			t.Errorf("InQuietHours(%s) = %v, want %v", clock, got, want)
+		}
+	}
+}
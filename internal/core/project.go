@@ -96,10 +96,11 @@ func InitProject(dir string, force bool) (Project, error) {
 	return Project{Root: root, DBPath: dbPath}, nil
 }
 
-// EnsureFrayGitignore ensures .fray/.gitignore contains sqlite ignores.
+// EnsureFrayGitignore ensures .fray/.gitignore contains sqlite and daemon
+// runtime ignores.
 func EnsureFrayGitignore(frayDir string) {
 	gitignore := filepath.Join(frayDir, ".gitignore")
-	entries := []string{"*.db", "*.db-wal", "*.db-shm"}
+	entries := []string{"*.db", "*.db-wal", "*.db-shm", "daemon.lock", "daemon.sock", "daemon.log", "scheduled.lock", "jsonl.lock"}
 
 	data, err := os.ReadFile(gitignore)
 	if err != nil {
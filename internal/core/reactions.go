@@ -66,3 +66,55 @@ func uniqueSorted(values []string) []string {
 	sort.Strings(out)
 	return out
 }
+
+// ReactionMeaning is the workflow effect a reaction carries.
+type ReactionMeaning string
+
+const (
+	// ReactionAnswer on a question's source message marks its questions answered.
+	ReactionAnswer ReactionMeaning = "answer"
+	// ReactionAck claims a message as "I'm on it" until the ack expires.
+	ReactionAck ReactionMeaning = "ack"
+	// ReactionBlock flags a message as blocked and wakes its author.
+	ReactionBlock ReactionMeaning = "block"
+	// ReactionNone disables a default meaning.
+	ReactionNone ReactionMeaning = "none"
+)
+
+// ReactionAckTTL is how long an ack reaction holds its claim.
+const ReactionAckTTL = 30 * 60
+
+// DefaultReactionMeanings maps reactions to meanings unless a project overrides them.
+var DefaultReactionMeanings = map[string]ReactionMeaning{
+	"✅": ReactionAnswer,
+	"👀": ReactionAck,
+	"🚫": ReactionBlock,
+}
+
+// ParseReactionMeaning validates a meaning name.
+func ParseReactionMeaning(value string) (ReactionMeaning, bool) {
+	switch meaning := ReactionMeaning(strings.ToLower(strings.TrimSpace(value))); meaning {
+	case ReactionAnswer, ReactionAck, ReactionBlock, ReactionNone:
+		return meaning, true
+	default:
+		return "", false
+	}
+}
+
+// ReactionMeanings applies project overrides to the defaults. Overrides of
+// "none" (or unknown meanings) remove the reaction from the table.
+func ReactionMeanings(overrides map[string]string) map[string]ReactionMeaning {
+	meanings := make(map[string]ReactionMeaning, len(DefaultReactionMeanings)+len(overrides))
+	for reaction, meaning := range DefaultReactionMeanings {
+		meanings[reaction] = meaning
+	}
+	for reaction, value := range overrides {
+		meaning, ok := ParseReactionMeaning(value)
+		if !ok || meaning == ReactionNone {
+			delete(meanings, reaction)
+			continue
+		}
+		meanings[reaction] = meaning
+	}
+	return meanings
+}
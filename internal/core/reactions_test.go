@@ -0,0 +1,24 @@
+package core
+
+import "testing"
+
+func TestReactionMeaningsOverrides(t *testing.T) {
+	meanings := ReactionMeanings(map[string]string{
+		"👀": "none",
+		"👍": "ACK",
+		"🤔": "bogus",
+	})
+
+	if meanings["✅"] != ReactionAnswer || meanings["🚫"] != ReactionBlock {
+		t.Fatalf("expected defaults to remain, got %v", meanings)
+	}
+	if _, ok := meanings["👀"]; ok {
+		t.Fatalf("expected none to disable the default ack")
+	}
+	if meanings["👍"] != ReactionAck {
+		t.Fatalf("expected 👍 to ack, got %q", meanings["👍"])
+	}
+	if _, ok := meanings["🤔"]; ok {
+		t.Fatalf("expected unknown meaning to be ignored")
+	}
+}
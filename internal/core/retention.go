@@ -0,0 +1,138 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Retention exemptions: messages a rule never prunes.
+const (
+	RetentionExemptPinned    = "pinned"
+	RetentionExemptFaved     = "faved"
+	RetentionExemptQuestions = "questions"
+)
+
+// RetentionConfig holds the project's retention rules, evaluated in order by
+// fray prune --policy. Every, if set, has fray daemon run the policy that often.
+type RetentionConfig struct {
+	Rules []RetentionRule `json:"rules"`
+	Every string          `json:"every,omitempty"`
+}
+
+// RetentionRule decides how long messages in matching scopes (the room or a
+// thread) are kept. A rule with no Thread or Type matches everything.
+type RetentionRule struct {
+	Thread  string   `json:"thread,omitempty"`  // path glob; "room" matches the room, "a/**" a subtree
+	Type    string   `json:"type,omitempty"`    // thread type: standard, knowledge, system
+	MaxAge  string   `json:"max_age,omitempty"` // prune messages older than this (30m, 2h, 7d, 2w)
+	Keep    int      `json:"keep,omitempty"`    // always keep the newest N per scope
+	Forever bool     `json:"forever,omitempty"` // never prune
+	Exempt  []string `json:"exempt,omitempty"`  // pinned, faved, questions; omitted = all three
+}
+
+// RetentionScope identifies the room or a thread for rule matching.
+type RetentionScope struct {
+	Path string // "room" or the thread path
+	Type string // thread type, empty for the room
+}
+
+// ParseAge parses "<n><unit>" durations where unit is m, h, d or w.
+func ParseAge(value string) (time.Duration, error) {
+	duration, ok := parseRelativeDuration(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid age %q: use 30m, 2h, 7d, or 2w", value)
+	}
+	return duration, nil
+}
+
+// Validate reports a rule that can't be evaluated.
+func (r RetentionRule) Validate() error {
+	if r.Thread != "" {
+		if _, err := path.Match(strings.TrimSuffix(r.Thread, "/**"), ""); err != nil {
+			return fmt.Errorf("invalid thread glob %q", r.Thread)
+		}
+	}
+	if r.MaxAge != "" {
+		if _, err := ParseAge(r.MaxAge); err != nil {
+			return err
+		}
+	}
+	if r.Keep < 0 {
+		return fmt.Errorf("invalid keep: %d", r.Keep)
+	}
+	if !r.Forever && r.MaxAge == "" && r.Keep == 0 {
+		return fmt.Errorf("rule needs max_age, keep, or forever")
+	}
+	for _, exempt := range r.Exempt {
+		switch exempt {
+		case RetentionExemptPinned, RetentionExemptFaved, RetentionExemptQuestions:
+		default:
+			return fmt.Errorf("unknown exemption %q (use pinned, faved, or questions)", exempt)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a scope.
+func (r RetentionRule) Matches(scope RetentionScope) bool {
+	if r.Type != "" && !strings.EqualFold(r.Type, scope.Type) {
+		return false
+	}
+	if r.Thread == "" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(r.Thread, "/**"); ok {
+		if scope.Path == prefix {
+			return true
+		}
+		parts := strings.Split(scope.Path, "/")
+		for i := 1; i < len(parts); i++ {
+			if matched, _ := path.Match(prefix, strings.Join(parts[:i], "/")); matched {
+				return true
+			}
+		}
+		return false
+	}
+	matched, _ := path.Match(r.Thread, scope.Path)
+	return matched
+}
+
+// Exempts reports whether the rule keeps messages with the given exemption.
+func (r RetentionRule) Exempts(exemption string) bool {
+	if r.Exempt == nil {
+		return true
+	}
+	for _, exempt := range r.Exempt {
+		if exempt == exemption {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe summarizes the rule's policy, e.g. "max_age 30d, keep 20".
+func (r RetentionRule) Describe() string {
+	if r.Forever {
+		return "forever"
+	}
+	var parts []string
+	if r.MaxAge != "" {
+		parts = append(parts, "max_age "+r.MaxAge)
+	}
+	if r.Keep > 0 {
+		parts = append(parts, fmt.Sprintf("keep %d", r.Keep))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MatchRetentionRule returns the index of the first rule matching scope, or -1.
+func MatchRetentionRule(rules []RetentionRule, scope RetentionScope) int {
+	for i, rule := range rules {
+		if rule.Matches(scope) {
+			return i
+		}
+	}
+	return -1
+}
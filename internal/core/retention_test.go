@@ -0,0 +1,52 @@
+package core
+
+import "testing"
+
+func TestMatchRetentionRule(t *testing.T) {
+	rules := []RetentionRule{
+		{Type: "knowledge", Forever: true},
+		{Thread: "opus/**", Keep: 50},
+		{Thread: "room", MaxAge: "30d"},
+		{Thread: "*", MaxAge: "7d"},
+	}
+
+	cases := []struct {
+		scope RetentionScope
+		want  int
+	}{
+		{RetentionScope{Path: "meta", Type: "knowledge"}, 0},
+		{RetentionScope{Path: "opus", Type: "standard"}, 1},
+		{RetentionScope{Path: "opus/notes/drafts", Type: "standard"}, 1},
+		{RetentionScope{Path: "room"}, 2},
+		{RetentionScope{Path: "design", Type: "standard"}, 3},
+		{RetentionScope{Path: "design/api", Type: "standard"}, -1},
+	}
+	for _, tc := range cases {
+		if got := MatchRetentionRule(rules, tc.scope); got != tc.want {
+			t.Errorf("%+v: got rule %d, want %d", tc.scope, got, tc.want)
+		}
+	}
+}
+
+func TestRetentionRuleValidateAndExempts(t *testing.T) {
+	if err := (RetentionRule{Thread: "*"}).Validate(); err == nil {
+		t.Fatalf("expected a rule without a policy to be invalid")
+	}
+	if err := (RetentionRule{MaxAge: "soon"}).Validate(); err == nil {
+		t.Fatalf("expected a bad max_age to be invalid")
+	}
+	if err := (RetentionRule{Keep: 5, Exempt: []string{"starred"}}).Validate(); err == nil {
+		t.Fatalf("expected an unknown exemption to be invalid")
+	}
+
+	if !(RetentionRule{}).Exempts(RetentionExemptFaved) {
+		t.Fatalf("expected omitted exemptions to default to all")
+	}
+	rule := RetentionRule{Exempt: []string{RetentionExemptPinned}}
+	if !rule.Exempts(RetentionExemptPinned) || rule.Exempts(RetentionExemptQuestions) {
+		t.Fatalf("unexpected exemptions for %v", rule.Exempt)
+	}
+	if (RetentionRule{Exempt: []string{}}).Exempts(RetentionExemptPinned) {
+		t.Fatalf("expected an empty list to exempt nothing")
+	}
+}
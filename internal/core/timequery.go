@@ -10,9 +10,19 @@ import (
 )
 
 func parseRelativeTime(value string) *time.Time {
+	duration, ok := parseRelativeDuration(value)
+	if !ok {
+		return nil
+	}
+	ts := time.Now().Add(-duration)
+	return &ts
+}
+
+// parseRelativeDuration parses "<n><unit>" where unit is m, h, d or w.
+func parseRelativeDuration(value string) (time.Duration, bool) {
 	value = strings.TrimSpace(value)
 	if value == "" {
-		return nil
+		return 0, false
 	}
 
 	unit := value[len(value)-1:]
@@ -28,21 +38,19 @@ func parseRelativeTime(value string) *time.Time {
 	case "w":
 		multiplier = 604800
 	default:
-		return nil
+		return 0, false
 	}
 	amount := int64(0)
 	for _, r := range amountStr {
 		if r < '0' || r > '9' {
-			return nil
+			return 0, false
 		}
 		amount = amount*10 + int64(r-'0')
 	}
 	if amount == 0 {
-		return nil
+		return 0, false
 	}
-
-	ts := time.Now().Add(-time.Duration(amount*multiplier) * time.Second)
-	return &ts
+	return time.Duration(amount*multiplier) * time.Second, true
 }
 
 func parseAbsoluteTime(value string) *time.Time {
@@ -190,3 +198,59 @@ func ParseTimeExpression(db *sql.DB, expression string, mode string) (*types.Mes
 
 	return nil, fmt.Errorf("invalid time expression: %s", expression)
 }
+
+var clockLayouts = []string{"15:04", "3:04pm", "3pm"}
+
+// ParseSendTime parses when a scheduled message should go out, relative to now.
+// Accepts durations ("30m", "in 2h", "+1d"), a clock time ("17:30", "9am";
+// tomorrow if already past), "tomorrow [time]" (09:00 by default), a local
+// date-time ("2006-01-02 15:04") or RFC3339. The result must be in the future.
+func ParseSendTime(expression string, now time.Time) (time.Time, error) {
+	value := strings.ToLower(strings.TrimSpace(expression))
+	value = strings.TrimPrefix(value, "in ")
+	value = strings.TrimSpace(strings.TrimPrefix(value, "+"))
+
+	var at time.Time
+	if duration, ok := parseRelativeDuration(value); ok {
+		at = now.Add(duration)
+	} else if rest, ok := strings.CutPrefix(value, "tomorrow"); ok {
+		hour, minute := 9, 0
+		if rest = strings.TrimSpace(rest); rest != "" {
+			clock, ok := parseClock(rest)
+			if !ok {
+				return time.Time{}, fmt.Errorf("invalid time: %s", expression)
+			}
+			hour, minute = clock.Hour(), clock.Minute()
+		}
+		next := now.AddDate(0, 0, 1)
+		at = time.Date(next.Year(), next.Month(), next.Day(), hour, minute, 0, 0, now.Location())
+	} else if clock, ok := parseClock(value); ok {
+		at = time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+	} else if parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(expression)); err == nil {
+		at = parsed
+	} else if parsed, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		at = parsed
+	} else if parsed, err := time.ParseInLocation("2006-01-02t15:04", value, now.Location()); err == nil {
+		at = parsed
+	} else {
+		return time.Time{}, fmt.Errorf("invalid time: %s (try 30m, 2h, 17:30, tomorrow 9am or 2006-01-02 15:04)", expression)
+	}
+
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("time is in the past: %s", expression)
+	}
+	return at, nil
+}
+
+func parseClock(value string) (time.Time, bool) {
+	value = strings.ReplaceAll(value, " ", "")
+	for _, layout := range clockLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
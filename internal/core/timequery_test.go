@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSendTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"30m":                  now.Add(30 * time.Minute),
+		"in 2h":                now.Add(2 * time.Hour),
+		"+1d":                  now.Add(24 * time.Hour),
+		"17:30":                time.Date(2026, 3, 10, 17, 30, 0, 0, time.UTC),
+		"9am":                  time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+		"tomorrow":             time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+		"tomorrow 3:15pm":      time.Date(2026, 3, 11, 15, 15, 0, 0, time.UTC),
+		"2026-03-12 08:00":     time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC),
+		"2026-03-12T08:00:00Z": time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC),
+	}
+	for expr, want := range cases {
+		got, err := ParseSendTime(expr, now)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("%q: expected %s, got %s", expr, want, got)
+		}
+	}
+
+	for _, expr := range []string{"", "soon", "2026-03-01 08:00", "tomorrow noonish"} {
+		if _, err := ParseSendTime(expr, now); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/notify"
 	"github.com/adamavenir/fray/internal/types"
 )
 
@@ -26,25 +29,38 @@ type Daemon struct {
 	detector     ActivityDetector
 	processes    map[string]*Process // agent_id -> process
 	handled      map[string]bool     // agent_id -> true if exit already handled
+	ending       map[string]bool     // agent_id -> true if ended on request (not a failure)
 	drivers      map[string]Driver   // driver name -> driver
 	stopCh       chan struct{}
 	cancelFunc   context.CancelFunc // cancels spawned process contexts
 	wg           sync.WaitGroup
 	lockPath     string
+	socketPath   string
+	listener     net.Listener
+	requests     chan sessionRequest // session requests, run on the watch loop
+	notifier     *notify.Notifier    // nil unless a notify rule listens for daemon errors
+	blockedSince int64               // reacted_at (ms) of the last blocking reaction handled
+	retention    retentionState
 	pollInterval time.Duration
 	debug        bool
+	session      string // agent_id when supervising a single session
 }
 
 // LockInfo represents the daemon lock file contents.
 type LockInfo struct {
-	PID       int   `json:"pid"`
-	StartedAt int64 `json:"started_at"`
+	PID       int    `json:"pid"`
+	StartedAt int64  `json:"started_at"`
+	Socket    string `json:"socket,omitempty"` // IPC socket for agent session commands
 }
 
 // Config holds daemon configuration options.
 type Config struct {
 	PollInterval time.Duration
 	Debug        bool
+	// Session limits the daemon to supervising this agent's session: no
+	// mention wakes, scheduled delivery, digests or retention, and requests
+	// for other agents are refused.
+	Session string
 }
 
 // DefaultConfig returns default daemon configuration.
@@ -60,6 +76,7 @@ func New(project core.Project, database *sql.DB, cfg Config) *Daemon {
 		cfg.PollInterval = DefaultConfig().PollInterval
 	}
 
+	frayDir := filepath.Dir(project.DBPath)
 	d := &Daemon{
 		project:      project,
 		database:     database,
@@ -67,13 +84,21 @@ func New(project core.Project, database *sql.DB, cfg Config) *Daemon {
 		detector:     NewActivityDetector(),
 		processes:    make(map[string]*Process),
 		handled:      make(map[string]bool),
+		ending:       make(map[string]bool),
 		drivers:      make(map[string]Driver),
 		stopCh:       make(chan struct{}),
-		lockPath:     filepath.Join(filepath.Dir(project.DBPath), "daemon.lock"),
+		lockPath:     filepath.Join(frayDir, "daemon.lock"),
+		socketPath:   socketPathFor(frayDir),
+		requests:     make(chan sessionRequest),
+		blockedSince: time.Now().UnixMilli(),
+		retention:    retentionState{ranAt: time.Now()},
 		pollInterval: cfg.PollInterval,
 		debug:        cfg.Debug,
+		session:      cfg.Session,
 	}
 
+	d.notifier = loadErrorNotifier(database)
+
 	// Register drivers
 	d.drivers["claude"] = &ClaudeDriver{}
 	d.drivers["codex"] = &CodexDriver{}
@@ -93,6 +118,13 @@ func (d *Daemon) Start(ctx context.Context) error {
 	procCtx, cancel := context.WithCancel(ctx)
 	d.cancelFunc = cancel
 
+	// Serve agent start/refresh/end requests from the CLI and chat
+	if err := d.listen(); err != nil {
+		cancel()
+		d.releaseLock()
+		return fmt.Errorf("listen on %s: %w", d.socketPath, err)
+	}
+
 	d.wg.Add(1)
 	go d.watchLoop(procCtx)
 
@@ -101,8 +133,12 @@ func (d *Daemon) Start(ctx context.Context) error {
 
 // Stop gracefully shuts down the daemon.
 func (d *Daemon) Stop() error {
-	// Signal watch loop to stop
+	// Signal watch loop to stop and refuse new session requests
 	close(d.stopCh)
+	if d.listener != nil {
+		d.listener.Close()
+		os.Remove(d.socketPath)
+	}
 
 	// Cancel process contexts - this kills spawned processes via CommandContext,
 	// allowing monitorProcess goroutines to exit
@@ -126,6 +162,7 @@ func (d *Daemon) Stop() error {
 	}
 	d.processes = make(map[string]*Process)
 	d.handled = make(map[string]bool)
+	d.ending = make(map[string]bool)
 	d.mu.Unlock()
 
 	// Release lock
@@ -150,6 +187,7 @@ func (d *Daemon) acquireLock() error {
 	info := LockInfo{
 		PID:       os.Getpid(),
 		StartedAt: time.Now().Unix(),
+		Socket:    d.socketPath,
 	}
 	data, err := json.Marshal(info)
 	if err != nil {
@@ -170,6 +208,50 @@ func (d *Daemon) debugf(format string, args ...any) {
 	}
 }
 
+// loadErrorNotifier returns the configured user's notifier when one of their
+// rules wants daemon errors.
+func loadErrorNotifier(database *sql.DB) *notify.Notifier {
+	if username, _ := db.GetConfig(database, "username"); username == "" {
+		return nil
+	}
+	notifier, err := notify.Load(notify.Options{})
+	if err != nil || !notifier.Wants(core.NotifyDaemonError) {
+		return nil
+	}
+	return notifier
+}
+
+// notifyError reports a managed agent failure to the user's notification
+// rules. It runs in the background so a slow sink never blocks the daemon.
+func (d *Daemon) notifyError(agentID, reason string) {
+	d.sendErrorEvent(agentID, fmt.Sprintf("@%s %s", agentID, reason))
+}
+
+// sendErrorEvent delivers a daemon error event in the background.
+func (d *Daemon) sendErrorEvent(from, body string) {
+	if d.notifier == nil {
+		return
+	}
+	select {
+	case <-d.stopCh:
+		return // processes killed on shutdown are not failures
+	default:
+	}
+	project := filepath.Base(d.project.Root)
+	event := notify.Event{
+		Kinds:   []string{core.NotifyDaemonError},
+		Project: project,
+		From:    from,
+		Title:   project + " · daemon",
+		Body:    body,
+	}
+	go func() {
+		if err := d.notifier.Notify(event); err != nil {
+			d.debugf("notify: %v", err)
+		}
+	}()
+}
+
 // truncate shortens a string for debug output.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -189,16 +271,21 @@ func isSchemaError(err error) bool {
 		strings.Contains(msg, "has no column")
 }
 
-// IsLocked returns true if a daemon is currently running.
-func IsLocked(frayDir string) bool {
-	lockPath := filepath.Join(frayDir, "daemon.lock")
-	data, err := os.ReadFile(lockPath)
+// readLockInfo reads the daemon lock file for a .fray directory.
+func readLockInfo(frayDir string) (LockInfo, error) {
+	var info LockInfo
+	data, err := os.ReadFile(filepath.Join(frayDir, "daemon.lock"))
 	if err != nil {
-		return false
+		return info, err
 	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
 
-	var info LockInfo
-	if json.Unmarshal(data, &info) != nil {
+// IsLocked returns true if a daemon is currently running.
+func IsLocked(frayDir string) bool {
+	info, err := readLockInfo(frayDir)
+	if err != nil {
 		return false
 	}
 
@@ -222,12 +309,34 @@ func (d *Daemon) watchLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			d.poll(ctx)
+		case sr := <-d.requests:
+			d.handleRequest(ctx, sr)
 		}
 	}
 }
 
 // poll checks for new mentions and updates process states.
 func (d *Daemon) poll(ctx context.Context) {
+	if d.session != "" {
+		// Supervising one session: only track its process.
+		d.updatePresence()
+		return
+	}
+
+	// Deliver due scheduled messages first so their mentions wake agents below.
+	if delivered, err := db.DeliverDueScheduled(d.database, d.project.DBPath, time.Now()); err != nil {
+		d.debugf("poll: error delivering scheduled messages: %v", err)
+	} else if len(delivered) > 0 {
+		d.debugf("poll: delivered %d scheduled messages", len(delivered))
+	}
+	if d.notifier != nil {
+		if err := d.notifier.Flush(); err != nil {
+			d.debugf("poll: notify digest: %v", err)
+		}
+	}
+
+	d.checkRetention()
+
 	// Get managed agents
 	agents, err := d.getManagedAgents()
 	if err != nil {
@@ -249,6 +358,8 @@ func (d *Daemon) poll(ctx context.Context) {
 
 	d.debugf("poll: checking %d managed agents", len(agents))
 
+	d.checkBlockingReactions(ctx, agents)
+
 	// Check for new mentions for each managed agent
 	for _, agent := range agents {
 		d.checkMentions(ctx, agent)
@@ -309,8 +420,9 @@ func (d *Daemon) checkMentions(ctx context.Context, agent types.Agent) {
 		// Reply to agent: threaded reply to something the agent wrote
 		isDirectAddress := IsDirectAddress(msg, agent.AgentID)
 		isReplyToAgent := IsReplyToAgent(d.database, msg, agent.AgentID)
+		isHandoff := IsHandoffTo(d.database, msg, agent.AgentID)
 
-		if !isDirectAddress && !isReplyToAgent {
+		if !isDirectAddress && !isReplyToAgent && !isHandoff {
 			d.debugf("    %s: skip (not direct address or reply) - body: %q", msg.ID, truncate(msg.Body, 50))
 			if !hasQueued && !spawned {
 				lastProcessedID = msg.ID
@@ -323,7 +435,7 @@ func (d *Daemon) checkMentions(ctx context.Context, agent types.Agent) {
 		if msg.Home != "" && msg.Home != "room" {
 			thread, _ = db.GetThread(d.database, msg.Home)
 		}
-		if !CanTriggerSpawn(msg, thread) {
+		if !isHandoff && !CanTriggerSpawn(msg, thread) {
 			isHuman := msg.Type == types.MessageTypeUser
 			d.debugf("    %s: skip (ownership check failed) - from: %s, type: %s, isHuman: %v", msg.ID, msg.FromAgent, msg.Type, isHuman)
 			if !hasQueued && !spawned {
@@ -377,6 +489,152 @@ func (d *Daemon) checkMentions(ctx context.Context, agent types.Agent) {
 	}
 }
 
+// checkBlockingReactions wakes managed agents whose messages just got a
+// blocking reaction. Busy agents get the message queued like a mention.
+func (d *Daemon) checkBlockingReactions(ctx context.Context, agents []types.Agent) {
+	blocked, err := db.GetBlockingReactionsSince(d.database, d.project.DBPath, d.blockedSince)
+	if err != nil {
+		d.debugf("poll: error checking blocking reactions: %v", err)
+		return
+	}
+
+	managed := make(map[string]types.Agent, len(agents))
+	for _, agent := range agents {
+		managed[agent.AgentID] = agent
+	}
+
+	for _, entry := range blocked {
+		d.blockedSince = entry.ReactedAt
+		agent, ok := managed[entry.Author]
+		if !ok {
+			continue
+		}
+		if current, err := db.GetAgent(d.database, agent.AgentID); err == nil && current != nil {
+			agent.Presence = current.Presence
+		}
+		if agent.Presence == types.PresenceSpawning || agent.Presence == types.PresenceActive {
+			d.debugf("  @%s: %s blocked by @%s, queued (agent busy)", agent.AgentID, entry.MessageGUID, entry.BlockedBy)
+			d.debouncer.QueueMention(agent.AgentID, entry.MessageGUID)
+			continue
+		}
+
+		d.debugf("  @%s: %s blocked by @%s, waking", agent.AgentID, entry.MessageGUID, entry.BlockedBy)
+		trigger := entry.MessageGUID
+		if _, err := d.launch(ctx, agent, d.buildBlockedPrompt(agent, entry), &trigger); err != nil {
+			d.debugf("  @%s: spawn failed: %v", agent.AgentID, err)
+			continue
+		}
+		agent.Presence = types.PresenceSpawning
+		managed[agent.AgentID] = agent
+	}
+}
+
+// retentionState tracks scheduled retention runs.
+type retentionState struct {
+	checkedAt time.Time // last read of the retention config
+	ranAt     time.Time // last run (or daemon start)
+	running   bool
+}
+
+// checkRetention runs "fray prune --policy" in the background when the
+// project's retention config sets an interval and it has passed since the
+// last run. The config is re-read at most once a minute.
+func (d *Daemon) checkRetention() {
+	now := time.Now()
+	d.mu.Lock()
+	if d.retention.running || now.Sub(d.retention.checkedAt) < time.Minute {
+		d.mu.Unlock()
+		return
+	}
+	d.retention.checkedAt = now
+	d.mu.Unlock()
+
+	config, err := db.ReadProjectConfig(d.project.DBPath)
+	if err != nil || config == nil || config.Retention == nil || config.Retention.Every == "" {
+		return
+	}
+	every, err := core.ParseAge(config.Retention.Every)
+	if err != nil {
+		d.reportRetentionError(err.Error())
+		return
+	}
+
+	d.mu.Lock()
+	if now.Sub(d.retention.ranAt) < every {
+		d.mu.Unlock()
+		return
+	}
+	d.retention.ranAt = now
+	d.retention.running = true
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer func() {
+			d.mu.Lock()
+			d.retention.running = false
+			d.mu.Unlock()
+		}()
+
+		exe, err := os.Executable()
+		if err != nil {
+			d.reportRetentionError(fmt.Sprintf("locate fray binary: %v", err))
+			return
+		}
+		proc := exec.Command(exe, "prune", "--policy", "--scheduled")
+		proc.Dir = d.project.Root
+		output, err := proc.CombinedOutput()
+		if err != nil {
+			d.reportRetentionError(fmt.Sprintf("prune --policy: %v: %s", err, strings.TrimSpace(string(output))))
+			return
+		}
+		d.debugf("retention: %s", strings.TrimSpace(string(output)))
+	}()
+}
+
+// reportRetentionError logs a failed scheduled retention run (to daemon.log
+// when backgrounded) and sends it to the user's daemon-error rules.
+func (d *Daemon) reportRetentionError(reason string) {
+	fmt.Fprintf(os.Stderr, "[daemon] retention: %s\n", reason)
+	d.sendErrorEvent("daemon", "retention failed: "+reason)
+}
+
+// buildBlockedPrompt is the wake prompt for a blocking reaction.
+func (d *Daemon) buildBlockedPrompt(agent types.Agent, entry db.BlockingReaction) string {
+	_, _, minCheckin, _ := GetTimeouts(agent.Invoke)
+	return fmt.Sprintf(`@%s reacted %s (blocked) to your message %s. Check fray for context.
+%s
+Run: fray get %s
+
+---
+Checkin: Posting to fray resets a %dm timer. Silence = session recycled (resumable on @mention).`,
+		entry.BlockedBy, entry.Reaction, entry.MessageGUID, d.buildTasksSection(agent.AgentID), agent.AgentID, minCheckin/60000)
+}
+
+// buildTasksSection lists the agent's open tasks for a wake prompt.
+// Returns "" when there are none so the prompt is unchanged.
+func (d *Daemon) buildTasksSection(agentID string) string {
+	tasks, err := db.GetTasks(d.database, &types.TaskQueryOptions{
+		Statuses: []types.TaskStatus{types.TaskStatusInProgress, types.TaskStatusBlocked, types.TaskStatusTodo},
+		Assignee: &agentID,
+	})
+	if err != nil || len(tasks) == 0 {
+		return ""
+	}
+
+	const maxTasks = 5
+	var lines []string
+	for i, task := range tasks {
+		if i == maxTasks {
+			lines = append(lines, fmt.Sprintf("  ... and %d more (fray tasks @%s)", len(tasks)-maxTasks, agentID))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s [%s] %s", task.GUID, task.Status, task.Title))
+	}
+	return "\nYour open tasks:\n" + strings.Join(lines, "\n") + "\n"
+}
+
 // getMessagesAfter returns messages mentioning agent after the given watermark.
 // Includes mentions in all threads (not just room) and replies to agent's messages.
 func (d *Daemon) getMessagesAfter(watermark, agentID string) ([]types.Message, error) {
@@ -401,28 +659,48 @@ func (d *Daemon) spawnAgent(ctx context.Context, agent types.Agent, triggerMsgID
 		return "", fmt.Errorf("agent %s has no driver configured", agent.AgentID)
 	}
 
+	// Build wake prompt and get all included mentions
+	prompt, allMentions := d.buildWakePrompt(agent, triggerMsgID)
+	d.debugf("  wake prompt includes %d mentions", len(allMentions))
+
+	if _, err := d.launch(ctx, agent, prompt, &triggerMsgID); err != nil {
+		return "", err
+	}
+
+	// Return the last mention included in the prompt
+	lastMention := triggerMsgID
+	if len(allMentions) > 0 {
+		lastMention = allMentions[len(allMentions)-1]
+	}
+	return lastMention, nil
+}
+
+// launch spawns an agent session with the given prompt and tracks it until
+// exit. triggeredBy is nil for sessions started by request.
+func (d *Daemon) launch(ctx context.Context, agent types.Agent, prompt string, triggeredBy *string) (*Process, error) {
+	if agent.Invoke == nil || agent.Invoke.Driver == "" {
+		return nil, fmt.Errorf("agent %s has no driver configured", agent.AgentID)
+	}
+
 	driver := d.drivers[agent.Invoke.Driver]
 	if driver == nil {
-		return "", fmt.Errorf("unknown driver: %s", agent.Invoke.Driver)
+		return nil, fmt.Errorf("unknown driver: %s", agent.Invoke.Driver)
 	}
 
 	d.debugf("  spawning @%s with driver %s", agent.AgentID, agent.Invoke.Driver)
 
 	// Update presence to spawning
 	if err := db.UpdateAgentPresence(d.database, agent.AgentID, types.PresenceSpawning); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Build wake prompt and get all included mentions
-	prompt, allMentions := d.buildWakePrompt(agent, triggerMsgID)
-	d.debugf("  wake prompt includes %d mentions", len(allMentions))
-
 	// Spawn process
 	proc, err := driver.Spawn(ctx, agent, prompt)
 	if err != nil {
 		d.debugf("  spawn error: %v", err)
 		db.UpdateAgentPresence(d.database, agent.AgentID, types.PresenceError)
-		return "", err
+		d.notifyError(agent.AgentID, fmt.Sprintf("failed to spawn: %v", err))
+		return nil, err
 	}
 
 	d.debugf("  spawned pid %d, session %s", proc.Cmd.Process.Pid, proc.SessionID)
@@ -440,7 +718,7 @@ func (d *Daemon) spawnAgent(ctx context.Context, agent types.Agent, triggerMsgID
 	sessionStart := types.SessionStart{
 		AgentID:     agent.AgentID,
 		SessionID:   proc.SessionID,
-		TriggeredBy: &triggerMsgID,
+		TriggeredBy: triggeredBy,
 		StartedAt:   time.Now().Unix(),
 	}
 	db.AppendSessionStart(d.project.DBPath, sessionStart)
@@ -454,12 +732,7 @@ func (d *Daemon) spawnAgent(ctx context.Context, agent types.Agent, triggerMsgID
 	d.wg.Add(1)
 	go d.monitorProcess(agent.AgentID, proc)
 
-	// Return the last mention included in the prompt
-	lastMention := triggerMsgID
-	if len(allMentions) > 0 {
-		lastMention = allMentions[len(allMentions)-1]
-	}
-	return lastMention, nil
+	return proc, nil
 }
 
 // monitorProcess drains stdout/stderr and waits for process exit.
@@ -557,12 +830,12 @@ func (d *Daemon) buildWakePrompt(agent types.Agent, triggerMsgID string) (string
 
 Trigger messages:
 %s
-
+%s
 Run: fray get %s
 
 ---
 Checkin: Posting to fray resets a %dm timer. Silence = session recycled (resumable on @mention).`,
-		triggerInfo, agent.AgentID, minCheckinMins)
+		triggerInfo, d.buildTasksSection(agent.AgentID), agent.AgentID, minCheckinMins)
 
 	return prompt, allMentions
 }
@@ -599,6 +872,7 @@ func (d *Daemon) updatePresence() {
 				if agent.Presence == types.PresenceSpawning && elapsed > spawnTimeout {
 					// Spawning timeout - mark as error
 					db.UpdateAgentPresence(d.database, agentID, types.PresenceError)
+					d.notifyError(agentID, "timed out while spawning")
 				} else if agent.Presence == types.PresenceActive {
 					lastActivity := d.detector.LastActivityTime(pid)
 					if time.Since(lastActivity).Milliseconds() > idleAfter {
@@ -684,10 +958,15 @@ func (d *Daemon) handleProcessExit(agentID string, proc *Process) {
 
 	// Only update presence and remove from map if this is the current process
 	if isCurrentProc {
-		if exitCode == 0 {
+		if d.ending[agentID] {
+			// Ended on request: signal exit codes are expected, not failures
+			delete(d.ending, agentID)
+			db.UpdateAgentPresence(d.database, agentID, types.PresenceOffline)
+		} else if exitCode == 0 {
 			db.UpdateAgentPresence(d.database, agentID, types.PresenceIdle)
 		} else {
 			db.UpdateAgentPresence(d.database, agentID, types.PresenceError)
+			d.notifyError(agentID, fmt.Sprintf("exited with code %d", exitCode))
 		}
 		// NOTE: Do NOT clear session ID here. Session remains resumable until agent runs `fray bye`.
 		// Daemon-initiated exits (done-detection) are soft ends; session context persists on disk.
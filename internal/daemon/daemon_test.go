@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
@@ -489,6 +490,51 @@ func TestMentionDetection_ChainReplyWakes(t *testing.T) {
 	}
 }
 
+func TestBlockingReactionQueuesBusyAuthor(t *testing.T) {
+	h := newTestHarness(t)
+
+	alice := h.createAgent("alice", true)
+	h.createAgent("bob", false)
+	if err := db.UpdateAgentPresence(h.db, "alice", types.PresenceActive); err != nil {
+		t.Fatalf("update presence: %v", err)
+	}
+
+	blockedMsg := h.postMessage("alice", "shipping the migration now", types.MessageTypeAgent)
+	otherMsg := h.postMessage("alice", "and the docs", types.MessageTypeAgent)
+
+	d := &Daemon{
+		project:   core.Project{DBPath: h.projectPath},
+		database:  h.db,
+		debouncer: h.debouncer,
+	}
+	now := time.Now().UnixMilli()
+	for _, reaction := range []struct{ msg, agent, emoji string }{
+		{blockedMsg.ID, "bob", "🚫"},
+		{otherMsg.ID, "bob", "👍"},   // no meaning
+		{otherMsg.ID, "alice", "🚫"}, // self-reaction
+	} {
+		if err := db.InsertReaction(h.db, reaction.msg, reaction.agent, reaction.emoji, now); err != nil {
+			t.Fatalf("insert reaction: %v", err)
+		}
+	}
+
+	d.checkBlockingReactions(context.Background(), []types.Agent{alice})
+
+	pending := h.debouncer.FlushPending("alice")
+	if len(pending) != 1 || pending[0] != blockedMsg.ID {
+		t.Fatalf("expected blocked message queued for alice, got %v", pending)
+	}
+	if d.blockedSince != now {
+		t.Fatalf("expected watermark to advance to %d, got %d", now, d.blockedSince)
+	}
+
+	// Already handled reactions are not seen again.
+	d.checkBlockingReactions(context.Background(), []types.Agent{alice})
+	if h.debouncer.HasPending("alice") {
+		t.Fatalf("expected no repeat wake")
+	}
+}
+
 // Helper
 func strPtr(s string) *string {
 	return &s
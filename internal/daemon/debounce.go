@@ -209,6 +209,16 @@ func IsReplyToAgent(database *sql.DB, msg types.Message, agentID string) bool {
 	return false
 }
 
+// IsHandoffTo returns true if the message is a handoff addressed to the given agent.
+// Handoffs wake the recipient even though they are posted by another agent.
+func IsHandoffTo(database *sql.DB, msg types.Message, agentID string) bool {
+	handoff, err := db.GetHandoffByMessage(database, msg.ID)
+	if err != nil || handoff == nil {
+		return false
+	}
+	return handoff.ToAgent == agentID
+}
+
 // ShouldSpawn determines if a mention should trigger a spawn.
 // Returns false if:
 // - Message is a self-mention
@@ -2,8 +2,11 @@ package daemon
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/adamavenir/fray/internal/types"
@@ -83,3 +86,68 @@ func GetTimeouts(cfg *types.InvokeConfig) (spawnTimeout, idleAfter, minCheckin,
 
 	return
 }
+
+// RunOnce runs a single prompt through an agent's driver in a fresh session
+// and returns what the agent printed. When a daemon is running for frayDir the
+// daemon runs it, so the process belongs to the daemon like every other agent
+// process; otherwise it runs here. It does not touch the agent's presence or
+// saved session.
+func RunOnce(ctx context.Context, frayDir string, agent types.Agent, prompt string) (string, error) {
+	req := Request{Op: OpRunOnce, AgentID: agent.AgentID, Prompt: prompt}
+	timeout := ipcCallTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		req.TimeoutMs = timeout.Milliseconds()
+	}
+	resp, err := call(frayDir, req, timeout)
+	if err == nil {
+		return resp.Output, nil
+	}
+	if !errors.Is(err, ErrNoDaemon) {
+		return "", err
+	}
+	return runOnceWith(ctx, GetDriver(driverName(agent)), agent, prompt)
+}
+
+func driverName(agent types.Agent) string {
+	if agent.Invoke == nil {
+		return ""
+	}
+	return agent.Invoke.Driver
+}
+
+// runOnceWith runs a prompt through driver and collects its output.
+func runOnceWith(ctx context.Context, driver Driver, agent types.Agent, prompt string) (string, error) {
+	if driverName(agent) == "" {
+		return "", fmt.Errorf("agent %s has no driver configured", agent.AgentID)
+	}
+	if driver == nil {
+		return "", fmt.Errorf("unknown driver: %s", agent.Invoke.Driver)
+	}
+
+	agent.LastSessionID = nil
+	proc, err := driver.Spawn(ctx, agent, prompt)
+	if err != nil {
+		return "", err
+	}
+	defer driver.Cleanup(proc)
+
+	var stderr strings.Builder
+	stderrDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&stderr, proc.Stderr)
+		close(stderrDone)
+	}()
+	output, readErr := io.ReadAll(proc.Stdout)
+	<-stderrDone
+	if err := proc.Cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%s: %w: %s", agent.Invoke.Driver, err, strings.TrimSpace(stderr.String()))
+	}
+	if readErr != nil {
+		return "", readErr
+	}
+	return string(output), nil
+}
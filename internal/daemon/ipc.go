@@ -0,0 +1,348 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// Session operations accepted over the daemon socket.
+const (
+	OpPing    = "ping"
+	OpStart   = "start"
+	OpRefresh = "refresh"
+	OpEnd     = "end"
+	OpRunOnce = "run_once"
+)
+
+const (
+	ipcDialTimeout = 2 * time.Second
+	ipcCallTimeout = 30 * time.Second
+	// endGrace is how long an ended session gets to exit after SIGTERM before
+	// it is killed.
+	endGrace = 5 * time.Second
+	// maxSocketPath stays under the sun_path limit (104 bytes on macOS).
+	maxSocketPath = 100
+)
+
+// ErrNoDaemon is returned by Call when no daemon is running for the project.
+var ErrNoDaemon = errors.New("daemon is not running")
+
+// Request asks the daemon to manage an agent session.
+type Request struct {
+	Op      string `json:"op"`
+	AgentID string `json:"agent_id,omitempty"`
+	Prompt  string `json:"prompt,omitempty"` // start/refresh/run_once: prompt for the new session
+	// TimeoutMs bounds a run_once; zero uses the default call timeout.
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// Response reports the outcome of a Request.
+type Response struct {
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	PID       int    `json:"pid,omitempty"`
+	Ended     bool   `json:"ended,omitempty"`  // a running session was stopped
+	Output    string `json:"output,omitempty"` // run_once: what the agent printed
+}
+
+// sessionRequest carries a Request to the watch loop, which runs session
+// operations between polls so they never race mention-triggered spawns.
+type sessionRequest struct {
+	req   Request
+	reply chan Response
+}
+
+// socketPathFor returns the daemon socket path for a .fray directory, falling
+// back to the temp dir when the project path is too long for a socket.
+func socketPathFor(frayDir string) string {
+	path := filepath.Join(frayDir, "daemon.sock")
+	if len(path) <= maxSocketPath {
+		return path
+	}
+	sum := sha256.Sum256([]byte(frayDir))
+	return filepath.Join(os.TempDir(), "fray-"+hex.EncodeToString(sum[:6])+".sock")
+}
+
+// listen opens the daemon socket and serves requests until it is closed.
+func (d *Daemon) listen() error {
+	_ = os.Remove(d.socketPath) // left behind by a daemon that died
+	listener, err := net.Listen("unix", d.socketPath)
+	if err != nil {
+		return err
+	}
+	_ = os.Chmod(d.socketPath, 0o600)
+	d.listener = listener
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go d.serveConn(conn)
+		}
+	}()
+	return nil
+}
+
+func (d *Daemon) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ipcCallTimeout))
+
+	var req Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("bad request: %v", err)})
+		return
+	}
+	if req.Op == OpRunOnce {
+		// One-shot runs can take minutes, so they run here rather than
+		// holding up the watch loop.
+		timeout := runOnceTimeout(req)
+		_ = conn.SetDeadline(time.Now().Add(timeout + ipcDialTimeout))
+		_ = json.NewEncoder(conn).Encode(d.runOnce(req, timeout))
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(d.Do(req))
+}
+
+func runOnceTimeout(req Request) time.Duration {
+	if req.TimeoutMs > 0 {
+		return time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	return ipcCallTimeout
+}
+
+// runOnce runs a one-shot prompt for the CLI with the daemon's drivers. The
+// run is cancelled when the daemon stops.
+func (d *Daemon) runOnce(req Request, timeout time.Duration) Response {
+	agent, err := db.GetAgent(d.database, req.AgentID)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	if agent == nil {
+		return Response{Error: fmt.Sprintf("agent not found: @%s", req.AgentID)}
+	}
+
+	d.wg.Add(1)
+	defer d.wg.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	d.debugf("run-once: @%s with driver %s", agent.AgentID, driverName(*agent))
+	output, err := runOnceWith(ctx, d.drivers[driverName(*agent)], *agent, req.Prompt)
+	if err != nil {
+		d.debugf("run-once: @%s failed: %v", agent.AgentID, err)
+		return Response{Error: err.Error(), AgentID: agent.AgentID}
+	}
+	return Response{OK: true, AgentID: agent.AgentID, Output: output}
+}
+
+// Do runs a session request on the watch loop and returns its result.
+func (d *Daemon) Do(req Request) Response {
+	reply := make(chan Response, 1)
+	select {
+	case d.requests <- sessionRequest{req: req, reply: reply}:
+	case <-d.stopCh:
+		return Response{Error: "daemon is stopping"}
+	}
+	select {
+	case resp := <-reply:
+		return resp
+	case <-d.stopCh:
+		return Response{Error: "daemon is stopping"}
+	}
+}
+
+// handleRequest runs a session request on the watch loop and replies on
+// sr.reply. Ending a session waits for the process to exit, which happens off
+// the loop so polling and other requests carry on meanwhile.
+func (d *Daemon) handleRequest(ctx context.Context, sr sessionRequest) {
+	req := sr.req
+	if req.Op == OpPing {
+		sr.reply <- Response{OK: true}
+		return
+	}
+	if d.session != "" && req.AgentID != d.session {
+		sr.reply <- Response{Error: fmt.Sprintf("this daemon only supervises @%s", d.session)}
+		return
+	}
+
+	agent, err := db.GetAgent(d.database, req.AgentID)
+	if err != nil {
+		sr.reply <- Response{Error: err.Error()}
+		return
+	}
+	if agent == nil {
+		sr.reply <- Response{Error: fmt.Sprintf("agent not found: @%s", req.AgentID)}
+		return
+	}
+	if !agent.Managed {
+		sr.reply <- Response{Error: fmt.Sprintf("agent @%s is not managed", agent.AgentID)}
+		return
+	}
+
+	switch req.Op {
+	case OpEnd, OpRefresh:
+		proc := d.signalEnd(agent.AgentID)
+		if proc != nil {
+			d.wg.Add(1)
+			go d.finishEnd(sr, agent.AgentID, proc)
+			return
+		}
+		if req.Op == OpEnd {
+			sr.reply <- Response{OK: true, AgentID: agent.AgentID}
+			return
+		}
+	case OpStart:
+		d.mu.RLock()
+		running := d.processes[agent.AgentID]
+		d.mu.RUnlock()
+		if running != nil {
+			sr.reply <- Response{Error: fmt.Sprintf("@%s is already running (pid %d); use refresh to restart it", agent.AgentID, running.Cmd.Process.Pid)}
+			return
+		}
+	default:
+		sr.reply <- Response{Error: fmt.Sprintf("unknown op: %s", req.Op)}
+		return
+	}
+
+	// Re-read: ending the old session updated presence.
+	if refreshed, err := db.GetAgent(d.database, agent.AgentID); err == nil && refreshed != nil {
+		agent = refreshed
+	}
+	proc, err := d.launch(ctx, *agent, req.Prompt, nil)
+	if err != nil {
+		sr.reply <- Response{Error: fmt.Sprintf("spawn failed: %v", err), AgentID: agent.AgentID}
+		return
+	}
+	resp := Response{OK: true, AgentID: agent.AgentID, SessionID: proc.SessionID}
+	if proc.Cmd.Process != nil {
+		resp.PID = proc.Cmd.Process.Pid
+	}
+	sr.reply <- resp
+}
+
+// finishEnd waits for an ended session to exit and replies to the request.
+// A refresh then starts the new session back on the watch loop.
+func (d *Daemon) finishEnd(sr sessionRequest, agentID string, proc *Process) {
+	defer d.wg.Done()
+	d.awaitExit(agentID, proc)
+
+	resp := Response{OK: true, AgentID: agentID}
+	if sr.req.Op == OpRefresh {
+		resp = d.Do(Request{Op: OpStart, AgentID: agentID, Prompt: sr.req.Prompt})
+	}
+	resp.Ended = true
+	sr.reply <- resp
+}
+
+// signalEnd marks the agent's running session as ending and sends it SIGTERM.
+// With no running session, presence is set offline and nil is returned.
+func (d *Daemon) signalEnd(agentID string) *Process {
+	d.mu.Lock()
+	proc := d.processes[agentID]
+	if proc != nil {
+		d.ending[agentID] = true
+	}
+	d.mu.Unlock()
+
+	if proc == nil || proc.Cmd.Process == nil {
+		db.UpdateAgentPresence(d.database, agentID, types.PresenceOffline)
+		return nil
+	}
+	_ = proc.Cmd.Process.Signal(syscall.SIGTERM)
+	return proc
+}
+
+// awaitExit waits for an ended session's exit to be recorded, killing it if
+// it outlives endGrace. Returns early when the daemon stops.
+func (d *Daemon) awaitExit(agentID string, proc *Process) {
+	deadline := time.Now().Add(endGrace)
+	killed := false
+	for {
+		d.mu.RLock()
+		current := d.processes[agentID]
+		d.mu.RUnlock()
+		if current != proc {
+			return
+		}
+		if !killed && time.Now().After(deadline) {
+			_ = proc.Cmd.Process.Kill()
+			killed = true
+			deadline = time.Now().Add(endGrace)
+		} else if killed && time.Now().After(deadline) {
+			d.debugf("end: @%s did not exit after kill", agentID)
+			return
+		}
+		select {
+		case <-d.stopCh:
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Running reports whether the daemon has a live process for the agent.
+func (d *Daemon) Running(agentID string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.processes[agentID] != nil
+}
+
+// Call sends a request to the daemon running for a .fray directory.
+func Call(frayDir string, req Request) (Response, error) {
+	return call(frayDir, req, ipcCallTimeout)
+}
+
+func call(frayDir string, req Request, timeout time.Duration) (Response, error) {
+	if !IsLocked(frayDir) {
+		return Response{}, ErrNoDaemon
+	}
+	socketPath := socketPathFor(frayDir)
+	if info, err := readLockInfo(frayDir); err == nil && info.Socket != "" {
+		socketPath = info.Socket
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, ipcDialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("connect to daemon (restart it to enable agent commands): %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("read daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
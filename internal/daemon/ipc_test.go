@@ -0,0 +1,186 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// sleepDriver spawns a long-running shell process in place of an agent CLI.
+type sleepDriver struct{}
+
+func (sleepDriver) Name() string { return "claude" }
+
+func (sleepDriver) Spawn(ctx context.Context, agent types.Agent, prompt string) (*Process, error) {
+	cmd := exec.CommandContext(ctx, "sleep", "30")
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Process{Cmd: cmd, StartedAt: time.Now(), SessionID: "sess-" + agent.AgentID}, nil
+}
+
+func (sleepDriver) Cleanup(proc *Process) error { return nil }
+
+func TestIPCStartAndEndSession(t *testing.T) {
+	h := newTestHarness(t)
+	h.createAgent("opus", true)
+	h.createAgent("human", false)
+
+	project, err := core.DiscoverProject(h.projectDir)
+	if err != nil {
+		t.Fatalf("discover project: %v", err)
+	}
+	d := New(project, h.db, Config{PollInterval: 50 * time.Millisecond})
+	d.drivers["claude"] = sleepDriver{}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	stopped := false
+	t.Cleanup(func() {
+		if !stopped {
+			d.Stop()
+		}
+	})
+
+	frayDir := filepath.Join(h.projectDir, ".fray")
+	if _, err := Call(frayDir, Request{Op: OpPing}); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	resp, err := Call(frayDir, Request{Op: OpStart, AgentID: "opus", Prompt: "hello"})
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	if resp.SessionID != "sess-opus" || resp.PID == 0 || !d.Running("opus") {
+		t.Fatalf("expected running session, got %+v", resp)
+	}
+
+	if _, err := Call(frayDir, Request{Op: OpStart, AgentID: "opus"}); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("expected already running error, got %v", err)
+	}
+	if _, err := Call(frayDir, Request{Op: OpStart, AgentID: "human"}); err == nil || !strings.Contains(err.Error(), "not managed") {
+		t.Fatalf("expected not managed error, got %v", err)
+	}
+
+	resp, err = Call(frayDir, Request{Op: OpEnd, AgentID: "opus"})
+	if err != nil {
+		t.Fatalf("end session: %v", err)
+	}
+	if !resp.Ended || d.Running("opus") {
+		t.Fatalf("expected session to be stopped, got %+v", resp)
+	}
+	agent, _ := db.GetAgent(h.db, "opus")
+	if agent == nil || agent.Presence != types.PresenceOffline {
+		t.Fatalf("expected offline presence after end, got %+v", agent)
+	}
+
+	data, err := os.ReadFile(filepath.Join(frayDir, "agents.jsonl"))
+	if err != nil {
+		t.Fatalf("read agents.jsonl: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"session_end"`) {
+		t.Fatalf("expected session_end record, got %s", data)
+	}
+
+	stopped = true
+	if err := d.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if _, err := Call(frayDir, Request{Op: OpPing}); err != ErrNoDaemon {
+		t.Fatalf("expected ErrNoDaemon after stop, got %v", err)
+	}
+}
+
+func TestIPCSessionDaemon(t *testing.T) {
+	h := newTestHarness(t)
+	h.createAgent("opus", true)
+	h.createAgent("sonnet", true)
+
+	project, err := core.DiscoverProject(h.projectDir)
+	if err != nil {
+		t.Fatalf("discover project: %v", err)
+	}
+	d := New(project, h.db, Config{PollInterval: 50 * time.Millisecond, Session: "opus"})
+	d.drivers["claude"] = sleepDriver{}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { d.Stop() })
+
+	if resp := d.Do(Request{Op: OpStart, AgentID: "sonnet"}); !strings.Contains(resp.Error, "only supervises @opus") {
+		t.Fatalf("expected other agents to be refused, got %+v", resp)
+	}
+
+	first := d.Do(Request{Op: OpStart, AgentID: "opus"})
+	if first.Error != "" || first.PID == 0 {
+		t.Fatalf("expected running session, got %+v", first)
+	}
+	resp := d.Do(Request{Op: OpRefresh, AgentID: "opus"})
+	if resp.Error != "" || !resp.Ended || resp.PID == 0 || resp.PID == first.PID {
+		t.Fatalf("expected refresh to replace the session, got %+v", resp)
+	}
+	if !d.Running("opus") || d.Running("sonnet") {
+		t.Fatalf("expected only opus to be running")
+	}
+}
+
+// echoDriver prints the prompt and exits, like a one-shot agent run.
+type echoDriver struct{}
+
+func (echoDriver) Name() string { return "claude" }
+
+func (echoDriver) Spawn(ctx context.Context, agent types.Agent, prompt string) (*Process, error) {
+	cmd := exec.CommandContext(ctx, "echo", "summary of: "+prompt)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Process{Cmd: cmd, Stdout: stdout, Stderr: stderr, StartedAt: time.Now()}, nil
+}
+
+func (echoDriver) Cleanup(proc *Process) error { return nil }
+
+func TestIPCRunOnce(t *testing.T) {
+	h := newTestHarness(t)
+	agent := h.createAgent("summarizer", true)
+
+	project, err := core.DiscoverProject(h.projectDir)
+	if err != nil {
+		t.Fatalf("discover project: %v", err)
+	}
+	d := New(project, h.db, Config{PollInterval: 50 * time.Millisecond})
+	d.drivers["claude"] = echoDriver{}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { d.Stop() })
+
+	// With a daemon running, the daemon's driver runs the prompt
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := RunOnce(ctx, filepath.Join(h.projectDir, ".fray"), agent, "the thread")
+	if err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+	if strings.TrimSpace(output) != "summary of: the thread" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+	if d.Running("summarizer") {
+		t.Fatalf("expected one-shot run not to register a session")
+	}
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// QuestionAnswer pairs a question with the answer given to it.
+type QuestionAnswer struct {
+	Question types.Question
+	Answer   string
+}
+
+// PostAnswerSummary posts a single message with all Q&A pairs and marks each
+// question answered in it. Shared by `fray answer` and chat.
+func PostAnswerSummary(database *sql.DB, dbPath string, identity string, answers []QuestionAnswer) (types.Message, error) {
+	now := time.Now().Unix()
+
+	// Collect unique askers
+	askers := make(map[string]struct{})
+	for _, pair := range answers {
+		askers[pair.Question.FromAgent] = struct{}{}
+	}
+	askerList := make([]string, 0, len(askers))
+	for asker := range askers {
+		askerList = append(askerList, "@"+asker)
+	}
+
+	// Build the summary message body in parseable format
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("answered %s\n\n", strings.Join(askerList, " ")))
+
+	for _, pair := range answers {
+		// Question
+		body.WriteString(fmt.Sprintf("Q: %s\n", pair.Question.Re))
+
+		// Answer with indented multi-line support
+		answerLines := strings.Split(pair.Answer, "\n")
+		body.WriteString(fmt.Sprintf("A: %s\n", answerLines[0]))
+		for _, line := range answerLines[1:] {
+			body.WriteString(fmt.Sprintf("   %s\n", line))
+		}
+		body.WriteString("\n")
+	}
+
+	bodyStr := strings.TrimSpace(body.String())
+
+	// Extract mentions from all answers
+	bases, _ := GetAgentBases(database)
+	mentions := core.ExtractMentions(bodyStr, bases)
+	mentions = core.ExpandAllMention(mentions, bases)
+
+	// Determine home (use first question's thread if any)
+	home := ""
+	if len(answers) > 0 && answers[0].Question.ThreadGUID != nil {
+		home = *answers[0].Question.ThreadGUID
+	}
+
+	// Create the summary message
+	created, err := CreateMessage(database, types.Message{
+		TS:        now,
+		FromAgent: identity,
+		Body:      bodyStr,
+		Mentions:  mentions,
+		Home:      home,
+	})
+	if err != nil {
+		return types.Message{}, err
+	}
+
+	if err := AppendMessage(dbPath, created); err != nil {
+		return types.Message{}, err
+	}
+
+	touchAgentLastSeen(database, identity, now)
+
+	// Update all questions to point to this summary message
+	statusValue := string(types.QuestionStatusAnswered)
+	for _, pair := range answers {
+		updated, err := UpdateQuestion(database, pair.Question.GUID, QuestionUpdates{
+			Status:     types.OptionalString{Set: true, Value: &statusValue},
+			AnsweredIn: types.OptionalString{Set: true, Value: &created.ID},
+		})
+		if err != nil {
+			return types.Message{}, err
+		}
+
+		if err := AppendQuestionUpdate(dbPath, QuestionUpdateJSONLRecord{
+			GUID:       updated.GUID,
+			Status:     &statusValue,
+			AnsweredIn: &created.ID,
+		}); err != nil {
+			return types.Message{}, err
+		}
+	}
+
+	return created, nil
+}
+
+// AskQuestion posts question as a message from asker and marks it open, asked
+// in that message. toAgent and threadGUID are optional; threadGUID only applies
+// when the question has no thread yet. This is how a wondering (unasked)
+// question is promoted to an ask.
+func AskQuestion(database *sql.DB, dbPath string, question types.Question, asker string, toAgent, threadGUID *string) (*types.Question, types.Message, error) {
+	now := time.Now().Unix()
+
+	body := question.Re
+	if toAgent != nil {
+		body = fmt.Sprintf("@%s %s", *toAgent, question.Re)
+	}
+
+	bases, err := GetAgentBases(database)
+	if err != nil {
+		return nil, types.Message{}, err
+	}
+	mentions := core.ExtractMentions(body, bases)
+	mentions = core.ExpandAllMention(mentions, bases)
+
+	home := ""
+	if threadGUID != nil {
+		home = *threadGUID
+	} else if question.ThreadGUID != nil {
+		home = *question.ThreadGUID
+	}
+
+	created, err := CreateMessage(database, types.Message{
+		TS:        now,
+		FromAgent: asker,
+		Body:      body,
+		Mentions:  mentions,
+		Home:      home,
+	})
+	if err != nil {
+		return nil, types.Message{}, err
+	}
+
+	if err := AppendMessage(dbPath, created); err != nil {
+		return nil, types.Message{}, err
+	}
+
+	touchAgentLastSeen(database, asker, now)
+
+	statusValue := string(types.QuestionStatusOpen)
+	questionUpdates := QuestionUpdates{
+		Status:  types.OptionalString{Set: true, Value: &statusValue},
+		AskedIn: types.OptionalString{Set: true, Value: &created.ID},
+	}
+	updateRecord := QuestionUpdateJSONLRecord{
+		GUID:    question.GUID,
+		Status:  &statusValue,
+		AskedIn: &created.ID,
+	}
+	if toAgent != nil {
+		questionUpdates.ToAgent = types.OptionalString{Set: true, Value: toAgent}
+		updateRecord.ToAgent = toAgent
+	}
+	if threadGUID != nil && question.ThreadGUID == nil {
+		questionUpdates.ThreadGUID = types.OptionalString{Set: true, Value: threadGUID}
+		updateRecord.ThreadGUID = threadGUID
+	}
+
+	updated, err := UpdateQuestion(database, question.GUID, questionUpdates)
+	if err != nil {
+		return nil, types.Message{}, err
+	}
+	if err := AppendQuestionUpdate(dbPath, updateRecord); err != nil {
+		return nil, types.Message{}, err
+	}
+
+	return updated, created, nil
+}
+
+// touchAgentLastSeen bumps last_seen when identity is an agent (not a user).
+func touchAgentLastSeen(database *sql.DB, identity string, now int64) {
+	agent, _ := GetAgent(database, identity)
+	if agent != nil {
+		updates := AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
+		_ = UpdateAgent(database, identity, updates)
+	}
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+)
+
+// Audit actions that rewrite cached history. Rebuild replays these, since the
+// message log itself still carries the old agent names.
+const (
+	AuditAgentRename = "agent.rename"
+	AuditAgentMerge  = "agent.merge"
+)
+
+// Linked projects only live in the cache, so rebuild replays links too.
+const (
+	AuditProjectLink   = "project.link"
+	AuditProjectUnlink = "project.unlink"
+)
+
+// AppendAudit records an administrative action in audit.jsonl.
+func AppendAudit(projectPath string, record AuditJSONLRecord) (AuditJSONLRecord, error) {
+	record, err := prepareAudit(record)
+	if err != nil {
+		return AuditJSONLRecord{}, err
+	}
+	if err := appendJSONLine(filepath.Join(resolveFrayDir(projectPath), auditFile), record); err != nil {
+		return AuditJSONLRecord{}, err
+	}
+	touchDatabaseFile(projectPath)
+	return record, nil
+}
+
+// AppendGlobalAudit records an action in the global audit log. Used for
+// actions that remove a channel's own .fray directory.
+func AppendGlobalAudit(record AuditJSONLRecord) (AuditJSONLRecord, error) {
+	record, err := prepareAudit(record)
+	if err != nil {
+		return AuditJSONLRecord{}, err
+	}
+	dir, err := core.GlobalConfigDir()
+	if err != nil {
+		return AuditJSONLRecord{}, err
+	}
+	if err := appendJSONLine(filepath.Join(dir, auditFile), record); err != nil {
+		return AuditJSONLRecord{}, err
+	}
+	return record, nil
+}
+
+// ReadAudit returns a project's audit entries, oldest first.
+func ReadAudit(projectPath string) ([]AuditJSONLRecord, error) {
+	return readAuditFile(filepath.Join(resolveFrayDir(projectPath), auditFile))
+}
+
+// ReadGlobalAudit returns the global audit entries, oldest first.
+func ReadGlobalAudit() ([]AuditJSONLRecord, error) {
+	dir, err := core.GlobalConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return readAuditFile(filepath.Join(dir, auditFile))
+}
+
+func prepareAudit(record AuditJSONLRecord) (AuditJSONLRecord, error) {
+	record.Type = "audit"
+	if record.ID == "" {
+		guid, err := core.GenerateGUID("aud")
+		if err != nil {
+			return AuditJSONLRecord{}, err
+		}
+		record.ID = guid
+	}
+	if record.TS == 0 {
+		record.TS = time.Now().Unix()
+	}
+	return record, nil
+}
+
+func readAuditFile(path string) ([]AuditJSONLRecord, error) {
+	records, err := readJSONLFile[AuditJSONLRecord](path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merged branches can both carry the same entry.
+	seen := make(map[string]struct{}, len(records))
+	entries := make([]AuditJSONLRecord, 0, len(records))
+	for _, record := range records {
+		if record.Type != "audit" {
+			continue
+		}
+		if _, ok := seen[record.ID]; ok {
+			continue
+		}
+		seen[record.ID] = struct{}{}
+		entries = append(entries, record)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TS < entries[j].TS
+	})
+	return entries, nil
+}
+
+// replayAudit re-applies renames and merges to freshly rebuilt messages,
+// reactions and claims, as the live commands do, and restores linked projects.
+// Each rename or merge only touches rows from before it, so replaying twice is
+// a no-op and a name reused later keeps its own history. It never writes the
+// log.
+func replayAudit(db DBTX, entries []AuditJSONLRecord) error {
+	for _, entry := range entries {
+		var fromID, toID string
+		invalidate := false
+		switch entry.Action {
+		case AuditProjectLink:
+			if err := replayProjectLink(db, entry.Target, auditString(entry.After, "path")); err != nil {
+				return err
+			}
+			continue
+		case AuditProjectUnlink:
+			if _, err := db.Exec("DELETE FROM fray_linked_projects WHERE alias = ?", entry.Target); err != nil {
+				return err
+			}
+			continue
+		case AuditAgentRename:
+			fromID = auditString(entry.Before, "agent_id")
+			toID = auditString(entry.After, "agent_id")
+		case AuditAgentMerge:
+			fromID = auditString(entry.Before, "agent_id")
+			toID = auditString(entry.After, "agent_id")
+			invalidate = true
+		default:
+			continue
+		}
+		if fromID == "" || toID == "" || fromID == toID {
+			continue
+		}
+		if _, err := reassignAgentMessages(db, fromID, toID, entry.TS, invalidate); err != nil {
+			return err
+		}
+		if err := updateReactionsForAgent(db, fromID, toID, entry.TS); err != nil {
+			return err
+		}
+		if _, err := db.Exec("UPDATE fray_claims SET agent_id = ? WHERE agent_id = ? AND created_at <= ?", toID, fromID, entry.TS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayProjectLink restores a link, skipping targets that aren't on this
+// machine (the log may come from a clone elsewhere).
+func replayProjectLink(db DBTX, alias, path string) error {
+	if alias == "" || path == "" {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return nil
+	}
+	_, err := db.Exec("INSERT OR REPLACE INTO fray_linked_projects (alias, path) VALUES (?, ?)", alias, path)
+	return err
+}
+
+func auditString(values map[string]any, key string) string {
+	value, _ := values[key].(string)
+	return value
+}
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+const blobsDir = "blobs"
+
+// MaxBlobSize caps a single attachment. Blobs are committed with .fray/, so
+// anything larger belongs in git LFS or behind a link.
+const MaxBlobSize = 25 << 20
+
+// StoreBlob copies a file into the content-addressed blob store and returns its
+// attachment metadata. Blobs live at .fray/blobs/<hash[:2]>/<hash>, so identical
+// content is stored once and the layout stays friendly to git.
+func StoreBlob(projectPath, srcPath string) (types.Attachment, error) {
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return types.Attachment{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, MaxBlobSize+1))
+	file.Close()
+	if err != nil {
+		return types.Attachment{}, err
+	}
+	if len(data) > MaxBlobSize {
+		return types.Attachment{}, fmt.Errorf("attachment %s is larger than %d MB", filepath.Base(srcPath), MaxBlobSize>>20)
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	blobPath, err := BlobPath(projectPath, hash)
+	if err != nil {
+		return types.Attachment{}, err
+	}
+	if _, err := os.Stat(blobPath); os.IsNotExist(err) {
+		if err := ensureDir(filepath.Dir(blobPath)); err != nil {
+			return types.Attachment{}, err
+		}
+		tmp := blobPath + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return types.Attachment{}, err
+		}
+		if err := os.Rename(tmp, blobPath); err != nil {
+			_ = os.Remove(tmp)
+			return types.Attachment{}, err
+		}
+	} else if err != nil {
+		return types.Attachment{}, err
+	}
+
+	name := filepath.Base(srcPath)
+	return types.Attachment{
+		Name: name,
+		Mime: detectMime(name, data),
+		Size: int64(len(data)),
+		Hash: hash,
+	}, nil
+}
+
+// BlobPath returns the on-disk path for a blob hash. Hashes come from message
+// records, so anything but a SHA-256 hex digest is rejected before it can
+// name a path outside the blob store.
+func BlobPath(projectPath, hash string) (string, error) {
+	if !validBlobHash(hash) {
+		return "", fmt.Errorf("invalid blob hash: %q", hash)
+	}
+	frayDir := resolveFrayDir(projectPath)
+	return filepath.Join(frayDir, blobsDir, hash[:2], hash), nil
+}
+
+// OpenBlob opens a stored blob for reading.
+func OpenBlob(projectPath, hash string) (io.ReadCloser, error) {
+	path, err := BlobPath(projectPath, hash)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("blob not found: %s", hash)
+	}
+	return f, err
+}
+
+// FindAttachment returns the attachment on msg matching name, or by hash prefix.
+// Attachments whose recorded hash is not a valid blob hash are never returned.
+func FindAttachment(msg types.Message, name string) (*types.Attachment, error) {
+	var match *types.Attachment
+	for i := range msg.Attachments {
+		if msg.Attachments[i].Name == name {
+			match = &msg.Attachments[i]
+			break
+		}
+	}
+	if match == nil {
+		for i := range msg.Attachments {
+			if len(name) >= 4 && strings.HasPrefix(msg.Attachments[i].Hash, name) {
+				match = &msg.Attachments[i]
+				break
+			}
+		}
+	}
+	if match != nil {
+		if !validBlobHash(match.Hash) {
+			return nil, fmt.Errorf("attachment %q on %s has an invalid hash", match.Name, msg.ID)
+		}
+		return match, nil
+	}
+	if len(msg.Attachments) == 0 {
+		return nil, fmt.Errorf("message %s has no attachments", msg.ID)
+	}
+	names := make([]string, len(msg.Attachments))
+	for i, attachment := range msg.Attachments {
+		names[i] = attachment.Name
+	}
+	return nil, fmt.Errorf("attachment %q not found on %s (have: %s)", name, msg.ID, strings.Join(names, ", "))
+}
+
+// validBlobHash reports whether hash is a lowercase hex SHA-256 digest.
+func validBlobHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, r := range hash {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func detectMime(name string, data []byte) string {
+	detected := mime.TypeByExtension(filepath.Ext(name))
+	if detected == "" {
+		detected = http.DetectContentType(data)
+	}
+	if mediaType, _, err := mime.ParseMediaType(detected); err == nil {
+		return mediaType
+	}
+	return detected
+}
+
+func encodeAttachments(attachments []types.Attachment) any {
+	if len(attachments) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(attachments)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+func decodeAttachments(value sql.NullString) []types.Attachment {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	var attachments []types.Attachment
+	if err := json.Unmarshal([]byte(value.String), &attachments); err != nil {
+		return nil
+	}
+	return attachments
+}
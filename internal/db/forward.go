@@ -0,0 +1,210 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// ChannelTarget is a channel from the global config that messages can be
+// forwarded into.
+type ChannelTarget struct {
+	ID      string
+	Name    string
+	Project core.Project
+}
+
+// ForwardInput describes a linked copy of a message for another channel.
+type ForwardInput struct {
+	SourceChannel string        // channel name of the original
+	Source        types.Message // original message
+	FromAgent     string        // sender of the copy; defaults to Source.FromAgent
+	Body          string        // body of the copy
+	Type          types.MessageType
+	Mentions      []string // agents to mention in addition to those in Body
+}
+
+// ForwardedMessage is a copy delivered into another channel.
+type ForwardedMessage struct {
+	Channel string        `json:"channel"`
+	Message types.Message `json:"message"`
+}
+
+// ResolveChannelTarget finds a channel by ID or name in the global config.
+func ResolveChannelTarget(ref string) (*ChannelTarget, error) {
+	config, err := core.ReadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	id, channel, ok := core.FindChannelByRef(ref, config)
+	if !ok && config != nil {
+		for channelID, candidate := range config.Channels {
+			if strings.EqualFold(candidate.Name, ref) {
+				id, channel, ok = channelID, candidate, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("channel not found: %s", ref)
+	}
+
+	frayDir := filepath.Join(channel.Path, ".fray")
+	if info, err := os.Stat(frayDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("channel %s has no .fray directory at %s", channel.Name, channel.Path)
+	}
+	return &ChannelTarget{
+		ID:      id,
+		Name:    channel.Name,
+		Project: core.Project{Root: channel.Path, DBPath: filepath.Join(frayDir, "fray.db")},
+	}, nil
+}
+
+// ForwardMessage posts a linked copy into the target channel's room. The copy
+// is from "<source channel>:<agent>" and references the original as
+// "<source channel>:<msg-id>".
+func ForwardMessage(target *ChannelTarget, in ForwardInput) (types.Message, error) {
+	conn, err := OpenDatabase(target.Project)
+	if err != nil {
+		return types.Message{}, err
+	}
+	defer conn.Close()
+	if err := InitSchema(conn); err != nil {
+		return types.Message{}, err
+	}
+
+	bases, err := GetAgentBases(conn)
+	if err != nil {
+		return types.Message{}, err
+	}
+	users, _ := GetActiveUsers(conn)
+	for _, user := range users {
+		bases[user] = struct{}{}
+	}
+	mentions := core.ExtractMentions(in.Body, bases)
+	for _, agent := range in.Mentions {
+		if !containsString(mentions, agent) {
+			mentions = append(mentions, agent)
+		}
+	}
+
+	msgType := in.Type
+	if msgType == "" {
+		msgType = types.MessageTypeAgent
+	}
+	from := in.FromAgent
+	if from == "" {
+		from = in.Source.FromAgent
+	}
+	reference := in.SourceChannel + ":" + in.Source.ID
+	created, err := CreateMessage(conn, types.Message{
+		TS:         time.Now().Unix(),
+		FromAgent:  in.SourceChannel + ":" + from,
+		Body:       in.Body,
+		Mentions:   mentions,
+		Type:       msgType,
+		References: &reference,
+		Home:       "room",
+	})
+	if err != nil {
+		return types.Message{}, err
+	}
+	if err := AppendMessage(target.Project.DBPath, created); err != nil {
+		return types.Message{}, err
+	}
+	return created, nil
+}
+
+// ForwardChannelMentions delivers a linked copy of msg into each other channel
+// named by an @channel:agent mention, with those mentions rewritten to plain
+// @agent. Only channels in the global config are forwarded to. Copies that were delivered are returned even when others fail.
+func ForwardChannelMentions(projectPath string, msg types.Message) ([]ForwardedMessage, error) {
+	config, err := core.ReadGlobalConfig()
+	if err != nil || config == nil {
+		return nil, err
+	}
+	var channels []string
+	for channelID, channel := range config.Channels {
+		channels = append(channels, channelID, channel.Name)
+	}
+	channelMentions := core.ExtractChannelMentions(msg.Body, channels)
+	if len(channelMentions) == 0 {
+		return nil, nil
+	}
+
+	sourceName, sourceID := LocalChannel(projectPath)
+
+	// Group agents by channel (names match case-insensitively), keeping
+	// first-mention order
+	var order []string
+	agentsByChannel := map[string][]string{}
+	for _, mention := range channelMentions {
+		if strings.EqualFold(mention.Channel, sourceName) || mention.Channel == sourceID {
+			continue
+		}
+		key := strings.ToLower(mention.Channel)
+		if _, ok := agentsByChannel[key]; !ok {
+			order = append(order, mention.Channel)
+		}
+		agentsByChannel[key] = append(agentsByChannel[key], mention.Agent)
+	}
+
+	var forwarded []ForwardedMessage
+	var errs []error
+	for _, channel := range order {
+		target, err := ResolveChannelTarget(channel)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if target.ID == sourceID || target.SameChannel(projectPath) {
+			continue
+		}
+		copyMsg, err := ForwardMessage(target, ForwardInput{
+			SourceChannel: sourceName,
+			Source:        msg,
+			Body:          regexp.MustCompile(`(?i)@`+regexp.QuoteMeta(channel)+`:`).ReplaceAllLiteralString(msg.Body, "@"),
+			Mentions:      agentsByChannel[strings.ToLower(channel)],
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("forward to %s: %w", target.Name, err))
+			continue
+		}
+		forwarded = append(forwarded, ForwardedMessage{Channel: target.Name, Message: copyMsg})
+	}
+	return forwarded, errors.Join(errs...)
+}
+
+// LocalChannel returns the channel name and ID of the project at projectPath.
+// The name falls back to the project directory when no channel is configured.
+func LocalChannel(projectPath string) (name, id string) {
+	name = filepath.Base(filepath.Dir(resolveFrayDir(projectPath)))
+	if config, err := ReadProjectConfig(projectPath); err == nil && config != nil {
+		if config.ChannelName != "" {
+			name = config.ChannelName
+		}
+		id = config.ChannelID
+	}
+	return name, id
+}
+
+// SameChannel reports whether target is the project at projectPath.
+func (target *ChannelTarget) SameChannel(projectPath string) bool {
+	return filepath.Clean(resolveFrayDir(target.Project.DBPath)) == filepath.Clean(resolveFrayDir(projectPath))
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"crypto/ed25519"
+	"database/sql"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// Public keys are registered per known agent in fray-config.json and mirrored
+// into fray_config as public_key:<agent_id> so posts can be checked without
+// reading the config file. The cached value lists the current key first, then
+// any retired ones, separated by spaces.
+const publicKeyConfigPrefix = "public_key:"
+
+// GetAgentPublicKeys returns an agent's registered public keys, current
+// first, or nil when it has none.
+func GetAgentPublicKeys(db *sql.DB, agentID string) ([]ed25519.PublicKey, error) {
+	value, err := GetConfig(db, publicKeyConfigPrefix+agentID)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var keys []ed25519.PublicKey
+	for _, field := range strings.Fields(value) {
+		key, err := core.ParsePublicKey(field)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// RegisterAgentPublicKey records an agent's public key under its known-agent
+// entry and in the cache. A different key already registered is retired
+// rather than dropped, so messages signed with it still verify.
+func RegisterAgentPublicKey(db *sql.DB, projectPath, agentGUID, agentID string, key ed25519.PublicKey) error {
+	encoded := core.EncodePublicKey(key)
+	var retired []string
+	config, err := ReadProjectConfig(projectPath)
+	if err != nil {
+		return err
+	}
+	if config != nil {
+		if entry, ok := config.KnownAgents[agentGUID]; ok {
+			retired = entry.RetiredKeys
+			if entry.PublicKey != nil && *entry.PublicKey != encoded && !slices.Contains(retired, *entry.PublicKey) {
+				retired = append(retired, *entry.PublicKey)
+			}
+		}
+	}
+	retired = slices.DeleteFunc(slices.Clone(retired), func(value string) bool { return value == encoded })
+
+	name := agentID
+	if _, err := UpdateProjectConfig(projectPath, ProjectConfig{
+		KnownAgents: map[string]ProjectKnownAgent{agentGUID: {Name: &name, PublicKey: &encoded, RetiredKeys: retired}},
+	}); err != nil {
+		return err
+	}
+	return SetConfig(db, publicKeyConfigPrefix+agentID, strings.Join(append([]string{encoded}, retired...), " "))
+}
+
+// ReadAgentPublicKeys returns registered public keys by agent ID, current
+// first.
+func ReadAgentPublicKeys(projectPath string) (map[string][]ed25519.PublicKey, error) {
+	config, err := ReadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := ReadAgents(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return publicKeysFromConfig(config, agents), nil
+}
+
+func publicKeysFromConfig(config *ProjectConfig, agents []AgentJSONLRecord) map[string][]ed25519.PublicKey {
+	keys := map[string][]ed25519.PublicKey{}
+	if config == nil {
+		return keys
+	}
+	agentIDs := make(map[string]string, len(agents))
+	for _, agent := range agents {
+		agentIDs[agent.ID] = agent.AgentID
+	}
+	for guid, entry := range config.KnownAgents {
+		if entry.PublicKey == nil {
+			continue
+		}
+		agentID := agentIDs[guid]
+		if agentID == "" && entry.Name != nil {
+			agentID = *entry.Name
+		}
+		if agentID == "" {
+			continue
+		}
+		for _, value := range append([]string{*entry.PublicKey}, entry.RetiredKeys...) {
+			if key, err := core.ParsePublicKey(value); err == nil {
+				keys[agentID] = append(keys[agentID], key)
+			}
+		}
+	}
+	return keys
+}
+
+func encodePublicKeys(keys []ed25519.PublicKey) string {
+	encoded := make([]string, 0, len(keys))
+	for _, key := range keys {
+		encoded = append(encoded, core.EncodePublicKey(key))
+	}
+	return strings.Join(encoded, " ")
+}
+
+// signMessageBody signs a body with the author's local key and reports how the
+// result verifies against the registered key. It only signs when the author is
+// the acting identity (FRAY_AGENT_ID, else the stored username), so "verified"
+// means the post came from a session running as the key holder, not merely
+// from a machine that has the key and was passed --as.
+func signMessageBody(db *sql.DB, messageID, fromAgent, body string) (*string, types.SignatureStatus, error) {
+	publicKeys, err := GetAgentPublicKeys(db, fromAgent)
+	if err != nil {
+		return nil, "", err
+	}
+	var privateKey ed25519.PrivateKey
+	if actingIdentity(db) == fromAgent {
+		privateKey, err = core.LoadAgentKey(fromAgent)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	var signature *string
+	if privateKey != nil {
+		value := core.SignMessage(privateKey, messageID, fromAgent, body)
+		signature = &value
+	}
+	return signature, core.VerifyMessage(publicKeys, messageID, fromAgent, body, signature), nil
+}
+
+// actingIdentity returns who is running this process: FRAY_AGENT_ID when set,
+// else the project's stored username.
+func actingIdentity(db *sql.DB) string {
+	if agentID := os.Getenv("FRAY_AGENT_ID"); agentID != "" {
+		return agentID
+	}
+	username, _ := GetConfig(db, "username")
+	return username
+}
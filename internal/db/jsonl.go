@@ -1,12 +1,19 @@
 package db
 
-import "github.com/adamavenir/fray/internal/types"
+import (
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
 
 const (
 	messagesFile      = "messages.jsonl"
+	historyFile       = "history.jsonl"
 	agentsFile        = "agents.jsonl"
 	questionsFile     = "questions.jsonl"
 	threadsFile       = "threads.jsonl"
+	scheduledFile     = "scheduled.jsonl"
+	auditFile         = "audit.jsonl"
+	tasksFile         = "tasks.jsonl"
 	projectConfigFile = "fray-config.json"
 )
 
@@ -28,6 +35,8 @@ type MessageJSONLRecord struct {
 	TS               int64               `json:"ts"`
 	EditedAt         *int64              `json:"edited_at"`
 	ArchivedAt       *int64              `json:"archived_at"`
+	Attachments      []types.Attachment  `json:"attachments,omitempty"`
+	Signature        *string             `json:"signature,omitempty"` // author's ed25519 signature over id, from_agent, body
 }
 
 // MessageUpdateJSONLRecord represents a message update entry in JSONL.
@@ -39,6 +48,8 @@ type MessageUpdateJSONLRecord struct {
 	ArchivedAt *int64               `json:"archived_at,omitempty"`
 	Reactions  *map[string][]string `json:"reactions,omitempty"`
 	Reason     *string              `json:"reason,omitempty"`
+	Signature  *string              `json:"signature,omitempty"` // author's signature over the new body
+	EditedBy   *string              `json:"edited_by,omitempty"` // curator who edited another agent's message
 }
 
 // QuestionJSONLRecord represents a question entry in JSONL.
@@ -67,6 +78,36 @@ type QuestionUpdateJSONLRecord struct {
 	AnsweredIn *string `json:"answered_in,omitempty"`
 }
 
+// TaskJSONLRecord represents a task entry in JSONL.
+type TaskJSONLRecord struct {
+	Type          string   `json:"type"`
+	GUID          string   `json:"guid"`
+	Title         string   `json:"title"`
+	CreatedBy     string   `json:"created_by"`
+	Assignee      *string  `json:"assignee,omitempty"`
+	Status        string   `json:"status"`
+	ThreadGUID    *string  `json:"thread_guid,omitempty"`
+	MessageGUID   *string  `json:"message_guid,omitempty"`
+	DependsOn     []string `json:"depends_on,omitempty"`
+	BlockedReason *string  `json:"blocked_reason,omitempty"`
+	CreatedAt     int64    `json:"created_at"`
+	UpdatedAt     int64    `json:"updated_at,omitempty"`
+}
+
+// TaskUpdateJSONLRecord represents a task update entry in JSONL. DependsOn
+// replaces the whole dependency list; an empty string clears a pointer field.
+type TaskUpdateJSONLRecord struct {
+	Type          string    `json:"type"`
+	GUID          string    `json:"guid"`
+	Status        *string   `json:"status,omitempty"`
+	Assignee      *string   `json:"assignee,omitempty"`
+	ThreadGUID    *string   `json:"thread_guid,omitempty"`
+	MessageGUID   *string   `json:"message_guid,omitempty"`
+	DependsOn     *[]string `json:"depends_on,omitempty"`
+	BlockedReason *string   `json:"blocked_reason,omitempty"`
+	UpdatedAt     int64     `json:"updated_at"`
+}
+
 // ThreadJSONLRecord represents a thread entry in JSONL.
 type ThreadJSONLRecord struct {
 	Type              string   `json:"type"`
@@ -80,19 +121,27 @@ type ThreadJSONLRecord struct {
 	AnchorMessageGUID *string  `json:"anchor_message_guid,omitempty"`
 	AnchorHidden      bool     `json:"anchor_hidden,omitempty"`
 	LastActivityAt    *int64   `json:"last_activity_at,omitempty"`
+	OwnerAgent        *string  `json:"owner_agent,omitempty"`
+	Writers           []string `json:"writers,omitempty"`
+	Readers           []string `json:"readers,omitempty"`
+	Curators          []string `json:"curators,omitempty"`
 }
 
 // ThreadUpdateJSONLRecord represents a thread update entry in JSONL.
 type ThreadUpdateJSONLRecord struct {
-	Type              string  `json:"type"`
-	GUID              string  `json:"guid"`
-	Name              *string `json:"name,omitempty"`
-	Status            *string `json:"status,omitempty"`
-	ThreadType        *string `json:"thread_type,omitempty"`
-	ParentThread      *string `json:"parent_thread,omitempty"`
-	AnchorMessageGUID *string `json:"anchor_message_guid,omitempty"`
-	AnchorHidden      *bool   `json:"anchor_hidden,omitempty"`
-	LastActivityAt    *int64  `json:"last_activity_at,omitempty"`
+	Type              string    `json:"type"`
+	GUID              string    `json:"guid"`
+	Name              *string   `json:"name,omitempty"`
+	Status            *string   `json:"status,omitempty"`
+	ThreadType        *string   `json:"thread_type,omitempty"`
+	ParentThread      *string   `json:"parent_thread,omitempty"`
+	AnchorMessageGUID *string   `json:"anchor_message_guid,omitempty"`
+	AnchorHidden      *bool     `json:"anchor_hidden,omitempty"`
+	LastActivityAt    *int64    `json:"last_activity_at,omitempty"`
+	OwnerAgent        *string   `json:"owner_agent,omitempty"`
+	Writers           *[]string `json:"writers,omitempty"`
+	Readers           *[]string `json:"readers,omitempty"`
+	Curators          *[]string `json:"curators,omitempty"`
 }
 
 // ThreadSubscribeJSONLRecord represents a subscription event.
@@ -270,6 +319,68 @@ type GhostCursorJSONLRecord struct {
 	SetAt       int64  `json:"set_at"`
 }
 
+// HandoffJSONLRecord represents an agent handoff event in JSONL.
+// One record captures every part of the handoff so it can be audited and replayed.
+type HandoffJSONLRecord struct {
+	Type              string   `json:"type"` // "agent_handoff"
+	GUID              string   `json:"guid"`
+	FromAgent         string   `json:"from_agent"`
+	ToAgent           string   `json:"to_agent"`
+	Home              string   `json:"home"`
+	MessageGUID       string   `json:"message_guid"`
+	Summary           string   `json:"summary,omitempty"`
+	Claims            []string `json:"claims,omitempty"`
+	CursorMessageGUID *string  `json:"cursor_message_guid,omitempty"`
+	OwnerTransferred  bool     `json:"owner_transferred,omitempty"`
+	CreatedAt         int64    `json:"created_at"`
+}
+
+// ThreadSummaryJSONLRecord represents a generated thread summary in JSONL.
+type ThreadSummaryJSONLRecord struct {
+	Type             string `json:"type"` // "thread_summary"
+	GUID             string `json:"guid"`
+	ThreadGUID       string `json:"thread_guid"`
+	MessageGUID      string `json:"message_guid"`
+	Body             string `json:"body"`
+	Source           string `json:"source"`
+	Summarizer       string `json:"summarizer"`
+	Trigger          string `json:"trigger"`
+	FirstMessageGUID string `json:"first_message_guid"`
+	LastMessageGUID  string `json:"last_message_guid"`
+	MessageCount     int    `json:"message_count"`
+	FromTS           int64  `json:"from_ts"`
+	ToTS             int64  `json:"to_ts"`
+	CreatedAt        int64  `json:"created_at"`
+}
+
+// ScheduledMessageJSONLRecord represents a pending scheduled message in JSONL.
+type ScheduledMessageJSONLRecord struct {
+	Type      string            `json:"type"` // "scheduled_message"
+	GUID      string            `json:"guid"`
+	FromAgent string            `json:"from_agent"`
+	Home      string            `json:"home"`
+	Body      string            `json:"body"`
+	MsgType   types.MessageType `json:"message_type"`
+	SendAt    int64             `json:"send_at"`
+	CreatedAt int64             `json:"created_at"`
+}
+
+// ScheduledCancelJSONLRecord represents a cancelled scheduled message in JSONL.
+type ScheduledCancelJSONLRecord struct {
+	Type        string `json:"type"` // "scheduled_cancel"
+	GUID        string `json:"guid"`
+	CancelledBy string `json:"cancelled_by,omitempty"`
+	CancelledAt int64  `json:"cancelled_at"`
+}
+
+// ScheduledSentJSONLRecord represents a delivered scheduled message in JSONL.
+type ScheduledSentJSONLRecord struct {
+	Type        string `json:"type"` // "scheduled_sent"
+	GUID        string `json:"guid"`
+	MessageGUID string `json:"message_guid"`
+	SentAt      int64  `json:"sent_at"`
+}
+
 // ReactionJSONLRecord represents a reaction event in JSONL.
 type ReactionJSONLRecord struct {
 	Type        string `json:"type"` // "reaction"
@@ -330,6 +441,18 @@ type RoleStopJSONLRecord struct {
 	StoppedAt int64  `json:"stopped_at"`
 }
 
+// AuditJSONLRecord records one administrative action in audit.jsonl.
+type AuditJSONLRecord struct {
+	Type   string         `json:"type"` // "audit"
+	ID     string         `json:"id"`
+	Actor  string         `json:"actor"`
+	Action string         `json:"action"` // e.g. "agent.rename", "thread.archive", "message.delete"
+	Target string         `json:"target"`
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+	TS     int64          `json:"ts"`
+}
+
 // ProjectKnownAgent stores per-project known-agent data.
 type ProjectKnownAgent struct {
 	Name        *string  `json:"name,omitempty"`
@@ -339,6 +462,19 @@ type ProjectKnownAgent struct {
 	FirstSeen   *string  `json:"first_seen,omitempty"`
 	Status      *string  `json:"status,omitempty"`
 	Nicks       []string `json:"nicks,omitempty"`
+	PublicKey   *string  `json:"public_key,omitempty"` // "ed25519:<base64>" for signed messages
+	// RetiredKeys are public keys replaced by --rotate, kept so messages
+	// signed before the rotation still verify.
+	RetiredKeys []string `json:"retired_keys,omitempty"`
+}
+
+// SummarizerConfig configures how thread summaries are generated on archive
+// and prune. Command runs through the shell with the transcript on stdin;
+// Agent runs a managed agent's driver once. Command wins if both are set.
+type SummarizerConfig struct {
+	Command string `json:"command,omitempty"`
+	Agent   string `json:"agent,omitempty"`
+	Timeout int    `json:"timeout,omitempty"` // seconds, default 120
 }
 
 // ProjectConfig represents the per-project config file.
@@ -348,4 +484,7 @@ type ProjectConfig struct {
 	ChannelName string                       `json:"channel_name,omitempty"`
 	CreatedAt   string                       `json:"created_at,omitempty"`
 	KnownAgents map[string]ProjectKnownAgent `json:"known_agents,omitempty"`
+	Reactions   map[string]string            `json:"reactions,omitempty"` // reaction -> meaning, overriding core.DefaultReactionMeanings
+	Summarizer  *SummarizerConfig            `json:"summarizer,omitempty"`
+	Retention   *core.RetentionConfig        `json:"retention,omitempty"` // rules for fray prune --policy
 }
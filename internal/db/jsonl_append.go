@@ -20,6 +20,12 @@ func appendJSONLine(filePath string, record any) error {
 		return err
 	}
 
+	unlock, err := lockForAppend(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
 		return err
@@ -60,6 +66,8 @@ func AppendMessage(projectPath string, message types.Message) error {
 		TS:               message.TS,
 		EditedAt:         message.EditedAt,
 		ArchivedAt:       message.ArchivedAt,
+		Attachments:      message.Attachments,
+		Signature:        message.Signature,
 	}
 
 	if err := appendJSONLine(filepath.Join(frayDir, messagesFile), record); err != nil {
@@ -237,6 +245,41 @@ func AppendQuestionUpdate(projectPath string, update QuestionUpdateJSONLRecord)
 	return nil
 }
 
+// AppendTask appends a task record to JSONL.
+func AppendTask(projectPath string, task types.Task) error {
+	frayDir := resolveFrayDir(projectPath)
+	record := TaskJSONLRecord{
+		Type:          "task",
+		GUID:          task.GUID,
+		Title:         task.Title,
+		CreatedBy:     task.CreatedBy,
+		Assignee:      task.Assignee,
+		Status:        string(task.Status),
+		ThreadGUID:    task.ThreadGUID,
+		MessageGUID:   task.MessageGUID,
+		DependsOn:     task.DependsOn,
+		BlockedReason: task.BlockedReason,
+		CreatedAt:     task.CreatedAt,
+		UpdatedAt:     task.UpdatedAt,
+	}
+	if err := appendJSONLine(filepath.Join(frayDir, tasksFile), record); err != nil {
+		return err
+	}
+	touchDatabaseFile(projectPath)
+	return nil
+}
+
+// AppendTaskUpdate appends a task update record to JSONL.
+func AppendTaskUpdate(projectPath string, update TaskUpdateJSONLRecord) error {
+	frayDir := resolveFrayDir(projectPath)
+	update.Type = "task_update"
+	if err := appendJSONLine(filepath.Join(frayDir, tasksFile), update); err != nil {
+		return err
+	}
+	touchDatabaseFile(projectPath)
+	return nil
+}
+
 // AppendThread appends a thread record to JSONL.
 func AppendThread(projectPath string, thread types.Thread, subscribed []string) error {
 	frayDir := resolveFrayDir(projectPath)
@@ -252,6 +295,10 @@ func AppendThread(projectPath string, thread types.Thread, subscribed []string)
 		AnchorMessageGUID: thread.AnchorMessageGUID,
 		AnchorHidden:      thread.AnchorHidden,
 		LastActivityAt:    thread.LastActivityAt,
+		OwnerAgent:        thread.OwnerAgent,
+		Writers:           thread.Writers,
+		Readers:           thread.Readers,
+		Curators:          thread.Curators,
 	}
 	if err := appendJSONLine(filepath.Join(frayDir, threadsFile), record); err != nil {
 		return err
@@ -410,6 +457,55 @@ func AppendGhostCursor(projectPath string, cursor types.GhostCursor) error {
 	return nil
 }
 
+// AppendHandoff appends an agent handoff event to JSONL.
+func AppendHandoff(projectPath string, handoff types.Handoff) error {
+	frayDir := resolveFrayDir(projectPath)
+	record := HandoffJSONLRecord{
+		Type:              "agent_handoff",
+		GUID:              handoff.GUID,
+		FromAgent:         handoff.FromAgent,
+		ToAgent:           handoff.ToAgent,
+		Home:              handoff.Home,
+		MessageGUID:       handoff.MessageGUID,
+		Summary:           handoff.Summary,
+		Claims:            handoff.Claims,
+		CursorMessageGUID: handoff.CursorMessageGUID,
+		OwnerTransferred:  handoff.OwnerTransferred,
+		CreatedAt:         handoff.CreatedAt,
+	}
+	if err := appendJSONLine(filepath.Join(frayDir, agentsFile), record); err != nil {
+		return err
+	}
+	touchDatabaseFile(projectPath)
+	return nil
+}
+
+// AppendThreadSummary appends a generated thread summary to JSONL.
+func AppendThreadSummary(projectPath string, summary types.ThreadSummary) error {
+	frayDir := resolveFrayDir(projectPath)
+	record := ThreadSummaryJSONLRecord{
+		Type:             "thread_summary",
+		GUID:             summary.GUID,
+		ThreadGUID:       summary.ThreadGUID,
+		MessageGUID:      summary.MessageGUID,
+		Body:             summary.Body,
+		Source:           summary.Source,
+		Summarizer:       summary.Summarizer,
+		Trigger:          summary.Trigger,
+		FirstMessageGUID: summary.FirstMessageGUID,
+		LastMessageGUID:  summary.LastMessageGUID,
+		MessageCount:     summary.MessageCount,
+		FromTS:           summary.FromTS,
+		ToTS:             summary.ToTS,
+		CreatedAt:        summary.CreatedAt,
+	}
+	if err := appendJSONLine(filepath.Join(frayDir, threadsFile), record); err != nil {
+		return err
+	}
+	touchDatabaseFile(projectPath)
+	return nil
+}
+
 // AppendReaction appends a reaction record to JSONL.
 func AppendReaction(projectPath, messageGUID, agentID, emoji string, reactedAt int64) error {
 	frayDir := resolveFrayDir(projectPath)
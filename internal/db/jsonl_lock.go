@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// jsonlLockFile serializes appends with whole-file rewrites. Appends hold it
+// shared, so they never wait on each other; prune and restore hold it
+// exclusively while they read, rewrite and re-cache the JSONL files, so an
+// append from another process can't land in a file that is being replaced.
+const jsonlLockFile = "jsonl.lock"
+
+var (
+	rewriteMu   sync.Mutex
+	rewriteHeld = map[string]int{} // fray dir -> rewrite locks held by this process
+)
+
+// LockJSONLForRewrite takes the exclusive JSONL lock for a project, waiting
+// for in-flight appends, and returns its release. Appends from this process
+// go ahead while it is held.
+func LockJSONLForRewrite(projectPath string) (func(), error) {
+	frayDir := filepath.Clean(resolveFrayDir(projectPath))
+	if err := ensureDir(frayDir); err != nil {
+		return nil, err
+	}
+	release, err := lockFile(filepath.Join(frayDir, jsonlLockFile), true)
+	if err != nil {
+		return nil, fmt.Errorf("lock %s: %w", jsonlLockFile, err)
+	}
+	rewriteMu.Lock()
+	rewriteHeld[frayDir]++
+	rewriteMu.Unlock()
+	return func() {
+		rewriteMu.Lock()
+		if rewriteHeld[frayDir]--; rewriteHeld[frayDir] <= 0 {
+			delete(rewriteHeld, frayDir)
+		}
+		rewriteMu.Unlock()
+		release()
+	}, nil
+}
+
+// lockForAppend takes the shared JSONL lock for the directory holding
+// filePath, unless this process is rewriting that directory.
+func lockForAppend(filePath string) (func(), error) {
+	dir := filepath.Clean(filepath.Dir(filePath))
+	rewriteMu.Lock()
+	held := rewriteHeld[dir] > 0
+	rewriteMu.Unlock()
+	if held {
+		return func() {}, nil
+	}
+	return lockFile(filepath.Join(dir, jsonlLockFile), false)
+}
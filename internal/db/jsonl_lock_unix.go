@@ -0,0 +1,29 @@
+//go:build !windows
+
+package db
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an flock on path, exclusive or shared. The lock goes away
+// with the process, so a crashed writer never leaves it behind.
+func lockFile(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
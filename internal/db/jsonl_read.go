@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
 )
 
@@ -60,8 +61,18 @@ func readJSONLFile[T any](filePath string) ([]T, error) {
 
 // ReadMessages reads message records and applies updates.
 func ReadMessages(projectPath string) ([]MessageJSONLRecord, error) {
-	frayDir := resolveFrayDir(projectPath)
-	lines, err := readJSONLLines(filepath.Join(frayDir, messagesFile))
+	return readMessageRecords(filepath.Join(resolveFrayDir(projectPath), messagesFile))
+}
+
+// ReadHistoryMessages reads the messages prune archived to history.jsonl.
+// Every prune appends a full copy of messages.jsonl, so the latest copy of a
+// message wins.
+func ReadHistoryMessages(projectPath string) ([]MessageJSONLRecord, error) {
+	return readMessageRecords(filepath.Join(resolveFrayDir(projectPath), historyFile))
+}
+
+func readMessageRecords(path string) ([]MessageJSONLRecord, error) {
+	lines, err := readJSONLLines(path)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +110,7 @@ func ReadMessages(projectPath string) ([]MessageJSONLRecord, error) {
 				EditedAt   json.RawMessage `json:"edited_at"`
 				ArchivedAt json.RawMessage `json:"archived_at"`
 				Reactions  json.RawMessage `json:"reactions"`
+				Signature  *string         `json:"signature"`
 			}
 			if err := json.Unmarshal([]byte(line), &update); err != nil {
 				continue
@@ -111,6 +123,8 @@ func ReadMessages(projectPath string) ([]MessageJSONLRecord, error) {
 				var body string
 				if err := json.Unmarshal(update.Body, &body); err == nil {
 					existing.Body = body
+					// A signature covers one body, so a new body replaces it
+					existing.Signature = update.Signature
 				}
 			}
 			if update.EditedAt != nil {
@@ -235,6 +249,7 @@ func GetMessageVersions(projectPath string, messageID string) (*types.MessageVer
 		body      string
 		timestamp *int64
 		reason    string
+		signature *string
 		seq       int
 	}
 
@@ -272,6 +287,7 @@ func GetMessageVersions(projectPath string, messageID string) (*types.MessageVer
 				EditedAt   json.RawMessage `json:"edited_at"`
 				ArchivedAt json.RawMessage `json:"archived_at"`
 				Reason     json.RawMessage `json:"reason"`
+				Signature  *string         `json:"signature"`
 			}
 			if err := json.Unmarshal([]byte(line), &update); err != nil {
 				continue
@@ -305,7 +321,7 @@ func GetMessageVersions(projectPath string, messageID string) (*types.MessageVer
 					if update.Reason != nil && string(update.Reason) != "null" {
 						_ = json.Unmarshal(update.Reason, &reason)
 					}
-					updates = append(updates, versionUpdate{body: body, timestamp: editedAt, reason: reason, seq: seq})
+					updates = append(updates, versionUpdate{body: body, timestamp: editedAt, reason: reason, signature: update.Signature, seq: seq})
 					seq++
 				}
 			}
@@ -316,6 +332,12 @@ func GetMessageVersions(projectPath string, messageID string) (*types.MessageVer
 		return nil, fmt.Errorf("message not found: %s", messageID)
 	}
 
+	publicKeys, err := ReadAgentPublicKeys(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := publicKeys[original.FromAgent]
+
 	for i := range updates {
 		if updates[i].timestamp == nil {
 			value := original.TS
@@ -332,18 +354,20 @@ func GetMessageVersions(projectPath string, messageID string) (*types.MessageVer
 
 	versions := make([]types.MessageVersion, 0, len(updates)+1)
 	versions = append(versions, types.MessageVersion{
-		Version:    1,
-		Body:       original.Body,
-		Timestamp:  original.TS,
-		IsOriginal: true,
+		Version:         1,
+		Body:            original.Body,
+		Timestamp:       original.TS,
+		IsOriginal:      true,
+		SignatureStatus: core.VerifyMessage(publicKey, original.ID, original.FromAgent, original.Body, original.Signature),
 	})
 
 	for i, update := range updates {
 		versions = append(versions, types.MessageVersion{
-			Version:   i + 2,
-			Body:      update.body,
-			Timestamp: *update.timestamp,
-			Reason:    update.reason,
+			Version:         i + 2,
+			Body:            update.body,
+			Timestamp:       *update.timestamp,
+			Reason:          update.reason,
+			SignatureStatus: core.VerifyMessage(publicKey, original.ID, original.FromAgent, update.body, update.signature),
 		})
 	}
 
@@ -451,6 +475,87 @@ func ApplyMessageEditCounts(projectPath string, messages []types.Message) ([]typ
 	return messages, nil
 }
 
+// ReadTasks reads task records and applies updates.
+func ReadTasks(projectPath string) ([]TaskJSONLRecord, error) {
+	frayDir := resolveFrayDir(projectPath)
+	lines, err := readJSONLLines(filepath.Join(frayDir, tasksFile))
+	if err != nil {
+		return nil, err
+	}
+
+	taskMap := make(map[string]TaskJSONLRecord)
+	order := make([]string, 0)
+
+	for _, line := range lines {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			continue
+		}
+
+		switch envelope.Type {
+		case "task":
+			var record TaskJSONLRecord
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				continue
+			}
+			if record.Status == "" {
+				record.Status = string(types.TaskStatusTodo)
+			}
+			if _, ok := taskMap[record.GUID]; !ok {
+				order = append(order, record.GUID)
+			}
+			taskMap[record.GUID] = record
+		case "task_update":
+			var update TaskUpdateJSONLRecord
+			if err := json.Unmarshal([]byte(line), &update); err != nil {
+				continue
+			}
+			existing, ok := taskMap[update.GUID]
+			if !ok {
+				continue
+			}
+			if update.Status != nil {
+				existing.Status = *update.Status
+			}
+			if update.Assignee != nil {
+				existing.Assignee = clearableString(update.Assignee)
+			}
+			if update.ThreadGUID != nil {
+				existing.ThreadGUID = clearableString(update.ThreadGUID)
+			}
+			if update.MessageGUID != nil {
+				existing.MessageGUID = clearableString(update.MessageGUID)
+			}
+			if update.BlockedReason != nil {
+				existing.BlockedReason = clearableString(update.BlockedReason)
+			}
+			if update.DependsOn != nil {
+				existing.DependsOn = *update.DependsOn
+			}
+			if update.UpdatedAt > existing.UpdatedAt {
+				existing.UpdatedAt = update.UpdatedAt
+			}
+			taskMap[update.GUID] = existing
+		}
+	}
+
+	tasks := make([]TaskJSONLRecord, 0, len(order))
+	for _, guid := range order {
+		tasks = append(tasks, taskMap[guid])
+	}
+	return tasks, nil
+}
+
+// clearableString maps an empty update value to nil.
+func clearableString(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
 // ReadQuestions reads question records and applies updates.
 func ReadQuestions(projectPath string) ([]QuestionJSONLRecord, error) {
 	frayDir := resolveFrayDir(projectPath)
@@ -591,6 +696,22 @@ func ReadThreads(projectPath string) ([]ThreadJSONLRecord, []threadSubscriptionE
 			if update.LastActivityAt != nil {
 				existing.LastActivityAt = update.LastActivityAt
 			}
+			if update.OwnerAgent != nil {
+				if *update.OwnerAgent == "" {
+					existing.OwnerAgent = nil
+				} else {
+					existing.OwnerAgent = update.OwnerAgent
+				}
+			}
+			if update.Writers != nil {
+				existing.Writers = *update.Writers
+			}
+			if update.Readers != nil {
+				existing.Readers = *update.Readers
+			}
+			if update.Curators != nil {
+				existing.Curators = *update.Curators
+			}
 			threadMap[update.GUID] = existing
 		case "thread_subscribe":
 			var event ThreadSubscribeJSONLRecord
@@ -894,13 +1015,29 @@ func ReadGhostCursors(projectPath string) ([]GhostCursorJSONLRecord, error) {
 			continue
 		}
 
-		if envelope.Type == "ghost_cursor" {
+		switch envelope.Type {
+		case "ghost_cursor":
 			var record GhostCursorJSONLRecord
 			if err := json.Unmarshal([]byte(line), &record); err != nil {
 				continue
 			}
 			key := cursorKey{agentID: record.AgentID, home: record.Home}
 			cursorMap[key] = record
+		case "agent_handoff":
+			// Handoffs set a must-read cursor for the recipient in the same record.
+			var handoff HandoffJSONLRecord
+			if err := json.Unmarshal([]byte(line), &handoff); err != nil || handoff.CursorMessageGUID == nil {
+				continue
+			}
+			key := cursorKey{agentID: handoff.ToAgent, home: handoff.Home}
+			cursorMap[key] = GhostCursorJSONLRecord{
+				Type:        "ghost_cursor",
+				AgentID:     handoff.ToAgent,
+				Home:        handoff.Home,
+				MessageGUID: *handoff.CursorMessageGUID,
+				MustRead:    true,
+				SetAt:       handoff.CreatedAt * 1000,
+			}
 		}
 	}
 
@@ -911,10 +1048,75 @@ func ReadGhostCursors(projectPath string) ([]GhostCursorJSONLRecord, error) {
 	return cursors, nil
 }
 
+// ReadHandoffs reads agent handoff events from agents.jsonl in append order.
+func ReadHandoffs(projectPath string) ([]HandoffJSONLRecord, error) {
+	frayDir := resolveFrayDir(projectPath)
+	lines, err := readJSONLLines(filepath.Join(frayDir, agentsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var handoffs []HandoffJSONLRecord
+	for _, line := range lines {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			continue
+		}
+		if envelope.Type != "agent_handoff" {
+			continue
+		}
+		var record HandoffJSONLRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		handoffs = append(handoffs, record)
+	}
+	return handoffs, nil
+}
+
+// ReadThreadSummaries reads generated thread summaries from threads.jsonl.
+func ReadThreadSummaries(projectPath string) ([]ThreadSummaryJSONLRecord, error) {
+	frayDir := resolveFrayDir(projectPath)
+	lines, err := readJSONLLines(filepath.Join(frayDir, threadsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []ThreadSummaryJSONLRecord
+	for _, line := range lines {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			continue
+		}
+		if envelope.Type != "thread_summary" {
+			continue
+		}
+		var record ThreadSummaryJSONLRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		summaries = append(summaries, record)
+	}
+	return summaries, nil
+}
+
 // ReadReactions reads all reaction records from messages.jsonl.
 func ReadReactions(projectPath string) ([]ReactionJSONLRecord, error) {
-	frayDir := resolveFrayDir(projectPath)
-	lines, err := readJSONLLines(filepath.Join(frayDir, messagesFile))
+	return readReactionRecords(filepath.Join(resolveFrayDir(projectPath), messagesFile))
+}
+
+// ReadHistoryReactions reads the reaction records archived to history.jsonl.
+// Records repeat once per prune that archived them.
+func ReadHistoryReactions(projectPath string) ([]ReactionJSONLRecord, error) {
+	return readReactionRecords(filepath.Join(resolveFrayDir(projectPath), historyFile))
+}
+
+func readReactionRecords(path string) ([]ReactionJSONLRecord, error) {
+	lines, err := readJSONLLines(path)
 	if err != nil {
 		return nil, err
 	}
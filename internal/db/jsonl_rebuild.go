@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
 )
 
@@ -66,6 +67,26 @@ func UpdateProjectConfig(projectPath string, updates ProjectConfig) (*ProjectCon
 		existing.KnownAgents[id] = mergeKnownAgent(prior, agent)
 	}
 
+	for reaction, meaning := range updates.Reactions {
+		if existing.Reactions == nil {
+			existing.Reactions = map[string]string{}
+		}
+		existing.Reactions[reaction] = meaning
+	}
+
+	// An empty summarizer turns summaries off
+	if updates.Summarizer != nil {
+		if updates.Summarizer.Command == "" && updates.Summarizer.Agent == "" {
+			existing.Summarizer = nil
+		} else {
+			existing.Summarizer = updates.Summarizer
+		}
+	}
+
+	if updates.Retention != nil {
+		existing.Retention = updates.Retention
+	}
+
 	if updates.Version != 0 {
 		existing.Version = updates.Version
 	}
@@ -116,6 +137,12 @@ func mergeKnownAgent(existing, updates ProjectKnownAgent) ProjectKnownAgent {
 	if updates.Nicks != nil {
 		merged.Nicks = updates.Nicks
 	}
+	if updates.PublicKey != nil {
+		merged.PublicKey = updates.PublicKey
+	}
+	if updates.RetiredKeys != nil {
+		merged.RetiredKeys = updates.RetiredKeys
+	}
 	return merged
 }
 
@@ -143,6 +170,10 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 	if err != nil {
 		return err
 	}
+	auditEntries, err := ReadAudit(projectPath)
+	if err != nil {
+		return err
+	}
 	pinEvents, err := ReadMessagePins(projectPath)
 	if err != nil {
 		return err
@@ -159,6 +190,10 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 	if err != nil {
 		return err
 	}
+	tasks, err := ReadTasks(projectPath)
+	if err != nil {
+		return err
+	}
 	threads, subEvents, msgEvents, err := ReadThreads(projectPath)
 	if err != nil {
 		return err
@@ -171,6 +206,14 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 	if err != nil {
 		return err
 	}
+	handoffs, err := ReadHandoffs(projectPath)
+	if err != nil {
+		return err
+	}
+	threadSummaries, err := ReadThreadSummaries(projectPath)
+	if err != nil {
+		return err
+	}
 	reactions, err := ReadReactions(projectPath)
 	if err != nil {
 		return err
@@ -206,6 +249,9 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 	if _, err := db.Exec("DROP TABLE IF EXISTS fray_questions"); err != nil {
 		return err
 	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS fray_tasks"); err != nil {
+		return err
+	}
 	if _, err := db.Exec("DROP TABLE IF EXISTS fray_thread_messages"); err != nil {
 		return err
 	}
@@ -224,6 +270,12 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 	if _, err := db.Exec("DROP TABLE IF EXISTS fray_ghost_cursors"); err != nil {
 		return err
 	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS fray_handoffs"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS fray_thread_summaries"); err != nil {
+		return err
+	}
 	if _, err := db.Exec("DROP TABLE IF EXISTS fray_faves"); err != nil {
 		return err
 	}
@@ -237,6 +289,13 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 		return fmt.Errorf("initSchemaWith: %w", err)
 	}
 
+	publicKeys := publicKeysFromConfig(config, agents)
+	for agentID, keys := range publicKeys {
+		if _, err := db.Exec("INSERT OR REPLACE INTO fray_config (key, value) VALUES (?, ?)", publicKeyConfigPrefix+agentID, encodePublicKeys(keys)); err != nil {
+			return err
+		}
+	}
+
 	if config != nil && config.ChannelID != "" {
 		if _, err := db.Exec("INSERT OR REPLACE INTO fray_config (key, value) VALUES (?, ?)", "channel_id", config.ChannelID); err != nil {
 			return err
@@ -305,8 +364,8 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 
 	insertMessage := `
 		INSERT OR REPLACE INTO fray_messages (
-			guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions, attachments, signature, signature_status
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	for _, message := range messages {
@@ -328,6 +387,8 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 			home = "room"
 		}
 
+		signatureStatus := core.VerifyMessage(publicKeys[message.FromAgent], message.ID, message.FromAgent, message.Body, message.Signature)
+
 		if _, err := db.Exec(insertMessage,
 			message.ID,
 			message.TS,
@@ -344,6 +405,9 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 			message.EditedAt,
 			message.ArchivedAt,
 			string(reactionsJSON),
+			encodeAttachments(message.Attachments),
+			message.Signature,
+			nullableSignatureStatus(signatureStatus),
 		); err != nil {
 			return err
 		}
@@ -385,6 +449,38 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 		}
 	}
 
+	if len(tasks) > 0 {
+		insertTask := `
+			INSERT OR REPLACE INTO fray_tasks (` + taskColumns + `)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		for _, task := range tasks {
+			dependsJSON, err := encodeTaskDependencies(task.DependsOn)
+			if err != nil {
+				return err
+			}
+			updatedAt := task.UpdatedAt
+			if updatedAt == 0 {
+				updatedAt = task.CreatedAt
+			}
+			if _, err := db.Exec(insertTask,
+				task.GUID,
+				task.Title,
+				task.CreatedBy,
+				task.Assignee,
+				task.Status,
+				task.ThreadGUID,
+				task.MessageGUID,
+				dependsJSON,
+				task.BlockedReason,
+				task.CreatedAt,
+				updatedAt,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
 	if len(threads) > 0 {
 		// Topologically sort threads so parents are inserted before children
 		// (required for FK constraint on parent_thread)
@@ -392,8 +488,9 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 
 		insertThread := `
 			INSERT OR REPLACE INTO fray_threads (
-				guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent,
+				writers, readers, curators
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`
 		for i, thread := range threads {
 			status := thread.Status
@@ -418,6 +515,10 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 				thread.AnchorMessageGUID,
 				anchorHidden,
 				thread.LastActivityAt,
+				thread.OwnerAgent,
+				encodeAgentList(thread.Writers),
+				encodeAgentList(thread.Readers),
+				encodeAgentList(thread.Curators),
 			); err != nil {
 				parent := ""
 				if thread.ParentThread != nil {
@@ -611,6 +712,46 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 		}
 	}
 
+	// Rebuild handoffs; ownership transfers are thread_update records
+	for _, handoff := range handoffs {
+		claimsJSON, err := json.Marshal(handoff.Claims)
+		if err != nil {
+			return err
+		}
+		ownerTransferred := 0
+		if handoff.OwnerTransferred {
+			ownerTransferred = 1
+		}
+		if _, err := db.Exec(`
+			INSERT OR REPLACE INTO fray_handoffs (
+				guid, from_agent, to_agent, home, message_guid, summary, claims, cursor_message_guid, owner_transferred, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, handoff.GUID, handoff.FromAgent, handoff.ToAgent, handoff.Home, handoff.MessageGUID, handoff.Summary,
+			string(claimsJSON), handoff.CursorMessageGUID, ownerTransferred, handoff.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range threadSummaries {
+		if err := insertThreadSummary(db, types.ThreadSummary{
+			GUID:             record.GUID,
+			ThreadGUID:       record.ThreadGUID,
+			MessageGUID:      record.MessageGUID,
+			Body:             record.Body,
+			Source:           record.Source,
+			Summarizer:       record.Summarizer,
+			Trigger:          record.Trigger,
+			FirstMessageGUID: record.FirstMessageGUID,
+			LastMessageGUID:  record.LastMessageGUID,
+			MessageCount:     record.MessageCount,
+			FromTS:           record.FromTS,
+			ToTS:             record.ToTS,
+			CreatedAt:        record.CreatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Rebuild reactions from reaction records
 	if len(reactions) > 0 {
 		for _, r := range reactions {
@@ -623,6 +764,11 @@ func RebuildDatabaseFromJSONL(db DBTX, projectPath string) error {
 		}
 	}
 
+	// Renames and merges rewrite authors, mentions and reactions rebuilt above
+	if err := replayAudit(db, auditEntries); err != nil {
+		return err
+	}
+
 	// Rebuild faves from fave events
 	if len(faveEvents) > 0 {
 		type faveKey struct {
@@ -1,11 +1,16 @@
 package db
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
 )
 
@@ -337,6 +342,90 @@ func TestRebuildPreservesManagedAgentFields(t *testing.T) {
 	}
 }
 
+func TestStoreBlobAndRebuildAttachments(t *testing.T) {
+	projectDir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(src, []byte("line 1\nline 2\n"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	first, err := StoreBlob(projectDir, src)
+	if err != nil {
+		t.Fatalf("store blob: %v", err)
+	}
+	second, err := StoreBlob(projectDir, src)
+	if err != nil {
+		t.Fatalf("store blob again: %v", err)
+	}
+	if first.Hash != second.Hash || first.Size != 14 || first.Name != "build.log" {
+		t.Fatalf("unexpected attachments: %#v %#v", first, second)
+	}
+	entries, err := os.ReadDir(filepath.Join(projectDir, ".fray", "blobs", first.Hash[:2]))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one deduplicated blob, got %v (err %v)", entries, err)
+	}
+
+	large := filepath.Join(t.TempDir(), "dump.bin")
+	if err := os.WriteFile(large, nil, 0o644); err != nil {
+		t.Fatalf("write large source: %v", err)
+	}
+	if err := os.Truncate(large, MaxBlobSize+1); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	if _, err := StoreBlob(projectDir, large); err == nil {
+		t.Fatal("expected oversized attachment to be rejected")
+	}
+
+	message := types.Message{
+		ID:          "msg-attach01",
+		TS:          123,
+		FromAgent:   "alice",
+		Body:        "see log",
+		Mentions:    []string{},
+		Type:        types.MessageTypeAgent,
+		Attachments: []types.Attachment{first},
+	}
+	if err := AppendMessage(projectDir, message); err != nil {
+		t.Fatalf("append message: %v", err)
+	}
+
+	dbConn := openTestDB(t)
+	if err := RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	rebuilt, err := GetMessage(dbConn, message.ID)
+	if err != nil || rebuilt == nil {
+		t.Fatalf("get message: %v", err)
+	}
+	attachment, err := FindAttachment(*rebuilt, "build.log")
+	if err != nil {
+		t.Fatalf("find attachment: %v", err)
+	}
+	if attachment.Hash != first.Hash {
+		t.Fatalf("expected hash %s, got %s", first.Hash, attachment.Hash)
+	}
+	if _, err := FindAttachment(*rebuilt, "missing.log"); err == nil {
+		t.Fatal("expected error for unknown attachment")
+	}
+
+	// Hashes come from JSONL, so one that would escape the blob store is refused.
+	tampered := *rebuilt
+	tampered.Attachments = []types.Attachment{{Name: "evil.log", Hash: "../../../../etc/passwd"}}
+	if _, err := FindAttachment(tampered, "evil.log"); err == nil {
+		t.Fatal("expected error for an attachment with an invalid hash")
+	}
+	for _, hash := range []string{"../../etc/passwd", strings.ToUpper(first.Hash), first.Hash[:63], ""} {
+		if _, err := OpenBlob(projectDir, hash); err == nil {
+			t.Fatalf("expected OpenBlob to reject %q", hash)
+		}
+	}
+	blob, err := OpenBlob(projectDir, first.Hash)
+	if err != nil {
+		t.Fatalf("open blob: %v", err)
+	}
+	blob.Close()
+}
+
 func TestUpdateProjectConfigMergesKnownAgents(t *testing.T) {
 	projectDir := t.TempDir()
 
@@ -447,6 +536,57 @@ func TestAppendAndReadQuestions(t *testing.T) {
 	}
 }
 
+func TestAskQuestionAndPostAnswerSummary(t *testing.T) {
+	db := openTestDB(t)
+	requireSchema(t, db)
+	projectDir := t.TempDir()
+
+	question, err := CreateQuestion(db, types.Question{
+		Re:        "which cache?",
+		FromAgent: "alice",
+		Status:    types.QuestionStatusUnasked,
+		CreatedAt: 100,
+	})
+	if err != nil {
+		t.Fatalf("create question: %v", err)
+	}
+
+	toAgent := "bob"
+	asked, askMsg, err := AskQuestion(db, projectDir, question, "adam", &toAgent, nil)
+	if err != nil {
+		t.Fatalf("ask question: %v", err)
+	}
+	if asked.Status != types.QuestionStatusOpen || asked.AskedIn == nil || *asked.AskedIn != askMsg.ID {
+		t.Fatalf("expected open question asked in %s, got %+v", askMsg.ID, asked)
+	}
+	if askMsg.Body != "@bob which cache?" || askMsg.FromAgent != "adam" {
+		t.Fatalf("unexpected ask message: %+v", askMsg)
+	}
+
+	summary, err := PostAnswerSummary(db, projectDir, "bob", []QuestionAnswer{{Question: *asked, Answer: "redis"}})
+	if err != nil {
+		t.Fatalf("post answer summary: %v", err)
+	}
+	if summary.Body != "answered @alice\n\nQ: which cache?\nA: redis" {
+		t.Fatalf("unexpected summary body: %q", summary.Body)
+	}
+
+	messages, err := ReadMessages(projectDir)
+	if err != nil {
+		t.Fatalf("read messages: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected ask and answer messages in JSONL, got %d", len(messages))
+	}
+	answered, err := GetQuestion(db, question.GUID)
+	if err != nil {
+		t.Fatalf("get question: %v", err)
+	}
+	if answered.Status != types.QuestionStatusAnswered || answered.AnsweredIn == nil || *answered.AnsweredIn != summary.ID {
+		t.Fatalf("expected question answered in %s, got %+v", summary.ID, answered)
+	}
+}
+
 func TestReadThreadsEvents(t *testing.T) {
 	projectDir := t.TempDir()
 
@@ -644,3 +784,253 @@ func TestRebuildDatabaseFromJSONL(t *testing.T) {
 		t.Fatalf("expected bob unsubscribed after rebuild")
 	}
 }
+
+func TestScheduledMessagesLifecycle(t *testing.T) {
+	projectDir := t.TempDir()
+	db := openTestDB(t)
+	requireSchema(t, db)
+	now := time.Unix(10_000, 0)
+
+	due, err := AppendScheduledMessage(projectDir, types.ScheduledMessage{
+		FromAgent: "alice",
+		Body:      "standup in 5 @bob",
+		SendAt:    now.Unix() - 1,
+	})
+	if err != nil {
+		t.Fatalf("append due: %v", err)
+	}
+	later, err := AppendScheduledMessage(projectDir, types.ScheduledMessage{
+		FromAgent: "alice",
+		Home:      "thrd-00000001",
+		Body:      "later",
+		SendAt:    now.Unix() + 3600,
+	})
+	if err != nil {
+		t.Fatalf("append later: %v", err)
+	}
+	cancelled, err := AppendScheduledMessage(projectDir, types.ScheduledMessage{FromAgent: "alice", Body: "nope", SendAt: now.Unix() - 5})
+	if err != nil {
+		t.Fatalf("append cancelled: %v", err)
+	}
+	if err := CancelScheduledMessage(projectDir, cancelled.GUID, "alice"); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := CancelScheduledMessage(projectDir, cancelled.GUID, "alice"); err != ErrScheduledNotPending {
+		t.Fatalf("expected not pending on second cancel, got %v", err)
+	}
+
+	pending, err := ReadPendingScheduled(projectDir)
+	if err != nil {
+		t.Fatalf("read pending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].GUID != due.GUID || pending[1].GUID != later.GUID {
+		t.Fatalf("expected due then later pending, got %+v", pending)
+	}
+
+	delivered, err := DeliverDueScheduled(db, projectDir, now)
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if len(delivered) != 1 || delivered[0].Body != due.Body || delivered[0].Home != "room" {
+		t.Fatalf("expected one room delivery, got %+v", delivered)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".fray", scheduledLockFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected delivery lock released")
+	}
+
+	again, err := DeliverDueScheduled(db, projectDir, now)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("expected no redelivery, got %d (%v)", len(again), err)
+	}
+	pending, _ = ReadPendingScheduled(projectDir)
+	if len(pending) != 1 || pending[0].GUID != later.GUID {
+		t.Fatalf("expected only later pending, got %+v", pending)
+	}
+	messages, err := ReadMessages(projectDir)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("expected delivered message in jsonl, got %d (%v)", len(messages), err)
+	}
+}
+
+func TestRebuildVerifiesMessageSignatures(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+
+	for _, agent := range []types.Agent{
+		{GUID: "usr-alice001", AgentID: "alice", RegisteredAt: 1, LastSeen: 1},
+		{GUID: "usr-bob00001", AgentID: "bob", RegisteredAt: 1, LastSeen: 1},
+	} {
+		if err := AppendAgent(projectDir, agent); err != nil {
+			t.Fatalf("append agent: %v", err)
+		}
+	}
+	key, err := core.GenerateAgentKey("alice")
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	publicKey := core.EncodePublicKey(key.Public().(ed25519.PublicKey))
+	if _, err := UpdateProjectConfig(projectDir, ProjectConfig{
+		KnownAgents: map[string]ProjectKnownAgent{"usr-alice001": {PublicKey: &publicKey}},
+	}); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+
+	sign := func(id, body string) *string {
+		signature := core.SignMessage(key, id, "alice", body)
+		return &signature
+	}
+	messages := []types.Message{
+		{ID: "msg-signed01", TS: 1, FromAgent: "alice", Body: "hello", Signature: sign("msg-signed01", "hello")},
+		{ID: "msg-unsign01", TS: 2, FromAgent: "alice", Body: "posted with --as"},
+		{ID: "msg-forged01", TS: 3, FromAgent: "alice", Body: "altered", Signature: sign("msg-forged01", "original")},
+		{ID: "msg-nokey001", TS: 4, FromAgent: "bob", Body: "no key"},
+	}
+	for _, message := range messages {
+		if err := AppendMessage(projectDir, message); err != nil {
+			t.Fatalf("append message: %v", err)
+		}
+	}
+	body := "hello again"
+	if err := AppendMessageUpdate(projectDir, MessageUpdateJSONLRecord{ID: "msg-signed01", Body: &body, EditedAt: intPtr(5), Signature: sign("msg-signed01", body)}); err != nil {
+		t.Fatalf("append update: %v", err)
+	}
+	unsignedBody := "edited without the key"
+	if err := AppendMessageUpdate(projectDir, MessageUpdateJSONLRecord{ID: "msg-signed01", Body: &unsignedBody, EditedAt: intPtr(6)}); err != nil {
+		t.Fatalf("append update: %v", err)
+	}
+
+	dbConn := openTestDB(t)
+	if err := RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+
+	expected := map[string]types.SignatureStatus{
+		"msg-signed01": types.SignatureUnverified,
+		"msg-unsign01": types.SignatureUnverified,
+		"msg-forged01": types.SignatureInvalid,
+		"msg-nokey001": "",
+	}
+	for id, status := range expected {
+		msg, err := GetMessage(dbConn, id)
+		if err != nil || msg == nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if msg.SignatureStatus != status {
+			t.Fatalf("expected %s to be %q, got %q", id, status, msg.SignatureStatus)
+		}
+	}
+
+	history, err := GetMessageVersions(projectDir, "msg-signed01")
+	if err != nil {
+		t.Fatalf("versions: %v", err)
+	}
+	statuses := []types.SignatureStatus{}
+	for _, version := range history.Versions {
+		statuses = append(statuses, version.SignatureStatus)
+	}
+	if len(statuses) != 3 || statuses[0] != types.SignatureVerified || statuses[1] != types.SignatureVerified || statuses[2] != types.SignatureUnverified {
+		t.Fatalf("unexpected version statuses: %v", statuses)
+	}
+
+	// Only the acting identity signs: another agent posting --as alice cannot
+	// borrow the key on this machine
+	t.Setenv("FRAY_AGENT_ID", "bob")
+	created, err := CreateMessage(dbConn, types.Message{FromAgent: "alice", Body: "posted by bob"})
+	if err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	if created.Signature != nil || created.SignatureStatus != types.SignatureUnverified {
+		t.Fatalf("expected unsigned post from another identity, got %+v", created)
+	}
+
+	// The registered key is cached, so new posts are signed and checked on insert
+	t.Setenv("FRAY_AGENT_ID", "alice")
+	created, err = CreateMessage(dbConn, types.Message{FromAgent: "alice", Body: "signed live"})
+	if err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	if created.Signature == nil || created.SignatureStatus != types.SignatureVerified {
+		t.Fatalf("expected verified live post, got %+v", created)
+	}
+	if err := AppendMessage(projectDir, created); err != nil {
+		t.Fatalf("append message: %v", err)
+	}
+
+	// Rotating keeps the old key registered, so earlier signatures still verify
+	rotatedKey, err := core.GenerateAgentKey("alice")
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	rotated := rotatedKey.Public().(ed25519.PublicKey)
+	if err := RegisterAgentPublicKey(dbConn, projectDir, "usr-alice001", "alice", rotated); err != nil {
+		t.Fatalf("register rotated key: %v", err)
+	}
+	config, err := ReadProjectConfig(projectDir)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	entry := config.KnownAgents["usr-alice001"]
+	if entry.PublicKey == nil || *entry.PublicKey != core.EncodePublicKey(rotated) || len(entry.RetiredKeys) != 1 || entry.RetiredKeys[0] != publicKey {
+		t.Fatalf("expected old key to be retired, got %+v", entry)
+	}
+	if err := RebuildDatabaseFromJSONL(dbConn, projectDir); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	msg, err := GetMessage(dbConn, created.ID)
+	if err != nil || msg == nil || msg.SignatureStatus != types.SignatureVerified {
+		t.Fatalf("expected pre-rotation post to stay verified, got %+v (err %v)", msg, err)
+	}
+	created, err = CreateMessage(dbConn, types.Message{FromAgent: "alice", Body: "signed with the new key"})
+	if err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	if created.SignatureStatus != types.SignatureVerified || !strings.HasPrefix(*created.Signature, core.PublicKeyID(rotated)+":") {
+		t.Fatalf("expected post signed with the rotated key, got %+v", created)
+	}
+}
+
+func TestLockJSONLForRewriteHoldsOffOtherAppenders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("JSONL locking is a no-op on windows")
+	}
+	projectDir := t.TempDir()
+
+	unlock, err := LockJSONLForRewrite(projectDir)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	// The rewriting process still appends its own records.
+	message := types.Message{ID: "msg-lock0001", TS: 1, FromAgent: "alice", Body: "hi", Type: types.MessageTypeAgent}
+	if err := AppendMessage(projectDir, message); err != nil {
+		t.Fatalf("append while rewriting: %v", err)
+	}
+
+	// Another appender (its own open file, as another process would have)
+	// waits for the rewrite.
+	acquired := make(chan func())
+	go func() {
+		release, err := lockFile(filepath.Join(resolveFrayDir(projectDir), jsonlLockFile), false)
+		if err != nil {
+			t.Errorf("shared lock: %v", err)
+			close(acquired)
+			return
+		}
+		acquired <- release
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("appender got the lock during a rewrite")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case release := <-acquired:
+		if release != nil {
+			release()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("appender still blocked after the rewrite")
+	}
+}
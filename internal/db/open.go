@@ -64,7 +64,7 @@ func OpenDatabase(project core.Project) (*sql.DB, error) {
 }
 
 func getJSONLMtime(frayDir string) int64 {
-	files := []string{"messages.jsonl", "agents.jsonl", "questions.jsonl", "threads.jsonl"}
+	files := []string{"messages.jsonl", "agents.jsonl", "questions.jsonl", "threads.jsonl", "tasks.jsonl"}
 	latest := int64(0)
 	for _, name := range files {
 		path := filepath.Join(frayDir, name)
@@ -371,7 +371,7 @@ func RenameAgent(db *sql.DB, oldID, newID string) error {
 		return err
 	}
 
-	if err := updateReactionsForAgent(db, oldID, newID); err != nil {
+	if err := updateReactionsForAgent(db, oldID, newID, 0); err != nil {
 		return err
 	}
 
@@ -403,16 +403,53 @@ func MergeAgentHistory(db *sql.DB, fromID, toID string) (int64, error) {
 }
 
 func mergeAgentHistoryWith(tx *sql.Tx, fromID, toID string) (int64, error) {
-	result, err := tx.Exec("UPDATE fray_messages SET from_agent = ? WHERE from_agent = ?", toID, fromID)
+	messageCount, err := reassignAgentMessages(tx, fromID, toID, 0, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := updateReactionsForAgent(tx, fromID, toID, 0); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("UPDATE fray_claims SET agent_id = ? WHERE agent_id = ?", toID, fromID); err != nil {
+		return 0, err
+	}
+
+	return messageCount, nil
+}
+
+// reassignAgentMessages moves authorship and mentions from one agent to
+// another. A non-zero before limits it to messages posted at or before that
+// time. With invalidateSigned, signed messages are marked invalid since their
+// signature no longer matches the author.
+func reassignAgentMessages(db DBTX, fromID, toID string, before int64, invalidateSigned bool) (int64, error) {
+	bound := ""
+	args := []any{toID, fromID}
+	if before > 0 {
+		bound = " AND ts <= ?"
+		args = append(args, before)
+	}
+	status := "signature_status"
+	if invalidateSigned {
+		status = "CASE WHEN signature IS NULL THEN signature_status ELSE 'invalid' END"
+	}
+	result, err := db.Exec(`
+		UPDATE fray_messages
+		SET from_agent = ?, signature_status = `+status+`
+		WHERE from_agent = ?`+bound, args...)
 	if err != nil {
 		return 0, err
 	}
 	messageCount, _ := result.RowsAffected()
 
-	rows, err := tx.Query(`
+	mentionArgs := []any{fmt.Sprintf("%%\"%s\"%%", fromID)}
+	if before > 0 {
+		mentionArgs = append(mentionArgs, before)
+	}
+	rows, err := db.Query(`
 		SELECT guid, mentions FROM fray_messages
-		WHERE mentions LIKE ?
-	`, fmt.Sprintf("%%\"%s\"%%", fromID))
+		WHERE mentions LIKE ?`+bound, mentionArgs...)
 	if err != nil {
 		return 0, err
 	}
@@ -445,7 +482,7 @@ func mergeAgentHistoryWith(tx *sql.Tx, fromID, toID string) (int64, error) {
 		if err != nil {
 			return 0, err
 		}
-		if _, err := tx.Exec("UPDATE fray_messages SET mentions = ? WHERE guid = ?", string(updatedJSON), guid); err != nil {
+		if _, err := db.Exec("UPDATE fray_messages SET mentions = ? WHERE guid = ?", string(updatedJSON), guid); err != nil {
 			return 0, err
 		}
 	}
@@ -453,30 +490,33 @@ func mergeAgentHistoryWith(tx *sql.Tx, fromID, toID string) (int64, error) {
 		return 0, err
 	}
 
-	if err := updateReactionsForAgent(tx, fromID, toID); err != nil {
-		return 0, err
-	}
-
-	if _, err := tx.Exec("UPDATE fray_claims SET agent_id = ? WHERE agent_id = ?", toID, fromID); err != nil {
-		return 0, err
-	}
-
 	return messageCount, nil
 }
 
-func updateReactionsForAgent(db DBTX, oldID, newID string) error {
+// updateReactionsForAgent moves reactions from one agent to another. A
+// non-zero before (unix seconds) limits it to reactions made in or before that
+// second (legacy JSON reactions carry no time, so their message's time is used).
+func updateReactionsForAgent(db DBTX, oldID, newID string, before int64) error {
+	reactionBound, messageBound := "", ""
+	args := []any{newID, oldID}
+	legacyArgs := []any{fmt.Sprintf("%%\"%s\"%%", oldID)}
+	if before > 0 {
+		// reacted_at is in milliseconds
+		reactionBound, messageBound = " AND reacted_at < ?", " AND ts <= ?"
+		args = append(args, (before+1)*1000)
+		legacyArgs = append(legacyArgs, before)
+	}
+
 	// Update reactions in the new fray_reactions table
 	if _, err := db.Exec(`
-		UPDATE fray_reactions SET agent_id = ? WHERE agent_id = ?
-	`, newID, oldID); err != nil {
+		UPDATE fray_reactions SET agent_id = ? WHERE agent_id = ?`+reactionBound, args...); err != nil {
 		return err
 	}
 
 	// Also update legacy reactions JSON in messages table for backward compat
 	rows, err := db.Query(`
 		SELECT guid, reactions FROM fray_messages
-		WHERE reactions LIKE ?
-	`, fmt.Sprintf("%%\"%s\"%%", oldID))
+		WHERE reactions LIKE ?`+messageBound, legacyArgs...)
 	if err != nil {
 		return err
 	}
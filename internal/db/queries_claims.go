@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
 	"github.com/gobwas/glob"
 	"modernc.org/sqlite"
@@ -26,6 +27,32 @@ func PruneExpiredClaims(db *sql.DB) (int64, error) {
 	return result.RowsAffected()
 }
 
+// PruneClosedIssueClaims removes claims whose tracker issue has been closed.
+// Claims are matched to providers by claim type; unknown issues are kept.
+func PruneClosedIssueClaims(db *sql.DB, providers []core.IssueProvider) ([]types.Claim, error) {
+	var released []types.Claim
+	for _, provider := range providers {
+		claims, err := GetClaimsByType(db, types.ClaimType(provider.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			issue, err := provider.LookupIssue(claim.Pattern)
+			if err != nil || issue == nil || !issue.IsClosed() {
+				continue
+			}
+			deleted, err := DeleteClaim(db, claim.ClaimType, claim.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			if deleted {
+				released = append(released, claim)
+			}
+		}
+	}
+	return released, nil
+}
+
 // CreateClaim inserts a new claim.
 func CreateClaim(db *sql.DB, claim types.ClaimInput) (*types.Claim, error) {
 	now := time.Now().Unix()
@@ -62,7 +89,7 @@ func CreateClaim(db *sql.DB, claim types.ClaimInput) (*types.Claim, error) {
 // GetClaim returns a claim by type and pattern.
 func GetClaim(db *sql.DB, claimType types.ClaimType, pattern string) (*types.Claim, error) {
 	row := db.QueryRow(`
-		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at
+		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at, message_guid
 		FROM fray_claims
 		WHERE claim_type = ? AND pattern = ?
 	`, claimType, pattern)
@@ -79,7 +106,7 @@ func GetClaim(db *sql.DB, claimType types.ClaimType, pattern string) (*types.Cla
 // GetClaimsByAgent returns claims for an agent.
 func GetClaimsByAgent(db *sql.DB, agentID string) ([]types.Claim, error) {
 	rows, err := db.Query(`
-		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at
+		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at, message_guid
 		FROM fray_claims
 		WHERE agent_id = ?
 		ORDER BY created_at
@@ -95,7 +122,7 @@ func GetClaimsByAgent(db *sql.DB, agentID string) ([]types.Claim, error) {
 // GetClaimsByType returns claims of a type.
 func GetClaimsByType(db *sql.DB, claimType types.ClaimType) ([]types.Claim, error) {
 	rows, err := db.Query(`
-		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at
+		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at, message_guid
 		FROM fray_claims
 		WHERE claim_type = ?
 		ORDER BY created_at
@@ -114,7 +141,7 @@ func GetAllClaims(db *sql.DB) ([]types.Claim, error) {
 		return nil, err
 	}
 	rows, err := db.Query(`
-		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at
+		SELECT id, agent_id, claim_type, pattern, reason, created_at, expires_at, message_guid
 		FROM fray_claims
 		ORDER BY created_at
 	`)
@@ -150,7 +177,16 @@ func DeleteClaimsByAgent(db *sql.DB, agentID string) (int64, error) {
 
 // FindConflictingFileClaims returns conflicting file claims.
 func FindConflictingFileClaims(db *sql.DB, filePaths []string, excludeAgent string) ([]types.Claim, error) {
-	claims, err := GetClaimsByType(db, types.ClaimTypeFile)
+	return findConflictingClaims(db, types.ClaimTypeFile, filePaths, excludeAgent)
+}
+
+// FindConflictingBranchClaims returns branch claims held by other agents that match the given branches.
+func FindConflictingBranchClaims(db *sql.DB, branches []string, excludeAgent string) ([]types.Claim, error) {
+	return findConflictingClaims(db, types.ClaimTypeBranch, branches, excludeAgent)
+}
+
+func findConflictingClaims(db *sql.DB, claimType types.ClaimType, values []string, excludeAgent string) ([]types.Claim, error) {
+	claims, err := GetClaimsByType(db, claimType)
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +200,8 @@ func FindConflictingFileClaims(db *sql.DB, filePaths []string, excludeAgent stri
 		if err != nil {
 			continue
 		}
-		for _, filePath := range filePaths {
-			if matcher.Match(filePath) {
+		for _, value := range values {
+			if matcher.Match(value) {
 				conflicts = append(conflicts, claim)
 				break
 			}
@@ -187,6 +223,12 @@ func CheckFileConflict(db *sql.DB, filePath, excludeAgent string) (*types.Claim,
 	return &conflicts[0], nil
 }
 
+// SetClaimMessage records the message that announced a claim.
+func SetClaimMessage(db *sql.DB, claimID int64, messageGUID string) error {
+	_, err := db.Exec("UPDATE fray_claims SET message_guid = ? WHERE id = ?", messageGUID, claimID)
+	return err
+}
+
 // UpdateClaimsAgentID updates claim ownership.
 func UpdateClaimsAgentID(db *sql.DB, oldID, newID string) error {
 	_, err := db.Exec("UPDATE fray_claims SET agent_id = ? WHERE agent_id = ?", newID, oldID)
@@ -226,7 +268,7 @@ func GetClaimCountsByAgent(db *sql.DB) (map[string]int64, error) {
 
 func scanClaim(scanner interface{ Scan(dest ...any) error }) (types.Claim, error) {
 	var row claimRow
-	if err := scanner.Scan(&row.ID, &row.AgentID, &row.ClaimType, &row.Pattern, &row.Reason, &row.CreatedAt, &row.ExpiresAt); err != nil {
+	if err := scanner.Scan(&row.ID, &row.AgentID, &row.ClaimType, &row.Pattern, &row.Reason, &row.CreatedAt, &row.ExpiresAt, &row.MessageGUID); err != nil {
 		return types.Claim{}, err
 	}
 	return row.toClaim(), nil
@@ -257,23 +299,25 @@ func isConstraintError(err error) bool {
 }
 
 type claimRow struct {
-	ID        int64
-	AgentID   string
-	ClaimType types.ClaimType
-	Pattern   string
-	Reason    sql.NullString
-	CreatedAt int64
-	ExpiresAt sql.NullInt64
+	ID          int64
+	AgentID     string
+	ClaimType   types.ClaimType
+	Pattern     string
+	Reason      sql.NullString
+	CreatedAt   int64
+	ExpiresAt   sql.NullInt64
+	MessageGUID sql.NullString
 }
 
 func (row claimRow) toClaim() types.Claim {
 	return types.Claim{
-		ID:        row.ID,
-		AgentID:   row.AgentID,
-		ClaimType: row.ClaimType,
-		Pattern:   row.Pattern,
-		Reason:    nullStringPtr(row.Reason),
-		CreatedAt: row.CreatedAt,
-		ExpiresAt: nullIntPtr(row.ExpiresAt),
+		ID:          row.ID,
+		AgentID:     row.AgentID,
+		ClaimType:   row.ClaimType,
+		Pattern:     row.Pattern,
+		Reason:      nullStringPtr(row.Reason),
+		CreatedAt:   row.CreatedAt,
+		ExpiresAt:   nullIntPtr(row.ExpiresAt),
+		MessageGUID: nullStringPtr(row.MessageGUID),
 	}
 }
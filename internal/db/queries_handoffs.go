@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// CreateHandoff records an agent handoff.
+func CreateHandoff(db *sql.DB, handoff types.Handoff) (types.Handoff, error) {
+	if handoff.GUID == "" {
+		guid, err := generateUniqueGUIDForTable(db, "fray_handoffs", "hoff")
+		if err != nil {
+			return types.Handoff{}, err
+		}
+		handoff.GUID = guid
+	}
+	if handoff.CreatedAt == 0 {
+		handoff.CreatedAt = time.Now().Unix()
+	}
+	if handoff.Claims == nil {
+		handoff.Claims = []string{}
+	}
+	claimsJSON, err := json.Marshal(handoff.Claims)
+	if err != nil {
+		return types.Handoff{}, err
+	}
+	ownerTransferred := 0
+	if handoff.OwnerTransferred {
+		ownerTransferred = 1
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO fray_handoffs (
+			guid, from_agent, to_agent, home, message_guid, summary, claims, cursor_message_guid, owner_transferred, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, handoff.GUID, handoff.FromAgent, handoff.ToAgent, handoff.Home, handoff.MessageGUID, handoff.Summary,
+		string(claimsJSON), handoff.CursorMessageGUID, ownerTransferred, handoff.CreatedAt)
+	if err != nil {
+		return types.Handoff{}, err
+	}
+	return handoff, nil
+}
+
+// GetHandoffByMessage returns the handoff posted as the given message, if any.
+func GetHandoffByMessage(db *sql.DB, messageGUID string) (*types.Handoff, error) {
+	row := db.QueryRow(`
+		SELECT guid, from_agent, to_agent, home, message_guid, summary, claims, cursor_message_guid, owner_transferred, created_at
+		FROM fray_handoffs
+		WHERE message_guid = ?
+	`, messageGUID)
+
+	var handoff types.Handoff
+	var summary sql.NullString
+	var claimsJSON string
+	var cursor sql.NullString
+	var ownerTransferred int
+	err := row.Scan(&handoff.GUID, &handoff.FromAgent, &handoff.ToAgent, &handoff.Home, &handoff.MessageGUID,
+		&summary, &claimsJSON, &cursor, &ownerTransferred, &handoff.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	handoff.Summary = summary.String
+	handoff.CursorMessageGUID = nullStringPtr(cursor)
+	handoff.OwnerTransferred = ownerTransferred != 0
+	if err := json.Unmarshal([]byte(claimsJSON), &handoff.Claims); err != nil {
+		handoff.Claims = []string{}
+	}
+	return &handoff, nil
+}
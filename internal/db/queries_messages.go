@@ -7,15 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adamavenir/fray/internal/core"
 	"github.com/adamavenir/fray/internal/types"
 )
 
 // messageColumns is the explicit column list for SELECT queries.
 // This prevents column order issues when migrations add columns via ALTER TABLE.
-const messageColumns = `guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions`
+const messageColumns = `guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions, attachments, signature, signature_status`
 
 // messageColumnsAliased is the same but with m. prefix for JOINs.
-const messageColumnsAliased = `m.guid, m.ts, m.channel_id, m.home, m.from_agent, m.body, m.mentions, m.type, m."references", m.surface_message, m.reply_to, m.quote_message_guid, m.edited_at, m.archived_at, m.reactions`
+const messageColumnsAliased = `m.guid, m.ts, m.channel_id, m.home, m.from_agent, m.body, m.mentions, m.type, m."references", m.surface_message, m.reply_to, m.quote_message_guid, m.edited_at, m.archived_at, m.reactions, m.attachments, m.signature, m.signature_status`
 
 // CreateMessage inserts a new message.
 func CreateMessage(db *sql.DB, message types.Message) (types.Message, error) {
@@ -58,10 +59,15 @@ func CreateMessage(db *sql.DB, message types.Message) (types.Message, error) {
 		home = "room"
 	}
 
+	signature, signatureStatus, err := signMessageBody(db, guid, message.FromAgent, message.Body)
+	if err != nil {
+		return types.Message{}, err
+	}
+
 	_, err = db.Exec(`
-		INSERT INTO fray_messages (guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, NULL, ?)
-	`, guid, ts, channelID, home, message.FromAgent, message.Body, string(mentionsJSON), msgType, message.References, message.SurfaceMessage, message.ReplyTo, message.QuoteMessageGUID, string(reactionsJSON))
+		INSERT INTO fray_messages (guid, ts, channel_id, home, from_agent, body, mentions, type, "references", surface_message, reply_to, quote_message_guid, edited_at, archived_at, reactions, attachments, signature, signature_status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, NULL, ?, ?, ?, ?)
+	`, guid, ts, channelID, home, message.FromAgent, message.Body, string(mentionsJSON), msgType, message.References, message.SurfaceMessage, message.ReplyTo, message.QuoteMessageGUID, string(reactionsJSON), encodeAttachments(message.Attachments), signature, nullableSignatureStatus(signatureStatus))
 	if err != nil {
 		return types.Message{}, err
 	}
@@ -85,9 +91,19 @@ func CreateMessage(db *sql.DB, message types.Message) (types.Message, error) {
 		QuoteMessageGUID: message.QuoteMessageGUID,
 		EditedAt:         nil,
 		ArchivedAt:       nil,
+		Attachments:      message.Attachments,
+		Signature:        signature,
+		SignatureStatus:  signatureStatus,
 	}, nil
 }
 
+func nullableSignatureStatus(status types.SignatureStatus) any {
+	if status == "" {
+		return nil
+	}
+	return string(status)
+}
+
 // loadReactionsForMessages loads reactions from fray_reactions table into the messages.
 func loadReactionsForMessages(db *sql.DB, messages []types.Message) error {
 	if len(messages) == 0 {
@@ -434,6 +450,52 @@ func GetAgentLastPostTime(database *sql.DB, agentID string) (int64, error) {
 	return ts, nil
 }
 
+// GetRecentMessagesByAgent returns an agent's most recent messages, newest first.
+func GetRecentMessagesByAgent(db *sql.DB, agentID string, limit int) ([]types.Message, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT %s FROM fray_messages
+		WHERE from_agent = ? AND archived_at IS NULL
+		ORDER BY ts DESC, guid DESC
+		LIMIT ?
+	`, messageColumns), agentID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMessages(rows)
+}
+
+// SearchMessages returns messages whose body contains query (case-insensitive),
+// newest first. It spans the room and all threads, including archived messages.
+func SearchMessages(db *sql.DB, query string, limit int) ([]types.Message, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	pattern := "%" + escaper.Replace(query) + "%"
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT %s FROM fray_messages
+		WHERE body LIKE ? ESCAPE '\' AND body != '[deleted]'
+		ORDER BY ts DESC, rowid DESC
+		LIMIT ?
+	`, messageColumns), pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMessagesWithReactions(db, rows)
+}
+
 // GetMessage returns a message by GUID.
 func GetMessage(db *sql.DB, messageID string) (*types.Message, error) {
 	row := db.QueryRow("SELECT "+messageColumns+" FROM fray_messages WHERE guid = ?", messageID)
@@ -535,13 +597,42 @@ func EditMessage(db *sql.DB, messageID, newBody, agentID string) error {
 		return fmt.Errorf("cannot edit message from another agent (message from %s)", msg.FromAgent)
 	}
 
+	// The new body is re-signed; an edit without the author's key drops the signature
+	signature, signatureStatus, err := signMessageBody(db, messageID, msg.FromAgent, newBody)
+	if err != nil {
+		return err
+	}
+
 	editedAt := time.Now().Unix()
-	if _, err := db.Exec("UPDATE fray_messages SET body = ?, edited_at = ? WHERE guid = ?", newBody, editedAt, messageID); err != nil {
+	if _, err := db.Exec("UPDATE fray_messages SET body = ?, edited_at = ?, signature = ?, signature_status = ? WHERE guid = ?", newBody, editedAt, signature, nullableSignatureStatus(signatureStatus), messageID); err != nil {
 		return err
 	}
 	return nil
 }
 
+// EditMessageAsCurator updates the body of another agent's message on a thread
+// curator's behalf. The author's signature covered the old body, so it is
+// dropped rather than re-signed under the author's identity.
+func EditMessageAsCurator(db *sql.DB, messageID, newBody string) error {
+	msg, err := GetMessage(db, messageID)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("message %s not found", messageID)
+	}
+
+	publicKeys, err := GetAgentPublicKeys(db, msg.FromAgent)
+	if err != nil {
+		return err
+	}
+	signatureStatus := core.VerifyMessage(publicKeys, messageID, msg.FromAgent, newBody, nil)
+
+	editedAt := time.Now().Unix()
+	_, err = db.Exec("UPDATE fray_messages SET body = ?, edited_at = ?, signature = NULL, signature_status = ? WHERE guid = ?", newBody, editedAt, nullableSignatureStatus(signatureStatus), messageID)
+	return err
+}
+
 // DeleteMessage marks a message as deleted.
 func DeleteMessage(db *sql.DB, messageID string) error {
 	msg, err := GetMessage(db, messageID)
@@ -552,8 +643,15 @@ func DeleteMessage(db *sql.DB, messageID string) error {
 		return fmt.Errorf("message %s not found", messageID)
 	}
 
+	// The tombstone carries no signature, so it verifies the same way after a rebuild
+	publicKeys, err := GetAgentPublicKeys(db, msg.FromAgent)
+	if err != nil {
+		return err
+	}
+	signatureStatus := core.VerifyMessage(publicKeys, messageID, msg.FromAgent, "[deleted]", nil)
+
 	deletedAt := time.Now().Unix()
-	_, err = db.Exec("UPDATE fray_messages SET body = ?, archived_at = ? WHERE guid = ?", "[deleted]", deletedAt, messageID)
+	_, err = db.Exec("UPDATE fray_messages SET body = ?, archived_at = ?, signature = NULL, signature_status = ? WHERE guid = ?", "[deleted]", deletedAt, nullableSignatureStatus(signatureStatus), messageID)
 	return err
 }
 
@@ -643,6 +741,9 @@ type messageRow struct {
 	QuoteMessageGUID sql.NullString
 	EditedAt         sql.NullInt64
 	ArchivedAt       sql.NullInt64
+	Attachments      sql.NullString
+	Signature        sql.NullString
+	SignatureStatus  sql.NullString
 }
 
 func (row messageRow) toMessage() (types.Message, error) {
@@ -687,6 +788,9 @@ func (row messageRow) toMessage() (types.Message, error) {
 		QuoteMessageGUID: nullStringPtr(row.QuoteMessageGUID),
 		EditedAt:         nullIntPtr(row.EditedAt),
 		ArchivedAt:       nullIntPtr(row.ArchivedAt),
+		Attachments:      decodeAttachments(row.Attachments),
+		Signature:        nullStringPtr(row.Signature),
+		SignatureStatus:  types.SignatureStatus(row.SignatureStatus.String),
 	}, nil
 }
 
@@ -739,7 +843,7 @@ func scanMessages(rows *sql.Rows) ([]types.Message, error) {
 
 func scanMessage(scanner interface{ Scan(dest ...any) error }) (types.Message, error) {
 	var row messageRow
-	if err := scanner.Scan(&row.GUID, &row.TS, &row.ChannelID, &row.Home, &row.FromAgent, &row.Body, &row.Mentions, &row.MsgType, &row.References, &row.SurfaceMessage, &row.ReplyTo, &row.QuoteMessageGUID, &row.EditedAt, &row.ArchivedAt, &row.Reactions); err != nil {
+	if err := scanner.Scan(&row.GUID, &row.TS, &row.ChannelID, &row.Home, &row.FromAgent, &row.Body, &row.Mentions, &row.MsgType, &row.References, &row.SurfaceMessage, &row.ReplyTo, &row.QuoteMessageGUID, &row.EditedAt, &row.ArchivedAt, &row.Reactions, &row.Attachments, &row.Signature, &row.SignatureStatus); err != nil {
 		return types.Message{}, err
 	}
 	return row.toMessage()
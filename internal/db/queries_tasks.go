@@ -0,0 +1,287 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// TaskUpdates represents partial task updates.
+type TaskUpdates struct {
+	Status        types.OptionalString
+	Assignee      types.OptionalString
+	ThreadGUID    types.OptionalString
+	MessageGUID   types.OptionalString
+	BlockedReason types.OptionalString
+	DependsOn     *[]string
+}
+
+const taskColumns = "guid, title, created_by, assignee, status, thread_guid, message_guid, depends_on, blocked_reason, created_at, updated_at"
+
+// CreateTask inserts a new task.
+func CreateTask(db *sql.DB, task types.Task) (types.Task, error) {
+	if task.GUID == "" {
+		guid, err := generateUniqueGUIDForTable(db, "fray_tasks", "tsk")
+		if err != nil {
+			return types.Task{}, err
+		}
+		task.GUID = guid
+	}
+	if task.Status == "" {
+		task.Status = types.TaskStatusTodo
+	}
+	if task.CreatedAt == 0 {
+		task.CreatedAt = time.Now().Unix()
+	}
+	if task.UpdatedAt == 0 {
+		task.UpdatedAt = task.CreatedAt
+	}
+
+	dependsJSON, err := encodeTaskDependencies(task.DependsOn)
+	if err != nil {
+		return types.Task{}, err
+	}
+	if _, err := db.Exec(`
+		INSERT INTO fray_tasks (`+taskColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, task.GUID, task.Title, task.CreatedBy, task.Assignee, string(task.Status), task.ThreadGUID, task.MessageGUID,
+		dependsJSON, task.BlockedReason, task.CreatedAt, task.UpdatedAt); err != nil {
+		return types.Task{}, err
+	}
+	return task, nil
+}
+
+// UpdateTask updates task fields and bumps updated_at.
+func UpdateTask(db *sql.DB, guid string, updates TaskUpdates) (*types.Task, error) {
+	fields := []string{"updated_at = ?"}
+	args := []any{time.Now().Unix()}
+
+	if updates.Status.Set {
+		fields = append(fields, "status = ?")
+		args = append(args, nullableValue(updates.Status.Value))
+	}
+	if updates.Assignee.Set {
+		fields = append(fields, "assignee = ?")
+		args = append(args, nullableValue(updates.Assignee.Value))
+	}
+	if updates.ThreadGUID.Set {
+		fields = append(fields, "thread_guid = ?")
+		args = append(args, nullableValue(updates.ThreadGUID.Value))
+	}
+	if updates.MessageGUID.Set {
+		fields = append(fields, "message_guid = ?")
+		args = append(args, nullableValue(updates.MessageGUID.Value))
+	}
+	if updates.BlockedReason.Set {
+		fields = append(fields, "blocked_reason = ?")
+		args = append(args, nullableValue(updates.BlockedReason.Value))
+	}
+	if updates.DependsOn != nil {
+		dependsJSON, err := encodeTaskDependencies(*updates.DependsOn)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, "depends_on = ?")
+		args = append(args, dependsJSON)
+	}
+
+	args = append(args, guid)
+	query := fmt.Sprintf("UPDATE fray_tasks SET %s WHERE guid = ?", strings.Join(fields, ", "))
+	if _, err := db.Exec(query, args...); err != nil {
+		return nil, err
+	}
+	return GetTask(db, guid)
+}
+
+// GetTask returns a task by GUID.
+func GetTask(db *sql.DB, guid string) (*types.Task, error) {
+	row := db.QueryRow(`SELECT `+taskColumns+` FROM fray_tasks WHERE guid = ?`, guid)
+	task, err := scanTask(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTaskByPrefix returns the most recent task matching a GUID prefix.
+func GetTaskByPrefix(db *sql.DB, prefix string) (*types.Task, error) {
+	rows, err := db.Query(`
+		SELECT `+taskColumns+`
+		FROM fray_tasks
+		WHERE guid = ? OR guid LIKE ?
+		ORDER BY created_at DESC
+	`, prefix, fmt.Sprintf("%s%%", prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// GetTasks returns tasks filtered by options, oldest first.
+func GetTasks(db *sql.DB, opts *types.TaskQueryOptions) ([]types.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM fray_tasks`
+	var conditions []string
+	var args []any
+
+	if opts != nil {
+		if len(opts.Statuses) > 0 {
+			placeholders := make([]string, 0, len(opts.Statuses))
+			for _, status := range opts.Statuses {
+				placeholders = append(placeholders, "?")
+				args = append(args, string(status))
+			}
+			conditions = append(conditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ", ")))
+		}
+		if opts.Assignee != nil {
+			if *opts.Assignee == "" {
+				conditions = append(conditions, "(assignee IS NULL OR assignee = '')")
+			} else {
+				conditions = append(conditions, "assignee = ?")
+				args = append(args, *opts.Assignee)
+			}
+		}
+		if opts.ThreadGUID != nil {
+			conditions = append(conditions, "thread_guid = ?")
+			args = append(args, *opts.ThreadGUID)
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at ASC, rowid ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetOpenDependencies returns the tasks a task depends on that are not done.
+func GetOpenDependencies(db *sql.DB, task types.Task) ([]types.Task, error) {
+	var open []types.Task
+	for _, guid := range task.DependsOn {
+		dep, err := GetTask(db, guid)
+		if err != nil {
+			return nil, err
+		}
+		if dep != nil && dep.Status != types.TaskStatusDone {
+			open = append(open, *dep)
+		}
+	}
+	return open, nil
+}
+
+// TaskDependsOn reports whether task (transitively) depends on target.
+func TaskDependsOn(db *sql.DB, task types.Task, target string) (bool, error) {
+	seen := map[string]struct{}{}
+	queue := append([]string{}, task.DependsOn...)
+	for len(queue) > 0 {
+		guid := queue[0]
+		queue = queue[1:]
+		if guid == target {
+			return true, nil
+		}
+		if _, ok := seen[guid]; ok {
+			continue
+		}
+		seen[guid] = struct{}{}
+		dep, err := GetTask(db, guid)
+		if err != nil {
+			return false, err
+		}
+		if dep != nil {
+			queue = append(queue, dep.DependsOn...)
+		}
+	}
+	return false, nil
+}
+
+func encodeTaskDependencies(depends []string) (string, error) {
+	if len(depends) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(depends)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func scanTask(scanner interface{ Scan(dest ...any) error }) (types.Task, error) {
+	var row taskRow
+	if err := scanner.Scan(&row.GUID, &row.Title, &row.CreatedBy, &row.Assignee, &row.Status, &row.ThreadGUID,
+		&row.MessageGUID, &row.DependsOn, &row.BlockedReason, &row.CreatedAt, &row.UpdatedAt); err != nil {
+		return types.Task{}, err
+	}
+	return row.toTask(), nil
+}
+
+func scanTasks(rows *sql.Rows) ([]types.Task, error) {
+	var tasks []types.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+type taskRow struct {
+	GUID          string
+	Title         string
+	CreatedBy     string
+	Assignee      sql.NullString
+	Status        sql.NullString
+	ThreadGUID    sql.NullString
+	MessageGUID   sql.NullString
+	DependsOn     sql.NullString
+	BlockedReason sql.NullString
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+func (row taskRow) toTask() types.Task {
+	status := types.TaskStatusTodo
+	if row.Status.Valid && row.Status.String != "" {
+		status = types.TaskStatus(row.Status.String)
+	}
+	var depends []string
+	if row.DependsOn.Valid && row.DependsOn.String != "" && row.DependsOn.String != "[]" {
+		_ = json.Unmarshal([]byte(row.DependsOn.String), &depends)
+	}
+	return types.Task{
+		GUID:          row.GUID,
+		Title:         row.Title,
+		CreatedBy:     row.CreatedBy,
+		Assignee:      nullStringPtr(row.Assignee),
+		Status:        status,
+		ThreadGUID:    nullStringPtr(row.ThreadGUID),
+		MessageGUID:   nullStringPtr(row.MessageGUID),
+		DependsOn:     depends,
+		BlockedReason: nullStringPtr(row.BlockedReason),
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
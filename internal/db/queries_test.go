@@ -135,6 +135,56 @@ func TestGetMessagesWithMentionUnread(t *testing.T) {
 	}
 }
 
+func TestSearchMessages(t *testing.T) {
+	db := openTestDB(t)
+	requireSchema(t, db)
+
+	bodies := []struct {
+		home string
+		body string
+	}{
+		{"room", "Deploy plan looks good"},
+		{"thrd-ops", "the DEPLOY failed at 50%"},
+		{"room", "unrelated chatter"},
+		{"room", "100% done"},
+	}
+	var ids []string
+	for _, entry := range bodies {
+		msg, err := CreateMessage(db, types.Message{
+			FromAgent: "alice.1",
+			Body:      entry.body,
+			Home:      entry.home,
+			Type:      types.MessageTypeAgent,
+		})
+		if err != nil {
+			t.Fatalf("create message: %v", err)
+		}
+		ids = append(ids, msg.ID)
+	}
+	if _, err := db.Exec("UPDATE fray_messages SET archived_at = 1 WHERE guid = ?", ids[0]); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	messages, err := SearchMessages(db, "deploy", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 matches across homes and archive, got %d", len(messages))
+	}
+	if messages[0].ID != ids[1] || messages[1].ID != ids[0] {
+		t.Fatalf("expected newest first, got %s, %s", messages[0].ID, messages[1].ID)
+	}
+
+	messages, err = SearchMessages(db, "%", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected %% to match literally, got %d", len(messages))
+	}
+}
+
 func TestClaimsConflicts(t *testing.T) {
 	db := openTestDB(t)
 	requireSchema(t, db)
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/adamavenir/fray/internal/types"
+)
+
+const threadSummaryColumns = `guid, thread_guid, message_guid, body, source, summarizer, trigger_type,
+	first_message_guid, last_message_guid, message_count, from_ts, to_ts, created_at`
+
+// CreateThreadSummary records a generated thread summary.
+func CreateThreadSummary(db *sql.DB, summary types.ThreadSummary) (types.ThreadSummary, error) {
+	if summary.GUID == "" {
+		guid, err := generateUniqueGUIDForTable(db, "fray_thread_summaries", "sum")
+		if err != nil {
+			return types.ThreadSummary{}, err
+		}
+		summary.GUID = guid
+	}
+	if summary.CreatedAt == 0 {
+		summary.CreatedAt = time.Now().Unix()
+	}
+	if err := insertThreadSummary(db, summary); err != nil {
+		return types.ThreadSummary{}, err
+	}
+	return summary, nil
+}
+
+func insertThreadSummary(db DBTX, summary types.ThreadSummary) error {
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO fray_thread_summaries (`+threadSummaryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, summary.GUID, summary.ThreadGUID, summary.MessageGUID, summary.Body, summary.Source, summary.Summarizer,
+		summary.Trigger, summary.FirstMessageGUID, summary.LastMessageGUID, summary.MessageCount,
+		summary.FromTS, summary.ToTS, summary.CreatedAt)
+	return err
+}
+
+// GetThreadSummaryByMessage returns the summary posted as the given message, if any.
+func GetThreadSummaryByMessage(db *sql.DB, messageGUID string) (*types.ThreadSummary, error) {
+	row := db.QueryRow(`SELECT `+threadSummaryColumns+` FROM fray_thread_summaries WHERE message_guid = ?`, messageGUID)
+	return scanThreadSummary(row)
+}
+
+// GetThreadSummaries returns a thread's summaries, oldest first.
+func GetThreadSummaries(db *sql.DB, threadGUID string) ([]types.ThreadSummary, error) {
+	rows, err := db.Query(`
+		SELECT `+threadSummaryColumns+`
+		FROM fray_thread_summaries
+		WHERE thread_guid = ?
+		ORDER BY created_at ASC, rowid ASC
+	`, threadGUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []types.ThreadSummary
+	for rows.Next() {
+		summary, err := scanThreadSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, rows.Err()
+}
+
+func scanThreadSummary(scanner interface{ Scan(dest ...any) error }) (*types.ThreadSummary, error) {
+	var summary types.ThreadSummary
+	err := scanner.Scan(&summary.GUID, &summary.ThreadGUID, &summary.MessageGUID, &summary.Body, &summary.Source,
+		&summary.Summarizer, &summary.Trigger, &summary.FirstMessageGUID, &summary.LastMessageGUID,
+		&summary.MessageCount, &summary.FromTS, &summary.ToTS, &summary.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -18,6 +19,10 @@ type ThreadUpdates struct {
 	AnchorMessageGUID types.OptionalString
 	AnchorHidden      types.OptionalBool
 	LastActivityAt    types.OptionalInt64
+	OwnerAgent        types.OptionalString
+	Writers           types.OptionalStrings
+	Readers           types.OptionalStrings
+	Curators          types.OptionalStrings
 }
 
 // CreateThread inserts a new thread.
@@ -50,9 +55,10 @@ func CreateThread(db *sql.DB, thread types.Thread) (types.Thread, error) {
 	}
 
 	_, err := db.Exec(`
-		INSERT INTO fray_threads (guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, guid, thread.Name, thread.ParentThread, string(status), string(threadType), createdAt, thread.AnchorMessageGUID, anchorHidden, thread.LastActivityAt)
+		INSERT INTO fray_threads (guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent, writers, readers, curators)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, guid, thread.Name, thread.ParentThread, string(status), string(threadType), createdAt, thread.AnchorMessageGUID, anchorHidden, thread.LastActivityAt, thread.OwnerAgent,
+		encodeAgentList(thread.Writers), encodeAgentList(thread.Readers), encodeAgentList(thread.Curators))
 	if err != nil {
 		return types.Thread{}, err
 	}
@@ -101,6 +107,22 @@ func UpdateThread(db *sql.DB, guid string, updates ThreadUpdates) (*types.Thread
 		fields = append(fields, "last_activity_at = ?")
 		args = append(args, updates.LastActivityAt.Value)
 	}
+	if updates.OwnerAgent.Set {
+		fields = append(fields, "owner_agent = ?")
+		args = append(args, nullableValue(updates.OwnerAgent.Value))
+	}
+	if updates.Writers.Set {
+		fields = append(fields, "writers = ?")
+		args = append(args, encodeAgentList(updates.Writers.Value))
+	}
+	if updates.Readers.Set {
+		fields = append(fields, "readers = ?")
+		args = append(args, encodeAgentList(updates.Readers.Value))
+	}
+	if updates.Curators.Set {
+		fields = append(fields, "curators = ?")
+		args = append(args, encodeAgentList(updates.Curators.Value))
+	}
 
 	if len(fields) == 0 {
 		return GetThread(db, guid)
@@ -117,7 +139,7 @@ func UpdateThread(db *sql.DB, guid string, updates ThreadUpdates) (*types.Thread
 // GetThread returns a thread by GUID.
 func GetThread(db *sql.DB, guid string) (*types.Thread, error) {
 	row := db.QueryRow(`
-		SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at
+		SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent, writers, readers, curators
 		FROM fray_threads WHERE guid = ?
 	`, guid)
 
@@ -134,7 +156,7 @@ func GetThread(db *sql.DB, guid string) (*types.Thread, error) {
 // GetThreadByPrefix returns the first thread matching a GUID prefix.
 func GetThreadByPrefix(db *sql.DB, prefix string) (*types.Thread, error) {
 	rows, err := db.Query(`
-		SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at
+		SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent, writers, readers, curators
 		FROM fray_threads
 		WHERE guid = ? OR guid LIKE ?
 		ORDER BY created_at ASC
@@ -159,12 +181,12 @@ func GetThreadByName(db *sql.DB, name string, parent *string) (*types.Thread, er
 	var row *sql.Row
 	if parent == nil {
 		row = db.QueryRow(`
-			SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at
+			SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent, writers, readers, curators
 			FROM fray_threads WHERE name = ? AND parent_thread IS NULL
 		`, name)
 	} else {
 		row = db.QueryRow(`
-			SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at
+			SELECT guid, name, parent_thread, status, type, created_at, anchor_message_guid, anchor_hidden, last_activity_at, owner_agent, writers, readers, curators
 			FROM fray_threads WHERE name = ? AND parent_thread = ?
 		`, name, *parent)
 	}
@@ -182,7 +204,7 @@ func GetThreadByName(db *sql.DB, name string, parent *string) (*types.Thread, er
 // GetThreads returns threads filtered by options.
 func GetThreads(db *sql.DB, options *types.ThreadQueryOptions) ([]types.Thread, error) {
 	query := `
-		SELECT DISTINCT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at
+		SELECT DISTINCT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at, t.owner_agent, t.writers, t.readers, t.curators
 		FROM fray_threads t
 	`
 	var conditions []string
@@ -305,7 +327,7 @@ func IsMessageInThread(db *sql.DB, threadGUID, messageGUID string) (bool, error)
 
 func scanThread(scanner interface{ Scan(dest ...any) error }) (types.Thread, error) {
 	var row threadRow
-	if err := scanner.Scan(&row.GUID, &row.Name, &row.ParentThread, &row.Status, &row.Type, &row.CreatedAt, &row.AnchorMessageGUID, &row.AnchorHidden, &row.LastActivityAt); err != nil {
+	if err := scanner.Scan(&row.GUID, &row.Name, &row.ParentThread, &row.Status, &row.Type, &row.CreatedAt, &row.AnchorMessageGUID, &row.AnchorHidden, &row.LastActivityAt, &row.OwnerAgent, &row.Writers, &row.Readers, &row.Curators); err != nil {
 		return types.Thread{}, err
 	}
 	return row.toThread(), nil
@@ -336,6 +358,10 @@ type threadRow struct {
 	AnchorMessageGUID sql.NullString
 	AnchorHidden      sql.NullInt64
 	LastActivityAt    sql.NullInt64
+	OwnerAgent        sql.NullString
+	Writers           sql.NullString
+	Readers           sql.NullString
+	Curators          sql.NullString
 }
 
 func (row threadRow) toThread() types.Thread {
@@ -364,9 +390,36 @@ func (row threadRow) toThread() types.Thread {
 	if row.LastActivityAt.Valid {
 		thread.LastActivityAt = &row.LastActivityAt.Int64
 	}
+	thread.OwnerAgent = nullStringPtr(row.OwnerAgent)
+	thread.Writers = decodeAgentList(row.Writers)
+	thread.Readers = decodeAgentList(row.Readers)
+	thread.Curators = decodeAgentList(row.Curators)
 	return thread
 }
 
+// encodeAgentList stores an ACL agent list as JSON; empty lists are stored as NULL.
+func encodeAgentList(agents []string) any {
+	if len(agents) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(agents)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+func decodeAgentList(value sql.NullString) []string {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	var agents []string
+	if err := json.Unmarshal([]byte(value.String), &agents); err != nil {
+		return nil
+	}
+	return agents
+}
+
 // PinMessage pins a message within a thread.
 func PinMessage(db *sql.DB, messageGUID, threadGUID, pinnedBy string, pinnedAt int64) error {
 	if pinnedAt == 0 {
@@ -484,7 +537,7 @@ func IsThreadPinned(db *sql.DB, threadGUID string) (bool, error) {
 // GetPinnedThreads returns all pinned threads.
 func GetPinnedThreads(db *sql.DB) ([]types.Thread, error) {
 	rows, err := db.Query(`
-		SELECT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at
+		SELECT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at, t.owner_agent, t.writers, t.readers, t.curators
 		FROM fray_threads t
 		INNER JOIN fray_thread_pins p ON p.thread_guid = t.guid
 		ORDER BY p.pinned_at ASC
@@ -539,7 +592,7 @@ func IsThreadMuted(db *sql.DB, threadGUID, agentID string) (bool, error) {
 func GetMutedThreads(db *sql.DB, agentID string) ([]types.Thread, error) {
 	now := time.Now().Unix()
 	rows, err := db.Query(`
-		SELECT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at
+		SELECT t.guid, t.name, t.parent_thread, t.status, t.type, t.created_at, t.anchor_message_guid, t.anchor_hidden, t.last_activity_at, t.owner_agent, t.writers, t.readers, t.curators
 		FROM fray_threads t
 		INNER JOIN fray_thread_mutes m ON m.thread_guid = t.guid
 		WHERE m.agent_id = ?
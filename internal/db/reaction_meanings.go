@@ -0,0 +1,182 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+// ReactionEffect reports what a reaction with a workflow meaning changed.
+type ReactionEffect struct {
+	Meaning   core.ReactionMeaning `json:"meaning"`
+	Answered  []string             `json:"answered,omitempty"`   // questions marked answered
+	Claim     *types.Claim         `json:"claim,omitempty"`      // ack claim held by the reactor
+	ClaimedBy string               `json:"claimed_by,omitempty"` // someone else already acked
+	Blocked   bool                 `json:"blocked,omitempty"`
+}
+
+// ReadReactionMeanings returns the project's reaction table: the defaults
+// with fray-config.json overrides applied.
+func ReadReactionMeanings(projectPath string) (map[string]core.ReactionMeaning, error) {
+	config, err := ReadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return core.ReactionMeanings(nil), nil
+	}
+	return core.ReactionMeanings(config.Reactions), nil
+}
+
+// ApplyReactionMeaning runs the workflow transition for a reaction that was
+// just added. It returns nil when the reaction has no meaning here.
+func ApplyReactionMeaning(db *sql.DB, projectPath string, msg types.Message, agentID, reaction string, reactedAt int64) (*ReactionEffect, error) {
+	meanings, err := ReadReactionMeanings(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	meaning, ok := meanings[reaction]
+	if !ok {
+		return nil, nil
+	}
+
+	effect := &ReactionEffect{Meaning: meaning}
+	switch meaning {
+	case core.ReactionAnswer:
+		questions, err := GetQuestions(db, &types.QuestionQueryOptions{
+			AskedIn:  &msg.ID,
+			Statuses: []types.QuestionStatus{types.QuestionStatusUnasked, types.QuestionStatusOpen},
+		})
+		if err != nil {
+			return nil, err
+		}
+		answered := string(types.QuestionStatusAnswered)
+		for _, question := range questions {
+			if _, err := UpdateQuestion(db, question.GUID, QuestionUpdates{
+				Status: types.OptionalString{Set: true, Value: &answered},
+			}); err != nil {
+				return nil, err
+			}
+			if err := AppendQuestionUpdate(projectPath, QuestionUpdateJSONLRecord{
+				GUID:   question.GUID,
+				Status: &answered,
+			}); err != nil {
+				return nil, err
+			}
+			effect.Answered = append(effect.Answered, question.GUID)
+		}
+
+	case core.ReactionAck:
+		if _, err := PruneExpiredClaims(db); err != nil {
+			return nil, err
+		}
+		existing, err := GetClaim(db, types.ClaimTypeMessage, msg.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.AgentID != agentID {
+			effect.ClaimedBy = existing.AgentID
+			return effect, nil
+		}
+		// Re-acking refreshes the expiry
+		if existing != nil {
+			if _, err := DeleteClaim(db, types.ClaimTypeMessage, msg.ID); err != nil {
+				return nil, err
+			}
+		}
+		expiresAt := reactedAt/1000 + core.ReactionAckTTL
+		reason := "on it"
+		claim, err := CreateClaim(db, types.ClaimInput{
+			AgentID:   agentID,
+			ClaimType: types.ClaimTypeMessage,
+			Pattern:   msg.ID,
+			Reason:    &reason,
+			ExpiresAt: &expiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		effect.Claim = claim
+
+	case core.ReactionBlock:
+		effect.Blocked = true
+	}
+	return effect, nil
+}
+
+// BlockingReaction is a blocking reaction on someone's message.
+type BlockingReaction struct {
+	MessageGUID string `json:"message_guid"`
+	Author      string `json:"author"`
+	BlockedBy   string `json:"blocked_by"`
+	Reaction    string `json:"reaction"`
+	ReactedAt   int64  `json:"reacted_at"`
+}
+
+// GetBlockingReactionsSince returns blocking reactions added after since
+// (reacted_at, in milliseconds), oldest first. Self-reactions are skipped.
+func GetBlockingReactionsSince(db *sql.DB, projectPath string, since int64) ([]BlockingReaction, error) {
+	meanings, err := ReadReactionMeanings(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	var placeholders []string
+	args := []any{since}
+	for reaction, meaning := range meanings {
+		if meaning == core.ReactionBlock {
+			placeholders = append(placeholders, "?")
+			args = append(args, reaction)
+		}
+	}
+	if len(placeholders) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.Query(`
+		SELECT r.message_guid, m.from_agent, r.agent_id, r.emoji, r.reacted_at
+		FROM fray_reactions r
+		JOIN fray_messages m ON m.guid = r.message_guid
+		WHERE r.reacted_at > ? AND r.emoji IN (`+strings.Join(placeholders, ",")+`)
+		  AND r.agent_id != m.from_agent
+		ORDER BY r.reacted_at ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocked []BlockingReaction
+	for rows.Next() {
+		var entry BlockingReaction
+		if err := rows.Scan(&entry.MessageGUID, &entry.Author, &entry.BlockedBy, &entry.Reaction, &entry.ReactedAt); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, entry)
+	}
+	return blocked, rows.Err()
+}
+
+// Summary describes the effect in a short phrase for command and chat output.
+func (e *ReactionEffect) Summary() string {
+	if e == nil {
+		return ""
+	}
+	switch e.Meaning {
+	case core.ReactionAnswer:
+		if len(e.Answered) == 0 {
+			return "no open questions to answer"
+		}
+		return "answered " + strings.Join(e.Answered, ", ")
+	case core.ReactionAck:
+		if e.ClaimedBy != "" {
+			return "already acked by @" + e.ClaimedBy
+		}
+		return fmt.Sprintf("acked for %dm", core.ReactionAckTTL/60)
+	case core.ReactionBlock:
+		return "marked blocked"
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+const scheduledLockFile = "scheduled.lock"
+
+// scheduledLockStale is how long a delivery lock may be held before another
+// process assumes its holder died and takes over.
+const scheduledLockStale = time.Minute
+
+// ErrScheduledNotPending is returned when cancelling a message that was already
+// delivered or cancelled.
+var ErrScheduledNotPending = errors.New("scheduled message is not pending")
+
+// AppendScheduledMessage queues a message for later delivery in scheduled.jsonl.
+func AppendScheduledMessage(projectPath string, scheduled types.ScheduledMessage) (types.ScheduledMessage, error) {
+	if scheduled.GUID == "" {
+		guid, err := core.GenerateGUID("sched")
+		if err != nil {
+			return types.ScheduledMessage{}, err
+		}
+		scheduled.GUID = guid
+	}
+	if scheduled.CreatedAt == 0 {
+		scheduled.CreatedAt = time.Now().Unix()
+	}
+	if scheduled.Home == "" {
+		scheduled.Home = "room"
+	}
+	if scheduled.Type == "" {
+		scheduled.Type = types.MessageTypeAgent
+	}
+
+	record := ScheduledMessageJSONLRecord{
+		Type:      "scheduled_message",
+		GUID:      scheduled.GUID,
+		FromAgent: scheduled.FromAgent,
+		Home:      scheduled.Home,
+		Body:      scheduled.Body,
+		MsgType:   scheduled.Type,
+		SendAt:    scheduled.SendAt,
+		CreatedAt: scheduled.CreatedAt,
+	}
+	if err := appendJSONLine(filepath.Join(resolveFrayDir(projectPath), scheduledFile), record); err != nil {
+		return types.ScheduledMessage{}, err
+	}
+	return scheduled, nil
+}
+
+// ReadPendingScheduled returns scheduled messages that have been neither sent
+// nor cancelled, soonest first.
+func ReadPendingScheduled(projectPath string) ([]types.ScheduledMessage, error) {
+	lines, err := readJSONLLines(filepath.Join(resolveFrayDir(projectPath), scheduledFile))
+	if err != nil {
+		return nil, err
+	}
+
+	pending := map[string]types.ScheduledMessage{}
+	for _, line := range lines {
+		var envelope struct {
+			Type string `json:"type"`
+			GUID string `json:"guid"`
+		}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			continue
+		}
+		switch envelope.Type {
+		case "scheduled_message":
+			var record ScheduledMessageJSONLRecord
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				continue
+			}
+			pending[record.GUID] = types.ScheduledMessage{
+				GUID:      record.GUID,
+				FromAgent: record.FromAgent,
+				Home:      record.Home,
+				Body:      record.Body,
+				Type:      record.MsgType,
+				SendAt:    record.SendAt,
+				CreatedAt: record.CreatedAt,
+			}
+		case "scheduled_cancel", "scheduled_sent":
+			delete(pending, envelope.GUID)
+		}
+	}
+
+	result := make([]types.ScheduledMessage, 0, len(pending))
+	for _, scheduled := range pending {
+		result = append(result, scheduled)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SendAt == result[j].SendAt {
+			return result[i].CreatedAt < result[j].CreatedAt
+		}
+		return result[i].SendAt < result[j].SendAt
+	})
+	return result, nil
+}
+
+// CancelScheduledMessage cancels a pending scheduled message.
+func CancelScheduledMessage(projectPath, guid, cancelledBy string) error {
+	pending, err := ReadPendingScheduled(projectPath)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, scheduled := range pending {
+		if scheduled.GUID == guid {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrScheduledNotPending
+	}
+
+	record := ScheduledCancelJSONLRecord{
+		Type:        "scheduled_cancel",
+		GUID:        guid,
+		CancelledBy: cancelledBy,
+		CancelledAt: time.Now().Unix(),
+	}
+	return appendJSONLine(filepath.Join(resolveFrayDir(projectPath), scheduledFile), record)
+}
+
+// MatchScheduled finds a pending scheduled message by GUID or unique prefix,
+// with or without the "sched-" prefix.
+func MatchScheduled(pending []types.ScheduledMessage, ref string) (types.ScheduledMessage, error) {
+	ref = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ref)), "#")
+	var matches []types.ScheduledMessage
+	for _, scheduled := range pending {
+		if scheduled.GUID == ref || scheduled.GUID == "sched-"+ref {
+			return scheduled, nil
+		}
+		if strings.HasPrefix(scheduled.GUID, ref) || strings.HasPrefix(scheduled.GUID, "sched-"+ref) {
+			matches = append(matches, scheduled)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return types.ScheduledMessage{}, fmt.Errorf("no pending scheduled message: %s", ref)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, scheduled := range matches {
+		ids = append(ids, scheduled.GUID)
+	}
+	return types.ScheduledMessage{}, fmt.Errorf("ambiguous scheduled message %s: %s", ref, strings.Join(ids, ", "))
+}
+
+// DeliverDueScheduled posts every pending scheduled message whose send time has
+// passed and returns the created messages. A lock file in .fray/ keeps the
+// daemon and concurrent CLI invocations from delivering the same message twice;
+// if another process holds it, nothing is delivered.
+func DeliverDueScheduled(db *sql.DB, projectPath string, now time.Time) ([]types.Message, error) {
+	pending, err := ReadPendingScheduled(projectPath)
+	if err != nil || len(pending) == 0 || pending[0].SendAt > now.Unix() {
+		return nil, err
+	}
+
+	release, ok := acquireScheduledLock(projectPath, now)
+	if !ok {
+		return nil, nil
+	}
+	defer release()
+
+	// Re-read under the lock: another process may have delivered meanwhile.
+	pending, err = ReadPendingScheduled(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := GetAgentBases(db)
+	if err != nil {
+		return nil, err
+	}
+	users, _ := GetActiveUsers(db)
+	for _, user := range users {
+		bases[user] = struct{}{}
+	}
+
+	var delivered []types.Message
+	for _, scheduled := range pending {
+		if scheduled.SendAt > now.Unix() {
+			break
+		}
+		home := scheduled.Home
+		if home == "room" {
+			home = ""
+		}
+		mentions := core.ExtractMentions(scheduled.Body, bases)
+		mentions = core.ExpandAllMention(mentions, bases)
+		created, err := CreateMessage(db, types.Message{
+			TS:        now.Unix(),
+			FromAgent: scheduled.FromAgent,
+			Body:      scheduled.Body,
+			Mentions:  mentions,
+			Home:      home,
+			Type:      scheduled.Type,
+		})
+		if err != nil {
+			return delivered, err
+		}
+		if err := AppendMessage(projectPath, created); err != nil {
+			return delivered, err
+		}
+		// Cross-channel copies are best effort; the local send already landed
+		_, _ = ForwardChannelMentions(projectPath, created)
+		if err := appendJSONLine(filepath.Join(resolveFrayDir(projectPath), scheduledFile), ScheduledSentJSONLRecord{
+			Type:        "scheduled_sent",
+			GUID:        scheduled.GUID,
+			MessageGUID: created.ID,
+			SentAt:      now.Unix(),
+		}); err != nil {
+			return delivered, err
+		}
+		delivered = append(delivered, created)
+	}
+	return delivered, nil
+}
+
+func acquireScheduledLock(projectPath string, now time.Time) (func(), bool) {
+	lockPath := filepath.Join(resolveFrayDir(projectPath), scheduledLockFile)
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, true
+		}
+		info, statErr := os.Stat(lockPath)
+		if statErr != nil || now.Sub(info.ModTime()) < scheduledLockStale {
+			return nil, false
+		}
+		_ = os.Remove(lockPath)
+	}
+	return nil, false
+}
@@ -58,7 +58,10 @@ CREATE TABLE IF NOT EXISTS fray_messages (
   quote_message_guid TEXT,             -- quoted message guid for inline quotes
   edited_at INTEGER,                   -- unix timestamp of last edit
   archived_at INTEGER,                 -- unix timestamp of archival
-  reactions TEXT NOT NULL DEFAULT '{}' -- JSON object of reactions
+  reactions TEXT NOT NULL DEFAULT '{}', -- JSON object of reactions
+  attachments TEXT,                    -- JSON array of attachment metadata (blobs in .fray/blobs/)
+  signature TEXT,                      -- author's ed25519 signature of the current body
+  signature_status TEXT                -- verified, unverified, invalid, or NULL when the author has no key
 );
 
 CREATE INDEX IF NOT EXISTS idx_fray_messages_ts ON fray_messages(ts);
@@ -83,6 +86,24 @@ CREATE TABLE IF NOT EXISTS fray_questions (
 CREATE INDEX IF NOT EXISTS idx_fray_questions_status ON fray_questions(status);
 CREATE INDEX IF NOT EXISTS idx_fray_questions_thread ON fray_questions(thread_guid);
 
+-- Tasks
+CREATE TABLE IF NOT EXISTS fray_tasks (
+  guid TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  created_by TEXT NOT NULL,
+  assignee TEXT,
+  status TEXT DEFAULT 'todo',
+  thread_guid TEXT,
+  message_guid TEXT,
+  depends_on TEXT DEFAULT '[]',    -- JSON array of task GUIDs
+  blocked_reason TEXT,
+  created_at INTEGER NOT NULL,
+  updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fray_tasks_status ON fray_tasks(status);
+CREATE INDEX IF NOT EXISTS idx_fray_tasks_assignee ON fray_tasks(assignee);
+
 -- Threads
 CREATE TABLE IF NOT EXISTS fray_threads (
   guid TEXT PRIMARY KEY,
@@ -94,6 +115,10 @@ CREATE TABLE IF NOT EXISTS fray_threads (
   anchor_message_guid TEXT,
   anchor_hidden INTEGER NOT NULL DEFAULT 0,
   last_activity_at INTEGER,
+  owner_agent TEXT,
+  writers TEXT,                  -- JSON array; NULL/empty = anyone
+  readers TEXT,                  -- JSON array; NULL/empty = anyone
+  curators TEXT,                 -- JSON array; NULL/empty = owner (or anyone if unowned)
   FOREIGN KEY (parent_thread) REFERENCES fray_threads(guid)
 );
 
@@ -156,7 +181,7 @@ CREATE INDEX IF NOT EXISTS idx_fray_thread_mutes_expires ON fray_thread_mutes(ex
 -- Linked projects for cross-project messaging
 CREATE TABLE IF NOT EXISTS fray_linked_projects (
   alias TEXT PRIMARY KEY,
-  path TEXT NOT NULL                     -- absolute path to .fray directory
+  path TEXT NOT NULL                     -- absolute path to the linked fray.db
 );
 
 -- Configuration
@@ -207,6 +232,43 @@ CREATE TABLE IF NOT EXISTS fray_ghost_cursors (
 
 CREATE INDEX IF NOT EXISTS idx_fray_ghost_cursors_agent ON fray_ghost_cursors(agent_id);
 
+-- Agent-to-agent handoffs
+CREATE TABLE IF NOT EXISTS fray_handoffs (
+  guid TEXT PRIMARY KEY,
+  from_agent TEXT NOT NULL,
+  to_agent TEXT NOT NULL,
+  home TEXT NOT NULL,                -- "room" or thread GUID
+  message_guid TEXT NOT NULL,        -- posted handoff message
+  summary TEXT,
+  claims TEXT NOT NULL DEFAULT '[]', -- JSON array of transferred type:pattern claims
+  cursor_message_guid TEXT,
+  owner_transferred INTEGER NOT NULL DEFAULT 0,
+  created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fray_handoffs_message ON fray_handoffs(message_guid);
+CREATE INDEX IF NOT EXISTS idx_fray_handoffs_to ON fray_handoffs(to_agent);
+
+-- Generated thread summaries (anchor provenance)
+CREATE TABLE IF NOT EXISTS fray_thread_summaries (
+  guid TEXT PRIMARY KEY,
+  thread_guid TEXT NOT NULL,
+  message_guid TEXT NOT NULL,        -- anchor message holding the summary
+  body TEXT NOT NULL,
+  source TEXT NOT NULL,              -- "command" or "agent"
+  summarizer TEXT NOT NULL,
+  trigger_type TEXT NOT NULL,        -- "archive" or "prune"
+  first_message_guid TEXT NOT NULL,
+  last_message_guid TEXT NOT NULL,
+  message_count INTEGER NOT NULL,
+  from_ts INTEGER NOT NULL,
+  to_ts INTEGER NOT NULL,
+  created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fray_thread_summaries_thread ON fray_thread_summaries(thread_guid);
+CREATE INDEX IF NOT EXISTS idx_fray_thread_summaries_message ON fray_thread_summaries(message_guid);
+
 -- Resource claims for collision prevention
 CREATE TABLE IF NOT EXISTS fray_claims (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -216,6 +278,7 @@ CREATE TABLE IF NOT EXISTS fray_claims (
   reason TEXT,
   created_at INTEGER NOT NULL,
   expires_at INTEGER,              -- null = no expiry
+  message_guid TEXT,               -- claim or status message that announced it
   UNIQUE(claim_type, pattern)
 );
 
@@ -644,6 +707,21 @@ func migrateSchema(db DBTX) error {
 				return err
 			}
 		}
+		if !hasColumn(messageColumns, "attachments") {
+			if _, err := db.Exec("ALTER TABLE fray_messages ADD COLUMN attachments TEXT"); err != nil {
+				return err
+			}
+		}
+		if !hasColumn(messageColumns, "signature") {
+			if _, err := db.Exec("ALTER TABLE fray_messages ADD COLUMN signature TEXT"); err != nil {
+				return err
+			}
+		}
+		if !hasColumn(messageColumns, "signature_status") {
+			if _, err := db.Exec("ALTER TABLE fray_messages ADD COLUMN signature_status TEXT"); err != nil {
+				return err
+			}
+		}
 	}
 
 	receiptColumns, err := getTableInfo(db, "fray_read_receipts")
@@ -779,6 +857,28 @@ func migrateSchema(db DBTX) error {
 				return err
 			}
 		}
+		if !hasColumn(threadColumns, "owner_agent") {
+			if _, err := db.Exec("ALTER TABLE fray_threads ADD COLUMN owner_agent TEXT"); err != nil {
+				return err
+			}
+		}
+		for _, column := range []string{"writers", "readers", "curators"} {
+			if !hasColumn(threadColumns, column) {
+				if _, err := db.Exec("ALTER TABLE fray_threads ADD COLUMN " + column + " TEXT"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	claimColumns, err := getTableInfo(db, "fray_claims")
+	if err != nil {
+		return err
+	}
+	if len(claimColumns) > 0 && !hasColumn(claimColumns, "message_guid") {
+		if _, err := db.Exec("ALTER TABLE fray_claims ADD COLUMN message_guid TEXT"); err != nil {
+			return err
+		}
 	}
 
 	return nil
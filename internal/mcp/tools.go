@@ -102,6 +102,7 @@ func handlePost(ctx ToolContext, body string) *mcp.CallToolResult {
 		return toolError(err.Error())
 	}
 	_ = db.AppendMessage(ctx.Project.DBPath, created)
+	forwarded, _ := db.ForwardChannelMentions(ctx.Project.DBPath, created)
 
 	updates := db.AgentUpdates{LastSeen: types.OptionalInt64{Set: true, Value: &now}}
 	_ = db.UpdateAgent(ctx.DB, ctx.AgentID, updates)
@@ -110,6 +111,9 @@ func handlePost(ctx ToolContext, body string) *mcp.CallToolResult {
 	if len(mentions) > 0 {
 		mentionInfo = fmt.Sprintf(" (mentioned: %s)", strings.Join(mentions, ", "))
 	}
+	for _, fwd := range forwarded {
+		mentionInfo += fmt.Sprintf(" (forwarded to #%s)", fwd.Channel)
+	}
 	return toolResult(fmt.Sprintf("Posted message #%s%s", created.ID, mentionInfo), false)
 }
 
@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stateLockStale is how long a state lock may be held before it is assumed
+// abandoned.
+const stateLockStale = 30 * time.Second
+
+// digestState is the on-disk queue shared by chat, the daemon and CLI commands.
+type digestState struct {
+	Queued []queuedEvent `json:"queued"`
+}
+
+type queuedEvent struct {
+	Sink     string    `json:"sink"`
+	Rule     string    `json:"rule,omitempty"`
+	Event    Event     `json:"event"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// DefaultStatePath returns ~/.config/fray/notify-digest.json.
+func DefaultStatePath() (string, error) {
+	path, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(path, ".config", "fray", "notify-digest.json"), nil
+}
+
+// Pending returns the number of queued notifications.
+func (n *Notifier) Pending() (int, error) {
+	state, err := n.readState()
+	if err != nil {
+		return 0, err
+	}
+	return len(state.Queued), nil
+}
+
+// Flush sends queued notifications as one digest per sink, once the oldest has
+// waited a full digest interval and quiet hours are over. Chat and the daemon
+// call it on every tick.
+func (n *Notifier) Flush() error {
+	return n.flush(false)
+}
+
+// FlushNow sends all queued notifications regardless of interval and quiet
+// hours.
+func (n *Notifier) FlushNow() error {
+	return n.flush(true)
+}
+
+func (n *Notifier) flush(force bool) error {
+	interval, _ := n.config.Digest()
+	now := n.now()
+	if !force && n.config.InQuietHours(now) {
+		return nil
+	}
+	ready := func(state digestState) bool {
+		return len(state.Queued) > 0 && (force || now.Sub(state.Queued[0].QueuedAt) >= interval)
+	}
+	// Check without the lock first; this runs on every chat and daemon tick.
+	if state, err := n.readState(); err != nil || !ready(state) {
+		return err
+	}
+
+	var due []queuedEvent
+	err := n.withState(func(state *digestState) bool {
+		if !ready(*state) {
+			return false
+		}
+		due = state.Queued
+		state.Queued = nil
+		return true
+	})
+	if err != nil || len(due) == 0 {
+		return err
+	}
+
+	bySink := map[string][]queuedEvent{}
+	for _, item := range due {
+		bySink[item.Sink] = append(bySink[item.Sink], item)
+	}
+	names := make([]string, 0, len(bySink))
+	for name := range bySink {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		sink, ok := n.sinks[name]
+		if !ok {
+			continue
+		}
+		if err := sink.Send(digestNotification(bySink[name])); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notify digest: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func digestNotification(items []queuedEvent) Notification {
+	if len(items) == 1 {
+		event := items[0].Event
+		return Notification{Rule: items[0].Rule, Title: event.Title, Body: event.Body, Events: []Event{event}}
+	}
+	events := make([]Event, 0, len(items))
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		events = append(events, item.Event)
+		lines = append(lines, fmt.Sprintf("%s: %s", item.Event.Title, item.Event.Body))
+	}
+	return Notification{
+		Rule:   "digest",
+		Title:  fmt.Sprintf("fray · %d notifications", len(items)),
+		Body:   strings.Join(lines, "\n"),
+		Events: events,
+	}
+}
+
+func (n *Notifier) enqueue(items []queuedEvent) error {
+	return n.withState(func(state *digestState) bool {
+		state.Queued = append(state.Queued, items...)
+		return true
+	})
+}
+
+// withState runs fn on the state under a lock file, saving it when fn
+// returns true.
+func (n *Notifier) withState(fn func(*digestState) bool) error {
+	if err := os.MkdirAll(filepath.Dir(n.statePath), 0o755); err != nil {
+		return err
+	}
+	lockPath := n.statePath + ".lock"
+	locked := false
+	for attempt := 0; attempt < 50; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			locked = true
+			break
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > stateLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !locked {
+		return fmt.Errorf("notify state is locked: %s", lockPath)
+	}
+	defer os.Remove(lockPath)
+
+	state, err := n.readState()
+	if err != nil {
+		return err
+	}
+	if !fn(&state) {
+		return nil
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := n.statePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, n.statePath)
+}
+
+func (n *Notifier) readState() (digestState, error) {
+	var state digestState
+	data, err := os.ReadFile(n.statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("%s: %w", n.statePath, err)
+	}
+	return state, nil
+}
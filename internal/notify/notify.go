@@ -0,0 +1,248 @@
+// Package notify routes fray events (mentions, questions, claim conflicts,
+// daemon errors) to desktop notifications, shell hooks, and webhooks according
+// to the user's rules in ~/.config/fray/notify.json.
+package notify
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+)
+
+// Event is something that may warrant a notification.
+type Event struct {
+	Kinds      []string  `json:"kinds"` // core.Notify* kinds; a message can be both a mention and a reply
+	Project    string    `json:"project,omitempty"`
+	From       string    `json:"from,omitempty"`
+	ThreadGUID string    `json:"thread_guid,omitempty"`
+	ThreadPath string    `json:"thread_path,omitempty"`
+	MessageID  string    `json:"message_id,omitempty"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	At         time.Time `json:"at"`
+}
+
+// Notification is what a sink delivers: one event, or a digest of several.
+type Notification struct {
+	Rule   string  `json:"rule,omitempty"`
+	Title  string  `json:"title"`
+	Body   string  `json:"body"`
+	Events []Event `json:"events"`
+}
+
+// Options configures a Notifier.
+type Options struct {
+	// OS delivers desktop notifications; defaults to a beeep-backed sink.
+	OS Sink
+	// StatePath holds queued digest and quiet-hours notifications; defaults to
+	// ~/.config/fray/notify-digest.json.
+	StatePath string
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Notifier matches events against rules and delivers them to sinks.
+type Notifier struct {
+	config    *core.NotifyConfig
+	sinks     map[string]Sink
+	statePath string
+	now       func() time.Time
+}
+
+// Load builds a Notifier from the user's notify.json, or the default rules
+// when there is none.
+func Load(opts Options) (*Notifier, error) {
+	config, err := core.ReadNotifyConfig()
+	if err != nil {
+		return nil, err
+	}
+	return New(config, opts)
+}
+
+// New builds a Notifier for a config.
+func New(config *core.NotifyConfig, opts Options) (*Notifier, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.OS == nil {
+		opts.OS = OSSink{}
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.StatePath == "" {
+		statePath, err := DefaultStatePath()
+		if err != nil {
+			return nil, err
+		}
+		opts.StatePath = statePath
+	}
+
+	// Name unnamed rules by position so notifications and listings can refer
+	// to them.
+	named := *config
+	named.Rules = make([]core.NotifyRule, len(config.Rules))
+	for i, rule := range config.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("#%d", i+1)
+		}
+		named.Rules[i] = rule
+	}
+	config = &named
+
+	sinks := map[string]Sink{core.NotifySinkOS: opts.OS}
+	for name, sink := range config.Sinks {
+		switch sink.Type {
+		case core.NotifySinkOS:
+			sinks[name] = opts.OS
+		case core.NotifySinkCommand:
+			sinks[name] = CommandSink{Command: sink.Command}
+		case core.NotifySinkWebhook:
+			sinks[name] = WebhookSink{URL: sink.URL, Headers: sink.Headers}
+		}
+	}
+	return &Notifier{config: config, sinks: sinks, statePath: opts.StatePath, now: opts.Now}, nil
+}
+
+// Config returns the rules the notifier applies.
+func (n *Notifier) Config() *core.NotifyConfig {
+	return n.config
+}
+
+// Sink returns a configured sink by name.
+func (n *Notifier) Sink(name string) (Sink, bool) {
+	sink, ok := n.sinks[name]
+	return sink, ok
+}
+
+// SinkNames returns the configured sink names, sorted.
+func (n *Notifier) SinkNames() []string {
+	names := make([]string, 0, len(n.sinks))
+	for name := range n.sinks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Wants reports whether any rule could fire for an event kind, so callers can
+// skip gathering events nobody listens for.
+func (n *Notifier) Wants(kind string) bool {
+	for _, rule := range n.config.Rules {
+		if !rule.Ignore && (len(rule.On) == 0 || slices.Contains(rule.On, kind)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the rules that fire for an event. Rules apply in order; an
+// ignore rule that matches stops evaluation.
+func (n *Notifier) Match(event Event) []core.NotifyRule {
+	var matched []core.NotifyRule
+	for _, rule := range n.config.Rules {
+		if !ruleMatches(rule, event) {
+			continue
+		}
+		if rule.Ignore {
+			break
+		}
+		matched = append(matched, rule)
+	}
+	return matched
+}
+
+// Notify delivers an event to the sinks of every matching rule, once per sink.
+// Digest rules, and non-urgent rules during quiet hours, queue the event for
+// the next Flush instead.
+func (n *Notifier) Notify(event Event) error {
+	if event.At.IsZero() {
+		event.At = n.now()
+	}
+	quiet := n.config.InQuietHours(n.now())
+
+	sent := map[string]bool{}
+	var queued []queuedEvent
+	var errs []string
+	for _, rule := range n.Match(event) {
+		for _, sinkName := range ruleSinks(rule) {
+			if sent[sinkName] {
+				continue
+			}
+			sent[sinkName] = true
+			if rule.Digest || (quiet && !rule.Urgent) {
+				queued = append(queued, queuedEvent{Sink: sinkName, Rule: rule.Name, Event: event, QueuedAt: n.now()})
+				continue
+			}
+			sink, ok := n.sinks[sinkName]
+			if !ok {
+				continue
+			}
+			notification := Notification{Rule: rule.Name, Title: event.Title, Body: event.Body, Events: []Event{event}}
+			if err := sink.Send(notification); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", sinkName, err))
+			}
+		}
+	}
+	if len(queued) > 0 {
+		if err := n.enqueue(queued); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notify: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func ruleSinks(rule core.NotifyRule) []string {
+	if len(rule.Sinks) == 0 {
+		return []string{core.NotifySinkOS}
+	}
+	return rule.Sinks
+}
+
+func ruleMatches(rule core.NotifyRule, event Event) bool {
+	if len(rule.On) > 0 && !slices.ContainsFunc(event.Kinds, func(kind string) bool {
+		return slices.Contains(rule.On, kind)
+	}) {
+		return false
+	}
+	if len(rule.Keywords) > 0 {
+		text := strings.ToLower(event.Title + "\n" + event.Body)
+		if !slices.ContainsFunc(rule.Keywords, func(keyword string) bool {
+			return strings.Contains(text, strings.ToLower(keyword))
+		}) {
+			return false
+		}
+	}
+	if len(rule.From) > 0 && !slices.ContainsFunc(rule.From, func(agent string) bool {
+		return event.From != "" && core.MatchesMention(event.From, strings.TrimPrefix(agent, "@"))
+	}) {
+		return false
+	}
+	if len(rule.Threads) > 0 && !slices.ContainsFunc(rule.Threads, func(pattern string) bool {
+		return matchThread(pattern, event)
+	}) {
+		return false
+	}
+	return true
+}
+
+func matchThread(pattern string, event Event) bool {
+	if pattern == "room" {
+		return event.ThreadGUID == ""
+	}
+	if event.ThreadGUID == "" {
+		return false
+	}
+	if pattern == event.ThreadGUID {
+		return true
+	}
+	matched, _ := path.Match(pattern, event.ThreadPath)
+	return matched
+}
@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+)
+
+type recordingSink struct {
+	sent []Notification
+}
+
+func (s *recordingSink) Send(n Notification) error {
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func newTestNotifier(t *testing.T, config *core.NotifyConfig, now *time.Time) (*Notifier, *recordingSink) {
+	t.Helper()
+	sink := &recordingSink{}
+	notifier, err := New(config, Options{
+		OS:        sink,
+		StatePath: filepath.Join(t.TempDir(), "notify-digest.json"),
+		Now:       func() time.Time { return *now },
+	})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	return notifier, sink
+}
+
+func TestRuleMatching(t *testing.T) {
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.Local)
+	notifier, sink := newTestNotifier(t, &core.NotifyConfig{Rules: []core.NotifyRule{
+		{Name: "no-bots", From: []string{"ci"}, Ignore: true},
+		{Name: "deploys", On: []string{core.NotifyMessage}, Keywords: []string{"Deploy"}},
+		{Name: "design", On: []string{core.NotifyMessage}, Threads: []string{"design/*"}, From: []string{"opus"}},
+		{Name: "mentions", On: []string{core.NotifyMention}},
+	}}, &now)
+
+	cases := []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{"keyword", Event{Kinds: []string{core.NotifyMessage}, From: "alice", Body: "deploy finished"}, []string{"deploys"}},
+		{"ignored author", Event{Kinds: []string{core.NotifyMessage, core.NotifyMention}, From: "ci.runner", Body: "deploy failed"}, nil},
+		{"thread glob and author prefix", Event{Kinds: []string{core.NotifyMessage}, From: "opus.frontend", ThreadGUID: "thrd-1", ThreadPath: "design/api"}, []string{"design"}},
+		{"thread glob miss", Event{Kinds: []string{core.NotifyMessage}, From: "opus", ThreadGUID: "thrd-2", ThreadPath: "design/api/v2"}, nil},
+		{"mention", Event{Kinds: []string{core.NotifyMessage, core.NotifyMention}, From: "bob", Body: "@adam hi"}, []string{"mentions"}},
+	}
+	for _, tc := range cases {
+		var got []string
+		for _, rule := range notifier.Match(tc.event) {
+			got = append(got, rule.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: matched %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	// Two rules routing to the same sink deliver once.
+	if err := notifier.Notify(Event{Kinds: []string{core.NotifyMessage, core.NotifyMention}, From: "bob", Body: "@adam deploy?"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(sink.sent) != 1 || sink.sent[0].Rule != "deploys" {
+		t.Fatalf("expected one notification from the first matching rule, got %+v", sink.sent)
+	}
+}
+
+func TestQuietHoursAndDigest(t *testing.T) {
+	now := time.Date(2026, 1, 5, 23, 0, 0, 0, time.Local)
+	notifier, sink := newTestNotifier(t, &core.NotifyConfig{
+		Rules: []core.NotifyRule{
+			{Name: "urgent", On: []string{core.NotifyDaemonError}, Urgent: true},
+			{Name: "mentions", On: []string{core.NotifyMention}},
+			{Name: "questions", On: []string{core.NotifyQuestion}, Digest: true},
+		},
+		QuietHours:     &core.QuietHours{Start: "22:00", End: "07:30"},
+		DigestInterval: "10m",
+	}, &now)
+
+	for _, event := range []Event{
+		{Kinds: []string{core.NotifyMention}, Title: "@bob", Body: "one"},
+		{Kinds: []string{core.NotifyMention}, Title: "@bob", Body: "two"},
+		{Kinds: []string{core.NotifyDaemonError}, Title: "daemon", Body: "crashed"},
+	} {
+		if err := notifier.Notify(event); err != nil {
+			t.Fatalf("notify: %v", err)
+		}
+	}
+	if len(sink.sent) != 1 || sink.sent[0].Body != "crashed" {
+		t.Fatalf("expected only the urgent notification during quiet hours, got %+v", sink.sent)
+	}
+
+	now = time.Date(2026, 1, 6, 7, 0, 0, 0, time.Local)
+	if err := notifier.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if len(sink.sent) != 1 {
+		t.Fatalf("expected nothing flushed before quiet hours end, got %d", len(sink.sent))
+	}
+
+	now = time.Date(2026, 1, 6, 8, 0, 0, 0, time.Local)
+	if err := notifier.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if len(sink.sent) != 2 || len(sink.sent[1].Events) != 2 || sink.sent[1].Rule != "digest" {
+		t.Fatalf("expected one digest of two events, got %+v", sink.sent)
+	}
+
+	// Digest rules wait out the interval even outside quiet hours.
+	if err := notifier.Notify(Event{Kinds: []string{core.NotifyQuestion}, Title: "@bob asks", Body: "ship it?"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	now = now.Add(5 * time.Minute)
+	_ = notifier.Flush()
+	if pending, _ := notifier.Pending(); pending != 1 || len(sink.sent) != 2 {
+		t.Fatalf("expected digest to wait, pending=%d sent=%d", pending, len(sink.sent))
+	}
+	now = now.Add(5 * time.Minute)
+	_ = notifier.Flush()
+	if pending, _ := notifier.Pending(); pending != 0 || len(sink.sent) != 3 || sink.sent[2].Body != "ship it?" {
+		t.Fatalf("expected digest sent after interval, pending=%d sent=%+v", pending, sink.sent)
+	}
+}
+
+func TestWebhookAndCommandSinks(t *testing.T) {
+	received := make(chan Notification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- n
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	outPath := filepath.Join(t.TempDir(), "hook.out")
+	now := time.Now()
+	notifier, _ := newTestNotifier(t, &core.NotifyConfig{
+		Rules: []core.NotifyRule{{Name: "claims", On: []string{core.NotifyClaimConflict}, Sinks: []string{"hook", "ops"}}},
+		Sinks: map[string]core.NotifySink{
+			"hook": {Type: core.NotifySinkCommand, Command: `printf '%s|' "$FRAY_NOTIFY_TITLE" > "` + outPath + `"; cat >> "` + outPath + `"`},
+			"ops":  {Type: core.NotifySinkWebhook, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}},
+		},
+	}, &now)
+
+	event := Event{Kinds: []string{core.NotifyClaimConflict}, Project: "fray", From: "bob", Title: "fray · 1 claim conflict(s)", Body: "@bob holds src/*"}
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	select {
+	case n := <-received:
+		if n.Rule != "claims" || len(n.Events) != 1 || n.Events[0].From != "bob" {
+			t.Fatalf("unexpected webhook payload: %+v", n)
+		}
+	default:
+		t.Fatal("webhook not called")
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read hook output: %v", err)
+	}
+	title, payload, ok := strings.Cut(string(data), "|")
+	if !ok || title != event.Title || !strings.Contains(payload, `"body":"@bob holds src/*"`) {
+		t.Fatalf("unexpected hook output: %q", data)
+	}
+
+	server.Close()
+	if err := notifier.Notify(event); err == nil || !strings.Contains(err.Error(), "ops") {
+		t.Fatalf("expected webhook failure to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+const (
+	commandTimeout = 10 * time.Second
+	webhookTimeout = 5 * time.Second
+)
+
+// Sink delivers notifications.
+type Sink interface {
+	Send(Notification) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(Notification) error
+
+// Send calls f.
+func (f SinkFunc) Send(n Notification) error {
+	return f(n)
+}
+
+// OSSink shows a desktop notification via beeep.
+type OSSink struct{}
+
+// Send shows the notification.
+func (OSSink) Send(n Notification) error {
+	return beeep.Notify(n.Title, n.Body, "")
+}
+
+// CommandSink runs a shell command per notification. The notification JSON is
+// on stdin, and FRAY_NOTIFY_TITLE, FRAY_NOTIFY_BODY, FRAY_NOTIFY_RULE and
+// FRAY_NOTIFY_COUNT are set in its environment.
+type CommandSink struct {
+	Command string
+}
+
+// Send runs the command.
+func (s CommandSink) Send(n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("sh", "-c", s.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"FRAY_NOTIFY_TITLE="+n.Title,
+		"FRAY_NOTIFY_BODY="+n.Body,
+		"FRAY_NOTIFY_RULE="+n.Rule,
+		fmt.Sprintf("FRAY_NOTIFY_COUNT=%d", len(n.Events)),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+			return err
+		}
+		return nil
+	case <-time.After(commandTimeout):
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("command timed out after %s", commandTimeout)
+	}
+}
+
+// WebhookSink POSTs the notification JSON to a URL.
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+}
+
+// Send posts the notification.
+func (s WebhookSink) Send(n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fray-notify")
+	for key, value := range s.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -10,6 +10,17 @@ const (
 	MessageTypeSurface MessageType = "surface"
 )
 
+// SignatureStatus is the result of checking a message against its author's
+// public key. Empty means the author has no registered key and the message is
+// unsigned.
+type SignatureStatus string
+
+const (
+	SignatureVerified   SignatureStatus = "verified"   // signed by the author's registered key
+	SignatureUnverified SignatureStatus = "unverified" // unsigned from a keyed author, or signed with no registered key
+	SignatureInvalid    SignatureStatus = "invalid"    // signature does not match id, author, and body
+)
+
 // PresenceState represents the agent's daemon-managed presence.
 type PresenceState string
 
@@ -84,16 +95,29 @@ type Message struct {
 	Edited           bool                       `json:"edited,omitempty"`
 	EditCount        int                        `json:"edit_count,omitempty"`
 	ArchivedAt       *int64                     `json:"archived_at,omitempty"`
+	Attachments      []Attachment               `json:"attachments,omitempty"`
+	Signature        *string                    `json:"signature,omitempty"`
+	SignatureStatus  SignatureStatus            `json:"signature_status,omitempty"`
+}
+
+// Attachment is a file attached to a message. Content lives in a
+// content-addressed blob under .fray/blobs/, keyed by Hash.
+type Attachment struct {
+	Name string `json:"name"`
+	Mime string `json:"mime"`
+	Size int64  `json:"size"`
+	Hash string `json:"hash"` // sha256 hex of the content
 }
 
 // MessageVersion represents a version of a message body.
 type MessageVersion struct {
-	Version    int    `json:"version"`
-	Body       string `json:"body"`
-	Timestamp  int64  `json:"timestamp"`
-	Reason     string `json:"reason,omitempty"`
-	IsOriginal bool   `json:"is_original,omitempty"`
-	IsCurrent  bool   `json:"is_current,omitempty"`
+	Version         int             `json:"version"`
+	Body            string          `json:"body"`
+	Timestamp       int64           `json:"timestamp"`
+	Reason          string          `json:"reason,omitempty"`
+	IsOriginal      bool            `json:"is_original,omitempty"`
+	IsCurrent       bool            `json:"is_current,omitempty"`
+	SignatureStatus SignatureStatus `json:"signature_status,omitempty"`
 }
 
 // MessageVersionHistory summarizes all versions of a message.
@@ -160,6 +184,12 @@ type OptionalBool struct {
 	Value bool
 }
 
+// OptionalStrings represents a string list update.
+type OptionalStrings struct {
+	Set   bool
+	Value []string
+}
+
 // MessageQueryOptions controls message queries.
 type MessageQueryOptions struct {
 	Limit                 int
@@ -251,6 +281,38 @@ type Question struct {
 	CreatedAt  int64            `json:"created_at"`
 }
 
+// TaskStatus represents task lifecycle state.
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "todo"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusBlocked    TaskStatus = "blocked"
+	TaskStatusDone       TaskStatus = "done"
+)
+
+// Task represents a tracked work item.
+type Task struct {
+	GUID          string     `json:"guid"`
+	Title         string     `json:"title"`
+	CreatedBy     string     `json:"created_by"`
+	Assignee      *string    `json:"assignee,omitempty"`
+	Status        TaskStatus `json:"status"`
+	ThreadGUID    *string    `json:"thread_guid,omitempty"`
+	MessageGUID   *string    `json:"message_guid,omitempty"` // message the task came from
+	DependsOn     []string   `json:"depends_on,omitempty"`   // tasks that must be done first
+	BlockedReason *string    `json:"blocked_reason,omitempty"`
+	CreatedAt     int64      `json:"created_at"`
+	UpdatedAt     int64      `json:"updated_at"`
+}
+
+// TaskQueryOptions controls task queries.
+type TaskQueryOptions struct {
+	Statuses   []TaskStatus
+	Assignee   *string
+	ThreadGUID *string
+}
+
 // ThreadStatus represents thread lifecycle state.
 type ThreadStatus string
 
@@ -281,6 +343,9 @@ type Thread struct {
 	AnchorMessageGUID *string      `json:"anchor_message_guid,omitempty"`
 	AnchorHidden      bool         `json:"anchor_hidden,omitempty"`
 	LastActivityAt    *int64       `json:"last_activity_at,omitempty"`
+	Writers           []string     `json:"writers,omitempty"`  // agents allowed to post (empty = anyone)
+	Readers           []string     `json:"readers,omitempty"`  // agents allowed to read (empty = anyone)
+	Curators          []string     `json:"curators,omitempty"` // agents allowed to move, rename, archive, and moderate (empty = owner, or anyone if unowned)
 }
 
 // ThreadSubscription records a thread subscription.
@@ -302,20 +367,23 @@ type ThreadMessage struct {
 type ClaimType string
 
 const (
-	ClaimTypeFile  ClaimType = "file"
-	ClaimTypeBD    ClaimType = "bd"
-	ClaimTypeIssue ClaimType = "issue"
+	ClaimTypeFile    ClaimType = "file"
+	ClaimTypeBD      ClaimType = "bd"
+	ClaimTypeIssue   ClaimType = "issue"
+	ClaimTypeBranch  ClaimType = "branch"
+	ClaimTypeMessage ClaimType = "msg" // ack reaction on a message
 )
 
 // Claim represents a resource claim.
 type Claim struct {
-	ID        int64     `json:"id"`
-	AgentID   string    `json:"agent_id"`
-	ClaimType ClaimType `json:"claim_type"`
-	Pattern   string    `json:"pattern"`
-	Reason    *string   `json:"reason,omitempty"`
-	CreatedAt int64     `json:"created_at"`
-	ExpiresAt *int64    `json:"expires_at,omitempty"`
+	ID          int64     `json:"id"`
+	AgentID     string    `json:"agent_id"`
+	ClaimType   ClaimType `json:"claim_type"`
+	Pattern     string    `json:"pattern"`
+	Reason      *string   `json:"reason,omitempty"`
+	CreatedAt   int64     `json:"created_at"`
+	ExpiresAt   *int64    `json:"expires_at,omitempty"`
+	MessageGUID *string   `json:"message_guid,omitempty"` // message that announced the claim
 }
 
 // ClaimInput represents new-claim data.
@@ -379,6 +447,52 @@ type GhostCursor struct {
 	SessionAckAt *int64 `json:"session_ack_at,omitempty"` // when first viewed this session
 }
 
+// Handoff records a transfer of work from one agent to another.
+// A single handoff can move claims, thread ownership, and set a must-read
+// ghost cursor for the recipient.
+type Handoff struct {
+	GUID              string   `json:"guid"`
+	FromAgent         string   `json:"from_agent"`
+	ToAgent           string   `json:"to_agent"`
+	Home              string   `json:"home"`         // "room" or thread GUID
+	MessageGUID       string   `json:"message_guid"` // the posted handoff message
+	Summary           string   `json:"summary,omitempty"`
+	Claims            []string `json:"claims,omitempty"`              // transferred claims as type:pattern
+	CursorMessageGUID *string  `json:"cursor_message_guid,omitempty"` // must-read ghost cursor set for recipient
+	OwnerTransferred  bool     `json:"owner_transferred,omitempty"`   // thread ownership moved to recipient
+	CreatedAt         int64    `json:"created_at"`
+}
+
+// ThreadSummary records a generated summary posted as a thread's anchor,
+// with where it came from and the span of messages it covers.
+type ThreadSummary struct {
+	GUID             string `json:"guid"`
+	ThreadGUID       string `json:"thread_guid"`
+	MessageGUID      string `json:"message_guid"` // the anchor message holding the summary
+	Body             string `json:"body"`
+	Source           string `json:"source"`     // "command" or "agent"
+	Summarizer       string `json:"summarizer"` // command line or agent ID
+	Trigger          string `json:"trigger"`    // "archive" or "prune"
+	FirstMessageGUID string `json:"first_message_guid"`
+	LastMessageGUID  string `json:"last_message_guid"`
+	MessageCount     int    `json:"message_count"`
+	FromTS           int64  `json:"from_ts"`
+	ToTS             int64  `json:"to_ts"`
+	CreatedAt        int64  `json:"created_at"`
+}
+
+// ScheduledMessage is a message queued to post at a later time.
+// It stays pending until delivered or cancelled.
+type ScheduledMessage struct {
+	GUID      string      `json:"guid"`
+	FromAgent string      `json:"from_agent"`
+	Home      string      `json:"home"` // "room" or thread GUID
+	Body      string      `json:"body"`
+	Type      MessageType `json:"message_type"`
+	SendAt    int64       `json:"send_at"`
+	CreatedAt int64       `json:"created_at"`
+}
+
 // RoleAssignment represents a persistent role held by an agent.
 type RoleAssignment struct {
 	AgentID    string `json:"agent_id"`
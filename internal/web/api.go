@@ -0,0 +1,498 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+const (
+	defaultMessageLimit = 100
+	maxMessageLimit     = 500
+)
+
+// threadView is a thread as listed in the sidebar tree.
+type threadView struct {
+	GUID     string  `json:"guid"`
+	Name     string  `json:"name"`
+	Path     string  `json:"path"`
+	Parent   *string `json:"parent,omitempty"`
+	Depth    int     `json:"depth"`
+	Unread   int     `json:"unread"`
+	Faved    bool    `json:"faved"`
+	Muted    bool    `json:"muted"`
+	Nickname string  `json:"nickname,omitempty"`
+}
+
+// agentView is an agent's presence as shown in the agents panel.
+type agentView struct {
+	AgentID  string              `json:"agent_id"`
+	Avatar   string              `json:"avatar,omitempty"`
+	Presence types.PresenceState `json:"presence,omitempty"`
+	Status   string              `json:"status,omitempty"`
+	Managed  bool                `json:"managed,omitempty"`
+	LastSeen int64               `json:"last_seen"`
+	Active   bool                `json:"active"`
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"username": s.username,
+		"project":  s.projectName,
+	})
+}
+
+func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request) {
+	threads, err := db.GetThreads(s.db, &types.ThreadQueryOptions{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	guids := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		guids = append(guids, thread.GUID)
+	}
+	unread, _ := db.GetUnreadCountsForAgent(s.db, s.username, guids)
+	roomUnread, _ := db.GetRoomUnreadCount(s.db, s.username)
+	muted, _ := db.GetMutedThreadGUIDs(s.db, s.username)
+	nicknames, _ := db.GetThreadNicknames(s.db, s.username)
+	faved := map[string]bool{}
+	if favedGUIDs, err := db.GetFavedThreads(s.db, s.username); err == nil {
+		for _, guid := range favedGUIDs {
+			faved[guid] = true
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"room_unread": roomUnread,
+		"threads":     buildThreadTree(threads, unread, faved, muted, nicknames),
+	})
+}
+
+// buildThreadTree orders threads depth-first under their parents, with paths
+// like "design/api".
+func buildThreadTree(threads []types.Thread, unread map[string]int, faved, muted map[string]bool, nicknames map[string]string) []threadView {
+	byGUID := make(map[string]types.Thread, len(threads))
+	children := map[string][]types.Thread{}
+	for _, thread := range threads {
+		byGUID[thread.GUID] = thread
+	}
+	for _, thread := range threads {
+		parent := ""
+		if thread.ParentThread != nil {
+			if _, ok := byGUID[*thread.ParentThread]; ok {
+				parent = *thread.ParentThread
+			}
+		}
+		children[parent] = append(children[parent], thread)
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+
+	views := make([]threadView, 0, len(threads))
+	var walk func(parent, prefix string, depth int)
+	walk = func(parent, prefix string, depth int) {
+		for _, thread := range children[parent] {
+			path := thread.Name
+			if prefix != "" {
+				path = prefix + "/" + thread.Name
+			}
+			views = append(views, threadView{
+				GUID:     thread.GUID,
+				Name:     thread.Name,
+				Path:     path,
+				Parent:   thread.ParentThread,
+				Depth:    depth,
+				Unread:   unread[thread.GUID],
+				Faved:    faved[thread.GUID],
+				Muted:    muted[thread.GUID],
+				Nickname: nicknames[thread.GUID],
+			})
+			walk(thread.GUID, path, depth+1)
+		}
+	}
+	walk("", "", 0)
+	return views
+}
+
+// handleMessages returns the latest messages in the room or a thread
+// (?home=room|<thread-guid>&limit=N) and marks them read.
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	home := r.URL.Query().Get("home")
+	limit := defaultMessageLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", value))
+			return
+		}
+		limit = min(parsed, maxMessageLimit)
+	}
+
+	var messages []types.Message
+	var err error
+	if home == "" || home == "room" {
+		home = "room"
+		messages, err = db.GetMessages(s.db, &types.MessageQueryOptions{Limit: limit})
+	} else {
+		thread, lookupErr := db.GetThread(s.db, home)
+		if lookupErr != nil || thread == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("thread not found: %s", home))
+			return
+		}
+		messages, err = db.GetThreadMessages(s.db, thread.GUID)
+		if len(messages) > limit {
+			messages = messages[len(messages)-limit:]
+		}
+	}
+	if err == nil {
+		messages, err = db.ApplyMessageEditCounts(s.project.DBPath, messages)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if messages == nil {
+		messages = []types.Message{}
+	}
+
+	if len(messages) > 0 {
+		latest := messages[len(messages)-1]
+		readHome := home
+		if readHome == "room" {
+			readHome = ""
+		}
+		_ = db.SetReadTo(s.db, s.username, readHome, latest.ID, latest.TS)
+	}
+
+	faved := map[string]bool{}
+	if faves, err := db.GetFaves(s.db, s.username, "message"); err == nil {
+		for _, fave := range faves {
+			faved[fave.ItemGUID] = true
+		}
+	}
+	favedIDs := []string{}
+	for _, msg := range messages {
+		if faved[msg.ID] {
+			favedIDs = append(favedIDs, msg.ID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"home":     home,
+		"messages": messages,
+		"faved":    favedIDs,
+	})
+}
+
+type postRequest struct {
+	Home    string `json:"home"`
+	Body    string `json:"body"`
+	ReplyTo string `json:"reply_to,omitempty"`
+}
+
+// handlePost posts a message as the local user, like sending from chat. A
+// reply whose body is a short reaction becomes a reaction instead.
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	var req postRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("message body is required"))
+		return
+	}
+
+	var replyMsg *types.Message
+	if req.ReplyTo != "" {
+		msg, err := db.GetMessage(s.db, req.ReplyTo)
+		if err != nil || msg == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("message not found: %s", req.ReplyTo))
+			return
+		}
+		replyMsg = msg
+		if reaction, ok := core.NormalizeReactionText(body); ok {
+			s.react(w, msg.ID, reaction)
+			return
+		}
+	}
+
+	var thread *types.Thread
+	if req.Home != "" && req.Home != "room" {
+		found, err := db.GetThread(s.db, req.Home)
+		if err != nil || found == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("thread not found: %s", req.Home))
+			return
+		}
+		thread = found
+	}
+
+	bases, err := db.GetAgentBases(s.db)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	mentions := core.ExtractMentions(body, bases)
+	mentions = core.ExpandAllMention(mentions, bases)
+
+	message := types.Message{
+		FromAgent: s.username,
+		Body:      body,
+		Mentions:  mentions,
+		Type:      types.MessageTypeUser,
+	}
+	if replyMsg != nil {
+		message.ReplyTo = &replyMsg.ID
+	}
+	if thread != nil {
+		message.Home = thread.GUID
+	}
+	created, err := db.CreateMessage(s.db, message)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := db.AppendMessage(s.project.DBPath, created); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// Cross-channel copies are best effort; the local post already landed
+	_, _ = db.ForwardChannelMentions(s.project.DBPath, created)
+
+	if thread != nil && replyMsg != nil && replyMsg.Home != thread.GUID {
+		now := time.Now().Unix()
+		if err := db.AddMessageToThread(s.db, thread.GUID, replyMsg.ID, s.username, now); err == nil {
+			_ = db.AppendThreadMessage(s.project.DBPath, db.ThreadMessageJSONLRecord{
+				ThreadGUID:  thread.GUID,
+				MessageGUID: replyMsg.ID,
+				AddedBy:     s.username,
+				AddedAt:     now,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+type reactRequest struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+}
+
+func (s *Server) handleReact(w http.ResponseWriter, r *http.Request) {
+	var req reactRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	reaction, ok := core.NormalizeReactionText(req.Emoji)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid reaction: %q", req.Emoji))
+		return
+	}
+	s.react(w, req.MessageID, reaction)
+}
+
+func (s *Server) react(w http.ResponseWriter, messageID, reaction string) {
+	updated, reactedAt, err := db.AddReaction(s.db, messageID, s.username, reaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := db.AppendReaction(s.project.DBPath, messageID, s.username, reaction, reactedAt); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := db.ApplyReactionMeaning(s.db, s.project.DBPath, *updated, s.username, reaction, reactedAt); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// handleQuestions lists questions by status (?status=open|unasked|answered|closed).
+func (s *Server) handleQuestions(w http.ResponseWriter, r *http.Request) {
+	status := types.QuestionStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = types.QuestionStatusOpen
+	}
+	switch status {
+	case types.QuestionStatusOpen, types.QuestionStatusUnasked, types.QuestionStatusAnswered, types.QuestionStatusClosed:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid status: %s", status))
+		return
+	}
+	questions, err := db.GetQuestions(s.db, &types.QuestionQueryOptions{Statuses: []types.QuestionStatus{status}})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if questions == nil {
+		questions = []types.Question{}
+	}
+	writeJSON(w, http.StatusOK, questions)
+}
+
+type answerRequest struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// handleAnswer answers one question, posted like `fray answer`.
+func (s *Server) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	var req answerRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	answer := strings.TrimSpace(req.Answer)
+	if answer == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("answer is required"))
+		return
+	}
+	question, err := db.GetQuestion(s.db, req.Question)
+	if err != nil || question == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("question not found: %s", req.Question))
+		return
+	}
+	if question.Status == types.QuestionStatusClosed {
+		writeError(w, http.StatusConflict, fmt.Errorf("question %s is closed", question.GUID))
+		return
+	}
+	created, err := db.PostAnswerSummary(s.db, s.project.DBPath, s.username, []db.QuestionAnswer{{Question: *question, Answer: answer}})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleFaves(w http.ResponseWriter, r *http.Request) {
+	faves, err := db.GetFaves(s.db, s.username, "")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	threads := []types.Thread{}
+	messages := []types.Message{}
+	for _, fave := range faves {
+		switch fave.ItemType {
+		case "thread":
+			if thread, err := db.GetThread(s.db, fave.ItemGUID); err == nil && thread != nil {
+				threads = append(threads, *thread)
+			}
+		case "message":
+			if msg, err := db.GetMessage(s.db, fave.ItemGUID); err == nil && msg != nil {
+				messages = append(messages, *msg)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"threads": threads, "messages": messages})
+}
+
+type faveRequest struct {
+	Kind  string `json:"kind"` // "thread" or "message"
+	GUID  string `json:"guid"`
+	Faved bool   `json:"faved"`
+}
+
+// handleFave faves or unfaves a thread or message. Faving a thread also
+// follows it, as in chat.
+func (s *Server) handleFave(w http.ResponseWriter, r *http.Request) {
+	var req faveRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch req.Kind {
+	case "thread":
+		if thread, err := db.GetThread(s.db, req.GUID); err != nil || thread == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("thread not found: %s", req.GUID))
+			return
+		}
+	case "message":
+		if msg, err := db.GetMessage(s.db, req.GUID); err != nil || msg == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("message not found: %s", req.GUID))
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid kind: %s", req.Kind))
+		return
+	}
+
+	if !req.Faved {
+		if err := db.RemoveFave(s.db, s.username, req.Kind, req.GUID); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if err := db.AppendAgentUnfave(s.project.DBPath, s.username, req.Kind, req.GUID, time.Now().UnixMilli()); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, req)
+		return
+	}
+
+	favedAt, err := db.AddFave(s.db, s.username, req.Kind, req.GUID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := db.AppendAgentFave(s.project.DBPath, s.username, req.Kind, req.GUID, favedAt); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if req.Kind == "thread" {
+		now := time.Now().Unix()
+		if err := db.SubscribeThread(s.db, req.GUID, s.username, now); err == nil {
+			_ = db.AppendThreadSubscribe(s.project.DBPath, db.ThreadSubscribeJSONLRecord{
+				ThreadGUID:   req.GUID,
+				AgentID:      s.username,
+				SubscribedAt: now,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, req)
+}
+
+// handleAgents lists agents that have not left, most recently seen first.
+func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
+	agents, err := db.GetAgents(s.db)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	staleCutoff := time.Now().Add(-4 * time.Hour).Unix()
+	views := make([]agentView, 0, len(agents))
+	for _, agent := range agents {
+		if agent.LeftAt != nil {
+			continue
+		}
+		view := agentView{
+			AgentID:  agent.AgentID,
+			Presence: agent.Presence,
+			Managed:  agent.Managed,
+			LastSeen: agent.LastSeen,
+			Active:   agent.LastSeen >= staleCutoff,
+		}
+		if agent.Avatar != nil {
+			view.Avatar = *agent.Avatar
+		}
+		if agent.Status != nil {
+			view.Status = *agent.Status
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].LastSeen > views[j].LastSeen })
+	writeJSON(w, http.StatusOK, views)
+}
@@ -0,0 +1,285 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adamavenir/fray/internal/core"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+const (
+	tokenCookie  = "fray_token"
+	tokenHeader  = "X-Fray-Token"
+	pollInterval = time.Second
+	pingInterval = 15 * time.Second
+)
+
+// Options configures the web server.
+type Options struct {
+	DB          *sql.DB
+	Project     core.Project
+	ProjectName string
+	Username    string // identity for everything posted from the browser
+	Token       string // access token; generated when empty
+}
+
+// Server serves the web UI and its JSON API for one project.
+// It only listens on loopback and requires the access token printed at
+// startup, so only the local user can reach it.
+type Server struct {
+	db          *sql.DB
+	project     core.Project
+	projectName string
+	username    string
+	token       string
+	mux         *http.ServeMux
+}
+
+// NewServer creates a server for the project.
+func NewServer(opts Options) (*Server, error) {
+	if opts.Username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	token := opts.Token
+	if token == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("generate token: %w", err)
+		}
+		token = hex.EncodeToString(buf)
+	}
+
+	s := &Server{
+		db:          opts.DB,
+		project:     opts.Project,
+		projectName: opts.ProjectName,
+		username:    opts.Username,
+		token:       token,
+		mux:         http.NewServeMux(),
+	}
+	s.routes()
+	return s, nil
+}
+
+// Token returns the access token the browser must present.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Handler returns the server's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Serve runs the server on a loopback listener until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{Handler: s.mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) routes() {
+	static, _ := fs.Sub(staticFiles, "static")
+	assets := http.FileServer(http.FS(static))
+
+	s.mux.HandleFunc("/", s.handleIndex(static))
+	s.mux.Handle("/static/", s.guard(http.StripPrefix("/static/", assets)))
+
+	s.mux.Handle("GET /api/session", s.guard(http.HandlerFunc(s.handleSession)))
+	s.mux.Handle("GET /api/threads", s.guard(http.HandlerFunc(s.handleThreads)))
+	s.mux.Handle("GET /api/messages", s.guard(http.HandlerFunc(s.handleMessages)))
+	s.mux.Handle("POST /api/messages", s.guard(http.HandlerFunc(s.handlePost)))
+	s.mux.Handle("POST /api/reactions", s.guard(http.HandlerFunc(s.handleReact)))
+	s.mux.Handle("GET /api/questions", s.guard(http.HandlerFunc(s.handleQuestions)))
+	s.mux.Handle("POST /api/answers", s.guard(http.HandlerFunc(s.handleAnswer)))
+	s.mux.Handle("GET /api/faves", s.guard(http.HandlerFunc(s.handleFaves)))
+	s.mux.Handle("POST /api/faves", s.guard(http.HandlerFunc(s.handleFave)))
+	s.mux.Handle("GET /api/agents", s.guard(http.HandlerFunc(s.handleAgents)))
+	s.mux.Handle("GET /api/events", s.guard(http.HandlerFunc(s.handleEvents)))
+}
+
+// handleIndex serves the page. Opening the printed ?token= URL stores the
+// token in a cookie and redirects to a clean URL.
+func (s *Server) handleIndex(static fs.FS) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if !s.localHost(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if token := r.URL.Query().Get("token"); token != "" {
+			if !s.validToken(token) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		if !s.authorized(r) {
+			http.Error(w, "Open the URL printed by 'fray web' to sign in.", http.StatusUnauthorized)
+			return
+		}
+		page, err := fs.ReadFile(static, "index.html")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(page)
+	}
+}
+
+// guard rejects requests that are not from the local user: wrong Host
+// (DNS rebinding), missing token, or a cross-origin write.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.localHost(r) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("forbidden host"))
+			return
+		}
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid token"))
+			return
+		}
+		if r.Method != http.MethodGet {
+			if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+				writeError(w, http.StatusForbidden, fmt.Errorf("cross-origin request"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if token := r.Header.Get(tokenHeader); token != "" {
+		return s.validToken(token)
+	}
+	cookie, err := r.Cookie(tokenCookie)
+	return err == nil && s.validToken(cookie.Value)
+}
+
+func (s *Server) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) localHost(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	switch host {
+	case "localhost", "127.0.0.1", "::1", "[::1]":
+		return true
+	}
+	return false
+}
+
+// handleEvents streams an "update" server-sent event whenever the project's
+// JSONL logs or database change, so the page can refetch what it shows.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprint(w, "retry: 2000\n\n")
+	flusher.Flush()
+
+	last := s.changeStamp()
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-poll.C:
+			stamp := s.changeStamp()
+			if stamp.Equal(last) {
+				continue
+			}
+			last = stamp
+			data, _ := json.Marshal(map[string]int64{"at": stamp.UnixMilli()})
+			fmt.Fprintf(w, "event: update\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// changeStamp is the latest modification time across the project's JSONL
+// logs and database file.
+func (s *Server) changeStamp() time.Time {
+	frayDir := filepath.Dir(s.project.DBPath)
+	paths, _ := filepath.Glob(filepath.Join(frayDir, "*.jsonl"))
+	paths = append(paths, s.project.DBPath)
+	var latest time.Time
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func decodeBody(r *http.Request, dest any) error {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return fmt.Errorf("expected application/json")
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dest)
+}
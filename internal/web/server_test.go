@@ -0,0 +1,178 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamavenir/fray/internal/core"
+	"github.com/adamavenir/fray/internal/db"
+	"github.com/adamavenir/fray/internal/types"
+)
+
+const testToken = "test-token"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	projectDir := t.TempDir()
+	frayDir := filepath.Join(projectDir, ".fray")
+	if err := os.MkdirAll(frayDir, 0o755); err != nil {
+		t.Fatalf("mkdir .fray: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(frayDir, "fray-config.json"), []byte(`{"channel_id":"ch-test","channel_name":"test"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(frayDir, "messages.jsonl"), nil, 0o644); err != nil {
+		t.Fatalf("write messages: %v", err)
+	}
+
+	project, err := core.DiscoverProject(projectDir)
+	if err != nil {
+		t.Fatalf("discover project: %v", err)
+	}
+	database, err := db.OpenDatabase(project)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.InitSchema(database); err != nil {
+		t.Fatalf("init schema: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	server, err := NewServer(Options{DB: database, Project: project, ProjectName: "test", Username: "adam", Token: testToken})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	return server
+}
+
+func request(s *Server, method, path, body string, authed bool) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	req.Host = "127.0.0.1:7777"
+	if authed {
+		req.Header.Set(tokenHeader, testToken)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	s := newTestServer(t)
+
+	if rec := request(s, "GET", "/api/session", "", false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+	if rec := request(s, "GET", "/", "", false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for page without token, got %d", rec.Code)
+	}
+
+	rec := request(s, "GET", "/?token="+testToken, "", false)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected redirect after token login, got %d", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != tokenCookie || !cookies[0].HttpOnly {
+		t.Fatalf("expected http-only token cookie, got %+v", cookies)
+	}
+
+	req := httptest.NewRequest("GET", "/api/session", nil)
+	req.Host = "localhost:7777"
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected cookie auth to succeed, got %d", rec.Code)
+	}
+}
+
+func TestServerRejectsForeignHostAndOrigin(t *testing.T) {
+	s := newTestServer(t)
+
+	req := httptest.NewRequest("GET", "/api/session", nil)
+	req.Host = "evil.example:7777"
+	req.Header.Set(tokenHeader, testToken)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for foreign host, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/api/messages", strings.NewReader(`{"body":"hi"}`))
+	req.Host = "127.0.0.1:7777"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "http://evil.example")
+	req.Header.Set(tokenHeader, testToken)
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for cross-origin post, got %d", rec.Code)
+	}
+}
+
+func TestServerPostReactAndFave(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := request(s, "POST", "/api/messages", `{"home":"room","body":"hello from the browser"}`, true)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("post: %d %s", rec.Code, rec.Body.String())
+	}
+	var posted types.Message
+	if err := json.Unmarshal(rec.Body.Bytes(), &posted); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	if posted.FromAgent != "adam" || posted.Type != types.MessageTypeUser {
+		t.Fatalf("expected user message from adam, got %+v", posted)
+	}
+
+	rec = request(s, "POST", "/api/messages", `{"home":"room","body":"+1","reply_to":"`+posted.ID+`"}`, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reaction reply: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = request(s, "POST", "/api/faves", `{"kind":"message","guid":"`+posted.ID+`","faved":true}`, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("fave: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = request(s, "GET", "/api/messages?home=room", "", true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("messages: %d %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Messages []types.Message `json:"messages"`
+		Faved    []string        `json:"faved"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode messages: %v", err)
+	}
+	if len(payload.Messages) != 1 {
+		t.Fatalf("expected reaction not to post a message, got %d messages", len(payload.Messages))
+	}
+	if len(payload.Messages[0].Reactions) != 1 {
+		t.Fatalf("expected one reaction, got %+v", payload.Messages[0].Reactions)
+	}
+	if len(payload.Faved) != 1 || payload.Faved[0] != posted.ID {
+		t.Fatalf("expected message to be faved, got %v", payload.Faved)
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(s.project.DBPath), "messages.jsonl"))
+	if err != nil {
+		t.Fatalf("read messages.jsonl: %v", err)
+	}
+	if !strings.Contains(string(data), "hello from the browser") {
+		t.Fatalf("expected message in messages.jsonl")
+	}
+}
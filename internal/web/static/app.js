@@ -0,0 +1,326 @@
+// fray web UI: a thin client over the /api endpoints served by `fray web`.
+// Everything is re-fetched when the server reports an update over SSE.
+(() => {
+  "use strict";
+
+  const state = {
+    username: "",
+    home: "room",
+    threads: [],
+    faved: new Set(),
+    replyTo: null,
+  };
+
+  const $ = (id) => document.getElementById(id);
+
+  async function api(path, options = {}) {
+    const init = { credentials: "same-origin", ...options };
+    if (init.body !== undefined) {
+      init.method = init.method || "POST";
+      init.headers = { "Content-Type": "application/json" };
+      init.body = JSON.stringify(init.body);
+    }
+    const res = await fetch(path, init);
+    const data = await res.json().catch(() => ({}));
+    if (!res.ok) {
+      throw new Error(data.error || res.statusText);
+    }
+    return data;
+  }
+
+  function el(tag, props = {}, ...children) {
+    const node = document.createElement(tag);
+    for (const [key, value] of Object.entries(props)) {
+      if (key === "class") node.className = value;
+      else if (key.startsWith("on")) node.addEventListener(key.slice(2), value);
+      else node.setAttribute(key, value);
+    }
+    for (const child of children) {
+      if (child == null) continue;
+      node.append(child instanceof Node ? child : document.createTextNode(String(child)));
+    }
+    return node;
+  }
+
+  function escapeHTML(text) {
+    return text.replace(/[&<>"']/g, (c) => ({ "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" })[c]);
+  }
+
+  // renderMarkdown covers what chat renders: fenced code, inline code, bold,
+  // italics, links and @mentions. Input is escaped before any markup is added.
+  function renderMarkdown(text) {
+    const blocks = text.split(/```/);
+    return blocks
+      .map((block, i) => {
+        if (i % 2 === 1) {
+          const code = block.replace(/^[^\n]*\n/, "");
+          return `<pre><code>${escapeHTML(code)}</code></pre>`;
+        }
+        return block
+          .split(/\n{2,}/)
+          .filter((para) => para.trim() !== "")
+          .map((para) => `<p>${inline(para)}</p>`)
+          .join("");
+      })
+      .join("");
+  }
+
+  function inline(text) {
+    return escapeHTML(text)
+      .replace(/`([^`]+)`/g, "<code>$1</code>")
+      .replace(/\*\*([^*]+)\*\*/g, "<strong>$1</strong>")
+      .replace(/(^|[^*])\*([^*\n]+)\*/g, "$1<em>$2</em>")
+      .replace(/\[([^\]]+)\]\((https?:\/\/[^\s)]+)\)/g, '<a href="$2" target="_blank" rel="noopener">$1</a>')
+      .replace(/(^|\s)@([\w.-]+)/g, '$1<span class="mention">@$2</span>')
+      .replace(/\n/g, "<br>");
+  }
+
+  function formatTime(ts) {
+    return new Date(ts * 1000).toLocaleString([], { month: "short", day: "numeric", hour: "2-digit", minute: "2-digit" });
+  }
+
+  function threadLabel(guid) {
+    const thread = state.threads.find((t) => t.guid === guid);
+    return thread ? thread.path : guid;
+  }
+
+  // ---- sidebar ----
+
+  async function loadThreads() {
+    const data = await api("/api/threads");
+    state.threads = data.threads;
+    $("room-unread").textContent = data.room_unread ? data.room_unread : "";
+    $("room-link").classList.toggle("active", state.home === "room");
+    const list = $("threads");
+    list.replaceChildren(
+      ...data.threads.map((thread) => {
+        const link = el(
+          "a",
+          { href: `#${thread.guid}`, class: [thread.guid === state.home ? "active" : "", thread.muted ? "muted" : ""].join(" ") },
+          thread.faved ? "★ " : "",
+          thread.nickname || thread.name,
+          " ",
+          el("span", { class: "badge" }, thread.unread && !thread.muted ? thread.unread : ""),
+        );
+        return el("li", { style: `padding-left: ${thread.depth * 12}px` }, link);
+      }),
+    );
+    updateTitle();
+  }
+
+  function updateTitle() {
+    const fave = $("fave-thread");
+    if (state.home === "room") {
+      $("title").textContent = "#main";
+      fave.hidden = true;
+      return;
+    }
+    const thread = state.threads.find((t) => t.guid === state.home);
+    $("title").textContent = thread ? thread.path : state.home;
+    fave.hidden = false;
+    fave.textContent = thread && thread.faved ? "★" : "☆";
+    fave.dataset.faved = thread && thread.faved ? "1" : "";
+  }
+
+  // ---- messages ----
+
+  async function loadMessages() {
+    const view = $("messages");
+    const atBottom = view.scrollHeight - view.scrollTop - view.clientHeight < 40;
+    const data = await api(`/api/messages?home=${encodeURIComponent(state.home)}`);
+    state.faved = new Set(data.faved);
+    view.replaceChildren(...data.messages.map(renderMessage));
+    if (atBottom) view.scrollTop = view.scrollHeight;
+  }
+
+  function renderMessage(msg) {
+    const body = el("div", { class: "body" });
+    body.innerHTML = renderMarkdown(msg.body);
+
+    const reactions = el(
+      "div",
+      { class: "reactions" },
+      ...Object.entries(msg.reactions || {}).map(([emoji, entries]) =>
+        el("span", { title: entries.map((e) => e.agent_id).join(", ") }, `${emoji} ${entries.length}`),
+      ),
+    );
+
+    const faved = state.faved.has(msg.id);
+    const actions = el(
+      "span",
+      { class: "actions" },
+      el("button", { type: "button", title: "Reply", onclick: () => startReply(msg) }, "↩"),
+      el("button", { type: "button", title: "React", onclick: () => promptReaction(msg) }, "+"),
+      el("button", { type: "button", title: faved ? "Unfave" : "Fave", onclick: () => setFave("message", msg.id, !faved) }, faved ? "★" : "☆"),
+    );
+
+    return el(
+      "article",
+      { class: `message ${msg.type}`, id: msg.id },
+      el(
+        "div",
+        { class: "meta" },
+        el("span", { class: "from" }, `@${msg.from_agent}`),
+        formatTime(msg.ts),
+        msg.edited ? " (edited)" : "",
+        " ",
+        el("span", { class: "id" }, `#${msg.id}`),
+        actions,
+      ),
+      msg.reply_to ? el("div", { class: "reply-ref" }, `↪ reply to #${msg.reply_to}`) : null,
+      body,
+      Object.keys(msg.reactions || {}).length ? reactions : null,
+    );
+  }
+
+  function startReply(msg) {
+    state.replyTo = msg.id;
+    $("replying-to").textContent = `@${msg.from_agent} #${msg.id}`;
+    $("replying").hidden = false;
+    $("input").focus();
+  }
+
+  function cancelReply() {
+    state.replyTo = null;
+    $("replying").hidden = true;
+  }
+
+  async function promptReaction(msg) {
+    const emoji = window.prompt("Reaction", "👍");
+    if (!emoji) return;
+    await run(api("/api/reactions", { body: { message_id: msg.id, emoji } }));
+  }
+
+  async function setFave(kind, guid, faved) {
+    await run(api("/api/faves", { body: { kind, guid, faved } }));
+  }
+
+  async function send() {
+    const input = $("input");
+    const body = input.value.trim();
+    if (!body) return;
+    const payload = { home: state.home, body };
+    if (state.replyTo) payload.reply_to = state.replyTo;
+    await run(
+      api("/api/messages", { body: payload }).then(() => {
+        input.value = "";
+        cancelReply();
+      }),
+    );
+  }
+
+  // ---- panels ----
+
+  async function loadAgents() {
+    const agents = await api("/api/agents");
+    $("agents").replaceChildren(
+      ...agents.map((agent) =>
+        el(
+          "li",
+          { title: `last seen ${formatTime(agent.last_seen)}` },
+          el("span", { class: `presence ${agent.presence || (agent.active ? "active" : "offline")}` }),
+          agent.avatar ? `${agent.avatar} ` : "",
+          `@${agent.agent_id}`,
+          agent.status ? el("span", { class: "status" }, agent.status) : null,
+        ),
+      ),
+    );
+  }
+
+  async function loadQuestions() {
+    const questions = await api("/api/questions?status=open");
+    $("questions").replaceChildren(
+      ...questions.map((q) => {
+        const input = el("input", { placeholder: "Answer…" });
+        const form = el("form", {}, input, el("button", { type: "submit" }, "Answer"));
+        form.addEventListener("submit", (event) => {
+          event.preventDefault();
+          const answer = input.value.trim();
+          if (answer) run(api("/api/answers", { body: { question: q.guid, answer } }));
+        });
+        const canAnswer = !q.to_agent || q.to_agent === state.username;
+        return el(
+          "li",
+          { class: "question" },
+          q.re,
+          el("div", { class: "asked" }, `@${q.from_agent}${q.to_agent ? ` → @${q.to_agent}` : ""} · ${q.guid}`),
+          canAnswer ? form : null,
+        );
+      }),
+    );
+  }
+
+  async function loadFaves() {
+    const faves = await api("/api/faves");
+    $("faves").replaceChildren(
+      ...faves.threads.map((thread) => el("li", {}, el("a", { href: `#${thread.guid}` }, `★ ${threadLabel(thread.guid)}`))),
+      ...faves.messages.map((msg) =>
+        el("li", {}, el("a", { href: msg.home ? `#${msg.home}` : "#room" }, `@${msg.from_agent}: ${msg.body.slice(0, 60)}`)),
+      ),
+    );
+  }
+
+  // ---- wiring ----
+
+  async function run(promise) {
+    try {
+      await promise;
+      await refresh();
+    } catch (err) {
+      window.alert(err.message);
+    }
+  }
+
+  let refreshing = null;
+  function refresh() {
+    if (!refreshing) {
+      refreshing = Promise.all([loadThreads(), loadMessages(), loadAgents(), loadQuestions()])
+        .then(loadFaves)
+        .finally(() => {
+          refreshing = null;
+        });
+    }
+    return refreshing;
+  }
+
+  function navigate() {
+    state.home = decodeURIComponent(location.hash.slice(1)) || "room";
+    cancelReply();
+    refresh();
+  }
+
+  async function start() {
+    const session = await api("/api/session");
+    state.username = session.username;
+    $("identity").textContent = `@${session.username}`;
+    $("project").textContent = session.project || "fray";
+    document.title = `fray · ${session.project || ""}`;
+
+    $("input").addEventListener("keydown", (event) => {
+      if (event.key === "Enter" && !event.shiftKey) {
+        event.preventDefault();
+        send();
+      } else if (event.key === "Escape") {
+        cancelReply();
+      }
+    });
+    $("composer").addEventListener("submit", (event) => {
+      event.preventDefault();
+      send();
+    });
+    $("cancel-reply").addEventListener("click", cancelReply);
+    $("fave-thread").addEventListener("click", (event) => {
+      setFave("thread", state.home, !event.currentTarget.dataset.faved);
+    });
+    window.addEventListener("hashchange", navigate);
+
+    const events = new EventSource("/api/events");
+    events.addEventListener("update", () => refresh());
+
+    navigate();
+  }
+
+  start().catch((err) => {
+    document.body.textContent = err.message;
+  });
+})();